@@ -115,6 +115,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/snmptraps"
 	snmptrapsServer "github.com/DataDog/datadog-agent/comp/snmptraps/server"
 	traceagentStatusImpl "github.com/DataDog/datadog-agent/comp/trace/status/statusimpl"
+	localexternalmetrics "github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local/forwarder"
 	pkgcollector "github.com/DataDog/datadog-agent/pkg/collector"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net"
@@ -409,6 +410,9 @@ func getSharedFxOption() fx.Option {
 		fx.Provide(func(config config.Component) demultiplexerimpl.Params {
 			params := demultiplexerimpl.NewDefaultParams()
 			params.EnableNoAggregationPipeline = config.GetBool("dogstatsd_no_aggregation_pipeline")
+			if forwarder := localexternalmetrics.NewForwarder(config); forwarder != nil {
+				params.SeriesForwarder = forwarder
+			}
 			return params
 		}),
 		orchestratorForwarderImpl.Module(),
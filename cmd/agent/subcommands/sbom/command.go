@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package sbom implements 'agent sbom'.
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	cyclonedxgo "github.com/CycloneDX/cyclonedx-go"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/log/logimpl"
+	"github.com/DataDog/datadog-agent/pkg/sbom/vulns"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+const (
+	formatCycloneDX = "cyclonedx"
+	formatSPDX      = "spdx"
+)
+
+// cliParams are the command-line arguments for the sbom subcommands
+type cliParams struct {
+	*command.GlobalParams
+
+	// args are the positional command-line arguments
+	args []string
+
+	// dbPath is the path of the vulnerability database
+	dbPath string
+
+	// jsonOutput outputs the findings as JSON
+	jsonOutput bool
+
+	// scanPath is the filesystem path to scan
+	scanPath string
+
+	// imageArchive is the path of the container image archive to scan
+	imageArchive string
+
+	// scanHost scans the host filesystem
+	scanHost bool
+
+	// format is the format of the generated SBOM
+	format string
+
+	// output is the file where the generated SBOM is written
+	output string
+}
+
+// Commands returns a slice of subcommands for the 'agent' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+
+	sbomCommand := &cobra.Command{
+		Use:   "sbom",
+		Short: "SBOM related commands",
+		Long:  ``,
+	}
+
+	vulnsCommand := &cobra.Command{
+		Use:   "vulns <sbom-file>",
+		Short: "Match a CycloneDX SBOM against the local vulnerability database",
+		Long: `Match the packages of a CycloneDX SBOM, in JSON format, against the local vulnerability database.
+The database can either be a Trivy database or OSV entries in JSON format. Use "-" to read the SBOM from the standard input.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliParams.args = args
+			return fxutil.OneShot(matchVulnerabilities,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewAgentParams(globalParams.ConfFilePath),
+					LogParams:    logimpl.ForOneShot(command.LoggerName, "off", true)}),
+				core.Bundle(),
+			)
+		},
+	}
+	vulnsCommand.Flags().StringVar(&cliParams.dbPath, "db", "", "path of the vulnerability database, defaults to sbom.vulnerabilities.db_path")
+	vulnsCommand.Flags().BoolVarP(&cliParams.jsonOutput, "json", "j", false, "print out the findings as JSON")
+	sbomCommand.AddCommand(vulnsCommand)
+
+	scanCommand := &cobra.Command{
+		Use:   "scan",
+		Short: "Generate the SBOM of a filesystem path, a container image archive or the host",
+		Long: `Generate the SBOM of a filesystem path, a container image archive or the host, with the same analyzers
+as the ones used by the Agent. The container image archive can be generated with "docker save".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fxutil.OneShot(scanSBOM,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewAgentParams(globalParams.ConfFilePath),
+					LogParams:    logimpl.ForOneShot(command.LoggerName, "off", true)}),
+				core.Bundle(),
+			)
+		},
+	}
+	scanCommand.Flags().StringVar(&cliParams.scanPath, "path", "", "filesystem path to scan")
+	scanCommand.Flags().StringVar(&cliParams.imageArchive, "image-archive", "", "container image archive to scan")
+	scanCommand.Flags().BoolVar(&cliParams.scanHost, "host", false, "scan the host")
+	scanCommand.MarkFlagsMutuallyExclusive("path", "image-archive", "host")
+	scanCommand.MarkFlagsOneRequired("path", "image-archive", "host")
+	scanCommand.Flags().StringVarP(&cliParams.format, "format", "f", formatCycloneDX, "format of the SBOM: cyclonedx or spdx")
+	scanCommand.Flags().StringVarP(&cliParams.output, "output", "o", "", "file where the SBOM is written, defaults to the standard output")
+	sbomCommand.AddCommand(scanCommand)
+
+	return []*cobra.Command{sbomCommand}
+}
+
+func matchVulnerabilities(config config.Component, cliParams *cliParams) error {
+	dbPath := cliParams.dbPath
+	if dbPath == "" {
+		dbPath = vulns.DBPathFromConfig(config)
+	}
+	db, err := vulns.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	bom, err := readBOM(cliParams.args[0])
+	if err != nil {
+		return err
+	}
+
+	findings, err := vulns.MatchBOM(db, bom)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Some packages could not be matched: %v\n", err)
+	}
+
+	if cliParams.jsonOutput {
+		if findings == nil {
+			findings = []vulns.Finding{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(findings)
+	}
+	return printFindings(os.Stdout, findings)
+}
+
+func readBOM(path string) (*cyclonedxgo.BOM, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open SBOM: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var bom cyclonedxgo.BOM
+	if err := cyclonedxgo.NewBOMDecoder(r, cyclonedxgo.BOMFileFormatJSON).Decode(&bom); err != nil {
+		return nil, fmt.Errorf("unable to decode CycloneDX SBOM: %w", err)
+	}
+	return &bom, nil
+}
+
+func printFindings(w io.Writer, findings []vulns.Finding) error {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No vulnerability found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VULNERABILITY\tSEVERITY\tPACKAGE\tVERSION\tFIXED VERSION\tTITLE")
+	for _, finding := range findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", finding.VulnerabilityID, finding.Severity, finding.PackageName, finding.PackageVersion, finding.FixedVersion, finding.Title)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n%d vulnerabilities found\n", len(findings))
+	return nil
+}
@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestVulnsCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"sbom", "vulns", "--db", "/tmp/trivy.db", "--json", "sbom.json"},
+		matchVulnerabilities,
+		func(cliParams *cliParams) {
+			require.Equal(t, []string{"sbom.json"}, cliParams.args)
+			require.Equal(t, "/tmp/trivy.db", cliParams.dbPath)
+			require.True(t, cliParams.jsonOutput)
+		})
+}
+
+func TestScanCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"sbom", "scan", "--image-archive", "image.tar", "--format", "spdx", "--output", "sbom.json"},
+		scanSBOM,
+		func(cliParams *cliParams) {
+			require.Equal(t, "image.tar", cliParams.imageArchive)
+			require.Equal(t, formatSPDX, cliParams.format)
+			require.Equal(t, "sbom.json", cliParams.output)
+			require.False(t, cliParams.scanHost)
+		})
+}
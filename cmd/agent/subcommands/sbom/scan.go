@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build trivy
+
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	cyclonedxgo "github.com/CycloneDX/cyclonedx-go"
+	spdxjson "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors/archive"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors/host"
+	"github.com/DataDog/datadog-agent/pkg/sbom/scanner"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// spdxReport is implemented by the reports that can be converted to SPDX
+type spdxReport interface {
+	ToSPDX() (*spdx.Document, error)
+}
+
+func scanSBOM(config config.Component, cliParams *cliParams) error {
+	if cliParams.format != formatCycloneDX && cliParams.format != formatSPDX {
+		return fmt.Errorf("unsupported SBOM format %q, expected %q or %q", cliParams.format, formatCycloneDX, formatSPDX)
+	}
+
+	request, collectorName, err := scanRequestFromParams(cliParams)
+	if err != nil {
+		return err
+	}
+
+	collector, ok := collectors.Collectors[collectorName]
+	if !ok {
+		return fmt.Errorf("collector %s is not available", collectorName)
+	}
+	if err := collector.Init(config, optional.NewNoneOption[workloadmeta.Component]()); err != nil {
+		return fmt.Errorf("unable to initialize collector %s: %w", collectorName, err)
+	}
+	defer collector.Shutdown()
+
+	sbomScanner := scanner.NewScanner(config, map[string]collectors.Collector{collectorName: collector}, optional.NewNoneOption[workloadmeta.Component]())
+	result := sbomScanner.PerformScan(context.Background(), request)
+	if result.Error != nil {
+		return fmt.Errorf("failed to scan %s: %w", request.ID(), result.Error)
+	}
+
+	var w io.Writer = os.Stdout
+	if cliParams.output != "" {
+		f, err := os.Create(cliParams.output)
+		if err != nil {
+			return fmt.Errorf("unable to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return writeReport(w, result.Report, cliParams.format)
+}
+
+// scanRequestFromParams returns the scan request matching the command-line
+// arguments, along with the name of the collector that handles it.
+func scanRequestFromParams(cliParams *cliParams) (sbom.ScanRequest, string, error) {
+	switch {
+	case cliParams.imageArchive != "":
+		path, err := filepath.Abs(cliParams.imageArchive)
+		if err != nil {
+			return nil, "", err
+		}
+		return archive.NewScanRequest(path), collectors.ImageArchiveCollector, nil
+	case cliParams.scanPath != "":
+		path, err := filepath.Abs(cliParams.scanPath)
+		if err != nil {
+			return nil, "", err
+		}
+		return host.NewScanRequest(path, host.NewFS("/")), collectors.HostCollector, nil
+	case cliParams.scanHost:
+		scanPath := "/"
+		if hostRoot := os.Getenv("HOST_ROOT"); env.IsContainerized() && hostRoot != "" {
+			scanPath = hostRoot
+		}
+		return host.NewScanRequest(scanPath, host.NewFS("/")), collectors.HostCollector, nil
+	}
+	return nil, "", fmt.Errorf("one of --path, --image-archive or --host is required")
+}
+
+func writeReport(w io.Writer, report sbom.Report, format string) error {
+	if format == formatSPDX {
+		spdxReport, ok := report.(spdxReport)
+		if !ok {
+			return fmt.Errorf("the report cannot be converted to SPDX")
+		}
+		doc, err := spdxReport.ToSPDX()
+		if err != nil {
+			return fmt.Errorf("unable to convert report to SPDX: %w", err)
+		}
+		return spdxjson.Write(doc, w, spdxjson.Indent("  "))
+	}
+
+	bom, err := report.ToCycloneDX()
+	if err != nil {
+		return fmt.Errorf("unable to convert report to CycloneDX: %w", err)
+	}
+	return cyclonedxgo.NewBOMEncoder(w, cyclonedxgo.BOMFileFormatJSON).SetPretty(true).Encode(bom)
+}
@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !trivy
+
+package sbom
+
+import (
+	"errors"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+)
+
+func scanSBOM(_ config.Component, _ *cliParams) error {
+	return errors.New("SBOM scans are not supported by this build of the agent")
+}
@@ -27,6 +27,7 @@ import (
 	cmdprocesschecks "github.com/DataDog/datadog-agent/cmd/agent/subcommands/processchecks"
 	cmdremoteconfig "github.com/DataDog/datadog-agent/cmd/agent/subcommands/remoteconfig"
 	cmdrun "github.com/DataDog/datadog-agent/cmd/agent/subcommands/run"
+	cmdsbom "github.com/DataDog/datadog-agent/cmd/agent/subcommands/sbom"
 	cmdsecret "github.com/DataDog/datadog-agent/cmd/agent/subcommands/secret"
 	cmdsecrethelper "github.com/DataDog/datadog-agent/cmd/agent/subcommands/secrethelper"
 	cmdsnmp "github.com/DataDog/datadog-agent/cmd/agent/subcommands/snmp"
@@ -58,6 +59,7 @@ func AgentSubcommands() []command.SubcommandFactory {
 		cmdlaunchgui.Commands,
 		cmdremoteconfig.Commands,
 		cmdrun.Commands,
+		cmdsbom.Commands,
 		cmdsecret.Commands,
 		cmdsnmp.Commands,
 		cmdstatus.Commands,
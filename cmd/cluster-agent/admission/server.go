@@ -46,6 +46,8 @@ type MutateRequest struct {
 	Name string
 	// Namespace is the namespace of the object
 	Namespace string
+	// Kind is the kind of the object
+	Kind metav1.GroupVersionKind
 	// UserInfo contains information about the requesting user
 	UserInfo *authenticationv1.UserInfo
 	// DynamicClient holds a dynamic Kubernetes client
@@ -57,6 +59,19 @@ type MutateRequest struct {
 // WebhookFunc is the function that runs the webhook logic
 type WebhookFunc func(request *MutateRequest) ([]byte, error)
 
+// ValidationResult contains the result of a validation request
+type ValidationResult struct {
+	// Allowed is whether the object is admitted
+	Allowed bool
+	// Message explains why the object is not admitted
+	Message string
+	// Warnings are returned to the requesting user when the object is admitted
+	Warnings []string
+}
+
+// ValidatingWebhookFunc is the function that runs the validating webhook logic
+type ValidatingWebhookFunc func(request *MutateRequest) (*ValidationResult, error)
+
 // Server TODO <container-integrations>
 type Server struct {
 	decoder runtime.Decoder
@@ -94,7 +109,19 @@ func (s *Server) initDecoder() {
 // Register must be called to register the desired webhook handlers before calling Run.
 func (s *Server) Register(uri string, webhookName string, f WebhookFunc, dc dynamic.Interface, apiClient kubernetes.Interface) {
 	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
-		s.mutateHandler(w, r, webhookName, f, dc, apiClient)
+		s.handle(w, r, webhookName, func(request *MutateRequest) *admiv1.AdmissionResponse {
+			return mutationResponse(f(request))
+		}, dc, apiClient)
+	})
+}
+
+// RegisterValidation adds a validating admission webhook handler.
+// RegisterValidation must be called to register the desired webhook handlers before calling Run.
+func (s *Server) RegisterValidation(uri string, webhookName string, f ValidatingWebhookFunc, dc dynamic.Interface, apiClient kubernetes.Interface) {
+	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
+		s.handle(w, r, webhookName, func(request *MutateRequest) *admiv1.AdmissionResponse {
+			return validationResponse(f(request))
+		}, dc, apiClient)
 	})
 }
 
@@ -134,9 +161,9 @@ func (s *Server) Run(mainCtx context.Context, client kubernetes.Interface) error
 	return server.Shutdown(shutdownCtx)
 }
 
-// mutateHandler contains the main logic responsible for handling mutation requests.
+// handle contains the main logic responsible for handling admission requests.
 // It supports both v1 and v1beta1 requests.
-func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, webhookName string, mutateFunc WebhookFunc, dc dynamic.Interface, apiClient kubernetes.Interface) {
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, webhookName string, respond func(*MutateRequest) *admiv1.AdmissionResponse, dc dynamic.Interface, apiClient kubernetes.Interface) {
 	metrics.WebhooksReceived.Inc(webhookName)
 
 	start := time.Now()
@@ -184,12 +211,12 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, webhookNa
 			Raw:           admissionReviewReq.Request.Object.Raw,
 			Name:          admissionReviewReq.Request.Name,
 			Namespace:     admissionReviewReq.Request.Namespace,
+			Kind:          admissionReviewReq.Request.Kind,
 			UserInfo:      &admissionReviewReq.Request.UserInfo,
 			DynamicClient: dc,
 			APIClient:     apiClient,
 		}
-		jsonPatch, err := mutateFunc(&mutateRequest)
-		admissionReviewResp.Response = mutationResponse(jsonPatch, err)
+		admissionReviewResp.Response = respond(&mutateRequest)
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	case admiv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
@@ -203,12 +230,12 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, webhookNa
 			Raw:           admissionReviewReq.Request.Object.Raw,
 			Name:          admissionReviewReq.Request.Name,
 			Namespace:     admissionReviewReq.Request.Namespace,
+			Kind:          admissionReviewReq.Request.Kind,
 			UserInfo:      &admissionReviewReq.Request.UserInfo,
 			DynamicClient: dc,
 			APIClient:     apiClient,
 		}
-		jsonPatch, err := mutateFunc(&mutateRequest)
-		admissionReviewResp.Response = responseV1ToV1beta1(mutationResponse(jsonPatch, err))
+		admissionReviewResp.Response = responseV1ToV1beta1(respond(&mutateRequest))
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	default:
@@ -249,6 +276,35 @@ func mutationResponse(jsonPatch []byte, err error) *admiv1.AdmissionResponse {
 	}
 }
 
+// validationResponse returns the adequate v1.AdmissionResponse based on the validation result.
+func validationResponse(result *ValidationResult, err error) *admiv1.AdmissionResponse {
+	if err != nil {
+		log.Warnf("Failed to validate: %v", err)
+
+		return &admiv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Message: err.Error(),
+			},
+			Allowed: true,
+		}
+	}
+
+	response := &admiv1.AdmissionResponse{
+		Allowed:  result.Allowed,
+		Warnings: result.Warnings,
+	}
+	if !result.Allowed {
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: result.Message,
+			Reason:  metav1.StatusReasonForbidden,
+			Code:    http.StatusForbidden,
+		}
+	}
+
+	return response
+}
+
 // responseV1ToV1beta1 converts a v1.AdmissionResponse into a v1beta1.AdmissionResponse.
 func responseV1ToV1beta1(resp *admiv1.AdmissionResponse) *admiv1beta1.AdmissionResponse {
 	var patchType *admiv1beta1.PatchType
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/api/v1/externalmetrics"
 	languagedetection "github.com/DataDog/datadog-agent/cmd/cluster-agent/api/v1/languagedetection"
 
 	"github.com/cihub/seelog"
@@ -71,6 +72,9 @@ func StartServer(ctx context.Context, w workloadmeta.Component, taggerComp tagge
 	// API V1 Language Detection APIs
 	languagedetection.InstallLanguageDetectionEndpoints(ctx, apiRouter, w, cfg)
 
+	// API V1 Local External Metrics APIs
+	externalmetrics.InstallLocalMetricsEndpoints(apiRouter)
+
 	// Validate token for every request
 	router.Use(validateToken)
 
@@ -189,6 +193,7 @@ func isExternalPath(path string) bool {
 	return strings.HasPrefix(path, "/api/v1/metadata/") && len(strings.Split(path, "/")) == 7 || // support for agents < 6.5.0
 		path == "/version" ||
 		path == "/api/v1/languagedetection" ||
+		path == "/api/v1/externalmetrics/local" ||
 		strings.HasPrefix(path, "/api/v1/tags/pod/") && (len(strings.Split(path, "/")) == 6 || len(strings.Split(path, "/")) == 8) ||
 		strings.HasPrefix(path, "/api/v1/tags/node/") && len(strings.Split(path, "/")) == 6 ||
 		strings.HasPrefix(path, "/api/v1/tags/namespace/") && len(strings.Split(path, "/")) == 6 ||
@@ -0,0 +1,8 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package externalmetrics implements the API handler receiving the metrics
+// forwarded by the node agents to compute external metrics in the cluster.
+package externalmetrics
@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package externalmetrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/api"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local"
+)
+
+const localMetricsHandlerName = "local-external-metrics-handler"
+
+// InstallLocalMetricsEndpoints installs the endpoint receiving the metrics
+// forwarded by the node agents
+func InstallLocalMetricsEndpoints(r *mux.Router) {
+	handler := &localMetricsHandler{store: local.GetStore()}
+	r.HandleFunc("/externalmetrics/local", api.WithTelemetryWrapper(localMetricsHandlerName, api.WithLeaderProxyHandler(
+		localMetricsHandlerName,
+		handler.preHandler,
+		handler.leaderHandler,
+	))).Methods("POST")
+}
+
+type localMetricsHandler struct {
+	store *local.Store
+}
+
+// preHandler is called by both leader and followers and returns true if the
+// request should be forwarded or handled by the leader
+func (h *localMetricsHandler) preHandler(w http.ResponseWriter, r *http.Request) bool {
+	if h.store == nil {
+		http.Error(w, "Local external metrics are disabled on the cluster agent", http.StatusServiceUnavailable)
+		return false
+	}
+
+	if r.Body == nil {
+		http.Error(w, "Request body is empty", http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// leaderHandler is called only by the leader, which resolves the external
+// metrics, and stores the forwarded points
+func (h *localMetricsHandler) leaderHandler(w http.ResponseWriter, r *http.Request) {
+	var payload local.Payload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Failed to decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.store.Add(payload.Series, time.Now())
+	w.WriteHeader(http.StatusOK)
+}
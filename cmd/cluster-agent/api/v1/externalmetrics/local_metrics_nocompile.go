@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !kubeapiserver
+
+package externalmetrics
+
+import (
+	"github.com/gorilla/mux"
+)
+
+// InstallLocalMetricsEndpoints installs the endpoint receiving the metrics
+// forwarded by the node agents
+func InstallLocalMetricsEndpoints(_ *mux.Router) {}
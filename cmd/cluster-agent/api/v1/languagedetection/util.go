@@ -22,11 +22,25 @@ import (
 // containersLanguageWithDirtyFlag encapsulates containers languages along with a dirty flag
 // The dirty flag is used to know if the containers languages are flushed to workload metadata store or not.
 // The dirty flag is reset when languages are flushed to workload metadata store.
+// The runtime versions last reported for the languages are kept apart, as they don't have their own TTL.
 type containersLanguageWithDirtyFlag struct {
 	languages langUtil.TimedContainersLanguages
+	versions  langUtil.ContainersLanguages
 	dirty     bool
 }
 
+// mergeVersions merges runtime versions into the containers languages versions
+// returns true if new versions were added, and false otherwise
+func (c *containersLanguageWithDirtyFlag) mergeVersions(versions langUtil.ContainersLanguages) bool {
+	if len(versions) == 0 {
+		return false
+	}
+	if c.versions == nil {
+		c.versions = make(langUtil.ContainersLanguages)
+	}
+	return c.versions.Merge(versions)
+}
+
 func newContainersLanguageWithDirtyFlag() *containersLanguageWithDirtyFlag {
 	return &containersLanguageWithDirtyFlag{
 		languages: make(langUtil.TimedContainersLanguages),
@@ -85,6 +99,9 @@ func (ownersLanguages *OwnersLanguages) merge(other *OwnersLanguages) {
 		if modified := langsWithDirtyFlag.languages.Merge(containersLanguages.languages); modified {
 			langsWithDirtyFlag.dirty = true
 		}
+		if modified := langsWithDirtyFlag.mergeVersions(containersLanguages.versions); modified {
+			langsWithDirtyFlag.dirty = true
+		}
 	}
 }
 
@@ -102,7 +119,7 @@ func (ownersLanguages *OwnersLanguages) flush(wlm workloadmeta.Component) error
 		}
 
 		// Generate push event
-		if event := generatePushEvent(owner, containersLanguages.languages); event != nil {
+		if event := generatePushEvent(owner, containersLanguages.languages, containersLanguages.versions); event != nil {
 			pushError := wlm.Push(workloadmeta.SourceLanguageDetectionServer, *event)
 			if pushError != nil {
 				pushErrors = append(pushErrors, pushError)
@@ -210,10 +227,10 @@ func (ownersLanguages *OwnersLanguages) cleanRemovedOwners(wlm workloadmeta.Comp
 //                            //
 ////////////////////////////////
 
-// generatePushEvent generates a workloadmeta push event based on the owner languages
+// generatePushEvent generates a workloadmeta push event based on the owner languages and their runtime versions
 // if owner has no detected languages, it generates an unset event
 // else it generates a set event
-func generatePushEvent(owner langUtil.NamespacedOwnerReference, languages langUtil.TimedContainersLanguages) *workloadmeta.Event {
+func generatePushEvent(owner langUtil.NamespacedOwnerReference, languages langUtil.TimedContainersLanguages, versions langUtil.ContainersLanguages) *workloadmeta.Event {
 	_, found := langUtil.SupportedBaseOwners[owner.Kind]
 
 	if !found {
@@ -225,7 +242,7 @@ func generatePushEvent(owner langUtil.NamespacedOwnerReference, languages langUt
 	for container, langsetWithExpiration := range languages {
 		containerLanguages[container] = make(langUtil.LanguageSet)
 		for lang := range langsetWithExpiration {
-			containerLanguages[container][lang] = struct{}{}
+			containerLanguages[container][lang] = versions[container][lang]
 		}
 	}
 
@@ -277,6 +294,33 @@ func getContainersLanguagesFromPodDetail(podDetail *pbgo.PodLanguageDetails, exp
 	return &containersLanguages
 }
 
+// getContainersLanguageVersionsFromPodDetail returns the runtime versions of the languages of both standard
+// containers and init containers. Languages without a version are skipped.
+func getContainersLanguageVersionsFromPodDetail(podDetail *pbgo.PodLanguageDetails) langUtil.ContainersLanguages {
+	versions := make(langUtil.ContainersLanguages)
+
+	addVersions := func(container langUtil.Container, languages []*pbgo.Language) {
+		for _, language := range languages {
+			if language.Version == "" {
+				continue
+			}
+			if _, found := versions[container]; !found {
+				versions[container] = make(langUtil.LanguageSet)
+			}
+			versions[container].AddWithVersion(langUtil.Language(language.Name), language.Version)
+		}
+	}
+
+	for _, containerLanguageDetails := range podDetail.ContainerDetails {
+		addVersions(*langUtil.NewContainer(containerLanguageDetails.ContainerName), containerLanguageDetails.Languages)
+	}
+	for _, containerLanguageDetails := range podDetail.InitContainerDetails {
+		addVersions(*langUtil.NewInitContainer(containerLanguageDetails.ContainerName), containerLanguageDetails.Languages)
+	}
+
+	return versions
+}
+
 // getOwnersLanguages constructs OwnersLanguages from owners (i.e. k8s parent resource)
 func getOwnersLanguages(requestData *pbgo.ParentLanguageAnnotationRequest, expirationTime time.Time) *OwnersLanguages {
 	ownersContainersLanguages := newOwnersLanguages()
@@ -292,6 +336,9 @@ func getOwnersLanguages(requestData *pbgo.ParentLanguageAnnotationRequest, expir
 			if modified := langsWithDirtyFlag.languages.Merge(containersLanguages); modified {
 				langsWithDirtyFlag.dirty = true
 			}
+			if modified := langsWithDirtyFlag.mergeVersions(getContainersLanguageVersionsFromPodDetail(podDetail)); modified {
+				langsWithDirtyFlag.dirty = true
+			}
 		}
 	}
 
@@ -743,6 +743,42 @@ func TestGetContainersLanguagesFromPodDetail(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(containerslanguages, &expectedContainersLanguages), fmt.Sprintf("Expected %v, found %v", &expectedContainersLanguages, containerslanguages))
 }
 
+func TestGetContainersLanguageVersionsFromPodDetail(t *testing.T) {
+	podLanguageDetails := &pbgo.PodLanguageDetails{
+		Namespace: "default",
+		ContainerDetails: []*pbgo.ContainerLanguageDetails{
+			{
+				ContainerName: "app",
+				Languages: []*pbgo.Language{
+					{Name: "java", Version: "17.0.8"},
+					{Name: "python"},
+				},
+			},
+			{
+				ContainerName: "sidecar",
+				Languages: []*pbgo.Language{
+					{Name: "go"},
+				},
+			},
+		},
+		InitContainerDetails: []*pbgo.ContainerLanguageDetails{
+			{
+				ContainerName: "migrations",
+				Languages: []*pbgo.Language{
+					{Name: "ruby", Version: "3.2.2"},
+				},
+			},
+		},
+	}
+
+	expectedVersions := langUtil.ContainersLanguages{
+		*langUtil.NewContainer("app"):            {"java": {Version: "17.0.8"}},
+		*langUtil.NewInitContainer("migrations"): {"ruby": {Version: "3.2.2"}},
+	}
+
+	assert.Equal(t, expectedVersions, getContainersLanguageVersionsFromPodDetail(podLanguageDetails))
+}
+
 func TestGetOwnersLanguages(t *testing.T) {
 	mockExpiration := time.Now()
 
@@ -907,6 +943,7 @@ func TestGeneratePushEvent(t *testing.T) {
 	tests := []struct {
 		name          string
 		languages     langUtil.TimedContainersLanguages
+		versions      langUtil.ContainersLanguages
 		owner         langUtil.NamespacedOwnerReference
 		expectedEvent *workloadmeta.Event
 	}{
@@ -980,11 +1017,46 @@ func TestGeneratePushEvent(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "containers languages with versions",
+			languages: langUtil.TimedContainersLanguages{
+				langUtil.Container{Name: "container-1", Init: false}: {
+					"java":   mockExpiration,
+					"python": mockExpiration,
+				},
+			},
+			versions: langUtil.ContainersLanguages{
+				langUtil.Container{Name: "container-1", Init: false}: {
+					"java": {Version: "17.0.8"},
+					// versions of languages that are no longer detected are ignored
+					"ruby": {Version: "3.2.2"},
+				},
+				langUtil.Container{Name: "container-2", Init: false}: {
+					"go": {Version: "go1.21.3"},
+				},
+			},
+			owner: mockSupportedOwner,
+			expectedEvent: &workloadmeta.Event{
+				Type: workloadmeta.EventTypeSet,
+				Entity: &workloadmeta.KubernetesDeployment{
+					EntityID: workloadmeta.EntityID{
+						Kind: workloadmeta.KindKubernetesDeployment,
+						ID:   "some-ns/some-name",
+					},
+					DetectedLanguages: langUtil.ContainersLanguages{
+						langUtil.Container{Name: "container-1", Init: false}: {
+							"java":   {Version: "17.0.8"},
+							"python": {},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(tt *testing.T) {
-			actualEvent := generatePushEvent(test.owner, test.languages)
+			actualEvent := generatePushEvent(test.owner, test.languages, test.versions)
 
 			if actualEvent == nil && test.expectedEvent == nil {
 				return
@@ -62,6 +62,11 @@ func (a *AppService) Init() error {
 	return nil
 }
 
+// IsBatch returns false as apps serve requests until they are stopped
+func (a *AppService) IsBatch() bool {
+	return false
+}
+
 func isAppService() bool {
 	_, exists := os.LookupEnv(RunZip)
 	return exists
@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cloudservice
+
+import (
+	"os"
+
+	"github.com/DataDog/datadog-agent/cmd/serverless-init/cloudservice/helper"
+)
+
+const (
+	//nolint:revive // TODO(SERV) Fix revive linter
+	FunctionTargetEnvVar        = "FUNCTION_TARGET"
+	functionSignatureTypeEnvVar = "FUNCTION_SIGNATURE_TYPE"
+)
+
+// CloudFunction has helper functions for getting Google Cloud Functions (2nd gen) data.
+// 2nd gen functions are deployed as Cloud Run services, and are told apart by the
+// environment variables set by the functions framework.
+type CloudFunction struct{}
+
+// GetTags returns a map of gcp-related tags.
+func (c *CloudFunction) GetTags() map[string]string {
+	tags := metadataHelperFunc(helper.GetDefaultConfig()).TagMap()
+
+	for envVar, tagName := range map[string]string{
+		ServiceNameEnvVar:           "function_name",
+		revisionNameEnvVar:          "revision_name",
+		FunctionTargetEnvVar:        "function_target",
+		functionSignatureTypeEnvVar: "function_signature_type",
+	} {
+		if value := os.Getenv(envVar); value != "" {
+			tags[tagName] = value
+		}
+	}
+
+	tags["origin"] = c.GetOrigin()
+	tags["_dd.origin"] = c.GetOrigin()
+
+	return tags
+}
+
+// GetOrigin returns the `origin` attribute type for the given
+// cloud service.
+func (c *CloudFunction) GetOrigin() string {
+	return "cloudfunctions"
+}
+
+// GetPrefix returns the prefix that we're prefixing all
+// metrics with.
+func (c *CloudFunction) GetPrefix() string {
+	return "gcp.cloudfunctions"
+}
+
+// Init is empty for CloudFunction
+func (c *CloudFunction) Init() error {
+	return nil
+}
+
+// IsBatch returns false as functions serve requests until they are stopped
+func (c *CloudFunction) IsBatch() bool {
+	return false
+}
+
+func isCloudFunction() bool {
+	_, isService := os.LookupEnv(ServiceNameEnvVar)
+	_, hasTarget := os.LookupEnv(FunctionTargetEnvVar)
+	return isService && hasTarget
+}
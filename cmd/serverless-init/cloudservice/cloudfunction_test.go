@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cloudservice
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/cmd/serverless-init/cloudservice/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCloudFunctionTags(t *testing.T) {
+	service := &CloudFunction{}
+
+	metadataHelperFunc = func(*helper.GCPConfig) *helper.GCPMetadata {
+		return &helper.GCPMetadata{
+			ContainerID: &helper.Info{
+				TagName: "container_id",
+				Value:   "test_container",
+			},
+			Region: &helper.Info{
+				TagName: "region",
+				Value:   "test_region",
+			},
+			ProjectID: &helper.Info{
+				TagName: "project_id",
+				Value:   "test_project",
+			},
+		}
+	}
+
+	t.Setenv("K_SERVICE", "test_function")
+	t.Setenv("K_REVISION", "test_function-00001-abc")
+	t.Setenv("FUNCTION_TARGET", "HelloHTTP")
+	t.Setenv("FUNCTION_SIGNATURE_TYPE", "http")
+
+	tags := service.GetTags()
+
+	assert.Equal(t, map[string]string{
+		"container_id":            "test_container",
+		"region":                  "test_region",
+		"origin":                  "cloudfunctions",
+		"project_id":              "test_project",
+		"function_name":           "test_function",
+		"revision_name":           "test_function-00001-abc",
+		"function_target":         "HelloHTTP",
+		"function_signature_type": "http",
+		"_dd.origin":              "cloudfunctions",
+	}, tags)
+	assert.False(t, service.IsBatch())
+}
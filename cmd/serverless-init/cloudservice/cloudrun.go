@@ -60,6 +60,11 @@ func (c *CloudRun) Init() error {
 	return nil
 }
 
+// IsBatch returns false as services serve requests until they are stopped
+func (c *CloudRun) IsBatch() bool {
+	return false
+}
+
 func isCloudRunService() bool {
 	_, exists := os.LookupEnv(ServiceNameEnvVar)
 	return exists
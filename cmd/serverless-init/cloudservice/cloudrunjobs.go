@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cloudservice
+
+import (
+	"os"
+
+	"github.com/DataDog/datadog-agent/cmd/serverless-init/cloudservice/helper"
+)
+
+const (
+	//nolint:revive // TODO(SERV) Fix revive linter
+	JobNameEnvVar       = "CLOUD_RUN_JOB"
+	executionNameEnvVar = "CLOUD_RUN_EXECUTION"
+	taskIndexEnvVar     = "CLOUD_RUN_TASK_INDEX"
+	taskAttemptEnvVar   = "CLOUD_RUN_TASK_ATTEMPT"
+	taskCountEnvVar     = "CLOUD_RUN_TASK_COUNT"
+)
+
+// CloudRunJobs has helper functions for getting Google Cloud Run Jobs data.
+// Each task of a job execution runs its container to completion, so it is
+// handled as a batch-style workload.
+type CloudRunJobs struct{}
+
+// GetTags returns a map of gcp-related tags, including the job execution and
+// the index and attempt of the task.
+func (c *CloudRunJobs) GetTags() map[string]string {
+	tags := metadataHelperFunc(helper.GetDefaultConfig()).TagMap()
+
+	for envVar, tagName := range map[string]string{
+		JobNameEnvVar:       "job_name",
+		executionNameEnvVar: "execution_name",
+		taskIndexEnvVar:     "task_index",
+		taskAttemptEnvVar:   "task_attempt",
+		taskCountEnvVar:     "task_count",
+	} {
+		if value := os.Getenv(envVar); value != "" {
+			tags[tagName] = value
+		}
+	}
+
+	tags["origin"] = c.GetOrigin()
+	tags["_dd.origin"] = c.GetOrigin()
+
+	return tags
+}
+
+// GetOrigin returns the `origin` attribute type for the given
+// cloud service.
+func (c *CloudRunJobs) GetOrigin() string {
+	return "cloudrunjobs"
+}
+
+// GetPrefix returns the prefix that we're prefixing all
+// metrics with.
+func (c *CloudRunJobs) GetPrefix() string {
+	return "gcp.run.job"
+}
+
+// Init is empty for CloudRunJobs
+func (c *CloudRunJobs) Init() error {
+	return nil
+}
+
+// IsBatch returns true as a task exits once its work is done
+func (c *CloudRunJobs) IsBatch() bool {
+	return true
+}
+
+func isCloudRunJob() bool {
+	_, exists := os.LookupEnv(JobNameEnvVar)
+	return exists
+}
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cloudservice
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/cmd/serverless-init/cloudservice/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCloudRunJobsTags(t *testing.T) {
+	service := &CloudRunJobs{}
+
+	metadataHelperFunc = func(*helper.GCPConfig) *helper.GCPMetadata {
+		return &helper.GCPMetadata{
+			ContainerID: &helper.Info{
+				TagName: "container_id",
+				Value:   "test_container",
+			},
+			Region: &helper.Info{
+				TagName: "region",
+				Value:   "test_region",
+			},
+			ProjectID: &helper.Info{
+				TagName: "project_id",
+				Value:   "test_project",
+			},
+		}
+	}
+
+	t.Setenv("CLOUD_RUN_JOB", "test_job")
+	t.Setenv("CLOUD_RUN_EXECUTION", "test_job-x8kzq")
+	t.Setenv("CLOUD_RUN_TASK_INDEX", "2")
+	t.Setenv("CLOUD_RUN_TASK_ATTEMPT", "1")
+	t.Setenv("CLOUD_RUN_TASK_COUNT", "5")
+
+	tags := service.GetTags()
+
+	assert.Equal(t, map[string]string{
+		"container_id":   "test_container",
+		"region":         "test_region",
+		"origin":         "cloudrunjobs",
+		"project_id":     "test_project",
+		"job_name":       "test_job",
+		"execution_name": "test_job-x8kzq",
+		"task_index":     "2",
+		"task_attempt":   "1",
+		"task_count":     "5",
+		"_dd.origin":     "cloudrunjobs",
+	}, tags)
+	assert.True(t, service.IsBatch())
+}
@@ -113,6 +113,11 @@ func (c *ContainerApp) Init() error {
 	return nil
 }
 
+// IsBatch returns false as container apps serve requests until they are stopped
+func (c *ContainerApp) IsBatch() bool {
+	return false
+}
+
 func isContainerAppService() bool {
 	_, exists := os.LookupEnv(ContainerAppNameEnvVar)
 	return exists
@@ -23,6 +23,12 @@ type CloudService interface {
 
 	// Init bootstraps the CloudService.
 	Init() error
+
+	// IsBatch returns true for batch-style workloads, which run to
+	// completion instead of serving requests until they are stopped.
+	// Everything must be flushed before their process exits, and the
+	// exit code of the process reports the outcome to the platform.
+	IsBatch() bool
 }
 
 //nolint:revive // TODO(SERV) Fix revive linter
@@ -48,8 +54,22 @@ func (l *LocalService) Init() error {
 	return nil
 }
 
+// IsBatch is a default implementation that returns false
+func (l *LocalService) IsBatch() bool {
+	return false
+}
+
 //nolint:revive // TODO(SERV) Fix revive linter
 func GetCloudServiceType() CloudService {
+	if isCloudRunJob() {
+		return &CloudRunJobs{}
+	}
+
+	// 2nd gen functions also set the Cloud Run service environment variables
+	if isCloudFunction() {
+		return &CloudFunction{}
+	}
+
 	if isCloudRunService() {
 		return &CloudRun{}
 	}
@@ -21,8 +21,16 @@ func TestGetCloudServiceType(t *testing.T) {
 	t.Setenv(ServiceNameEnvVar, "test-name")
 	assert.Equal(t, "cloudrun", GetCloudServiceType().GetOrigin())
 
-	os.Unsetenv(ContainerAppNameEnvVar)
+	t.Setenv(FunctionTargetEnvVar, "test-target")
+	assert.Equal(t, "cloudfunctions", GetCloudServiceType().GetOrigin())
+
 	os.Unsetenv(ServiceNameEnvVar)
+	os.Unsetenv(FunctionTargetEnvVar)
+	t.Setenv(JobNameEnvVar, "test-name")
+	assert.Equal(t, "cloudrunjobs", GetCloudServiceType().GetOrigin())
+
+	os.Unsetenv(ContainerAppNameEnvVar)
+	os.Unsetenv(JobNameEnvVar)
 	t.Setenv(RunZip, "false")
 	assert.Equal(t, "appservice", GetCloudServiceType().GetOrigin())
 }
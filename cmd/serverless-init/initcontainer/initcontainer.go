@@ -10,6 +10,7 @@ package initcontainer
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
@@ -33,7 +34,17 @@ import (
 	"github.com/spf13/afero"
 )
 
-// Run is the entrypoint of the init process. It will spawn the customer process
+const (
+	// batchFlushTimeout is the time given to batch-style workloads to flush
+	// everything before exiting, as nothing is flushed after that
+	batchFlushTimeout = 30 * time.Second
+	// sketchesBucketInterval is the size of the buckets distributions are aggregated in
+	sketchesBucketInterval = 10 * time.Second
+)
+
+// Run is the entrypoint of the init process. It will spawn the customer process.
+// It returns the exit code of the init process, which is the exit code of the
+// customer process for batch-style workloads, and 0 otherwise.
 func Run(
 	cloudService cloudservice.CloudService,
 	logConfig *serverlessLog.Config,
@@ -41,14 +52,26 @@ func Run(
 	traceAgent *trace.ServerlessTraceAgent,
 	logsAgent logsAgent.ServerlessLogsAgent,
 	args []string,
-) {
+) int {
 	log.Debugf("Launching subprocess %v\n", args)
+	startTime := time.Now()
 	err := execute(logConfig, args)
 	if err != nil {
 		log.Debugf("Error exiting: %v\n", err)
 	}
-	metric.AddShutdownMetric(cloudService.GetPrefix(), metricAgent.GetExtraTags(), time.Now(), metricAgent.Demux)
-	flush(logConfig.FlushTimeout, metricAgent, traceAgent, logsAgent)
+	endTime := time.Now()
+	metric.AddShutdownMetric(cloudService.GetPrefix(), metricAgent.GetExtraTags(), endTime, metricAgent.Demux)
+
+	if !cloudService.IsBatch() {
+		flush(logConfig.FlushTimeout, metricAgent, traceAgent, logsAgent)
+		return 0
+	}
+
+	exitCode := exitCodeFromError(err)
+	metric.AddTaskEndedMetric(cloudService.GetPrefix(), metricAgent.GetExtraTags(), endTime, exitCode, metricAgent.Demux)
+	metric.AddTaskDurationMetric(cloudService.GetPrefix(), metricAgent.GetExtraTags(), startTime, endTime, metricAgent.Demux)
+	flushBatch(logConfig, &openBucketsFlusher{agent: metricAgent}, traceAgent, logsAgent)
+	return exitCode
 }
 
 func execute(logConfig *serverlessLog.Config, args []string) error {
@@ -64,8 +87,13 @@ func execute(logConfig *serverlessLog.Config, args []string) error {
 	cmd.Stderr = io.Writer(os.Stderr)
 
 	if logConfig.IsEnabled {
-		cmd.Stdout = io.MultiWriter(os.Stdout, serverlessLog.NewChannelWriter(logConfig.Channel, false))
-		cmd.Stderr = io.MultiWriter(os.Stderr, serverlessLog.NewChannelWriter(logConfig.Channel, true))
+		stdoutWriter := serverlessLog.NewChannelWriter(logConfig.Channel, false)
+		stderrWriter := serverlessLog.NewChannelWriter(logConfig.Channel, true)
+		cmd.Stdout = io.MultiWriter(os.Stdout, stdoutWriter)
+		cmd.Stderr = io.MultiWriter(os.Stderr, stderrWriter)
+		// the last lines of the process may not be newline-terminated
+		defer stdoutWriter.Flush()
+		defer stderrWriter.Flush()
 	}
 
 	err := cmd.Start()
@@ -79,6 +107,23 @@ func execute(logConfig *serverlessLog.Config, args []string) error {
 	return err
 }
 
+// exitCodeFromError returns the exit code of a process from the error returned when waiting for it.
+// As shells do, a process killed by a signal exits with 128 + the signal number.
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		// the process could not be started
+		return 1
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return exitErr.ExitCode()
+}
+
 func buildCommandParam(cmdArg []string) (string, []string) {
 	fields := cmdArg
 	if len(cmdArg) == 1 {
@@ -144,3 +189,41 @@ func flushAndWait(flushTimeout time.Duration, wg *sync.WaitGroup, agent serverle
 	}
 	wg.Done()
 }
+
+// openBucketsFlusher flushes the metric agent, including the distribution bucket
+// that is still open and would otherwise only be flushed by a later flush
+type openBucketsFlusher struct {
+	agent *metrics.ServerlessMetricAgent
+}
+
+func (f *openBucketsFlusher) Flush() {
+	if f.agent.IsReady() {
+		f.agent.Flush()
+		f.agent.Demux.ForceFlushToSerializer(time.Now().Add(sketchesBucketInterval), true)
+	}
+}
+
+// flushBatch flushes everything before the process of a batch-style workload exits.
+// As there is no later flush to catch up, it waits for the logs still in the channel
+// to be consumed, and gives more time to the agents to flush.
+func flushBatch(logConfig *serverlessLog.Config, metricAgent serverless.FlushableAgent, traceAgent serverless.FlushableAgent, logsAgent logsAgent.ServerlessLogsAgent) {
+	if logConfig.IsEnabled && logsAgent != nil && !waitForLogs(logConfig, batchFlushTimeout) {
+		log.Error("timed out while waiting for the logs to be processed")
+	}
+	if flush(batchFlushTimeout, metricAgent, traceAgent, logsAgent) {
+		log.Error("timed out while flushing, some data may not have been sent")
+	}
+}
+
+// waitForLogs waits until all the logs of the channel have been consumed by the
+// logs agent, and returns false if they were not consumed in time
+func waitForLogs(logConfig *serverlessLog.Config, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for len(logConfig.Channel) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
@@ -19,7 +19,9 @@ import (
 	serverlessLog "github.com/DataDog/datadog-agent/cmd/serverless-init/log"
 
 	logsAgent "github.com/DataDog/datadog-agent/comp/logs/agent"
+	logConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
 )
 
 func TestBuildCommandParamWithArgs(t *testing.T) {
@@ -86,6 +88,61 @@ func TestPropagateChildError(t *testing.T) {
 	})
 }
 
+func TestExitCodeFromError(t *testing.T) {
+	runTestOnLinuxOnly(t, func(t *testing.T) {
+		assert.Equal(t, 0, exitCodeFromError(execute(&serverlessLog.Config{}, []string{"bash", "-c", "exit 0"})))
+		assert.Equal(t, 123, exitCodeFromError(execute(&serverlessLog.Config{}, []string{"bash", "-c", "exit 123"})))
+		assert.Equal(t, 128+int(syscall.SIGTERM), exitCodeFromError(execute(&serverlessLog.Config{}, []string{"bash", "-c", "kill -TERM $$"})))
+		assert.Equal(t, 1, exitCodeFromError(execute(&serverlessLog.Config{}, []string{"/does/not/exist"})))
+	})
+}
+
+func TestExecuteSendsLastLine(t *testing.T) {
+	runTestOnLinuxOnly(t, func(t *testing.T) {
+		conf := &serverlessLog.Config{
+			Channel:   make(chan *logConfig.ChannelMessage, 10),
+			IsEnabled: true,
+		}
+		err := execute(conf, []string{"bash", "-c", "printf 'first\\nlast'"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(conf.Channel))
+		assert.Equal(t, "first", string((<-conf.Channel).Content))
+		assert.Equal(t, "last", string((<-conf.Channel).Content))
+	})
+}
+
+func TestFlushBatchWaitsForLogs(t *testing.T) {
+	metricAgent := &TestFlushableAgent{}
+	traceAgent := &TestFlushableAgent{}
+	mockLogsAgent := logsAgent.NewMockServerlessLogsAgent()
+	conf := &serverlessLog.Config{
+		Channel:   make(chan *logConfig.ChannelMessage, 10),
+		IsEnabled: true,
+	}
+	conf.Channel <- &logConfig.ChannelMessage{Content: []byte("log")}
+	consumed := atomic.NewBool(false)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-conf.Channel
+		consumed.Store(true)
+	}()
+
+	flushBatch(conf, metricAgent, traceAgent, mockLogsAgent)
+	assert.True(t, consumed.Load())
+	assert.True(t, metricAgent.hasBeenCalled)
+	assert.True(t, traceAgent.hasBeenCalled)
+	assert.True(t, mockLogsAgent.DidFlush())
+}
+
+func TestWaitForLogsTimeout(t *testing.T) {
+	conf := &serverlessLog.Config{
+		Channel: make(chan *logConfig.ChannelMessage, 10),
+	}
+	assert.True(t, waitForLogs(conf, 10*time.Millisecond))
+	conf.Channel <- &logConfig.ChannelMessage{Content: []byte("log")}
+	assert.False(t, waitForLogs(conf, 10*time.Millisecond))
+}
+
 func TestForwardSignalToChild(t *testing.T) {
 	runTestOnLinuxOnly(t, func(t *testing.T) {
 		resultChan := make(chan error)
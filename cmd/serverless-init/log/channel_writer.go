@@ -55,20 +55,32 @@ func (cw *ChannelWriter) Write(p []byte) (n int, err error) {
 			continue
 		}
 
-		channelMessage := &logConfig.ChannelMessage{
-			Content: []byte(line[:len(line)-1]),
-			IsError: cw.IsError,
-		}
+		cw.send(line[:len(line)-1])
+	}
+	return n, nil
+}
 
-		select {
-		case cw.Channel <- channelMessage:
-			// Success case -- the channel isn't full, and can accommodate our message
-		default:
-			// Channel is full (i.e, we aren't flushing data to Datadog as our backend is down).
-			// message will be dropped.
-			log.Debug("Log dropped due to full buffer")
-		}
+// Flush sends the last line left in the buffer to the channel, even though it is
+// not newline-terminated. It must be called once the writer has been closed.
+func (cw *ChannelWriter) Flush() {
+	if cw.Buffer.Len() > 0 {
+		cw.send(cw.Buffer.String())
+		cw.Buffer.Reset()
+	}
+}
 
+func (cw *ChannelWriter) send(line string) {
+	channelMessage := &logConfig.ChannelMessage{
+		Content: []byte(line),
+		IsError: cw.IsError,
+	}
+
+	select {
+	case cw.Channel <- channelMessage:
+		// Success case -- the channel isn't full, and can accommodate our message
+	default:
+		// Channel is full (i.e, we aren't flushing data to Datadog as our backend is down).
+		// message will be dropped.
+		log.Debug("Log dropped due to full buffer")
 	}
-	return n, nil
 }
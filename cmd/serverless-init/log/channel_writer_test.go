@@ -74,3 +74,31 @@ func TestChannelWriter_Write(t *testing.T) {
 		t.Fatalf("Expected message content 'partial data', but got '%s'", msg.Content)
 	}
 }
+
+func TestChannelWriter_Flush(t *testing.T) {
+	ch := make(chan *logConfig.ChannelMessage, 10)
+	cw := NewChannelWriter(ch, true)
+
+	cw.Flush()
+	if len(ch) != 0 {
+		t.Fatalf("Expected channel to be empty, but it has %d messages", len(ch))
+	}
+
+	_, err := cw.Write([]byte("line1\npartial"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-ch
+
+	cw.Flush()
+	if len(ch) != 1 {
+		t.Fatalf("Expected channel to have 1 message, but it has %d", len(ch))
+	}
+	msg := <-ch
+	if string(msg.Content) != "partial" || !msg.IsError {
+		t.Fatalf("Expected error message content 'partial' but got '%s'", msg.Content)
+	}
+	if cw.Buffer.Len() != 0 {
+		t.Fatalf("Expected buffer to be empty after flush")
+	}
+}
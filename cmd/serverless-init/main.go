@@ -40,6 +40,8 @@ const (
 
 type cliParams struct {
 	args []string
+	// exitCode is the exit code of the init process, set once the customer process exited
+	exitCode int
 }
 
 func main() {
@@ -56,11 +58,15 @@ func main() {
 	if err != nil {
 		logger.Error(err)
 	}
+
+	if cliParams.exitCode != 0 {
+		os.Exit(cliParams.exitCode)
+	}
 }
 
 func run(cliParams *cliParams) {
 	cloudService, logConfig, traceAgent, metricAgent, logsAgent := setup()
-	initcontainer.Run(cloudService, logConfig, metricAgent, traceAgent, logsAgent, cliParams.args)
+	cliParams.exitCode = initcontainer.Run(cloudService, logConfig, metricAgent, traceAgent, logsAgent, cliParams.args)
 }
 
 func setup() (cloudservice.CloudService, *log.Config, *trace.ServerlessTraceAgent, *metrics.ServerlessMetricAgent, logsAgent.ServerlessLogsAgent) {
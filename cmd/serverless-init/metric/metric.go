@@ -8,6 +8,7 @@ package metric
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
@@ -28,11 +29,31 @@ func AddShutdownMetric(metricPrefix string, tags []string, timestamp time.Time,
 	add(fmt.Sprintf("%v.enhanced.shutdown", metricPrefix), tags, time.Now(), demux)
 }
 
+// AddTaskEndedMetric adds the metric reporting the end of a batch task, tagged with
+// the exit code of the task, to the demultiplexer
+func AddTaskEndedMetric(metricPrefix string, tags []string, timestamp time.Time, exitCode int, demux aggregator.Demultiplexer) {
+	taskTags := append([]string{
+		"exit_code:" + strconv.Itoa(exitCode),
+		"succeeded:" + strconv.FormatBool(exitCode == 0),
+	}, tags...)
+	add(fmt.Sprintf("%v.enhanced.task.ended", metricPrefix), taskTags, timestamp, demux)
+}
+
+// AddTaskDurationMetric adds the metric reporting how long a batch task ran, in seconds,
+// to the demultiplexer
+func AddTaskDurationMetric(metricPrefix string, tags []string, startTime time.Time, endTime time.Time, demux aggregator.Demultiplexer) {
+	addWithValue(fmt.Sprintf("%v.enhanced.task.duration", metricPrefix), endTime.Sub(startTime).Seconds(), tags, endTime, demux)
+}
+
 func add(name string, tags []string, timestamp time.Time, demux aggregator.Demultiplexer) {
+	addWithValue(name, 1.0, tags, timestamp, demux)
+}
+
+func addWithValue(name string, value float64, tags []string, timestamp time.Time, demux aggregator.Demultiplexer) {
 	metricTimestamp := float64(timestamp.UnixNano()) / float64(time.Second)
 	demux.AggregateSample(metrics.MetricSample{
 		Name:       name,
-		Value:      1.0,
+		Value:      value,
 		Mtype:      metrics.DistributionType,
 		Tags:       tags,
 		SampleRate: 1,
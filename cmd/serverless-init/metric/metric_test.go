@@ -62,6 +62,32 @@ func TestAddShutdownMetric(t *testing.T) {
 	assert.Equal(t, metric.Tags[1], "tagb:valueb")
 }
 
+func TestAddTaskEndedMetric(t *testing.T) {
+	demux := createDemultiplexer(t)
+	timestamp := time.Now()
+	AddTaskEndedMetric("gcp.run.job", []string{"taga:valuea"}, timestamp, 3, demux)
+	generatedMetrics, timedMetrics := demux.WaitForSamples(100 * time.Millisecond)
+	assert.Equal(t, 0, len(timedMetrics))
+	assert.Equal(t, 1, len(generatedMetrics))
+	metric := generatedMetrics[0]
+	assert.Equal(t, metric.Name, "gcp.run.job.enhanced.task.ended")
+	assert.Equal(t, float64(timestamp.UnixNano())/float64(time.Second), metric.Timestamp)
+	assert.Equal(t, []string{"exit_code:3", "succeeded:false", "taga:valuea"}, metric.Tags)
+}
+
+func TestAddTaskDurationMetric(t *testing.T) {
+	demux := createDemultiplexer(t)
+	endTime := time.Now()
+	AddTaskDurationMetric("gcp.run.job", []string{"taga:valuea"}, endTime.Add(-90*time.Second), endTime, demux)
+	generatedMetrics, timedMetrics := demux.WaitForSamples(100 * time.Millisecond)
+	assert.Equal(t, 0, len(timedMetrics))
+	assert.Equal(t, 1, len(generatedMetrics))
+	metric := generatedMetrics[0]
+	assert.Equal(t, metric.Name, "gcp.run.job.enhanced.task.duration")
+	assert.Equal(t, 90.0, metric.Value)
+	assert.Equal(t, []string{"taga:valuea"}, metric.Tags)
+}
+
 func createDemultiplexer(t *testing.T) demultiplexer.FakeSamplerMock {
 	return fxutil.Test[demultiplexer.FakeSamplerMock](t, logimpl.MockModule(), compressionimpl.MockModule(), demultiplexerimpl.FakeSamplerMockModule(), hostnameimpl.MockModule())
 }
@@ -448,6 +448,7 @@ func TestDecryptConfig(t *testing.T) {
 		Name:          "cpu",
 		ADIdentifiers: []string{"redis"},
 		InitConfig:    []byte("param1: ENC[foo]"),
+		Source:        "file:/etc/datadog-agent/conf.d/cpu.d/conf.yaml",
 	}
 	changes := ac.processNewConfig(tpl)
 
@@ -461,6 +462,7 @@ func TestDecryptConfig(t *testing.T) {
 		MetricConfig:  integration.Data{},
 		LogsConfig:    integration.Data{},
 		ServiceID:     "abcd",
+		Source:        "file:/etc/datadog-agent/conf.d/cpu.d/conf.yaml",
 	}
 	assert.Equal(t, resolved, changes.Schedule[0])
 
@@ -494,6 +496,8 @@ func TestProcessClusterCheckConfigWithSecrets(t *testing.T) {
 		Instances:    []integration.Data{integration.Data("foo: ENC[bar]")},
 		MetricConfig: integration.Data{},
 		LogsConfig:   integration.Data{},
+		// the cluster checks keep the source of their template
+		Source: "file:/etc/datadog-agent/conf.d/testConfig.yaml",
 	}
 	changes := ac.processNewConfig(tpl)
 
@@ -506,6 +510,7 @@ func TestProcessClusterCheckConfigWithSecrets(t *testing.T) {
 		Instances:    []integration.Data{integration.Data("foo: barDecoded")},
 		MetricConfig: integration.Data{},
 		LogsConfig:   integration.Data{},
+		Source:       "file:/etc/datadog-agent/conf.d/testConfig.yaml",
 	}
 	assert.Equal(t, resolved, changes.Schedule[0])
 
@@ -14,6 +14,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/selector"
 	"github.com/DataDog/datadog-agent/comp/core/secrets"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -88,6 +89,10 @@ type reconcilingConfigManager struct {
 	// identifiers.  It is an index to activeServices.
 	servicesByADID multimap
 
+	// templateSelectors contains the parsed selectors of the templates
+	// having one, keyed by template digest.
+	templateSelectors map[string]*selector.Selector
+
 	// serviceResolutions maps a serviceID to the resolutions performed for
 	// that service: serviceID -> template digest -> resolved config digest.
 	serviceResolutions map[string]map[string]string
@@ -110,6 +115,7 @@ func newReconcilingConfigManager(secretResolver secrets.Component) configManager
 		activeServices:     map[string]serviceAndADIDs{},
 		templatesByADID:    newMultimap(),
 		servicesByADID:     newMultimap(),
+		templateSelectors:  map[string]*selector.Selector{},
 		serviceResolutions: map[string]map[string]string{},
 		scheduledConfigs:   map[string]integration.Config{},
 		secretResolver:     secretResolver,
@@ -196,6 +202,16 @@ func (cm *reconcilingConfigManager) processNewConfig(config integration.Config)
 
 	var changes integration.ConfigChanges
 	if config.IsTemplate() {
+		if config.ADSelector != "" {
+			sel, err := selector.Parse(config.ADSelector)
+			if err != nil {
+				// the template is tracked, but never resolved
+				log.Errorf("Ignoring template %s from %s: %s", config.Name, config.Source, err)
+				return integration.ConfigChanges{}, changedIDsOfSecretsWithConfigs
+			}
+			cm.templateSelectors[digest] = sel
+		}
+
 		//  2. update templatesByADID or servicesByADID to match
 		matchingServices := map[string]struct{}{}
 		for _, adID := range config.ADIdentifiers {
@@ -254,6 +270,8 @@ func (cm *reconcilingConfigManager) processDelConfigs(configs []integration.Conf
 
 		var changes integration.ConfigChanges
 		if config.IsTemplate() {
+			delete(cm.templateSelectors, digest)
+
 			//  2. update templatesByADID or servicesByADID to match
 			matchingServices := map[string]struct{}{}
 			for _, adID := range config.ADIdentifiers {
@@ -292,6 +310,24 @@ func (cm *reconcilingConfigManager) mapOverLoadedConfigs(f func(map[string]integ
 	f(cm.scheduledConfigs)
 }
 
+// filterTemplatesBySelector drops the templates whose selector does not match
+// the given service.
+//
+// This method must be called with cm.m locked.
+func (cm *reconcilingConfigManager) filterTemplatesBySelector(svc listeners.Service, templates map[string]integration.Config) {
+	selectorSvc, hasAttributes := svc.(listeners.SelectorService)
+	for digest, tpl := range templates {
+		sel, found := cm.templateSelectors[digest]
+		if !found {
+			continue
+		}
+		if !hasAttributes || !sel.Matches(selectorSvc.GetSelectorAttributes()) {
+			log.Debugf("Template %s does not apply to service %s: selector %q does not match", tpl.Name, svc.GetServiceID(), sel)
+			delete(templates, digest)
+		}
+	}
+}
+
 // reconcileService calculates the current set of resolved templates for the
 // given service and calculates the difference from what is currently recorded
 // in cm.serviceResolutions.  It updates cm.serviceResolutions and returns the
@@ -327,6 +363,7 @@ func (cm *reconcilingConfigManager) reconcileService(svcID string) integration.C
 	// allow the service to filter those templates, unless we are removing
 	// the service, in which case no resolutions are expected.
 	if svc != nil {
+		cm.filterTemplatesBySelector(svc, expectedResolutions)
 		svc.FilterTemplates(expectedResolutions)
 	}
 
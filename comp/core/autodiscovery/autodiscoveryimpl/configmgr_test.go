@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/selector"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
 	"github.com/DataDog/datadog-agent/pkg/util/testutil"
 )
@@ -103,11 +104,12 @@ func matchSvc(serviceID string) func(integration.Config) bool {
 
 var (
 	nonTemplateConfig             = integration.Config{Name: "non-template"}
-	nonTemplateConfigWithSecrets  = integration.Config{Name: "non-template-with-secrets", Instances: []integration.Data{integration.Data("foo: ENC[bar]")}}
+	nonTemplateConfigWithSecrets  = integration.Config{Name: "non-template-with-secrets", Instances: []integration.Data{integration.Data("foo: ENC[bar]")}, Source: "file:/etc/datadog-agent/conf.d/non-template.yaml"}
 	clusterCheckConfigWithSecrets = integration.Config{
 		Provider:  names.ClusterChecks,
 		Name:      "non-template-with-secrets-cluster-check",
 		Instances: []integration.Data{integration.Data("foo: ENC[bar]")},
+		Source:    "file:/etc/datadog-agent/conf.d/cluster-check.yaml",
 	}
 	templateConfig = integration.Config{Name: "template", LogsConfig: []byte("source: %%host%%"), ADIdentifiers: []string{"my-service"}}
 	myService      = &dummyService{ID: "my-service", ADIdentifiers: []string{"my-service"}, Hosts: map[string]string{"main": "myhost"}}
@@ -539,6 +541,64 @@ func (suite *ReconcilingConfigManagerSuite) TestServiceTemplateFiltering() {
 	)
 }
 
+// selectorService is a dummyService exposing selector attributes
+type selectorService struct {
+	dummyService
+	attributes selector.Attributes
+}
+
+func (s *selectorService) GetSelectorAttributes() selector.Attributes {
+	return s.attributes
+}
+
+// A template's selector determines which services it is resolved for.
+func (suite *ReconcilingConfigManagerSuite) TestServiceTemplateSelector() {
+	prodSvc := &selectorService{
+		dummyService: dummyService{ID: "prod", ADIdentifiers: []string{"redis"}},
+		attributes:   selector.Attributes{Image: "redis", Namespace: "prod", PodLabels: map[string]string{"tier": "cache"}},
+	}
+	devSvc := &selectorService{
+		dummyService: dummyService{ID: "dev", ADIdentifiers: []string{"redis"}},
+		attributes:   selector.Attributes{Image: "redis", Namespace: "dev", PodLabels: map[string]string{"tier": "cache"}},
+	}
+	// services without attributes never match templates with a selector
+	plainSvc := &dummyService{ID: "plain", ADIdentifiers: []string{"redis"}}
+
+	for _, svc := range []listeners.Service{prodSvc, devSvc, plainSvc} {
+		ids, _ := svc.GetADIdentifiers(context.TODO())
+		suite.cm.processNewService(ids, svc)
+	}
+
+	cfg := integration.Config{Name: "redis-prod", ADIdentifiers: []string{"redis"}, ADSelector: "namespace in (prod, staging) AND pod.label.tier == cache"}
+	changes, _ := suite.cm.processNewConfig(cfg)
+	assertConfigsMatch(suite.T(), changes.Schedule, matchAll(matchName("redis-prod"), matchSvc("prod")))
+	assertConfigsMatch(suite.T(), changes.Unschedule)
+
+	// templates without selector match all the services
+	cfgAll := integration.Config{Name: "redis-all", ADIdentifiers: []string{"redis"}}
+	changes, _ = suite.cm.processNewConfig(cfgAll)
+	assertConfigsMatch(suite.T(), changes.Schedule,
+		matchAll(matchName("redis-all"), matchSvc("prod")),
+		matchAll(matchName("redis-all"), matchSvc("dev")),
+		matchAll(matchName("redis-all"), matchSvc("plain")),
+	)
+
+	// templates with an invalid selector are never resolved
+	cfgInvalid := integration.Config{Name: "redis-invalid", ADIdentifiers: []string{"redis"}, ADSelector: "namespace in prod"}
+	changes, _ = suite.cm.processNewConfig(cfgInvalid)
+	assertConfigsMatch(suite.T(), changes.Schedule)
+	changes = suite.cm.processDelConfigs([]integration.Config{cfgInvalid})
+	assertConfigsMatch(suite.T(), changes.Unschedule)
+
+	changes = suite.cm.processDelConfigs([]integration.Config{cfg})
+	assertConfigsMatch(suite.T(), changes.Unschedule, matchAll(matchName("redis-prod"), matchSvc("prod")))
+	assertLoadedConfigsMatch(suite.T(), suite.cm,
+		matchAll(matchName("redis-all"), matchSvc("prod")),
+		matchAll(matchName("redis-all"), matchSvc("dev")),
+		matchAll(matchName("redis-all"), matchSvc("plain")),
+	)
+}
+
 func TestReconcilingConfigManagement(t *testing.T) {
 	mockResolver := MockSecretResolver{}
 	suite.Run(t, &ReconcilingConfigManagerSuite{
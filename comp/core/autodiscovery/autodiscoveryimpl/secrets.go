@@ -7,23 +7,55 @@ package autodiscoveryimpl
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
 	"github.com/DataDog/datadog-agent/comp/core/secrets"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+// operatorSourcePrefixes are the prefixes of the sources of the configs
+// defined by the operator of the agent, in configuration files or in a
+// key-value store. The cluster and endpoints checks dispatched by the Cluster
+// Agent keep the source of their template.
+var operatorSourcePrefixes = []string{
+	names.File + ":",
+	names.Consul + ":",
+	names.Etcd + ":",
+	names.Zookeeper + ":",
+}
+
+// isDefinedByOperator returns whether a config is defined by the operator of
+// the agent, the other configs being defined by workloads through container
+// labels, annotations or tags.
+func isDefinedByOperator(conf integration.Config) bool {
+	for _, prefix := range operatorSourcePrefixes {
+		if strings.HasPrefix(conf.Source, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func decryptConfig(conf integration.Config, secretResolver secrets.Component) (integration.Config, error) {
 	if config.Datadog.GetBool("secret_backend_skip_checks") {
 		log.Tracef("'secret_backend_skip_checks' is enabled, not decrypting configuration %q", conf.Name)
 		return conf, nil
 	}
 
+	// the built-in backends read the secrets the agent has access to, the
+	// workloads must not be able to get them by defining their own configs
+	resolve := secretResolver.ResolveWithoutBuiltinBackends
+	if isDefinedByOperator(conf) {
+		resolve = secretResolver.Resolve
+	}
+
 	var err error
 
 	// init_config
-	conf.InitConfig, err = secretResolver.Resolve(conf.InitConfig, conf.Name)
+	conf.InitConfig, err = resolve(conf.InitConfig, conf.Name)
 	if err != nil {
 		return conf, fmt.Errorf("error while decrypting secrets in 'init_config': %s", err)
 	}
@@ -32,7 +64,7 @@ func decryptConfig(conf integration.Config, secretResolver secrets.Component) (i
 	// we cannot update in place as, being a slice, it would modify the input config as well
 	instances := make([]integration.Data, 0, len(conf.Instances))
 	for _, inputInstance := range conf.Instances {
-		decryptedInstance, err := secretResolver.Resolve(inputInstance, conf.Name)
+		decryptedInstance, err := resolve(inputInstance, conf.Name)
 		if err != nil {
 			return conf, fmt.Errorf("error while decrypting secrets in an instance: %s", err)
 		}
@@ -41,13 +73,13 @@ func decryptConfig(conf integration.Config, secretResolver secrets.Component) (i
 	conf.Instances = instances
 
 	// metrics
-	conf.MetricConfig, err = secretResolver.Resolve(conf.MetricConfig, conf.Name)
+	conf.MetricConfig, err = resolve(conf.MetricConfig, conf.Name)
 	if err != nil {
 		return conf, fmt.Errorf("error while decrypting secrets in 'metrics': %s", err)
 	}
 
 	// logs
-	conf.LogsConfig, err = secretResolver.Resolve(conf.LogsConfig, conf.Name)
+	conf.LogsConfig, err = resolve(conf.LogsConfig, conf.Name)
 	if err != nil {
 		return conf, fmt.Errorf("error while decrypting secrets 'logs': %s", err)
 	}
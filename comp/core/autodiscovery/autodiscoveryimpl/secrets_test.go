@@ -24,7 +24,10 @@ type mockSecretScenario struct {
 	expectedOrigin string
 	returnedData   []byte
 	returnedError  error
-	called         int
+	// withoutBuiltinBackends is set for the scenarios expecting a call to
+	// ResolveWithoutBuiltinBackends
+	withoutBuiltinBackends bool
+	called                 int
 }
 
 type MockSecretResolver struct {
@@ -39,11 +42,19 @@ func (m *MockSecretResolver) Configure(_ secrets.ConfigParams) {}
 func (m *MockSecretResolver) GetDebugInfo(_ io.Writer) {}
 
 func (m *MockSecretResolver) Resolve(data []byte, origin string) ([]byte, error) {
+	return m.resolve(data, origin, false)
+}
+
+func (m *MockSecretResolver) ResolveWithoutBuiltinBackends(data []byte, origin string) ([]byte, error) {
+	return m.resolve(data, origin, true)
+}
+
+func (m *MockSecretResolver) resolve(data []byte, origin string, withoutBuiltinBackends bool) ([]byte, error) {
 	if m.scenarios == nil {
 		return data, nil
 	}
 	for n, scenario := range m.scenarios {
-		if bytes.Equal(data, scenario.expectedData) && origin == scenario.expectedOrigin {
+		if bytes.Equal(data, scenario.expectedData) && origin == scenario.expectedOrigin && withoutBuiltinBackends == scenario.withoutBuiltinBackends {
 			m.scenarios[n].called++
 			return scenario.returnedData, scenario.returnedError
 		}
@@ -119,14 +130,23 @@ var makeSharedScenarios = func() []mockSecretScenario {
 }
 
 func TestSecretResolve(t *testing.T) {
-	mockResolve := &MockSecretResolver{t, makeSharedScenarios()}
-
-	newConfig, err := decryptConfig(sharedTpl, mockResolve)
-	require.NoError(t, err)
-
-	assert.NotEqual(t, newConfig.Instances, sharedTpl.Instances)
-
-	assert.True(t, mockResolve.haveAllScenariosBeenCalled())
+	for _, source := range []string{
+		"file:/etc/datadog-agent/conf.d/cpu.d/conf.yaml",
+		"consul:/datadog/check_configs/redis",
+	} {
+		t.Run(source, func(t *testing.T) {
+			mockResolve := &MockSecretResolver{t, makeSharedScenarios()}
+
+			tpl := sharedTpl
+			tpl.Source = source
+			newConfig, err := decryptConfig(tpl, mockResolve)
+			require.NoError(t, err)
+
+			assert.NotEqual(t, newConfig.Instances, sharedTpl.Instances)
+
+			assert.True(t, mockResolve.haveAllScenariosBeenCalled())
+		})
+	}
 }
 
 func TestSkipSecretResolve(t *testing.T) {
@@ -144,3 +164,28 @@ func TestSkipSecretResolve(t *testing.T) {
 
 	assert.True(t, mockResolve.haveAllScenariosNotCalled())
 }
+
+func TestSecretResolveWorkloadConfig(t *testing.T) {
+	for _, source := range []string{
+		"container:docker://abcdef",
+		"kube_services:kube_service://default/redis",
+		"prometheus_pods:kubernetes_pod://abcdef",
+		// the Cloud Foundry configs have no source
+		"",
+	} {
+		t.Run(source, func(t *testing.T) {
+			scenarios := makeSharedScenarios()
+			for i := range scenarios {
+				scenarios[i].withoutBuiltinBackends = true
+			}
+			mockResolve := &MockSecretResolver{t, scenarios}
+
+			tpl := sharedTpl
+			tpl.Source = source
+			_, err := decryptConfig(tpl, mockResolve)
+			require.NoError(t, err)
+
+			assert.True(t, mockResolve.haveAllScenariosBeenCalled())
+		})
+	}
+}
@@ -17,10 +17,18 @@ import (
 )
 
 const (
-	openmetricsCheckName  = "openmetrics"
-	openmetricsInitConfig = "{}"
+	openmetricsCheckName = "openmetrics"
 )
 
+// openmetricsInitConfig returns the init_config of the openmetrics checks,
+// selecting the core check when it is enabled
+func openmetricsInitConfig() integration.Data {
+	if config.Datadog.GetBool("prometheus_scrape.use_core_check") {
+		return integration.Data(`{"loader":"core"}`)
+	}
+	return integration.Data("{}")
+}
+
 // buildInstances generates check config instances based on the Prometheus config and the object annotations
 // The second returned value is true if more than one instance is found
 func buildInstances(pc *types.PrometheusCheck, annotations map[string]string, namespacedName string) ([]integration.Data, bool) {
@@ -42,7 +42,7 @@ func ConfigsForService(pc *types.PrometheusCheck, svc *v1.Service) []integration
 		serviceID := apiserver.EntityForService(svc)
 		configs = append(configs, integration.Config{
 			Name:          openmetricsCheckName,
-			InitConfig:    integration.Data(openmetricsInitConfig),
+			InitConfig:    openmetricsInitConfig(),
 			Instances:     instances,
 			ClusterCheck:  true,
 			Provider:      names.PrometheusServices,
@@ -68,7 +68,7 @@ func ConfigsForServiceEndpoints(pc *types.PrometheusCheck, svc *v1.Service, ep *
 				epConfig := integration.Config{
 					ServiceID:     endpointsID,
 					Name:          openmetricsCheckName,
-					InitConfig:    integration.Data(openmetricsInitConfig),
+					InitConfig:    openmetricsInitConfig(),
 					Instances:     instances,
 					ClusterCheck:  true,
 					Provider:      names.PrometheusServices,
@@ -72,6 +72,11 @@ type Config struct {
 	// see ADIdentifiers.  (optional)
 	AdvancedADIdentifiers []AdvancedADIdentifier `json:"advanced_ad_identifiers"` // (include in digest: false)
 
+	// ADSelector is a selector expression restricting the services matching
+	// the AD identifiers the template is resolved for.  See the
+	// autodiscovery/selector package for the syntax.  (optional)
+	ADSelector string `json:"ad_selector"` // (include in digest: true)
+
 	// Provider is the name of the config provider that issued the config.  If
 	// this is "", then the config is a service config, representing a serivce
 	// discovered by a listener.
@@ -103,6 +108,10 @@ type Config struct {
 	// LogsExcluded is whether logs collection is disabled (set by container
 	// listeners only)
 	LogsExcluded bool `json:"logs_excluded"` // (include in digest: false)
+
+	// Placement holds the constraints on the nodes a cluster check is
+	// dispatched to (optional)
+	Placement *Placement `json:"placement,omitempty"` // (include in digest: true)
 }
 
 // CommonInstanceConfig holds the reserved fields for the yaml instance data
@@ -128,6 +137,43 @@ type AdvancedADIdentifier struct {
 	KubeEndpoints KubeNamespacedName `yaml:"kube_endpoints,omitempty"`
 }
 
+// Placement holds the constraints on the nodes a cluster check is dispatched
+// to by the cluster-agent.
+type Placement struct {
+	// RequiredNodeLabels are the labels the node of the agent running the
+	// check must have. The check isn't dispatched until such a node exists.
+	RequiredNodeLabels map[string]string `json:"required_node_labels,omitempty" yaml:"required_node_labels"`
+	// PreferredNodeLabels are the labels of the nodes to favor when
+	// dispatching the check.
+	PreferredNodeLabels map[string]string `json:"preferred_node_labels,omitempty" yaml:"preferred_node_labels"`
+	// AntiAffinityGroup spreads the checks of the same group on different
+	// nodes when possible.
+	AntiAffinityGroup string `json:"anti_affinity_group,omitempty" yaml:"anti_affinity_group"`
+}
+
+// String returns a stable representation of the placement constraints
+func (p *Placement) String() string {
+	var sb strings.Builder
+	sb.WriteString("required:")
+	sb.WriteString(labelsString(p.RequiredNodeLabels))
+	sb.WriteString(";preferred:")
+	sb.WriteString(labelsString(p.PreferredNodeLabels))
+	sb.WriteString(";anti_affinity_group:")
+	sb.WriteString(p.AntiAffinityGroup)
+	return sb.String()
+}
+
+// labelsString returns the labels in the key=value,key=value format, sorted
+// by key
+func labelsString(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 // KubeNamespacedName identifies a kubernetes object.
 type KubeNamespacedName struct {
 	Name      string `yaml:"name"`
@@ -404,6 +450,12 @@ func (c *Config) IntDigest() uint64 {
 	_, _ = h.Write([]byte(c.LogsConfig))
 	_, _ = h.Write([]byte(c.ServiceID))
 	_, _ = h.Write([]byte(strconv.FormatBool(c.IgnoreAutodiscoveryTags)))
+	if c.ADSelector != "" {
+		_, _ = h.Write([]byte(c.ADSelector))
+	}
+	if c.Placement != nil {
+		_, _ = h.Write([]byte(c.Placement.String()))
+	}
 
 	return h.Sum64()
 }
@@ -427,6 +479,12 @@ func (c *Config) FastDigest() uint64 {
 	_, _ = h.Write([]byte(c.LogsConfig))
 	_, _ = h.Write([]byte(c.ServiceID))
 	_, _ = h.Write([]byte(strconv.FormatBool(c.IgnoreAutodiscoveryTags)))
+	if c.ADSelector != "" {
+		_, _ = h.Write([]byte(c.ADSelector))
+	}
+	if c.Placement != nil {
+		_, _ = h.Write([]byte(c.Placement.String()))
+	}
 
 	return h.Sum64()
 }
@@ -464,6 +522,7 @@ func (c *Config) Dump(multiline bool) string {
 	fmt.Fprintf(&b, ws("LogsConfig: %s,"), dataField(c.LogsConfig))
 	fmt.Fprintf(&b, ws("ADIdentifiers: %#v,"), c.ADIdentifiers)
 	fmt.Fprintf(&b, ws("AdvancedADIdentifiers: %#v,"), c.AdvancedADIdentifiers)
+	fmt.Fprintf(&b, ws("ADSelector: %#v,"), c.ADSelector)
 	fmt.Fprintf(&b, ws("Provider: %#v,"), c.Provider)
 	fmt.Fprintf(&b, ws("ServiceID: %#v,"), c.ServiceID)
 	fmt.Fprintf(&b, ws("TaggerEntity: %#v,"), c.TaggerEntity)
@@ -118,9 +118,10 @@ func (l *ContainerListener) createContainerService(entity workloadmeta.Entity) {
 			containerImg.RawName,
 			container.Labels,
 		),
-		ports:    ports,
-		pid:      container.PID,
-		hostname: container.Hostname,
+		ports:      ports,
+		pid:        container.PID,
+		hostname:   container.Hostname,
+		attributes: containerSelectorAttributes(container, containerImg, pod),
 	}
 
 	if pod != nil {
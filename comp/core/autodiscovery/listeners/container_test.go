@@ -176,9 +176,10 @@ func TestCreateContainerService(t *testing.T) {
 							"gcr.io/foobar",
 							"foobar",
 						},
-						hosts: map[string]string{},
-						ports: []ContainerPort{},
-						ready: true,
+						hosts:      map[string]string{},
+						ports:      []ContainerPort{},
+						ready:      true,
+						attributes: containerSelectorAttributes(basicContainer, basicContainer.Image, nil),
 					},
 				},
 			},
@@ -212,9 +213,10 @@ func TestCreateContainerService(t *testing.T) {
 							"gcr.io/foobar",
 							"foobar",
 						},
-						hosts: map[string]string{},
-						ports: []ContainerPort{},
-						ready: true,
+						hosts:      map[string]string{},
+						ports:      []ContainerPort{},
+						ready:      true,
+						attributes: containerSelectorAttributes(runningContainerWithFinishedAtTime, runningContainerWithFinishedAtTime.Image, nil),
 					},
 				},
 			},
@@ -241,7 +243,8 @@ func TestCreateContainerService(t *testing.T) {
 								Name: "http",
 							},
 						},
-						ready: true,
+						ready:      true,
+						attributes: containerSelectorAttributes(multiplePortsContainer, multiplePortsContainer.Image, nil),
 					},
 				},
 			},
@@ -259,9 +262,10 @@ func TestCreateContainerService(t *testing.T) {
 							"gcr.io/foobar",
 							"foobar",
 						},
-						hosts: map[string]string{"pod": pod.IP},
-						ports: []ContainerPort{},
-						ready: pod.Ready,
+						hosts:      map[string]string{"pod": pod.IP},
+						ports:      []ContainerPort{},
+						ready:      pod.Ready,
+						attributes: containerSelectorAttributes(kubernetesContainer, kubernetesContainer.Image, pod),
 					},
 				},
 			},
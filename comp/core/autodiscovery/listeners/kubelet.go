@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/selector"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
@@ -97,6 +98,11 @@ func (l *KubeletListener) createPodService(
 		hosts:         map[string]string{"pod": pod.IP},
 		ports:         ports,
 		ready:         true,
+		attributes: selector.Attributes{
+			Namespace:      pod.Namespace,
+			PodLabels:      pod.Labels,
+			PodAnnotations: pod.Annotations,
+		},
 	}
 
 	svcID := buildSvcID(pod.GetID())
@@ -161,7 +167,8 @@ func (l *KubeletListener) createContainerService(
 			"namespace": pod.Namespace,
 			"pod_uid":   pod.ID,
 		},
-		hosts: map[string]string{"pod": pod.IP},
+		hosts:      map[string]string{"pod": pod.IP},
+		attributes: containerSelectorAttributes(container, containerImg, pod),
 
 		// Exclude non-running containers (including init containers)
 		// from metrics collection but keep them for collecting logs.
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/selector"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
 )
 
@@ -82,6 +83,9 @@ func TestKubeletCreatePodService(t *testing.T) {
 							"pod": "127.0.0.1",
 						},
 						ready: true,
+						attributes: selector.Attributes{
+							Namespace: podNamespace,
+						},
 					},
 				},
 			},
@@ -239,7 +243,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: basicContainer,
+						entity:     basicContainer,
+						attributes: containerSelectorAttributes(basicContainer, imageWithShortname, pod),
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"gcr.io/foobar:latest",
@@ -271,7 +276,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: recentlyStoppedContainer,
+						entity:     recentlyStoppedContainer,
+						attributes: containerSelectorAttributes(recentlyStoppedContainer, basicImage, pod),
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"foobar",
@@ -326,7 +332,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: runningContainerWithFinishedAtTime,
+						entity:     runningContainerWithFinishedAtTime,
+						attributes: containerSelectorAttributes(runningContainerWithFinishedAtTime, basicImage, pod),
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"foobar",
@@ -357,7 +364,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: multiplePortsContainer,
+						entity:     multiplePortsContainer,
+						attributes: containerSelectorAttributes(multiplePortsContainer, basicImage, pod),
 						adIdentifiers: []string{
 							"docker://foobarquux",
 							"foobar",
@@ -397,7 +405,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: customIDsContainer,
+						entity:     customIDsContainer,
+						attributes: containerSelectorAttributes(customIDsContainer, basicImage, podWithAnnotations),
 						adIdentifiers: []string{
 							"customid",
 							"docker://foobarquux",
@@ -441,7 +450,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: customIDsContainer,
+						entity:     customIDsContainer,
+						attributes: containerSelectorAttributes(customIDsContainer, basicImage, podWithMetricsExcludeAnnotation),
 						adIdentifiers: []string{
 							"customid",
 							"docker://foobarquux",
@@ -475,7 +485,8 @@ func TestKubeletCreateContainerService(t *testing.T) {
 				"container://foobarquux": {
 					parent: "kubernetes_pod://foobar",
 					service: &service{
-						entity: customIDsContainer,
+						entity:     customIDsContainer,
+						attributes: containerSelectorAttributes(customIDsContainer, basicImage, podWithLogsExcludeAnnotation),
 						adIdentifiers: []string{
 							"customid",
 							"docker://foobarquux",
@@ -18,6 +18,7 @@ const (
 	kubeEndpointsListenerName   = "kube_endpoints"
 	kubeServicesListenerName    = "kube_services"
 	kubeletListenerName         = "kubelet"
+	processListenerName         = "process"
 	snmpListenerName            = "snmp"
 	staticConfigListenerName    = "static config"
 	dbmAuroraListenerName       = "database-monitoring-aurora"
@@ -32,6 +33,7 @@ func RegisterListeners(serviceListenerFactories map[string]ServiceListenerFactor
 	Register(kubeEndpointsListenerName, NewKubeEndpointsListener, serviceListenerFactories)
 	Register(kubeServicesListenerName, NewKubeServiceListener, serviceListenerFactories)
 	Register(kubeletListenerName, func(config Config) (ServiceListener, error) { return NewKubeletListener(config, wmeta) }, serviceListenerFactories)
+	Register(processListenerName, func(config Config) (ServiceListener, error) { return NewProcessListener(config, wmeta) }, serviceListenerFactories)
 	Register(snmpListenerName, NewSNMPListener, serviceListenerFactories)
 	Register(staticConfigListenerName, NewStaticConfigListener, serviceListenerFactories)
 	Register(dbmAuroraListenerName, NewDBMAuroraListener, serviceListenerFactories)
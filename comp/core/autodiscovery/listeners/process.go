@@ -0,0 +1,491 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !serverless
+
+package listeners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobwas/glob"
+	gopsutilnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	processEntityPrefix     = "process://"
+	processListenerHostKey  = "host"
+	processDefaultLocalHost = "127.0.0.1"
+)
+
+// ProcessRule maps the processes whose name or command line matches a glob
+// pattern to an AD identifier.
+type ProcessRule struct {
+	ADIdentifier string `mapstructure:"ad_identifier"`
+	Name         string `mapstructure:"name"`
+	Cmdline      string `mapstructure:"cmdline"`
+
+	name    glob.Glob
+	cmdline glob.Glob
+}
+
+// matches returns whether the given process matches the rule
+func (r *ProcessRule) matches(name string, cmdline string) bool {
+	if r.name != nil && r.name.Match(name) {
+		return true
+	}
+	return r.cmdline != nil && r.cmdline.Match(cmdline)
+}
+
+// listeningPort is a TCP port a process is listening on
+type listeningPort struct {
+	ip   string
+	port int
+}
+
+// hostProcess is a process running on the host, outside of any container
+type hostProcess struct {
+	pid     int32
+	name    string
+	exe     string
+	cmdline []string
+}
+
+// ProcessListener creates services from the processes running on the host
+// that are listening on TCP ports. It is meant for non-containerized hosts,
+// where integrations would otherwise have to be configured by hand. The
+// processes are the ones reported to workloadmeta, their listening ports are
+// polled.
+type ProcessListener struct {
+	newService chan<- Service
+	delService chan<- Service
+	stop       chan struct{}
+	store      workloadmeta.Component
+	services   map[string]Service
+	processes  map[int32]*hostProcess
+	rules      []ProcessRule
+
+	// getProcess and getListeningPorts are overridden in tests
+	getProcess        func(pid int32) (*hostProcess, error)
+	getListeningPorts func() (map[int32][]listeningPort, error)
+
+	// ticks is used primarily for testing purposes so
+	// the frequency the discovery loop iterates can be controlled
+	ticks  <-chan time.Time
+	ticker *time.Ticker
+	m      sync.Mutex
+}
+
+// ProcessService is a process listening on TCP ports
+type ProcessService struct {
+	entityID      string
+	pid           int
+	adIdentifiers []string
+	hosts         map[string]string
+	ports         []ContainerPort
+}
+
+var _ Service = &ProcessService{}
+
+// NewProcessListener returns a new ProcessListener
+func NewProcessListener(_ Config, wmeta optional.Option[workloadmeta.Component]) (ServiceListener, error) {
+	var rules []ProcessRule
+	if err := config.Datadog.UnmarshalKey("process_listener.rules", &rules); err != nil {
+		return nil, fmt.Errorf("invalid process listener rules: %w", err)
+	}
+	rules, err := compileProcessRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	store, ok := wmeta.Get()
+	if !ok {
+		return nil, errors.New("workloadmeta store is not initialized")
+	}
+
+	return newProcessListener(rules, store, nil), nil
+}
+
+func newProcessListener(rules []ProcessRule, store workloadmeta.Component, ticks <-chan time.Time) *ProcessListener {
+	l := &ProcessListener{
+		services:          make(map[string]Service),
+		processes:         make(map[int32]*hostProcess),
+		stop:              make(chan struct{}),
+		store:             store,
+		rules:             rules,
+		getProcess:        getHostProcess,
+		getListeningPorts: listeningPortsByPID,
+		ticks:             ticks,
+	}
+	if l.ticks == nil {
+		l.ticker = time.NewTicker(config.Datadog.GetDuration("process_listener.discovery_interval"))
+		l.ticks = l.ticker.C
+	}
+	return l
+}
+
+// compileProcessRules validates the rules and compiles their patterns
+func compileProcessRules(rules []ProcessRule) ([]ProcessRule, error) {
+	for i := range rules {
+		rule := &rules[i]
+		if rule.ADIdentifier == "" {
+			return nil, fmt.Errorf("process listener rule %d has no ad_identifier", i)
+		}
+		if rule.Name == "" && rule.Cmdline == "" {
+			return nil, fmt.Errorf("process listener rule for %q needs a name or a cmdline pattern", rule.ADIdentifier)
+		}
+		var err error
+		if rule.Name != "" {
+			if rule.name, err = glob.Compile(rule.Name); err != nil {
+				return nil, fmt.Errorf("invalid name pattern %q: %w", rule.Name, err)
+			}
+		}
+		if rule.Cmdline != "" {
+			if rule.cmdline, err = glob.Compile(rule.Cmdline); err != nil {
+				return nil, fmt.Errorf("invalid cmdline pattern %q: %w", rule.Cmdline, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// Listen starts the discovery of processes
+func (l *ProcessListener) Listen(newSvc, delSvc chan<- Service) {
+	l.newService = newSvc
+	l.delService = delSvc
+
+	filter := workloadmeta.NewFilter(&workloadmeta.FilterParams{
+		Kinds:     []workloadmeta.Kind{workloadmeta.KindProcess},
+		Source:    workloadmeta.SourceAll,
+		EventType: workloadmeta.EventTypeAll,
+	})
+	ch := l.store.Subscribe("ad-processlistener", workloadmeta.NormalPriority, filter)
+
+	go l.run(ch)
+}
+
+// Stop stops the listener
+func (l *ProcessListener) Stop() {
+	l.stop <- struct{}{}
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
+
+func (l *ProcessListener) run(ch chan workloadmeta.EventBundle) {
+	defer l.store.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case evBundle, ok := <-ch:
+			if !ok {
+				return
+			}
+			// no downstream collector depends on AD having up to date data
+			evBundle.Acknowledge()
+			l.processEvents(evBundle.Events)
+		case <-l.ticks:
+			l.discoverProcesses()
+		}
+	}
+}
+
+// processEvents keeps track of the host processes known to workloadmeta, and
+// removes the services of the processes that are gone.
+func (l *ProcessListener) processEvents(events []workloadmeta.Event) {
+	var removed []Service
+
+	l.m.Lock()
+	for _, ev := range events {
+		proc, ok := ev.Entity.(*workloadmeta.Process)
+		if !ok {
+			continue
+		}
+		pid64, err := strconv.ParseInt(proc.ID, 10, 32)
+		if err != nil {
+			log.Debugf("invalid process ID %q: %s", proc.ID, err)
+			continue
+		}
+		pid := int32(pid64)
+
+		// processes running in containers are handled by the container listeners
+		if ev.Type == workloadmeta.EventTypeSet && proc.ContainerID == "" {
+			if _, found := l.processes[pid]; found {
+				continue
+			}
+			hostProc, err := l.getProcess(pid)
+			if err != nil {
+				log.Debugf("unable to inspect process %d: %s", pid, err)
+				continue
+			}
+			l.processes[pid] = hostProc
+			continue
+		}
+
+		delete(l.processes, pid)
+		entityID := processEntityPrefix + proc.ID
+		if svc, found := l.services[entityID]; found {
+			removed = append(removed, svc)
+			delete(l.services, entityID)
+		}
+	}
+	l.m.Unlock()
+
+	for _, svc := range removed {
+		l.delService <- svc
+	}
+}
+
+// discoverProcesses creates services for the host processes listening on TCP
+// ports, and removes the services of the processes that stopped listening.
+func (l *ProcessListener) discoverProcesses() {
+	var added, removed []Service
+
+	l.m.Lock()
+	if len(l.processes) == 0 && len(l.services) == 0 {
+		l.m.Unlock()
+		return
+	}
+
+	ports, err := l.getListeningPorts()
+	if err != nil {
+		l.m.Unlock()
+		log.Warnf("unable to list listening ports: %s", err)
+		return
+	}
+
+	discovered := make(map[string]struct{})
+	for pid, procPorts := range ports {
+		proc, found := l.processes[pid]
+		if !found {
+			continue
+		}
+		svc := l.createProcessService(proc, procPorts)
+		discovered[svc.entityID] = struct{}{}
+
+		if old, found := l.services[svc.entityID]; found {
+			// svcEqual ignores the ports when both services return them
+			// without error, and a process can start listening on new ports
+			if svcEqual(old, svc) && reflect.DeepEqual(old.(*ProcessService).ports, svc.ports) {
+				continue
+			}
+			removed = append(removed, old)
+		}
+		l.services[svc.entityID] = svc
+		added = append(added, svc)
+	}
+
+	for entityID, svc := range l.services {
+		if _, found := discovered[entityID]; !found {
+			removed = append(removed, svc)
+			delete(l.services, entityID)
+		}
+	}
+	l.m.Unlock()
+
+	for _, svc := range removed {
+		l.delService <- svc
+	}
+	for _, svc := range added {
+		l.newService <- svc
+	}
+}
+
+// getHostProcess returns the name, executable and command line of a process
+func getHostProcess(pid int32) (*hostProcess, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	name, err := proc.Name()
+	if err != nil {
+		return nil, err
+	}
+	cmdline, err := proc.CmdlineSlice()
+	if err != nil {
+		return nil, err
+	}
+	// the executable of the processes of other users can't always be resolved
+	exe, _ := proc.Exe()
+
+	return &hostProcess{
+		pid:     pid,
+		name:    name,
+		exe:     exe,
+		cmdline: cmdline,
+	}, nil
+}
+
+func (l *ProcessListener) createProcessService(proc *hostProcess, procPorts []listeningPort) *ProcessService {
+	name := proc.name
+	cmdline := strings.Join(proc.cmdline, " ")
+
+	var adIdentifiers []string
+	addIdentifier := func(id string) {
+		if id == "" {
+			return
+		}
+		for _, existing := range adIdentifiers {
+			if existing == id {
+				return
+			}
+		}
+		adIdentifiers = append(adIdentifiers, id)
+	}
+	addIdentifier(name)
+	if proc.exe != "" {
+		addIdentifier(filepath.Base(proc.exe))
+	}
+	for i := range l.rules {
+		if l.rules[i].matches(name, cmdline) {
+			addIdentifier(l.rules[i].ADIdentifier)
+		}
+	}
+
+	sort.Slice(procPorts, func(i, j int) bool {
+		return procPorts[i].port < procPorts[j].port
+	})
+	ports := make([]ContainerPort, 0, len(procPorts))
+	seenPorts := make(map[int]struct{})
+	for _, p := range procPorts {
+		if _, found := seenPorts[p.port]; found {
+			continue
+		}
+		seenPorts[p.port] = struct{}{}
+		ports = append(ports, ContainerPort{Port: p.port, Name: name})
+	}
+
+	return &ProcessService{
+		entityID:      processEntityPrefix + strconv.Itoa(int(proc.pid)),
+		pid:           int(proc.pid),
+		adIdentifiers: adIdentifiers,
+		hosts:         map[string]string{processListenerHostKey: processHost(procPorts)},
+		ports:         ports,
+	}
+}
+
+// processHost returns the address the checks should use to reach a process,
+// preferring the loopback address when the process listens on it or on all
+// the interfaces.
+func processHost(procPorts []listeningPort) string {
+	host := ""
+	for _, p := range procPorts {
+		ip := net.ParseIP(p.ip)
+		if ip == nil {
+			continue
+		}
+		if ip.IsUnspecified() || ip.Equal(net.IPv4(127, 0, 0, 1)) {
+			return processDefaultLocalHost
+		}
+		if host == "" || (ip.To4() != nil && net.ParseIP(host).To4() == nil) {
+			host = ip.String()
+		}
+	}
+	if host == "" {
+		return processDefaultLocalHost
+	}
+	return host
+}
+
+// listeningPortsByPID returns the TCP ports the processes are listening on
+func listeningPortsByPID() (map[int32][]listeningPort, error) {
+	conns, err := gopsutilnet.Connections("tcp")
+	if err != nil {
+		return nil, err
+	}
+	ports := make(map[int32][]listeningPort)
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" || conn.Pid == 0 {
+			continue
+		}
+		ports[conn.Pid] = append(ports[conn.Pid], listeningPort{ip: conn.Laddr.IP, port: int(conn.Laddr.Port)})
+	}
+	return ports, nil
+}
+
+// GetServiceID returns the unique entity name linked to that service
+func (s *ProcessService) GetServiceID() string {
+	return s.entityID
+}
+
+// GetTaggerEntity returns the unique entity ID linked to that service
+func (s *ProcessService) GetTaggerEntity() string {
+	return s.entityID
+}
+
+// GetADIdentifiers returns the process names and the identifiers of the
+// matching process listener rules
+func (s *ProcessService) GetADIdentifiers(context.Context) ([]string, error) {
+	return s.adIdentifiers, nil
+}
+
+// GetHosts returns the address the process can be reached at
+func (s *ProcessService) GetHosts(context.Context) (map[string]string, error) {
+	return s.hosts, nil
+}
+
+// GetPorts returns the TCP ports the process is listening on
+func (s *ProcessService) GetPorts(context.Context) ([]ContainerPort, error) {
+	return s.ports, nil
+}
+
+// GetTags returns the list of tags - currently always empty
+func (s *ProcessService) GetTags() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetPid returns the process ID
+func (s *ProcessService) GetPid(context.Context) (int, error) {
+	return s.pid, nil
+}
+
+// GetHostname returns nothing - not supported
+func (s *ProcessService) GetHostname(context.Context) (string, error) {
+	return "", ErrNotSupported
+}
+
+// IsReady returns true
+func (s *ProcessService) IsReady(context.Context) bool {
+	return true
+}
+
+// GetCheckNames returns nil
+func (s *ProcessService) GetCheckNames(context.Context) []string {
+	return nil
+}
+
+// HasFilter returns false
+func (s *ProcessService) HasFilter(containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig is not supported
+func (s *ProcessService) GetExtraConfig(string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// FilterTemplates does nothing.
+func (s *ProcessService) FilterTemplates(map[string]integration.Config) {
+}
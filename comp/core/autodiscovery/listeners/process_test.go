@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !serverless
+
+package listeners
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
+)
+
+func TestProcessListener(t *testing.T) {
+	rules, err := compileProcessRules([]ProcessRule{
+		{ADIdentifier: "my-app", Cmdline: "*java*-jar /opt/app/*.jar*"},
+		{ADIdentifier: "postgres-any", Name: "postgres*"},
+	})
+	require.NoError(t, err)
+
+	procs := map[int32]*hostProcess{
+		10: {pid: 10, name: "redis-server", exe: "/usr/bin/redis-server", cmdline: []string{"/usr/bin/redis-server", "127.0.0.1:6379"}},
+		20: {pid: 20, name: "java", exe: "/usr/lib/jvm/bin/java", cmdline: []string{"java", "-Xmx1g", "-jar", "/opt/app/app-1.2.jar"}},
+		30: {pid: 30, name: "postgres", exe: "/usr/lib/postgresql/15/bin/postgres", cmdline: []string{"/usr/lib/postgresql/15/bin/postgres", "-D", "/var/lib/postgresql"}},
+		40: {pid: 40, name: "bash", exe: "/bin/bash"},
+		50: {pid: 50, name: "nginx", exe: "/usr/sbin/nginx"},
+	}
+	ports := map[int32][]listeningPort{
+		10: {{ip: "127.0.0.1", port: 6379}},
+		20: {{ip: "::", port: 8443}, {ip: "0.0.0.0", port: 8080}, {ip: "::", port: 8080}},
+		30: {{ip: "10.0.0.5", port: 5432}},
+		50: {{ip: "0.0.0.0", port: 80}},
+	}
+
+	l := newProcessListener(rules, nil, make(chan time.Time))
+	l.getProcess = func(pid int32) (*hostProcess, error) {
+		proc, found := procs[pid]
+		if !found {
+			return nil, errors.New("process not found")
+		}
+		return proc, nil
+	}
+	l.getListeningPorts = func() (map[int32][]listeningPort, error) { return ports, nil }
+
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	l.newService = newSvc
+	l.delService = delSvc
+
+	l.processEvents([]workloadmeta.Event{
+		processEvent(workloadmeta.EventTypeSet, 10, ""),
+		processEvent(workloadmeta.EventTypeSet, 20, ""),
+		processEvent(workloadmeta.EventTypeSet, 30, ""),
+		processEvent(workloadmeta.EventTypeSet, 40, ""),
+		// containerized processes are left to the container listeners
+		processEvent(workloadmeta.EventTypeSet, 50, "abcdef"),
+	})
+	require.Len(t, newSvc, 0)
+
+	l.discoverProcesses()
+	require.Len(t, newSvc, 3)
+	require.Len(t, delSvc, 0)
+
+	services := make(map[string]Service)
+	for len(newSvc) > 0 {
+		svc := <-newSvc
+		services[svc.GetServiceID()] = svc
+	}
+
+	ctx := context.Background()
+	expected := map[string]struct {
+		adIdentifiers []string
+		host          string
+		ports         []ContainerPort
+		pid           int
+	}{
+		"process://10": {
+			adIdentifiers: []string{"redis-server"},
+			host:          "127.0.0.1",
+			ports:         []ContainerPort{{Port: 6379, Name: "redis-server"}},
+			pid:           10,
+		},
+		"process://20": {
+			adIdentifiers: []string{"java", "my-app"},
+			host:          "127.0.0.1",
+			ports:         []ContainerPort{{Port: 8080, Name: "java"}, {Port: 8443, Name: "java"}},
+			pid:           20,
+		},
+		"process://30": {
+			adIdentifiers: []string{"postgres", "postgres-any"},
+			host:          "10.0.0.5",
+			ports:         []ContainerPort{{Port: 5432, Name: "postgres"}},
+			pid:           30,
+		},
+	}
+	for id, exp := range expected {
+		svc, found := services[id]
+		require.True(t, found, id)
+
+		adIdentifiers, err := svc.GetADIdentifiers(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, exp.adIdentifiers, adIdentifiers, id)
+
+		hosts, err := svc.GetHosts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"host": exp.host}, hosts, id)
+
+		svcPorts, err := svc.GetPorts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, exp.ports, svcPorts, id)
+
+		pid, err := svc.GetPid(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, exp.pid, pid, id)
+	}
+
+	// nothing changed
+	l.discoverProcesses()
+	assert.Len(t, newSvc, 0)
+	assert.Len(t, delSvc, 0)
+
+	// redis stopped listening and postgres listens on a new port
+	delete(ports, 10)
+	ports[30] = append(ports[30], listeningPort{ip: "10.0.0.5", port: 5433})
+	l.discoverProcesses()
+
+	require.Len(t, newSvc, 1)
+	assert.Equal(t, "process://30", (<-newSvc).GetServiceID())
+	require.Len(t, delSvc, 2)
+	deleted := []string{(<-delSvc).GetServiceID(), (<-delSvc).GetServiceID()}
+	assert.ElementsMatch(t, []string{"process://10", "process://30"}, deleted)
+
+	// java exited
+	l.processEvents([]workloadmeta.Event{processEvent(workloadmeta.EventTypeUnset, 20, "")})
+	require.Len(t, delSvc, 1)
+	assert.Equal(t, "process://20", (<-delSvc).GetServiceID())
+
+	l.discoverProcesses()
+	assert.Len(t, newSvc, 0)
+	assert.Len(t, delSvc, 0)
+}
+
+func processEvent(eventType workloadmeta.EventType, pid int, containerID string) workloadmeta.Event {
+	return workloadmeta.Event{
+		Type: eventType,
+		Entity: &workloadmeta.Process{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindProcess,
+				ID:   strconv.Itoa(pid),
+			},
+			ContainerID: containerID,
+		},
+	}
+}
+
+func TestCompileProcessRules(t *testing.T) {
+	_, err := compileProcessRules([]ProcessRule{{Name: "nginx"}})
+	assert.Error(t, err)
+
+	_, err = compileProcessRules([]ProcessRule{{ADIdentifier: "nginx"}})
+	assert.Error(t, err)
+
+	_, err = compileProcessRules([]ProcessRule{{ADIdentifier: "nginx", Cmdline: "[nginx"}})
+	assert.Error(t, err)
+
+	rules, err := compileProcessRules([]ProcessRule{{ADIdentifier: "nginx", Name: "nginx*"}})
+	require.NoError(t, err)
+	assert.True(t, rules[0].matches("nginx", "nginx: master process"))
+	assert.False(t, rules[0].matches("apache2", "/usr/sbin/apache2 -k start"))
+}
+
+func TestProcessHost(t *testing.T) {
+	assert.Equal(t, "127.0.0.1", processHost(nil))
+	assert.Equal(t, "127.0.0.1", processHost([]listeningPort{{ip: "10.0.0.1"}, {ip: "0.0.0.0"}}))
+	assert.Equal(t, "10.0.0.1", processHost([]listeningPort{{ip: "fd00::1"}, {ip: "10.0.0.1"}}))
+	assert.Equal(t, "::1", processHost([]listeningPort{{ip: "::1"}}))
+}
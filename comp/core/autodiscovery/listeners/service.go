@@ -12,6 +12,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/selector"
 	"github.com/DataDog/datadog-agent/comp/core/tagger"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -34,9 +35,11 @@ type service struct {
 	extraConfig     map[string]string
 	metricsExcluded bool
 	logsExcluded    bool
+	attributes      selector.Attributes
 }
 
 var _ Service = &service{}
+var _ SelectorService = &service{}
 
 // GetServiceID returns the AD entity ID of the service.
 func (s *service) GetServiceID() string {
@@ -203,6 +206,31 @@ func (s *service) GetExtraConfig(key string) (string, error) {
 	return result, nil
 }
 
+// GetSelectorAttributes returns the attributes the selectors of the
+// templates are evaluated against.
+func (s *service) GetSelectorAttributes() selector.Attributes {
+	return s.attributes
+}
+
+// containerSelectorAttributes returns the selector attributes of a container
+// with the given image, and of the pod it belongs to, if any.
+func containerSelectorAttributes(container *workloadmeta.Container, image workloadmeta.ContainerImage, pod *workloadmeta.KubernetesPod) selector.Attributes {
+	attributes := selector.Attributes{
+		Image:     image.ShortName,
+		ImageName: image.Name,
+		ImageTag:  image.Tag,
+		Env:       container.EnvVars,
+
+		ContainerLabels: container.Labels,
+	}
+	if pod != nil {
+		attributes.Namespace = pod.Namespace
+		attributes.PodLabels = pod.Labels
+		attributes.PodAnnotations = pod.Annotations
+	}
+	return attributes
+}
+
 // svcEqual checks that two Services are equal to each other by doing a deep
 // equality check on data returned by most of Service's methods. Methods not
 // checked are HasFilter and GetExtraConfig.
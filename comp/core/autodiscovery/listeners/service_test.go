@@ -150,3 +150,30 @@ func TestServiceFilterTemplatesCCA(t *testing.T) {
 			filterDrops(&service{}, noLogsTpl, logsTpl, ccaTpl))
 	})
 }
+
+func TestContainerSelectorAttributes(t *testing.T) {
+	container := &workloadmeta.Container{
+		EntityMeta: workloadmeta.EntityMeta{
+			Labels: map[string]string{"tier": "web"},
+		},
+		EnvVars: map[string]string{"DD_ENV": "prod"},
+	}
+	image := workloadmeta.ContainerImage{ShortName: "nginx", Name: "docker.io/library/nginx", Tag: "1.25"}
+	pod := &workloadmeta.KubernetesPod{
+		EntityMeta: workloadmeta.EntityMeta{
+			Namespace:   "prod",
+			Labels:      map[string]string{"tier": "frontend"},
+			Annotations: map[string]string{"example.com/owner": "team-a"},
+		},
+	}
+
+	attributes := containerSelectorAttributes(container, image, pod)
+
+	// the labels of the pod don't override the ones of the container
+	assert.Equal(t, map[string]string{"tier": "web"}, attributes.ContainerLabels)
+	assert.Equal(t, map[string]string{"tier": "frontend"}, attributes.PodLabels)
+	assert.Equal(t, map[string]string{"example.com/owner": "team-a"}, attributes.PodAnnotations)
+	assert.Equal(t, "prod", attributes.Namespace)
+	assert.Equal(t, "nginx", attributes.Image)
+	assert.Equal(t, map[string]string{"DD_ENV": "prod"}, attributes.Env)
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/selector"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -47,6 +48,13 @@ type Service interface {
 	FilterTemplates(map[string]integration.Config)
 }
 
+// SelectorService is implemented by the services exposing the attributes the
+// selector expressions of the templates are evaluated against. Templates with
+// a selector never match the services that don't implement it.
+type SelectorService interface {
+	GetSelectorAttributes() selector.Attributes
+}
+
 // ServiceListener monitors running services and triggers check (un)scheduling
 //
 // It holds a cache of running services, listens to new/killed services and
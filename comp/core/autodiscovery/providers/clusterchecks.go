@@ -35,6 +35,7 @@ type ClusterChecksConfigProvider struct {
 	heartbeat        time.Time
 	lastChange       int64
 	identifier       string
+	nodeName         string
 	flushedConfigs   bool
 }
 
@@ -64,6 +65,10 @@ func NewClusterChecksConfigProvider(providerConfig *config.ConfigurationProvider
 		}
 	}
 
+	// The Kubernetes node name lets the cluster-agent honor the placement
+	// constraints of the cluster checks
+	c.nodeName = config.Datadog.GetString("kubernetes_kubelet_nodename")
+
 	if providerConfig.GraceTimeSeconds > 0 {
 		c.graceDuration = time.Duration(providerConfig.GraceTimeSeconds) * time.Second
 	}
@@ -114,6 +119,7 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
+		NodeName:   c.nodeName,
 	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
@@ -212,6 +218,7 @@ func (c *ClusterChecksConfigProvider) postHeartbeat(ctx context.Context) error {
 
 	status := types.NodeStatus{
 		LastChange: types.ExtraHeartbeatLastChangeValue,
+		NodeName:   c.nodeName,
 	}
 
 	_, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
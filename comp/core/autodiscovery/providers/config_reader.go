@@ -28,7 +28,9 @@ import (
 type configFormat struct {
 	ADIdentifiers           []string                           `yaml:"ad_identifiers"`
 	AdvancedADIdentifiers   []integration.AdvancedADIdentifier `yaml:"advanced_ad_identifiers"`
+	ADSelector              string                             `yaml:"ad_selector"`
 	ClusterCheck            bool                               `yaml:"cluster_check"`
+	Placement               *integration.Placement             `yaml:"placement"`
 	InitConfig              interface{}                        `yaml:"init_config"`
 	MetricConfig            interface{}                        `yaml:"jmx_metrics"`
 	LogsConfig              interface{}                        `yaml:"logs"`
@@ -409,9 +411,11 @@ func GetIntegrationConfigFromFile(name, fpath string) (integration.Config, error
 	// Copy auto discovery identifiers
 	conf.ADIdentifiers = cf.ADIdentifiers
 	conf.AdvancedADIdentifiers = cf.AdvancedADIdentifiers
+	conf.ADSelector = cf.ADSelector
 
-	// Copy cluster_check status
+	// Copy cluster_check status and placement constraints
 	conf.ClusterCheck = cf.ClusterCheck
+	conf.Placement = cf.Placement
 
 	// Copy ignore_autodiscovery_tags parameter
 	conf.IgnoreAutodiscoveryTags = cf.IgnoreAutodiscoveryTags
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selector
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenEqual
+	tokenNotEqual
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) is(kind tokenKind) bool {
+	return t.kind == kind
+}
+
+// isKeyword returns whether the token is the given case-insensitive keyword
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+// isValue returns whether the token can be used as a value
+func (t token) isValue() bool {
+	return t.kind == tokenWord || t.kind == tokenString
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ","})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+			}
+			if r == '&' {
+				tokens = append(tokens, token{tokenAnd, "&&"})
+			} else {
+				tokens = append(tokens, token{tokenOr, "||"})
+			}
+			i += 2
+		case r == '=':
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				i++
+			}
+			tokens = append(tokens, token{tokenEqual, "=="})
+		case r == '!':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{tokenNotEqual, "!="})
+				i += 2
+			} else {
+				tokens = append(tokens, token{tokenNot, "!"})
+				i++
+			}
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokenString, string(runes[i+1 : end])})
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			if start == i {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+			}
+			word := string(runes[start:i])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, token{tokenAnd, word})
+			case "OR":
+				tokens = append(tokens, token{tokenOr, word})
+			case "NOT":
+				tokens = append(tokens, token{tokenNot, word})
+			default:
+				tokens = append(tokens, token{tokenWord, word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func isWordRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return true
+	}
+	return strings.ContainsRune("._-/:@+*~", r)
+}
@@ -0,0 +1,324 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package selector implements the selector expressions that restrict the
+// services an autodiscovery template applies to.
+//
+// A selector is a boolean expression over the attributes of a service:
+//
+//	image == redis AND namespace in (prod, staging) AND pod.label.tier == cache
+//
+// The supported fields are:
+//   - image: the short name of the image
+//   - image_name: the full name of the image
+//   - image_tag: the tag of the image
+//   - namespace: the Kubernetes namespace
+//   - container.label.<key>: a label of the container
+//   - pod.label.<key>, pod.annotation.<key>: a label or annotation of the pod
+//   - env.<key>: an environment variable of the container
+//
+// Keys can contain dots and slashes. Container and pod labels are kept apart,
+// as they can use the same keys with different values.
+//
+// A field is compared with "==" (or "="), "!=", "in (...)" or "notin (...)",
+// or tested with "exists". Comparisons are combined with AND (&&), OR (||)
+// and NOT (!), AND binding tighter than OR, and grouped with parentheses.
+// Values can be quoted with single or double quotes.
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Attributes are the attributes of a service a selector is evaluated
+// against.
+type Attributes struct {
+	Image           string
+	ImageName       string
+	ImageTag        string
+	Namespace       string
+	ContainerLabels map[string]string
+	PodLabels       map[string]string
+	PodAnnotations  map[string]string
+	Env             map[string]string
+}
+
+// Selector is a parsed selector expression.
+type Selector struct {
+	expr string
+	root node
+}
+
+// Parse parses a selector expression.
+func Parse(expr string) (*Selector, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.peek().value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+	}
+	return &Selector{expr: expr, root: root}, nil
+}
+
+// Matches returns whether the given attributes match the selector.
+func (s *Selector) Matches(attrs Attributes) bool {
+	return s.root.eval(&attrs)
+}
+
+// String returns the selector expression.
+func (s *Selector) String() string {
+	return s.expr
+}
+
+type node interface {
+	eval(attrs *Attributes) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(attrs *Attributes) bool { return n.left.eval(attrs) && n.right.eval(attrs) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(attrs *Attributes) bool { return n.left.eval(attrs) || n.right.eval(attrs) }
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(attrs *Attributes) bool { return !n.operand.eval(attrs) }
+
+type operator int
+
+const (
+	opEqual operator = iota
+	opNotEqual
+	opIn
+	opNotIn
+	opExists
+)
+
+type comparisonNode struct {
+	field  string
+	key    string
+	op     operator
+	values []string
+}
+
+func (n comparisonNode) eval(attrs *Attributes) bool {
+	value, found := n.lookup(attrs)
+	switch n.op {
+	case opExists:
+		return found
+	case opEqual:
+		return found && value == n.values[0]
+	case opNotEqual:
+		return !found || value != n.values[0]
+	case opIn, opNotIn:
+		in := false
+		for _, v := range n.values {
+			if found && value == v {
+				in = true
+				break
+			}
+		}
+		return in == (n.op == opIn)
+	}
+	return false
+}
+
+func (n comparisonNode) lookup(attrs *Attributes) (string, bool) {
+	switch n.field {
+	case "image":
+		return attrs.Image, attrs.Image != ""
+	case "image_name":
+		return attrs.ImageName, attrs.ImageName != ""
+	case "image_tag":
+		return attrs.ImageTag, attrs.ImageTag != ""
+	case "namespace":
+		return attrs.Namespace, attrs.Namespace != ""
+	case "container.label":
+		v, ok := attrs.ContainerLabels[n.key]
+		return v, ok
+	case "pod.label":
+		v, ok := attrs.PodLabels[n.key]
+		return v, ok
+	case "pod.annotation":
+		v, ok := attrs.PodAnnotations[n.key]
+		return v, ok
+	case "env":
+		v, ok := attrs.Env[n.key]
+		return v, ok
+	}
+	return "", false
+}
+
+// parseField splits a field into its name and its key, for the fields that
+// take one.
+func parseField(s string) (string, string, error) {
+	switch s {
+	case "image", "image_name", "image_tag", "namespace":
+		return s, "", nil
+	}
+	for _, field := range []string{"container.label", "pod.label", "pod.annotation", "env"} {
+		if s == field || s == field+"." {
+			return "", "", fmt.Errorf("field %q needs a key, like %s.<key>", field, field)
+		}
+		if key, found := strings.CutPrefix(s, field+"."); found {
+			return field, key, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown field %q", s)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOr) {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenAnd) {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().is(tokenNot) {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next().is(tokenRParen) {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return n, nil
+	case tokenWord:
+		return p.parseComparison(t.value)
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q", t.value)
+}
+
+func (p *parser) parseComparison(fieldToken string) (node, error) {
+	field, key, err := parseField(fieldToken)
+	if err != nil {
+		return nil, err
+	}
+	n := comparisonNode{field: field, key: key}
+
+	t := p.next()
+	switch {
+	case t.is(tokenEqual), t.is(tokenNotEqual):
+		n.op = opEqual
+		if t.is(tokenNotEqual) {
+			n.op = opNotEqual
+		}
+		value := p.next()
+		if !value.isValue() {
+			return nil, fmt.Errorf("missing value after %s %s", fieldToken, t.value)
+		}
+		n.values = []string{value.value}
+	case t.isKeyword("in"), t.isKeyword("notin"):
+		n.op = opIn
+		if t.isKeyword("notin") {
+			n.op = opNotIn
+		}
+		if n.values, err = p.parseList(); err != nil {
+			return nil, err
+		}
+	case t.isKeyword("exists"):
+		n.op = opExists
+	default:
+		return nil, fmt.Errorf("missing operator after %s", fieldToken)
+	}
+	return n, nil
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if !p.next().is(tokenLParen) {
+		return nil, fmt.Errorf("expected a list of values in parentheses")
+	}
+	var values []string
+	for {
+		value := p.next()
+		if !value.isValue() {
+			return nil, fmt.Errorf("expected a value in list")
+		}
+		values = append(values, value.value)
+
+		switch t := p.next(); {
+		case t.is(tokenRParen):
+			return values, nil
+		case t.is(tokenComma):
+		default:
+			return nil, fmt.Errorf("expected ',' or ')' in list")
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	attrs := Attributes{
+		Image:     "redis",
+		ImageName: "docker.io/library/redis",
+		ImageTag:  "7.2",
+		Namespace: "prod",
+		ContainerLabels: map[string]string{
+			"tier":                "web",
+			"com.example.version": "1.2",
+		},
+		PodLabels: map[string]string{
+			"tier":                   "cache",
+			"app.kubernetes.io/team": "storage",
+		},
+		PodAnnotations: map[string]string{
+			"example.com/owner": "team a",
+		},
+		Env: map[string]string{
+			"DD_ENV": "prod",
+		},
+	}
+
+	for _, tc := range []struct {
+		expr     string
+		expected bool
+	}{
+		{"image == redis AND namespace in (prod, staging) AND pod.label.tier == cache", true},
+		{"image == redis && namespace in (dev, staging)", false},
+		{"image = redis", true},
+		{"image_name == docker.io/library/redis", true},
+		{"image_tag != 7.2", false},
+		{"image_tag notin (latest, 6.0)", true},
+		{"pod.label.app.kubernetes.io/team == storage", true},
+		{`pod.annotation.example.com/owner == "team a"`, true},
+		{"pod.annotation.example.com/owner == 'team b'", false},
+		{"env.DD_ENV == prod", true},
+		{"env.DD_SERVICE exists", false},
+		{"NOT env.DD_SERVICE exists", true},
+		{"pod.label.missing != value", true},
+		{"pod.label.missing in (value)", false},
+		{"pod.label.missing notin (value)", true},
+		{"namespace == dev OR pod.label.tier == cache", true},
+		{"namespace == dev or pod.label.tier == cache and image == nginx", false},
+		{"(namespace == dev or pod.label.tier == cache) and image == redis", true},
+		{"!(namespace == prod)", false},
+		{"container.label.tier == web AND pod.label.tier == cache", true},
+		{"container.label.tier == cache", false},
+		{"container.label.com.example.version == 1.2", true},
+		{"pod.label.com.example.version exists", false},
+	} {
+		sel, err := Parse(tc.expr)
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, tc.expected, sel.Matches(attrs), tc.expr)
+	}
+}
+
+func TestSelectorMissingAttributes(t *testing.T) {
+	sel, err := Parse("namespace != prod")
+	require.NoError(t, err)
+	assert.True(t, sel.Matches(Attributes{}))
+
+	sel, err = Parse("pod.label.tier == cache")
+	require.NoError(t, err)
+	assert.False(t, sel.Matches(Attributes{}))
+}
+
+func TestSelectorParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"image",
+		"image ==",
+		"image == redis AND",
+		"image == redis namespace == prod",
+		"unknown == value",
+		"label == value",
+		"pod.label. == value",
+		"namespace in prod",
+		"namespace in (prod",
+		"namespace in (prod staging)",
+		"(image == redis",
+		"image == 'redis",
+		"image & redis",
+		"image == redis)",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -23,6 +23,32 @@ type ConfigParams struct {
 	RemoveLinebreak  bool
 	RunPath          string
 	AuditFileMaxSize int
+	// BuiltinBackends enables the backends resolving the handles prefixed
+	// with their name, such as "ENC[env:NAME]", in-process
+	BuiltinBackends bool
+	Vault           VaultParams
+}
+
+// VaultParams holds the parameters of the built-in Vault backend
+type VaultParams struct {
+	Address   string
+	Namespace string
+	// AuthMethod is one of "token", "approle" or "kubernetes"
+	AuthMethod          string
+	Token               string
+	TokenFile           string
+	AppRoleMountPath    string
+	RoleID              string
+	SecretID            string
+	SecretIDFile        string
+	KubernetesMountPath string
+	KubernetesRole      string
+	KubernetesTokenFile string
+	// KVVersion is the version of the KV secrets engine, it is detected
+	// from the mount of the secret when 0
+	KVVersion     int
+	TLSCACert     string
+	TLSSkipVerify bool
 }
 
 // Component is the component type.
@@ -33,6 +59,9 @@ type Component interface {
 	GetDebugInfo(w io.Writer)
 	// Resolve resolves the secrets in the given yaml data by replacing secrets handles by their corresponding secret value
 	Resolve(data []byte, origin string) ([]byte, error)
+	// ResolveWithoutBuiltinBackends resolves the secrets in the given yaml data like Resolve, but never with the
+	// built-in backends. It is used for data that the agent doesn't trust, such as the configs defined by workloads.
+	ResolveWithoutBuiltinBackends(data []byte, origin string) ([]byte, error)
 	// SubscribeToChanges registers a callback to be invoked whenever secrets are resolved or refreshed
 	SubscribeToChanges(callback SecretChangeCallback)
 	// Refresh will resolve secret handles again, notifying any subscribers of changed values
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const builtinBackendSeparator = ":"
+
+// builtinBackend resolves secrets in-process, without calling the
+// secret_backend_command. A backend resolves the handles prefixed with its
+// name, such as 'ENC[env:MY_PASSWORD]'.
+type builtinBackend interface {
+	// fetch resolves the given secret paths, that is the handles without the
+	// backend prefix
+	fetch(ctx context.Context, paths []string) map[string]secrets.SecretVal
+	// describe returns details about the configuration of the backend, shown
+	// in the debug info
+	describe() string
+}
+
+func newBuiltinBackends(params secrets.ConfigParams) map[string]builtinBackend {
+	return map[string]builtinBackend{
+		"env":        &envBackend{},
+		"file":       &fileBackend{},
+		"k8s_secret": newKubernetesBackend(),
+		"vault":      newVaultBackend(params.Vault),
+	}
+}
+
+// builtinBackendFor returns the name of the built-in backend resolving a
+// handle and the path of the secret in this backend, or false if the handle
+// must be resolved by the secret_backend_command
+func (r *secretResolver) builtinBackendFor(handle string) (string, string, bool) {
+	name, path, found := strings.Cut(handle, builtinBackendSeparator)
+	if !found {
+		return "", "", false
+	}
+	if _, ok := r.builtinBackends[name]; !ok {
+		return "", "", false
+	}
+	return name, path, true
+}
+
+// canResolve returns whether a handle is resolved either by a built-in
+// backend, when they are allowed, or by the secret_backend_command
+func (r *secretResolver) canResolve(handle string, allowBuiltinBackends bool) bool {
+	if r.backendCommand != "" {
+		return true
+	}
+	if !allowBuiltinBackends {
+		return false
+	}
+	_, _, ok := r.builtinBackendFor(handle)
+	return ok
+}
+
+// fetchBuiltinSecrets resolves the handles of the built-in backends
+func (r *secretResolver) fetchBuiltinSecrets(handlesByBackend map[string]map[string]string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.backendTimeout)*time.Second)
+	defer cancel()
+
+	res := map[string]string{}
+	for name, handles := range handlesByBackend {
+		paths := make([]string, 0, len(handles))
+		for path := range handles {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		start := time.Now()
+		values := r.builtinBackends[name].fetch(ctx, paths)
+		elapsed := time.Since(start)
+		log.Debugf("built-in secret backend '%s' resolved %d secrets in %s", name, len(paths), elapsed)
+
+		for _, path := range paths {
+			handle := handles[path]
+			v := values[path]
+			if v.ErrorMsg != "" {
+				tlmSecretBackendElapsed.Add(float64(elapsed.Milliseconds()), name, "error")
+				return nil, fmt.Errorf("an error occurred while resolving '%s': %s", handle, v.ErrorMsg)
+			}
+			if r.removeTrailingLinebreak {
+				v.Value = strings.TrimRight(v.Value, "\r\n")
+			}
+			if v.Value == "" {
+				tlmSecretBackendElapsed.Add(float64(elapsed.Milliseconds()), name, "error")
+				return nil, fmt.Errorf("resolved secret for '%s' is empty", handle)
+			}
+			res[handle] = v.Value
+		}
+		tlmSecretBackendElapsed.Add(float64(elapsed.Milliseconds()), name, "0")
+	}
+	return res, nil
+}
+
+type builtinBackendInfo struct {
+	Name        string
+	Description string
+	Handles     int
+}
+
+// getBuiltinBackendsInfo returns the debug info of the built-in backends,
+// sorted by name
+func (r *secretResolver) getBuiltinBackendsInfo() []builtinBackendInfo {
+	infos := make([]builtinBackendInfo, 0, len(r.builtinBackends))
+	for name, backend := range r.builtinBackends {
+		info := builtinBackendInfo{Name: name, Description: backend.describe()}
+		for handle := range r.origin {
+			if backendName, _, ok := r.builtinBackendFor(handle); ok && backendName == name {
+				info.Handles++
+			}
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
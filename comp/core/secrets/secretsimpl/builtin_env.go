@@ -0,0 +1,35 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+)
+
+// envBackend resolves 'ENC[env:NAME]' handles from the environment of the
+// agent
+type envBackend struct{}
+
+func (b *envBackend) fetch(_ context.Context, paths []string) map[string]secrets.SecretVal {
+	res := make(map[string]secrets.SecretVal, len(paths))
+	for _, name := range paths {
+		value, found := os.LookupEnv(name)
+		if !found {
+			res[name] = secrets.SecretVal{ErrorMsg: fmt.Sprintf("environment variable %s is not set", name)}
+			continue
+		}
+		res[name] = secrets.SecretVal{Value: value}
+	}
+	return res
+}
+
+func (b *envBackend) describe() string {
+	return ""
+}
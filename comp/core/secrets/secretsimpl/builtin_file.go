@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/comp/core/secrets/utils"
+)
+
+// fileBackend resolves 'ENC[file:/path/to/secret]' handles by reading the
+// content of the file
+type fileBackend struct{}
+
+func (b *fileBackend) fetch(_ context.Context, paths []string) map[string]secrets.SecretVal {
+	res := make(map[string]secrets.SecretVal, len(paths))
+	for _, path := range paths {
+		res[path] = utils.ReadSecretFile(path)
+	}
+	return res
+}
+
+func (b *fileBackend) describe() string {
+	return ""
+}
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/comp/core/secrets/utils"
+)
+
+const (
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceAccountCAPath    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// kubernetesBackend resolves 'ENC[k8s_secret:namespace/name/key]' handles
+// by reading the secrets from the API server, using the service account of
+// the pod the agent runs in
+type kubernetesBackend struct {
+	// apiServerURL is empty when the agent doesn't run in a pod
+	apiServerURL string
+	tokenPath    string
+	caPath       string
+}
+
+func newKubernetesBackend() *kubernetesBackend {
+	b := &kubernetesBackend{
+		tokenPath: serviceAccountTokenPath,
+		caPath:    serviceAccountCAPath,
+	}
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host != "" && port != "" {
+		b.apiServerURL = "https://" + net.JoinHostPort(host, port)
+	}
+	return b
+}
+
+func (b *kubernetesBackend) fetch(ctx context.Context, paths []string) map[string]secrets.SecretVal {
+	res := make(map[string]secrets.SecretVal, len(paths))
+	if b.apiServerURL == "" {
+		for _, path := range paths {
+			res[path] = secrets.SecretVal{ErrorMsg: "the agent is not running in a Kubernetes pod"}
+		}
+		return res
+	}
+
+	client, err := b.newClient()
+	if err != nil {
+		for _, path := range paths {
+			res[path] = secrets.SecretVal{ErrorMsg: err.Error()}
+		}
+		return res
+	}
+
+	// the secrets are only read once even if several of their keys are used
+	secretsData := map[string]map[string][]byte{}
+	secretsErrors := map[string]error{}
+	getSecretData := func(namespace, name string) (map[string][]byte, error) {
+		secretName := namespace + "/" + name
+		if _, done := secretsErrors[secretName]; !done {
+			secretsData[secretName], secretsErrors[secretName] = b.readSecret(ctx, client, namespace, name)
+		}
+		return secretsData[secretName], secretsErrors[secretName]
+	}
+	for _, path := range paths {
+		res[path] = utils.ReadKubernetesSecret(getSecretData, path)
+	}
+	return res
+}
+
+func (b *kubernetesBackend) describe() string {
+	if b.apiServerURL == "" {
+		return "not running in Kubernetes"
+	}
+	return "API server " + b.apiServerURL
+}
+
+func (b *kubernetesBackend) newClient() (*http.Client, error) {
+	caCert, err := os.ReadFile(b.caPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the service account CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificate found in %s", b.caPath)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}, nil
+}
+
+// readSecret returns the decoded data of a secret
+func (b *kubernetesBackend) readSecret(ctx context.Context, client *http.Client, namespace, name string) (map[string][]byte, error) {
+	// the token is read every time since projected tokens are rotated
+	token, err := os.ReadFile(b.tokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the service account token: %w", err)
+	}
+
+	secretURL := fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s", b.apiServerURL, url.PathEscape(namespace), url.PathEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, SecretBackendOutputMaxSizeDefault))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to read secret %s/%s: the API server returned %s", namespace, name, resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("unable to parse secret %s/%s: %w", namespace, name, err)
+	}
+
+	data := make(map[string][]byte, len(secret.Data))
+	for key, encoded := range secret.Data {
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode key %s of secret %s/%s: %w", key, namespace, name, err)
+		}
+		data[key] = value
+	}
+	return data, nil
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+)
+
+func newFakeAPIServer(t *testing.T) *kubernetesBackend {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sa-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/namespaces/default/secrets/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// "cGFzc3dvcmQx" is "password1"
+		w.Write([]byte(`{"kind":"Secret","data":{"password":"cGFzc3dvcmQx"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.crt")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caCert, 0600))
+	tokenPath := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("sa-token\n"), 0600))
+
+	return &kubernetesBackend{
+		apiServerURL: server.URL,
+		tokenPath:    tokenPath,
+		caPath:       caPath,
+	}
+}
+
+func TestKubernetesBackend(t *testing.T) {
+	backend := newFakeAPIServer(t)
+
+	res := backend.fetch(context.Background(), []string{
+		"default/db/password",
+		"default/db/user",
+		"default/other/password",
+		"default/db",
+	})
+	assert.Equal(t, secrets.SecretVal{Value: "password1"}, res["default/db/password"])
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "key user not found in secret default/db"}, res["default/db/user"])
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "unable to read secret default/other: the API server returned 404 Not Found"}, res["default/other/password"])
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "invalid format. Use: \"namespace/name/key\""}, res["default/db"])
+}
+
+func TestKubernetesBackendOutsideKubernetes(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	backend := newKubernetesBackend()
+
+	res := backend.fetch(context.Background(), []string{"default/db/password"})
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "the agent is not running in a Kubernetes pod"}, res["default/db/password"])
+}
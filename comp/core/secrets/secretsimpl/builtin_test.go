@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+)
+
+var testBuiltinConf = []byte(`instances:
+- password: ENC[env:TEST_SECRET_PASSWORD]
+  token: ENC[file:%s]
+  user: ENC[user]
+`)
+
+func newBuiltinSecretResolver(t *testing.T) *secretResolver {
+	resolver := newEnabledSecretResolver()
+	resolver.Configure(secrets.ConfigParams{
+		BuiltinBackends: true,
+		RunPath:         t.TempDir(),
+	})
+	return resolver
+}
+
+func writeSecretFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestResolveBuiltinBackendsWithCommand(t *testing.T) {
+	t.Setenv("TEST_SECRET_PASSWORD", "password1")
+
+	resolver := newEnabledSecretResolver()
+	resolver.Configure(secrets.ConfigParams{
+		Command:         "some_command",
+		BuiltinBackends: true,
+		RunPath:         t.TempDir(),
+	})
+	var payload struct {
+		Secrets []string `json:"secrets"`
+	}
+	resolver.commandHookFunc = func(input string) ([]byte, error) {
+		require.NoError(t, json.Unmarshal([]byte(input), &payload))
+		return []byte(`{"env:TEST_SECRET_PASSWORD":{"value":"from_command"},"user":{"value":"user1"}}`), nil
+	}
+
+	resolved, err := resolver.Resolve([]byte("password: ENC[env:TEST_SECRET_PASSWORD]\nuser: ENC[user]\n"), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "password: from_command\nuser: user1\n", string(resolved))
+
+	// the secret_backend_command has priority and resolves every handle
+	assert.Empty(t, resolver.builtinBackends)
+	assert.ElementsMatch(t, []string{"env:TEST_SECRET_PASSWORD", "user"}, payload.Secrets)
+}
+
+func TestResolveBuiltinBackendsWithoutCommand(t *testing.T) {
+	t.Setenv("TEST_SECRET_PASSWORD", "password1")
+	tokenPath := writeSecretFile(t, "token1")
+
+	resolver := newBuiltinSecretResolver(t)
+	resolver.commandHookFunc = func(string) ([]byte, error) {
+		t.Fatal("the command must not be called")
+		return nil, nil
+	}
+
+	resolved, err := resolver.Resolve(bytes.ReplaceAll(testBuiltinConf, []byte("%s"), []byte(tokenPath)), "test")
+	require.NoError(t, err)
+	// the handles that aren't resolved by a built-in backend are left as is
+	assert.Equal(t, `instances:
+- password: password1
+  token: token1
+  user: ENC[user]
+`, string(resolved))
+}
+
+func TestResolveWithoutBuiltinBackends(t *testing.T) {
+	t.Setenv("TEST_SECRET_PASSWORD", "password1")
+	resolver := newBuiltinSecretResolver(t)
+
+	conf := []byte("password: ENC[env:TEST_SECRET_PASSWORD]\n")
+	resolved, err := resolver.ResolveWithoutBuiltinBackends(conf, "test")
+	require.NoError(t, err)
+	assert.Equal(t, string(conf), string(resolved))
+
+	// the secrets already resolved for a trusted config aren't leaked from the cache
+	resolved, err = resolver.Resolve(conf, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "password: password1\n", string(resolved))
+
+	resolved, err = resolver.ResolveWithoutBuiltinBackends(conf, "test")
+	require.NoError(t, err)
+	assert.Equal(t, string(conf), string(resolved))
+}
+
+func TestResolveBuiltinBackendsDisabled(t *testing.T) {
+	t.Setenv("TEST_SECRET_PASSWORD", "password1")
+
+	resolver := newEnabledSecretResolver()
+	resolver.Configure(secrets.ConfigParams{Command: "some_command"})
+	resolver.commandHookFunc = func(string) ([]byte, error) {
+		return []byte(`{"env:TEST_SECRET_PASSWORD":{"value":"from_command"}}`), nil
+	}
+
+	resolved, err := resolver.Resolve([]byte("password: ENC[env:TEST_SECRET_PASSWORD]\n"), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "password: from_command\n", string(resolved))
+}
+
+func TestResolveBuiltinBackendsError(t *testing.T) {
+	resolver := newBuiltinSecretResolver(t)
+
+	_, err := resolver.Resolve([]byte("password: ENC[env:TEST_SECRET_UNSET]\n"), "test")
+	assert.EqualError(t, err, "an error occurred while resolving 'env:TEST_SECRET_UNSET': environment variable TEST_SECRET_UNSET is not set")
+
+	_, err = resolver.Resolve([]byte("password: ENC[file:/does/not/exist]\n"), "test")
+	assert.EqualError(t, err, "an error occurred while resolving 'file:/does/not/exist': secret does not exist")
+
+	t.Setenv("TEST_SECRET_EMPTY", "")
+	_, err = resolver.Resolve([]byte("password: ENC[env:TEST_SECRET_EMPTY]\n"), "test")
+	assert.EqualError(t, err, "resolved secret for 'env:TEST_SECRET_EMPTY' is empty")
+}
+
+func TestRefreshBuiltinBackends(t *testing.T) {
+	originalAllowlistPaths := allowlistPaths
+	allowlistPaths = nil
+	defer func() { allowlistPaths = originalAllowlistPaths }()
+
+	t.Setenv("TEST_SECRET_PASSWORD", "password1")
+	resolver := newBuiltinSecretResolver(t)
+
+	var newValues []any
+	resolver.SubscribeToChanges(func(_, _ string, _ []string, _, newValue any) {
+		newValues = append(newValues, newValue)
+	})
+
+	_, err := resolver.Resolve([]byte("password: ENC[env:TEST_SECRET_PASSWORD]\n"), "test")
+	require.NoError(t, err)
+
+	t.Setenv("TEST_SECRET_PASSWORD", "password2")
+	output, err := resolver.Refresh()
+	require.NoError(t, err)
+	assert.Contains(t, output, "- 'env:TEST_SECRET_PASSWORD':\n\tused in 'test' configuration in entry 'password'")
+	assert.Equal(t, []any{"password1", "password2"}, newValues)
+}
+
+func TestDebugInfoBuiltinBackends(t *testing.T) {
+	t.Setenv("TEST_SECRET_PASSWORD", "password1")
+	resolver := newBuiltinSecretResolver(t)
+	resolver.builtinBackends["k8s_secret"] = &kubernetesBackend{}
+	resolver.builtinBackends["vault"] = newVaultBackend(secrets.VaultParams{Address: "https://vault:8200"})
+
+	_, err := resolver.Resolve([]byte("password: ENC[env:TEST_SECRET_PASSWORD]\n"), "test")
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	resolver.GetDebugInfo(&buffer)
+
+	expectedResult := `No secret_backend_command set: only the built-in backends are enabled
+
+=== Built-in backends ===
+- 'env': 1 secrets resolved
+- 'file': 0 secrets resolved
+- 'k8s_secret': 0 secrets resolved (not running in Kubernetes)
+- 'vault': 0 secrets resolved (address https://vault:8200, auth method token)
+
+=== Secrets stats ===
+Number of secrets resolved: 1
+Secrets handle resolved:
+
+- 'env:TEST_SECRET_PASSWORD':
+	used in 'test' configuration in entry 'password'
+`
+	assert.Equal(t, expectedResult, buffer.String())
+}
@@ -0,0 +1,360 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+)
+
+const (
+	vaultAuthToken      = "token"
+	vaultAuthAppRole    = "approle"
+	vaultAuthKubernetes = "kubernetes"
+
+	vaultKeySeparator = "#"
+)
+
+// errVaultForbidden is returned when Vault rejects the token, which is then
+// renewed by logging in again
+var errVaultForbidden = errors.New("permission denied")
+
+// vaultBackend resolves 'ENC[vault:<path>#<key>]' handles by reading the
+// key of a secret stored in a KV secrets engine. Both versions of the engine
+// are supported.
+type vaultBackend struct {
+	params secrets.VaultParams
+
+	m sync.Mutex
+	// token is the client token obtained by logging in, and tokenExpiration
+	// when it must be renewed, which is zero for tokens that don't expire
+	token           string
+	tokenExpiration time.Time
+	// mounts caches the mount of each secret path and the version of its KV
+	// engine
+	mounts map[string]vaultMount
+}
+
+type vaultMount struct {
+	path    string
+	version int
+}
+
+func newVaultBackend(params secrets.VaultParams) *vaultBackend {
+	if params.Address == "" {
+		params.Address = os.Getenv("VAULT_ADDR")
+	}
+	if params.Namespace == "" {
+		params.Namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if params.AuthMethod == "" {
+		params.AuthMethod = vaultAuthToken
+	}
+	if params.AppRoleMountPath == "" {
+		params.AppRoleMountPath = vaultAuthAppRole
+	}
+	if params.KubernetesMountPath == "" {
+		params.KubernetesMountPath = vaultAuthKubernetes
+	}
+	if params.KubernetesTokenFile == "" {
+		params.KubernetesTokenFile = serviceAccountTokenPath
+	}
+	params.Address = strings.TrimSuffix(params.Address, "/")
+	return &vaultBackend{
+		params: params,
+		mounts: map[string]vaultMount{},
+	}
+}
+
+func (b *vaultBackend) fetch(ctx context.Context, paths []string) map[string]secrets.SecretVal {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	res := make(map[string]secrets.SecretVal, len(paths))
+	client, err := b.newClient()
+	if err != nil {
+		for _, path := range paths {
+			res[path] = secrets.SecretVal{ErrorMsg: err.Error()}
+		}
+		return res
+	}
+
+	// the secrets are only read once even if several of their keys are used
+	secretsData := map[string]map[string]interface{}{}
+	secretsErrors := map[string]error{}
+	for _, path := range paths {
+		secretPath, key, found := strings.Cut(path, vaultKeySeparator)
+		if !found || secretPath == "" || key == "" {
+			res[path] = secrets.SecretVal{ErrorMsg: "invalid format. Use: \"<path>#<key>\""}
+			continue
+		}
+
+		if _, done := secretsErrors[secretPath]; !done {
+			secretsData[secretPath], secretsErrors[secretPath] = b.readSecret(ctx, client, secretPath)
+		}
+		if err := secretsErrors[secretPath]; err != nil {
+			res[path] = secrets.SecretVal{ErrorMsg: err.Error()}
+			continue
+		}
+
+		value, ok := secretsData[secretPath][key]
+		if !ok {
+			res[path] = secrets.SecretVal{ErrorMsg: fmt.Sprintf("key %s not found in secret %s", key, secretPath)}
+			continue
+		}
+		if s, ok := value.(string); ok {
+			res[path] = secrets.SecretVal{Value: s}
+		} else {
+			res[path] = secrets.SecretVal{Value: fmt.Sprint(value)}
+		}
+	}
+	return res
+}
+
+func (b *vaultBackend) describe() string {
+	if b.params.Address == "" {
+		return "no address set"
+	}
+	return fmt.Sprintf("address %s, auth method %s", b.params.Address, b.params.AuthMethod)
+}
+
+func (b *vaultBackend) newClient() (*http.Client, error) {
+	if b.params.Address == "" {
+		return nil, errors.New("the address of the Vault server is not set")
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: b.params.TLSSkipVerify}
+	if b.params.TLSCACert != "" {
+		caCert, err := os.ReadFile(b.params.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the Vault CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in %s", b.params.TLSCACert)
+		}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+	}, nil
+}
+
+// readSecret returns the data of a secret, logging in again once if the
+// token was rejected
+func (b *vaultBackend) readSecret(ctx context.Context, client *http.Client, path string) (map[string]interface{}, error) {
+	data, err := b.readSecretWithToken(ctx, client, path)
+	if errors.Is(err, errVaultForbidden) && b.params.AuthMethod != vaultAuthToken {
+		b.token = ""
+		data, err = b.readSecretWithToken(ctx, client, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func (b *vaultBackend) readSecretWithToken(ctx context.Context, client *http.Client, path string) (map[string]interface{}, error) {
+	token, err := b.getToken(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	mount := b.getMount(ctx, client, token, path)
+	apiPath := path
+	if mount.version == 2 {
+		apiPath = mount.path + "data/" + strings.TrimPrefix(path, mount.path)
+	}
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := b.request(ctx, client, http.MethodGet, apiPath, token, nil, &resp); err != nil {
+		return nil, err
+	}
+	if mount.version != 2 {
+		return resp.Data, nil
+	}
+	data, ok := resp.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the secret has no data, it may have been deleted")
+	}
+	return data, nil
+}
+
+// getMount returns the mount of a secret and the version of its KV engine,
+// either from the configuration or detected from the mount. KV version 1 is
+// assumed when it can't be detected, like the Vault CLI does.
+func (b *vaultBackend) getMount(ctx context.Context, client *http.Client, token, path string) vaultMount {
+	if b.params.KVVersion != 0 {
+		mountPath, _, _ := strings.Cut(path, "/")
+		return vaultMount{path: mountPath + "/", version: b.params.KVVersion}
+	}
+	if mount, found := b.mounts[path]; found {
+		return mount
+	}
+
+	mount := vaultMount{version: 1}
+	var resp struct {
+		Data struct {
+			Path    string            `json:"path"`
+			Options map[string]string `json:"options"`
+		} `json:"data"`
+	}
+	if err := b.request(ctx, client, http.MethodGet, "sys/internal/ui/mounts/"+path, token, nil, &resp); err != nil {
+		return mount
+	}
+	mount.path = resp.Data.Path
+	if resp.Data.Options["version"] == "2" {
+		mount.version = 2
+	}
+	b.mounts[path] = mount
+	return mount
+}
+
+// getToken returns the client token, logging in when the auth method
+// requires it and the current token expired
+func (b *vaultBackend) getToken(ctx context.Context, client *http.Client) (string, error) {
+	if b.params.AuthMethod == vaultAuthToken {
+		// the token is read every time since its file may be updated
+		return b.readToken()
+	}
+	if b.token != "" && (b.tokenExpiration.IsZero() || time.Now().Before(b.tokenExpiration)) {
+		return b.token, nil
+	}
+
+	var loginPath string
+	var payload map[string]string
+	switch b.params.AuthMethod {
+	case vaultAuthAppRole:
+		secretID, err := readValueOrFile(b.params.SecretID, b.params.SecretIDFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the AppRole secret ID: %w", err)
+		}
+		loginPath = "auth/" + b.params.AppRoleMountPath + "/login"
+		payload = map[string]string{"role_id": b.params.RoleID, "secret_id": secretID}
+	case vaultAuthKubernetes:
+		jwt, err := os.ReadFile(b.params.KubernetesTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the service account token: %w", err)
+		}
+		loginPath = "auth/" + b.params.KubernetesMountPath + "/login"
+		payload = map[string]string{"role": b.params.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))}
+	default:
+		return "", fmt.Errorf("unknown auth method %q, it must be one of %s, %s or %s", b.params.AuthMethod, vaultAuthToken, vaultAuthAppRole, vaultAuthKubernetes)
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := b.request(ctx, client, http.MethodPost, loginPath, "", payload, &resp); err != nil {
+		return "", fmt.Errorf("unable to log in with the %s auth method: %w", b.params.AuthMethod, err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("no client token returned by the %s auth method", b.params.AuthMethod)
+	}
+	b.token = resp.Auth.ClientToken
+	b.tokenExpiration = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		// renew the token a bit before it expires
+		b.tokenExpiration = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second * 9 / 10)
+	}
+	return b.token, nil
+}
+
+// readToken returns the token of the token auth method
+func (b *vaultBackend) readToken() (string, error) {
+	token, err := readValueOrFile(b.params.Token, b.params.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the Vault token: %w", err)
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if token == "" {
+		return "", errors.New("no Vault token set")
+	}
+	return token, nil
+}
+
+// request sends a request to the Vault API and decodes its response
+func (b *vaultBackend) request(ctx context.Context, client *http.Client, method, path, token string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.params.Address+"/v1/"+path, body)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if b.params.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.params.Namespace)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, SecretBackendOutputMaxSizeDefault))
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusForbidden:
+		return errVaultForbidden
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.New("secret not found")
+	case resp.StatusCode != http.StatusOK:
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(content, &vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			return fmt.Errorf("the Vault server returned %s: %s", resp.Status, strings.Join(vaultErr.Errors, ", "))
+		}
+		return fmt.Errorf("the Vault server returned %s", resp.Status)
+	}
+	return json.Unmarshal(content, out)
+}
+
+// readValueOrFile returns the value if set, or the content of the file
+func readValueOrFile(value, path string) (string, error) {
+	if value != "" || path == "" {
+		return value, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
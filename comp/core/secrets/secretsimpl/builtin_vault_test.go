@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+)
+
+// newFakeVault returns a Vault server with a KV v2 engine mounted at kv/ and a
+// KV v1 engine mounted at secret/, accepting the given client token
+func newFakeVault(t *testing.T, token string, logins *atomic.Int32) *httptest.Server {
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	authenticated := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != token {
+				w.WriteHeader(http.StatusForbidden)
+				writeJSON(w, map[string][]string{"errors": {"permission denied"}})
+				return
+			}
+			handler(w, r)
+		}
+	}
+
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		if payload["role_id"] != "role" || payload["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string][]string{"errors": {"invalid role or secret ID"}})
+			return
+		}
+		logins.Add(1)
+		writeJSON(w, map[string]interface{}{"auth": map[string]interface{}{"client_token": token, "lease_duration": 3600}})
+	})
+	mux.HandleFunc("/v1/sys/internal/ui/mounts/kv/", authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"path": "kv/", "options": map[string]string{"version": "2"}}})
+	}))
+	mux.HandleFunc("/v1/sys/internal/ui/mounts/secret/", authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"path": "secret/", "options": nil}})
+	}))
+	mux.HandleFunc("/v1/kv/data/team/db", authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+			"data":     map[string]interface{}{"password": "pass_v2", "port": 5432},
+			"metadata": map[string]interface{}{"version": 3},
+		}})
+	}))
+	mux.HandleFunc("/v1/secret/team/db", authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"password": "pass_v1"}})
+	}))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVaultBackendToken(t *testing.T) {
+	var logins atomic.Int32
+	server := newFakeVault(t, "client-token", &logins)
+	backend := newVaultBackend(secrets.VaultParams{Address: server.URL, Token: "client-token"})
+
+	res := backend.fetch(context.Background(), []string{
+		"kv/team/db#password",
+		"kv/team/db#port",
+		"secret/team/db#password",
+		"kv/team/db#unknown",
+		"kv/team/db",
+	})
+	assert.Equal(t, map[string]secrets.SecretVal{
+		"kv/team/db#password":     {Value: "pass_v2"},
+		"kv/team/db#port":         {Value: "5432"},
+		"secret/team/db#password": {Value: "pass_v1"},
+		"kv/team/db#unknown":      {ErrorMsg: "key unknown not found in secret kv/team/db"},
+		"kv/team/db":              {ErrorMsg: "invalid format. Use: \"<path>#<key>\""},
+	}, res)
+}
+
+func TestVaultBackendTokenFromEnv(t *testing.T) {
+	var logins atomic.Int32
+	server := newFakeVault(t, "client-token", &logins)
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "client-token")
+	backend := newVaultBackend(secrets.VaultParams{})
+
+	res := backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{Value: "pass_v2"}, res["kv/team/db#password"])
+}
+
+func TestVaultBackendInvalidToken(t *testing.T) {
+	var logins atomic.Int32
+	server := newFakeVault(t, "client-token", &logins)
+	backend := newVaultBackend(secrets.VaultParams{Address: server.URL, Token: "wrong-token", KVVersion: 2})
+
+	res := backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "unable to read secret kv/team/db: permission denied"}, res["kv/team/db#password"])
+}
+
+func TestVaultBackendAppRole(t *testing.T) {
+	var logins atomic.Int32
+	server := newFakeVault(t, "client-token", &logins)
+	backend := newVaultBackend(secrets.VaultParams{
+		Address:      server.URL,
+		AuthMethod:   "approle",
+		RoleID:       "role",
+		SecretIDFile: writeSecretFile(t, "secret\n"),
+		KVVersion:    2,
+	})
+
+	res := backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{Value: "pass_v2"}, res["kv/team/db#password"])
+	res = backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{Value: "pass_v2"}, res["kv/team/db#password"])
+	// the token is reused until it expires
+	assert.EqualValues(t, 1, logins.Load())
+
+	// a rejected token is renewed by logging in again
+	backend.token = "revoked-token"
+	res = backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{Value: "pass_v2"}, res["kv/team/db#password"])
+	assert.EqualValues(t, 2, logins.Load())
+}
+
+func TestVaultBackendAppRoleLoginError(t *testing.T) {
+	var logins atomic.Int32
+	server := newFakeVault(t, "client-token", &logins)
+	backend := newVaultBackend(secrets.VaultParams{
+		Address:    server.URL,
+		AuthMethod: "approle",
+		RoleID:     "role",
+		SecretID:   "wrong",
+	})
+
+	res := backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "unable to read secret kv/team/db: unable to log in with the approle auth method: the Vault server returned 400 Bad Request: invalid role or secret ID"}, res["kv/team/db#password"])
+}
+
+func TestVaultBackendNoAddress(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	backend := newVaultBackend(secrets.VaultParams{Token: "client-token"})
+
+	res := backend.fetch(context.Background(), []string{"kv/team/db#password"})
+	assert.Equal(t, secrets.SecretVal{ErrorMsg: "the address of the Vault server is not set"}, res["kv/team/db#password"])
+}
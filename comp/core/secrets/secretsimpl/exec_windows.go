@@ -16,6 +16,7 @@ import (
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/svc/mgr"
 
+	"github.com/DataDog/datadog-agent/comp/core/secrets/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/winutil"
 )
 
@@ -25,7 +26,7 @@ const ddAgentServiceName = "datadogagent"
 func commandContext(ctx context.Context, name string, arg ...string) (*exec.Cmd, func(), error) {
 	cmd := exec.CommandContext(ctx, name, arg...)
 	done := func() {}
-	localSystem, err := getLocalSystemSID()
+	localSystem, err := utils.GetLocalSystemSID()
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not query Local System SID: %s", err)
 	}
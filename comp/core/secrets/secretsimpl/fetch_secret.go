@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/comp/core/secrets/utils"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -53,7 +54,7 @@ func (r *secretResolver) execCommand(inputPayload string) ([]byte, error) {
 	}
 	defer done()
 
-	if err := checkRights(cmd.Path, r.commandAllowGroupExec); err != nil {
+	if err := utils.CheckRights(cmd.Path, r.commandAllowGroupExec); err != nil {
 		return nil, err
 	}
 
@@ -107,9 +108,32 @@ func (r *secretResolver) execCommand(inputPayload string) ([]byte, error) {
 	return stdout.buf.Bytes(), nil
 }
 
-// fetchSecret receives a list of secrets name to fetch, exec a custom
-// executable to fetch the actual secrets and returns them.
+// fetchSecret receives a list of secrets name to fetch, resolves them with the
+// built-in backends when they are enabled or exec a custom executable to fetch
+// them otherwise, and returns them.
 func (r *secretResolver) fetchSecret(secretsHandle []string) (map[string]string, error) {
+	if len(r.builtinBackends) == 0 {
+		return r.fetchSecretFromCommand(secretsHandle)
+	}
+
+	handlesByBackend := map[string]map[string]string{}
+	for _, handle := range secretsHandle {
+		name, path, ok := r.builtinBackendFor(handle)
+		if !ok {
+			// canResolve only lets the handles of the built-in backends through
+			return nil, fmt.Errorf("no built-in backend resolves the secret handle '%s'", handle)
+		}
+		if handlesByBackend[name] == nil {
+			handlesByBackend[name] = map[string]string{}
+		}
+		handlesByBackend[name][path] = handle
+	}
+	return r.fetchBuiltinSecrets(handlesByBackend)
+}
+
+// fetchSecretFromCommand exec the secret_backend_command to fetch the given
+// secrets and returns them.
+func (r *secretResolver) fetchSecretFromCommand(secretsHandle []string) (map[string]string, error) {
 	payload := map[string]interface{}{
 		"version": secrets.PayloadVersion,
 		"secrets": secretsHandle,
@@ -28,6 +28,7 @@ import (
 
 	flaretypes "github.com/DataDog/datadog-agent/comp/core/flare/types"
 	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/comp/core/secrets/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
@@ -78,6 +79,8 @@ type secretResolver struct {
 	backendTimeout          int
 	commandAllowGroupExec   bool
 	removeTrailingLinebreak bool
+	// builtinBackends resolve in-process the handles prefixed with their name
+	builtinBackends map[string]builtinBackend
 	// responseMaxSize defines max size of the JSON output from a secrets reader backend
 	responseMaxSize int
 	// refresh secrets at a regular interval
@@ -188,6 +191,15 @@ func (r *secretResolver) Configure(params secrets.ConfigParams) {
 	if r.auditFileMaxSize == 0 {
 		r.auditFileMaxSize = SecretAuditFileMaxSizeDefault
 	}
+	if params.BuiltinBackends {
+		// the built-in backends would take over the handles that the existing
+		// secret_backend_command resolves, the command has priority
+		if r.backendCommand != "" {
+			log.Warnf("'secret_backend_builtin_enabled' is ignored since 'secret_backend_command' is set")
+		} else {
+			r.builtinBackends = newBuiltinBackends(params)
+		}
+	}
 }
 
 func isEnc(str string) (bool, string) {
@@ -226,6 +238,16 @@ func (r *secretResolver) SubscribeToChanges(cb secrets.SecretChangeCallback) {
 // Resolve replaces all encoded secrets in data by executing "secret_backend_command" once if all secrets aren't
 // present in the cache.
 func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
+	return r.resolve(data, origin, true)
+}
+
+// ResolveWithoutBuiltinBackends replaces the encoded secrets in data like
+// Resolve, but leaves the handles of the built-in backends untouched.
+func (r *secretResolver) ResolveWithoutBuiltinBackends(data []byte, origin string) ([]byte, error) {
+	return r.resolve(data, origin, false)
+}
+
+func (r *secretResolver) resolve(data []byte, origin string, allowBuiltinBackends bool) ([]byte, error) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
@@ -233,7 +255,7 @@ func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
 		log.Infof("Agent secrets is disabled by caller")
 		return nil, nil
 	}
-	if data == nil || r.backendCommand == "" {
+	if data == nil || (r.backendCommand == "" && (!allowBuiltinBackends || len(r.builtinBackends) == 0)) {
 		return data, nil
 	}
 
@@ -249,7 +271,7 @@ func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
 
 	w := &walker{
 		resolver: func(path []string, value string) (string, error) {
-			if ok, handle := isEnc(value); ok {
+			if ok, handle := isEnc(value); ok && r.canResolve(handle, allowBuiltinBackends) {
 				// Check if we already know this secret
 				if secretValue, ok := r.cache[handle]; ok {
 					log.Debugf("Secret '%s' was retrieved from cache", handle)
@@ -297,7 +319,7 @@ func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
 		}
 
 		w.resolver = func(path []string, value string) (string, error) {
-			if ok, handle := isEnc(value); ok {
+			if ok, handle := isEnc(value); ok && r.canResolve(handle, allowBuiltinBackends) {
 				if secretValue, ok := secretResponse[handle]; ok {
 					log.Debugf("Secret '%s' was successfully resolved", handle)
 					// keep track of place where a handle was found
@@ -513,6 +535,7 @@ type secretInfo struct {
 	ExecutablePermissions        string
 	ExecutablePermissionsDetails interface{}
 	ExecutablePermissionsError   string
+	BuiltinBackends              []builtinBackendInfo
 	Handles                      map[string][][]string
 }
 
@@ -542,7 +565,7 @@ func (r *secretResolver) GetDebugInfo(w io.Writer) {
 		fmt.Fprintf(w, "Agent secrets is disabled by caller")
 		return
 	}
-	if r.backendCommand == "" {
+	if r.backendCommand == "" && len(r.builtinBackends) == 0 {
 		fmt.Fprintf(w, "No secret_backend_command set: secrets feature is not enabled")
 		return
 	}
@@ -560,22 +583,22 @@ func (r *secretResolver) GetDebugInfo(w io.Writer) {
 		return
 	}
 
-	err = checkRights(r.backendCommand, r.commandAllowGroupExec)
-
-	permissions := "OK, the executable has the correct permissions"
-	if err != nil {
-		permissions = fmt.Sprintf("error: %s", err)
-	}
-
-	details, err := r.getExecutablePermissions()
 	info := secretInfo{
-		Executable:                   r.backendCommand,
-		ExecutablePermissions:        permissions,
-		ExecutablePermissionsDetails: details,
-		Handles:                      map[string][][]string{},
-	}
-	if err != nil {
-		info.ExecutablePermissionsError = err.Error()
+		Executable:      r.backendCommand,
+		BuiltinBackends: r.getBuiltinBackendsInfo(),
+		Handles:         map[string][][]string{},
+	}
+	if r.backendCommand != "" {
+		info.ExecutablePermissions = "OK, the executable has the correct permissions"
+		if err := utils.CheckRights(r.backendCommand, r.commandAllowGroupExec); err != nil {
+			info.ExecutablePermissions = fmt.Sprintf("error: %s", err)
+		}
+
+		details, err := r.getExecutablePermissions()
+		info.ExecutablePermissionsDetails = details
+		if err != nil {
+			info.ExecutablePermissionsError = err.Error()
+		}
 	}
 
 	// we sort handles so the output is consistent and testable
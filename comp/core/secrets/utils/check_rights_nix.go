@@ -5,7 +5,9 @@
 
 //go:build !windows
 
-package secretsimpl
+// Package utils holds the helpers shared by the secrets implementation and
+// the other features running user-provided executables.
+package utils
 
 import (
 	"fmt"
@@ -13,7 +15,9 @@ import (
 	"syscall"
 )
 
-func checkRights(path string, allowGroupExec bool) error {
+// CheckRights checks that the given executable can only be modified and run
+// by the user running the agent (and, if allowGroupExec is set, by its group).
+func CheckRights(path string, allowGroupExec bool) error {
 	var stat syscall.Stat_t
 	if err := syscall.Stat(path, &stat); err != nil {
 		return fmt.Errorf("invalid executable '%s': can't stat it: %s", path, err)
@@ -5,7 +5,7 @@
 
 //go:build windows
 
-package secretsimpl
+package utils
 
 import (
 	"fmt"
@@ -18,9 +18,9 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/winutil"
 )
 
-// checkRights check that the given filename has access controls set only for
+// CheckRights check that the given filename has access controls set only for
 // Administrator, Local System and the datadog user.
-func checkRights(filename string, allowGroupExec bool) error {
+func CheckRights(filename string, allowGroupExec bool) error {
 	// this function ignore `allowGroupExec` since it was design for the cluster-agent,
 	// but the cluster-agent is not delivered for windows.
 	if allowGroupExec {
@@ -46,7 +46,7 @@ func checkRights(filename string, allowGroupExec bool) error {
 
 	// create the sids that are acceptable to us (local system account and
 	// administrators group)
-	localSystem, err := getLocalSystemSID()
+	localSystem, err := GetLocalSystemSID()
 	if err != nil {
 		return fmt.Errorf("could not query Local System SID: %s", err)
 	}
@@ -115,8 +115,8 @@ func getACL(filename string) (*winutil.ACL, error) {
 	return fileDacl, err
 }
 
-// getLocalSystemSID returns the SID of the Local System account
-func getLocalSystemSID() (*windows.SID, error) {
+// GetLocalSystemSID returns the SID of the Local System account
+func GetLocalSystemSID() (*windows.SID, error) {
 	var localSystem *windows.SID
 	err := windows.AllocateAndInitializeSid(&windows.SECURITY_NT_AUTHORITY,
 		1, // local system has 1 valid subauth
@@ -141,7 +141,7 @@ func getAdministratorsSID() (*windows.SID, error) {
 
 // getSecretUserSID returns the SID of the user running the secret backend
 func getSecretUserSID() (*windows.SID, error) {
-	localSystem, err := getLocalSystemSID()
+	localSystem, err := GetLocalSystemSID()
 	if err != nil {
 		return nil, fmt.Errorf("could not query Local System SID: %s", err)
 	}
@@ -160,7 +160,7 @@ func getSecretUserSID() (*windows.SID, error) {
 	}
 
 	if elevated || currentUser.Equals(localSystem) {
-		ddUser, err := getDDAgentUserSID()
+		ddUser, err := GetDDAgentUserSID()
 		if err != nil {
 			return nil, fmt.Errorf("could not resolve SID for ddagentuser user: %s", err)
 		}
@@ -169,8 +169,9 @@ func getSecretUserSID() (*windows.SID, error) {
 	return secretUser, nil
 }
 
-// getDDAgentUserSID returns the SID of the ddagentuser configured at installation time
-var getDDAgentUserSID = func() (*windows.SID, error) {
+// GetDDAgentUserSID returns the SID of the ddagentuser configured at installation time.
+// It can be overridden in tests.
+var GetDDAgentUserSID = func() (*windows.SID, error) {
 	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Datadog\Datadog Agent`, registry.QUERY_VALUE)
 	if err != nil {
 		return nil, fmt.Errorf("could not open installer registry key: %s", err)
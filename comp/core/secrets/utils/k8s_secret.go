@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+)
+
+// GetKubernetesSecretData returns the data of the secret with the given
+// namespace and name
+type GetKubernetesSecretData func(namespace, name string) (map[string][]byte, error)
+
+// ReadKubernetesSecret reads a key of a secret stored in Kubernetes. The path
+// has the "namespace/name/key" format.
+func ReadKubernetesSecret(getSecretData GetKubernetesSecretData, path string) secrets.SecretVal {
+	splitName := strings.Split(path, "/")
+
+	if len(splitName) != 3 {
+		return secrets.SecretVal{ErrorMsg: "invalid format. Use: \"namespace/name/key\""}
+	}
+
+	namespace, name, key := splitName[0], splitName[1], splitName[2]
+
+	data, err := getSecretData(namespace, name)
+	if err != nil {
+		return secrets.SecretVal{ErrorMsg: err.Error()}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return secrets.SecretVal{ErrorMsg: fmt.Sprintf("key %s not found in secret %s/%s", key, namespace, name)}
+	}
+
+	return secrets.SecretVal{Value: string(value)}
+}
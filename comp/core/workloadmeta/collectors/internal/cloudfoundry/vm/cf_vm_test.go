@@ -206,6 +206,10 @@ func (f *FakeDCAClient) PostLanguageMetadata(_ context.Context, _ *pbgo.ParentLa
 	panic("implement me")
 }
 
+func (f *FakeDCAClient) PostLocalExternalMetrics(_ context.Context, _ *apiv1.LocalExternalMetricsPayload) error {
+	panic("implement me")
+}
+
 func TestStartError(t *testing.T) {
 	fakeGardenUtil := FakeGardenUtil{}
 
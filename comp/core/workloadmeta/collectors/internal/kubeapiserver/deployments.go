@@ -76,7 +76,17 @@ func updateContainerLanguage(cl languagedetectionUtil.ContainersLanguages, conta
 	}
 
 	for _, lang := range strings.Split(languages, ",") {
-		cl[container][languagedetectionUtil.Language(strings.TrimSpace(lang))] = struct{}{}
+		cl[container].Add(languagedetectionUtil.Language(strings.TrimSpace(lang)))
+	}
+}
+
+func updateContainerLanguageVersions(cl languagedetectionUtil.ContainersLanguages, container languagedetectionUtil.Container, versions string) {
+	if _, found := cl[container]; !found {
+		cl[container] = make(languagedetectionUtil.LanguageSet)
+	}
+
+	for lang, version := range languagedetectionUtil.ParseLanguageVersions(versions) {
+		cl[container].AddWithVersion(lang, version)
 	}
 }
 
@@ -97,6 +107,17 @@ func (p deploymentParser) Parse(obj interface{}) workloadmeta.Entity {
 				},
 				languages)
 		}
+
+		containerName, isInitContainer = languagedetectionUtil.ExtractContainerFromVersionsAnnotationKey(annotation)
+		if containerName != "" && languages != "" {
+			updateContainerLanguageVersions(
+				containerLanguages,
+				languagedetectionUtil.Container{
+					Name: containerName,
+					Init: isInitContainer,
+				},
+				languages)
+		}
 	}
 
 	return &workloadmeta.KubernetesDeployment{
@@ -127,6 +127,32 @@ func TestDeploymentParser_Parse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "languages with versions",
+			expected: &workloadmeta.KubernetesDeployment{
+				EntityID: workloadmeta.EntityID{
+					Kind: workloadmeta.KindKubernetesDeployment,
+					ID:   "test-namespace/test-deployment",
+				},
+				InjectableLanguages: langUtil.ContainersLanguages{
+					*langUtil.NewContainer("nginx-cont"): {
+						langUtil.Language(languagemodels.Go):     {Version: "go1.21.3"},
+						langUtil.Language(languagemodels.Java):   {},
+						langUtil.Language(languagemodels.Python): {Version: "3.11.4"},
+					},
+				},
+			},
+			deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment",
+					Namespace: "test-namespace",
+					Annotations: map[string]string{
+						"internal.dd.datadoghq.com/nginx-cont.detected_langs":         "go,java,python",
+						"internal.dd.datadoghq.com/nginx-cont.detected_lang_versions": "go:go1.21.3, python:3.11.4",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
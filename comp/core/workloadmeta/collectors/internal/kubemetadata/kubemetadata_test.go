@@ -120,6 +120,10 @@ func (f *FakeDCAClient) PostLanguageMetadata(_ context.Context, _ *pbgo.ParentLa
 	panic("implement me")
 }
 
+func (f *FakeDCAClient) PostLocalExternalMetrics(_ context.Context, _ *apiv1.LocalExternalMetricsPayload) error {
+	panic("implement me")
+}
+
 func TestKubeMetadataCollector_getMetadata(t *testing.T) {
 	type fields struct {
 		dcaClient           clusteragent.DCAClientInterface
@@ -36,7 +36,8 @@ func toLanguage(proto *pbgo.Language) *languagemodels.Language {
 		return nil
 	}
 	return &languagemodels.Language{
-		Name: languagemodels.LanguageName(proto.GetName()),
+		Name:    languagemodels.LanguageName(proto.GetName()),
+		Version: proto.GetVersion(),
 	}
 }
 
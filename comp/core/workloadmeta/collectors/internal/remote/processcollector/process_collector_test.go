@@ -89,7 +89,7 @@ func TestCollection(t *testing.T) {
 							Pid:          123,
 							Nspid:        345,
 							ContainerID:  "cid",
-							Language:     &pbgo.Language{Name: string(languagemodels.Java)},
+							Language:     &pbgo.Language{Name: string(languagemodels.Java), Version: "17.0.8"},
 							CreationTime: creationTime,
 						},
 					},
@@ -104,7 +104,7 @@ func TestCollection(t *testing.T) {
 					},
 					NsPid:        345,
 					ContainerID:  "cid",
-					Language:     &languagemodels.Language{Name: languagemodels.Java},
+					Language:     &languagemodels.Language{Name: languagemodels.Java, Version: "17.0.8"},
 					CreationTime: time.UnixMilli(creationTime),
 				},
 			},
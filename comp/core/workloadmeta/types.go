@@ -873,12 +873,15 @@ func (d KubernetesDeployment) String(verbose bool) string {
 		for container, languages := range containersLanguages {
 			var langSb strings.Builder
 
-			for lang := range languages {
+			for lang, details := range languages {
 
 				if langSb.Len() != 0 {
 					_, _ = langSb.WriteString(",")
 				}
 				_, _ = langSb.WriteString(string(lang))
+				if details.Version != "" {
+					_, _ = langSb.WriteString(":" + details.Version)
+				}
 			}
 
 			if container.Init {
@@ -1212,6 +1215,9 @@ func (p Process) String(verbose bool) string { //nolint:revive // TODO fix reviv
 	_, _ = fmt.Fprintln(&sb, "Container ID:", p.ContainerID)
 	_, _ = fmt.Fprintln(&sb, "Creation time:", p.CreationTime)
 	_, _ = fmt.Fprintln(&sb, "Language:", p.Language.Name)
+	if p.Language.Version != "" {
+		_, _ = fmt.Fprintln(&sb, "Language version:", p.Language.Version)
+	}
 
 	return sb.String()
 }
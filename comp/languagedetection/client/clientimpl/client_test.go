@@ -306,7 +306,8 @@ func TestClientProcessEvent_EveryEntityStored(t *testing.T) {
 			ID:   "1234",
 		},
 		Language: &languagemodels.Language{
-			Name: "go",
+			Name:    "go",
+			Version: "go1.21.3",
 		},
 		ContainerID: initContainer.ID,
 	}
@@ -374,7 +375,7 @@ func TestClientProcessEvent_EveryEntityStored(t *testing.T) {
 						Name: "nginx-cont-name",
 						Init: true,
 					}: {
-						"go": {},
+						"go": {Version: "go1.21.3"},
 					},
 				},
 				ownerRef: &workloadmeta.KubernetesPodOwner{
@@ -79,6 +79,9 @@ func newRemoteConfigService(deps dependencies) (rcservice.Component, error) {
 		remoteconfig.WithDirectorRootOverride(deps.Cfg.GetString("site"), deps.Cfg.GetString("remote_configuration.director_root")),
 		remoteconfig.WithRcKey(deps.Cfg.GetString("remote_configuration.key")),
 	}
+	if localRepository := deps.Cfg.GetString("remote_configuration.local_repository"); localRepository != "" {
+		options = append(options, remoteconfig.WithLocalRepository(localRepository))
+	}
 	if deps.Params != nil {
 		options = append(options, deps.Params.Options...)
 	}
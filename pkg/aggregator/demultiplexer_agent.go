@@ -77,6 +77,19 @@ type AgentDemultiplexerOptions struct {
 
 	UseDogstatsdContextLimiter bool
 	DogstatsdMaxMetricsTags    int
+
+	// SeriesForwarder is nil unless some series are forwarded to another destination than the intake
+	SeriesForwarder SeriesForwarder
+}
+
+// SeriesForwarder forwards some of the series flushed by the AgentDemultiplexer
+// to another destination than the intake
+type SeriesForwarder interface {
+	// Sink wraps the serializer sink of a flush. The returned function is called
+	// once all the series of the flush have been appended.
+	Sink(metrics.SerieSink) (metrics.SerieSink, func())
+	Start()
+	Stop()
 }
 
 // DefaultAgentDemultiplexerOptions returns the default options to initialize an AgentDemultiplexer.
@@ -111,6 +124,9 @@ type dataOutputs struct {
 	forwarders       forwarders
 	sharedSerializer serializer.MetricSerializer
 	noAggSerializer  serializer.MetricSerializer
+
+	// seriesForwarder is nil unless series are forwarded to another destination than the intake
+	seriesForwarder SeriesForwarder
 }
 
 // InitAndStartAgentDemultiplexer creates a new Demultiplexer and runs what's necessary
@@ -206,6 +222,8 @@ func initAgentDemultiplexer(
 
 			sharedSerializer: sharedSerializer,
 			noAggSerializer:  noAggSerializer,
+
+			seriesForwarder: options.SeriesForwarder,
 		},
 
 		senders: newSenders(agg),
@@ -267,6 +285,11 @@ func (d *AgentDemultiplexer) run() {
 			d.log.Debug("not starting the container lifecycle forwarder")
 		}
 
+		// series forwarder
+		if d.seriesForwarder != nil {
+			d.seriesForwarder.Start()
+		}
+
 		d.log.Debug("Forwarders started")
 	}
 
@@ -357,6 +380,11 @@ func (d *AgentDemultiplexer) Stop(flush bool) {
 			d.dataOutputs.forwarders.containerLifecycle.Stop()
 			d.dataOutputs.forwarders.containerLifecycle = nil
 		}
+
+		if d.dataOutputs.seriesForwarder != nil {
+			d.dataOutputs.seriesForwarder.Stop()
+			d.dataOutputs.seriesForwarder = nil
+		}
 	}
 
 	// misc
@@ -406,6 +434,15 @@ func (d *AgentDemultiplexer) flushToSerializer(start time.Time, waitForSerialize
 		series,
 		sketches,
 		func(seriesSink metrics.SerieSink, sketchesSink metrics.SketchesSink) {
+			// copy the series forwarded to another destination
+			// --------------------------------------------------
+
+			if d.seriesForwarder != nil {
+				var forward func()
+				seriesSink, forward = d.seriesForwarder.Sink(seriesSink)
+				defer forward()
+			}
+
 			// flush DogStatsD pipelines (statsd/time samplers)
 			// ------------------------------------------------
 
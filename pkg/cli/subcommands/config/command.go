@@ -29,6 +29,9 @@ type cliParams struct {
 	// source enables detailed information about each source and its value
 	source bool
 
+	// skipChecks disables the validation of the checks configuration
+	skipChecks bool
+
 	// args are the positional command line args
 	args []string
 }
@@ -97,6 +100,16 @@ func MakeCommand(globalParamsGetter func() GlobalParams) *cobra.Command {
 	cmd.AddCommand(getCmd)
 	getCmd.Flags().BoolVarP(&cliParams.source, "source", "s", false, "print every source and its value")
 
+	validateCmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Validate a configuration file and the configuration of the checks",
+		Long: `Report the unknown keys, type mismatches and deprecated keys of a configuration file,
+which defaults to the one used by the agent, as well as the invalid check instances found in its confd_path.`,
+		RunE: oneShotRunE(validateConfig),
+	}
+	cmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&cliParams.skipChecks, "skip-checks", false, "do not validate the configuration of the checks")
+
 	return cmd
 }
 
@@ -83,3 +83,21 @@ func TestConfigGetCommand(t *testing.T) {
 			require.Equal(t, false, secretParams.Enabled)
 		})
 }
+
+func TestConfigValidateCommand(t *testing.T) {
+	commands := []*cobra.Command{
+		MakeCommand(func() GlobalParams {
+			return GlobalParams{}
+		}),
+	}
+
+	fxutil.TestOneShotSubcommand(t,
+		commands,
+		[]string{"config", "validate", "/etc/datadog-agent/datadog.yaml", "--skip-checks"},
+		validateConfig,
+		func(cliParams *cliParams, coreParams core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, []string{"/etc/datadog-agent/datadog.yaml"}, cliParams.args)
+			require.Equal(t, true, cliParams.skipChecks)
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
@@ -0,0 +1,274 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/log"
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+)
+
+// checkSpecFile is the example configuration shipped with an integration in
+// its conf.d folder, whose '@param' annotations describe the options of the
+// check
+const checkSpecFile = "conf.yaml.example"
+
+var (
+	specParamPattern   = regexp.MustCompile(`^(\s*)## @param\s+(\S+)\s+-\s+(.*?)\s+-\s+(required|optional)`)
+	specSectionPattern = regexp.MustCompile(`^([a-z_]+):`)
+)
+
+// checkParam is an instance option documented in a check spec
+type checkParam struct {
+	name     string
+	kind     string
+	required bool
+}
+
+// checkSpec holds the instance options documented by a check
+type checkSpec struct {
+	params map[string]checkParam
+}
+
+func validateConfig(_ log.Component, config config.Component, cliParams *cliParams) error {
+	if len(cliParams.args) > 1 {
+		return fmt.Errorf("at most one configuration file can be specified")
+	}
+
+	path := config.ConfigFileUsed()
+	if len(cliParams.args) == 1 {
+		path = cliParams.args[0]
+	}
+	if path == "" {
+		return fmt.Errorf("no configuration file found, specify the path of the file to validate")
+	}
+
+	// the file is read into a fresh config so that only its own settings are
+	// validated, not the ones coming from the environment
+	cfg := pkgconfigmodel.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	pkgconfigsetup.InitConfig(cfg)
+	cfg.SetConfigFile(path)
+	if err := cfg.ReadInConfig(); err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	issues := map[string][]string{}
+	for _, issue := range pkgconfigsetup.ValidateConfig(cfg) {
+		issues[path] = append(issues[path], issue.String())
+	}
+	if !cliParams.skipChecks {
+		for file, fileIssues := range validateChecksConfig(cfg.GetString("confd_path")) {
+			issues[file] = fileIssues
+		}
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("No issue found in %s\n", path)
+		return nil
+	}
+
+	files := make([]string, 0, len(issues))
+	count := 0
+	for file, fileIssues := range issues {
+		files = append(files, file)
+		count += len(fileIssues)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		fmt.Printf("=== %s ===\n", file)
+		for _, issue := range issues[file] {
+			fmt.Printf("  - %s\n", issue)
+		}
+		fmt.Println()
+	}
+	return fmt.Errorf("found %d configuration issues", count)
+}
+
+// validateChecksConfig validates the instances configured in the check
+// folders of confdPath against the spec of their check, and returns the
+// issues found in each file. Checks without a spec aren't validated.
+func validateChecksConfig(confdPath string) map[string][]string {
+	issues := map[string][]string{}
+	checkDirs, _ := filepath.Glob(filepath.Join(confdPath, "*.d"))
+	for _, checkDir := range checkDirs {
+		spec, err := loadCheckSpec(filepath.Join(checkDir, checkSpecFile))
+		if err != nil {
+			continue
+		}
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			files, _ := filepath.Glob(filepath.Join(checkDir, pattern))
+			for _, file := range files {
+				if fileIssues := spec.validateFile(file); len(fileIssues) > 0 {
+					issues[file] = fileIssues
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// loadCheckSpec reads the options of the check instances from the '@param'
+// annotations of its example configuration. Only the top-level options of
+// the instances are kept.
+func loadCheckSpec(path string) (*checkSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var params []checkParam
+	var indents []int
+	minIndent := -1
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := specSectionPattern.FindStringSubmatch(line); match != nil {
+			section = match[1]
+			continue
+		}
+		match := specParamPattern.FindStringSubmatch(line)
+		if match == nil || section != "instances" {
+			continue
+		}
+		indent := len(match[1])
+		if minIndent == -1 || indent < minIndent {
+			minIndent = indent
+		}
+		params = append(params, checkParam{name: match[2], kind: match[3], required: match[4] == "required"})
+		indents = append(indents, indent)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("no instance option documented in %s", path)
+	}
+
+	spec := &checkSpec{params: map[string]checkParam{}}
+	for i, param := range params {
+		if indents[i] == minIndent {
+			spec.params[param.name] = param
+		}
+	}
+	return spec, nil
+}
+
+// validateFile returns the issues found in the instances of a check
+// configuration file
+func (s *checkSpec) validateFile(path string) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var cf struct {
+		Instances []map[string]interface{} `yaml:"instances"`
+	}
+	if err := yaml.Unmarshal(content, &cf); err != nil {
+		return []string{fmt.Sprintf("unable to parse the file: %v", err)}
+	}
+
+	var issues []string
+	for i, instance := range cf.Instances {
+		for _, issue := range s.validateInstance(instance) {
+			issues = append(issues, fmt.Sprintf("instance %d: %s", i+1, issue))
+		}
+	}
+	return issues
+}
+
+// validateInstance returns the unknown options, the missing required options
+// and the options whose value doesn't match their documented type
+func (s *checkSpec) validateInstance(instance map[string]interface{}) []string {
+	candidates := make([]string, 0, len(s.params))
+	for name := range s.params {
+		candidates = append(candidates, name)
+	}
+
+	var issues []string
+	for name, value := range instance {
+		param, known := s.params[name]
+		if !known {
+			if isReservedInstanceParam(name) {
+				continue
+			}
+			issue := fmt.Sprintf("unknown option '%s'", name)
+			if suggestion := pkgconfigsetup.SuggestKey(name, candidates); suggestion != "" {
+				issue += fmt.Sprintf(", did you mean '%s'?", suggestion)
+			}
+			issues = append(issues, issue)
+			continue
+		}
+		if !matchesParamKind(param.kind, value) {
+			issues = append(issues, fmt.Sprintf("option '%s' must be of type '%s', got %v", name, param.kind, value))
+		}
+	}
+	for name, param := range s.params {
+		if _, found := instance[name]; param.required && !found {
+			issues = append(issues, fmt.Sprintf("missing required option '%s'", name))
+		}
+	}
+	sort.Strings(issues)
+	return issues
+}
+
+// isReservedInstanceParam returns whether an option is handled by the agent
+// for every check
+func isReservedInstanceParam(name string) bool {
+	if name == "loader" {
+		return true
+	}
+	t := reflect.TypeOf(integration.CommonInstanceConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("yaml") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesParamKind returns whether a value matches the type documented for an
+// option. Unknown types and template variables, resolved by Autodiscovery,
+// always match.
+func matchesParamKind(kind string, value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if s, ok := value.(string); ok && strings.Contains(s, "%%") {
+		return true
+	}
+
+	valueKind := reflect.TypeOf(value).Kind()
+	switch {
+	case strings.HasPrefix(kind, "list"):
+		return valueKind == reflect.Slice
+	case kind == "mapping" || kind == "object" || kind == "dictionary":
+		return valueKind == reflect.Map
+	case kind == "boolean":
+		return valueKind == reflect.Bool
+	case kind == "integer":
+		return valueKind == reflect.Int
+	case kind == "number":
+		return valueKind == reflect.Int || valueKind == reflect.Float64
+	case kind == "string":
+		return valueKind != reflect.Slice && valueKind != reflect.Map
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCheckSpec = `init_config:
+
+    ## @param global_option - string - optional
+    #
+    # global_option: <VALUE>
+
+instances:
+
+  -
+    ## @param url - string - required
+    ## URL to monitor.
+    #
+    url: <URL>
+
+    ## @param timeout - integer - optional - default: 10
+    #
+    # timeout: 10
+
+    ## @param verify - boolean - optional - default: true
+    #
+    # verify: true
+
+    ## @param headers - mapping - optional
+    #
+    # headers:
+    #
+    #   ## @param nested - string - optional
+    #   #
+    #   nested: <VALUE>
+
+    ## @param tags - list of strings - optional
+    #
+    # tags:
+    #   - <KEY_1>:<VALUE_1>
+`
+
+func writeTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLoadCheckSpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), checkSpecFile)
+	writeTestFile(t, path, testCheckSpec)
+
+	spec, err := loadCheckSpec(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]checkParam{
+		"url":     {name: "url", kind: "string", required: true},
+		"timeout": {name: "timeout", kind: "integer"},
+		"verify":  {name: "verify", kind: "boolean"},
+		"headers": {name: "headers", kind: "mapping"},
+		"tags":    {name: "tags", kind: "list of strings"},
+	}, spec.params)
+}
+
+func TestValidateChecksConfig(t *testing.T) {
+	confdPath := t.TempDir()
+	writeTestFile(t, filepath.Join(confdPath, "http.d", checkSpecFile), testCheckSpec)
+	writeTestFile(t, filepath.Join(confdPath, "http.d", "conf.yaml"), `init_config:
+instances:
+  - url: http://localhost
+    timeout: 5
+    min_collection_interval: 30
+  - url: http://localhost:%%port%%
+    timeotu: 5
+    verify: "no"
+    tags: team:agent
+  - timeout: "%%env_TIMEOUT%%"
+    headers:
+      nested: value
+`)
+	// checks without spec aren't validated
+	writeTestFile(t, filepath.Join(confdPath, "other.d", "conf.yaml"), "instances:\n  - unknown: true\n")
+
+	assert.Equal(t, map[string][]string{
+		filepath.Join(confdPath, "http.d", "conf.yaml"): {
+			"instance 2: option 'tags' must be of type 'list of strings', got team:agent",
+			"instance 2: option 'verify' must be of type 'boolean', got no",
+			"instance 2: unknown option 'timeotu', did you mean 'timeout'?",
+			"instance 3: missing required option 'url'",
+		},
+	}, validateChecksConfig(confdPath))
+}
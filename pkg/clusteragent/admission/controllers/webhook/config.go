@@ -29,6 +29,7 @@ type Config struct {
 	svcPort                  int32
 	timeout                  int32
 	failurePolicy            string
+	validationFailurePolicy  string
 	reinvocationPolicy       string
 }
 
@@ -44,6 +45,7 @@ func NewConfig(admissionV1Enabled, namespaceSelectorEnabled bool) Config {
 		svcPort:                  int32(443),
 		timeout:                  config.Datadog.GetInt32("admission_controller.timeout_seconds"),
 		failurePolicy:            config.Datadog.GetString("admission_controller.failure_policy"),
+		validationFailurePolicy:  config.Datadog.GetString("admission_controller.validation_failure_policy"),
 		reinvocationPolicy:       config.Datadog.GetString("admission_controller.reinvocation_policy"),
 	}
 }
@@ -64,3 +66,9 @@ func (w *Config) configName(suffix string) string {
 	name = strings.ReplaceAll(name, "_", ".")
 	return name
 }
+
+// getValidationFailurePolicy returns the failure policy of the validating
+// webhooks, which is set apart from the one of the mutating webhooks
+func (w *Config) getValidationFailurePolicy() string {
+	return w.validationFailurePolicy
+}
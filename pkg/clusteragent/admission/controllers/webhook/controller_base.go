@@ -29,6 +29,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/config"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/cwsinstrumentation"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/tagsfromlabels"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate/unifiedservicetagging"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -36,12 +37,13 @@ import (
 type Controller interface {
 	Run(stopCh <-chan struct{})
 	EnabledWebhooks() []MutatingWebhook
+	EnabledValidatingWebhooks() []ValidatingWebhook
 }
 
 // NewController returns the adequate implementation of the Controller interface.
 func NewController(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, admissionInterface admissionregistration.Interface, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config, wmeta workloadmeta.Component) Controller {
 	if config.useAdmissionV1() {
-		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config, wmeta)
+		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), admissionInterface.V1().ValidatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config, wmeta)
 	}
 
 	return NewControllerV1beta1(client, secretInformer, admissionInterface.V1beta1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config, wmeta)
@@ -68,6 +70,24 @@ type MutatingWebhook interface {
 	MutateFunc() admission.WebhookFunc
 }
 
+// ValidatingWebhook represents a validating webhook
+type ValidatingWebhook interface {
+	// Name returns the name of the webhook
+	Name() string
+	// IsEnabled returns whether the webhook is enabled
+	IsEnabled() bool
+	// Endpoint returns the endpoint of the webhook
+	Endpoint() string
+	// Rules returns the kubernetes resources and the operations on them for
+	// which the webhook should be invoked
+	Rules() []admiv1.RuleWithOperations
+	// LabelSelectors returns the label selectors that specify when the webhook
+	// should be invoked
+	LabelSelectors(useNamespaceSelector bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector)
+	// ValidateFunc returns the function that validates the resources
+	ValidateFunc() admission.ValidatingWebhookFunc
+}
+
 // mutatingWebhooks returns the list of mutating webhooks. Notice that the order
 // of the webhooks returned is the order in which they will be executed. For
 // now, the only restriction is that the agent sidecar webhook needs to go after
@@ -98,6 +118,16 @@ func mutatingWebhooks(wmeta workloadmeta.Component) []MutatingWebhook {
 	return webhooks
 }
 
+// validatingWebhooks returns the list of validating webhooks.
+func validatingWebhooks() []ValidatingWebhook {
+	var webhooks []ValidatingWebhook
+	if webhook := unifiedservicetagging.NewWebhook(); webhook != nil {
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks
+}
+
 // controllerBase acts as a base class for ControllerV1 and ControllerV1beta1.
 // It contains the shared fields and provides shared methods.
 // For the nolint:structcheck see https://github.com/golangci/golangci-lint/issues/537
@@ -111,6 +141,8 @@ type controllerBase struct {
 	isLeaderFunc     func() bool
 	isLeaderNotif    <-chan struct{}
 	mutatingWebhooks []MutatingWebhook
+	// validatingWebhooks are only supported by ControllerV1
+	validatingWebhooks []ValidatingWebhook
 }
 
 // EnabledWebhooks returns the list of enabled webhooks.
@@ -126,6 +158,19 @@ func (c *controllerBase) EnabledWebhooks() []MutatingWebhook {
 	return res
 }
 
+// EnabledValidatingWebhooks returns the list of enabled validating webhooks.
+func (c *controllerBase) EnabledValidatingWebhooks() []ValidatingWebhook {
+	var res []ValidatingWebhook
+
+	for _, webhook := range c.validatingWebhooks {
+		if webhook.IsEnabled() {
+			res = append(res, webhook)
+		}
+	}
+
+	return res
+}
+
 // enqueueOnLeaderNotif watches leader notifications and triggers a
 // reconciliation in case the current process becomes leader.
 // This ensures that the latest configuration of the leader
@@ -34,12 +34,17 @@ import (
 // It uses the admissionregistration/v1 API.
 type ControllerV1 struct {
 	controllerBase
-	webhooksLister   admissionlisters.MutatingWebhookConfigurationLister
-	webhookTemplates []admiv1.MutatingWebhook
+	webhooksLister             admissionlisters.MutatingWebhookConfigurationLister
+	webhookTemplates           []admiv1.MutatingWebhook
+	validatingWebhooksLister   admissionlisters.ValidatingWebhookConfigurationLister
+	validatingWebhooksSynced   cache.InformerSynced
+	validatingWebhookTemplates []admiv1.ValidatingWebhook
 }
 
 // NewControllerV1 returns a new Webhook Controller using admissionregistration/v1.
-func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config, wmeta workloadmeta.Component) *ControllerV1 {
+// The ValidatingWebhookConfiguration informer is only used when validating
+// webhooks are enabled.
+func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, validatingWebhookInformer admissioninformers.ValidatingWebhookConfigurationInformer, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config, wmeta workloadmeta.Component) *ControllerV1 {
 	controller := &ControllerV1{}
 	controller.clientSet = client
 	controller.config = config
@@ -51,6 +56,7 @@ func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.S
 	controller.isLeaderFunc = isLeaderFunc
 	controller.isLeaderNotif = isLeaderNotif
 	controller.mutatingWebhooks = mutatingWebhooks(wmeta)
+	controller.validatingWebhooks = validatingWebhooks()
 	controller.generateTemplates()
 
 	if _, err := secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -69,6 +75,18 @@ func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.S
 		log.Errorf("cannot add event handler to webhook informer: %v", err)
 	}
 
+	if len(controller.validatingWebhookTemplates) > 0 {
+		controller.validatingWebhooksLister = validatingWebhookInformer.Lister()
+		controller.validatingWebhooksSynced = validatingWebhookInformer.Informer().HasSynced
+		if _, err := validatingWebhookInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.handleWebhook,
+			UpdateFunc: controller.handleValidatingWebhookUpdate,
+			DeleteFunc: controller.handleWebhook,
+		}); err != nil {
+			log.Errorf("cannot add event handler to validating webhook informer: %v", err)
+		}
+	}
+
 	return controller
 }
 
@@ -80,7 +98,11 @@ func (c *ControllerV1) Run(stopCh <-chan struct{}) {
 	log.Infof("Starting webhook controller for secret %s/%s and webhook %s - Using admissionregistration/v1", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 	defer log.Infof("Stopping webhook controller for secret %s/%s and webhook %s", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 
-	if ok := cache.WaitForCacheSync(stopCh, c.secretsSynced, c.webhooksSynced); !ok {
+	synced := []cache.InformerSynced{c.secretsSynced, c.webhooksSynced}
+	if c.validatingWebhooksSynced != nil {
+		synced = append(synced, c.validatingWebhooksSynced)
+	}
+	if ok := cache.WaitForCacheSync(stopCh, synced...); !ok {
 		return
 	}
 
@@ -125,13 +147,52 @@ func (c *ControllerV1) handleWebhookUpdate(oldObj, newObj interface{}) {
 	c.handleWebhook(newObj)
 }
 
-// reconcile creates/updates the webhook object on new events.
+// handleValidatingWebhookUpdate handles the new ValidatingWebhookConfiguration
+// reported in update events.
+// It can be a callback function for update events.
+func (c *ControllerV1) handleValidatingWebhookUpdate(oldObj, newObj interface{}) {
+	if !c.isLeaderFunc() {
+		return
+	}
+
+	newWebhook, ok := newObj.(*admiv1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", newObj)
+		return
+	}
+
+	oldWebhook, ok := oldObj.(*admiv1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", oldObj)
+		return
+	}
+
+	if newWebhook.ResourceVersion == oldWebhook.ResourceVersion {
+		return
+	}
+
+	c.handleWebhook(newObj)
+}
+
+// reconcile creates/updates the webhook objects on new events.
 func (c *ControllerV1) reconcile() error {
 	secret, err := c.getSecret()
 	if err != nil {
 		return err
 	}
 
+	if err := c.reconcileMutatingWebhook(secret); err != nil {
+		return err
+	}
+
+	if len(c.validatingWebhookTemplates) == 0 {
+		return nil
+	}
+
+	return c.reconcileValidatingWebhook(secret)
+}
+
+func (c *ControllerV1) reconcileMutatingWebhook(secret *corev1.Secret) error {
 	webhook, err := c.webhooksLister.Get(c.config.getWebhookName())
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -147,6 +208,22 @@ func (c *ControllerV1) reconcile() error {
 	return c.updateWebhook(secret, webhook)
 }
 
+func (c *ControllerV1) reconcileValidatingWebhook(secret *corev1.Secret) error {
+	webhook, err := c.validatingWebhooksLister.Get(c.config.getWebhookName())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Infof("Validating Webhook %s was not found, creating it", c.config.getWebhookName())
+			return c.createValidatingWebhook(secret)
+		}
+
+		return err
+	}
+
+	log.Debugf("The Validating Webhook %s was found, updating it", c.config.getWebhookName())
+
+	return c.updateValidatingWebhook(secret, webhook)
+}
+
 // createWebhook creates a new MutatingWebhookConfiguration object.
 func (c *ControllerV1) createWebhook(secret *corev1.Secret) error {
 	webhook := &admiv1.MutatingWebhookConfiguration{
@@ -184,6 +261,43 @@ func (c *ControllerV1) newWebhooks(secret *corev1.Secret) []admiv1.MutatingWebho
 	return webhooks
 }
 
+// createValidatingWebhook creates a new ValidatingWebhookConfiguration object.
+func (c *ControllerV1) createValidatingWebhook(secret *corev1.Secret) error {
+	webhook := &admiv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.config.getWebhookName(),
+		},
+		Webhooks: c.newValidatingWebhooks(secret),
+	}
+
+	_, err := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), webhook, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		log.Infof("Validating Webhook %s already exists", webhook.GetName())
+		return nil
+	}
+
+	return err
+}
+
+// updateValidatingWebhook stores a new configuration in the ValidatingWebhookConfiguration object.
+func (c *ControllerV1) updateValidatingWebhook(secret *corev1.Secret, webhook *admiv1.ValidatingWebhookConfiguration) error {
+	webhook = webhook.DeepCopy()
+	webhook.Webhooks = c.newValidatingWebhooks(secret)
+	_, err := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{})
+	return err
+}
+
+// newValidatingWebhooks generates ValidatingWebhook objects from config templates with updated CABundle from Secret.
+func (c *ControllerV1) newValidatingWebhooks(secret *corev1.Secret) []admiv1.ValidatingWebhook {
+	webhooks := []admiv1.ValidatingWebhook{}
+	for _, tpl := range c.validatingWebhookTemplates {
+		tpl.ClientConfig.CABundle = certificate.GetCABundle(secret.Data)
+		webhooks = append(webhooks, tpl)
+	}
+
+	return webhooks
+}
+
 func (c *ControllerV1) generateTemplates() {
 	webhooks := []admiv1.MutatingWebhook{}
 
@@ -208,6 +322,18 @@ func (c *ControllerV1) generateTemplates() {
 	}
 
 	c.webhookTemplates = webhooks
+
+	validatingWebhooks := []admiv1.ValidatingWebhook{}
+	for _, webhook := range c.validatingWebhooks {
+		if !webhook.IsEnabled() {
+			continue
+		}
+
+		nsSelector, objSelector := webhook.LabelSelectors(c.config.useNamespaceSelector())
+		validatingWebhooks = append(validatingWebhooks, c.getValidatingWebhookSkeleton(webhook.Name(), webhook.Endpoint(), webhook.Rules(), nsSelector, objSelector))
+	}
+
+	c.validatingWebhookTemplates = validatingWebhooks
 }
 
 func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string, operations []admiv1.OperationType, resources []string, namespaceSelector, objectSelector *metav1.LabelSelector) admiv1.MutatingWebhook {
@@ -215,7 +341,7 @@ func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string, operations []
 	sideEffects := admiv1.SideEffectClassNone
 	port := c.config.getServicePort()
 	timeout := c.config.getTimeout()
-	failurePolicy := c.getAdmiV1FailurePolicy()
+	failurePolicy := getAdmiV1FailurePolicy(c.config.getFailurePolicy())
 	reinvocationPolicy := c.getReinvocationPolicy()
 	webhook := admiv1.MutatingWebhook{
 		Name: c.config.configName(nameSuffix),
@@ -250,8 +376,36 @@ func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string, operations []
 	return webhook
 }
 
-func (c *ControllerV1) getAdmiV1FailurePolicy() admiv1.FailurePolicyType {
-	policy := strings.ToLower(c.config.getFailurePolicy())
+func (c *ControllerV1) getValidatingWebhookSkeleton(nameSuffix, path string, rules []admiv1.RuleWithOperations, namespaceSelector, objectSelector *metav1.LabelSelector) admiv1.ValidatingWebhook {
+	matchPolicy := admiv1.Exact
+	sideEffects := admiv1.SideEffectClassNone
+	port := c.config.getServicePort()
+	timeout := c.config.getTimeout()
+	failurePolicy := getAdmiV1FailurePolicy(c.config.getValidationFailurePolicy())
+
+	return admiv1.ValidatingWebhook{
+		Name: c.config.configName(nameSuffix),
+		ClientConfig: admiv1.WebhookClientConfig{
+			Service: &admiv1.ServiceReference{
+				Namespace: c.config.getServiceNs(),
+				Name:      c.config.getServiceName(),
+				Port:      &port,
+				Path:      &path,
+			},
+		},
+		Rules:                   rules,
+		FailurePolicy:           &failurePolicy,
+		MatchPolicy:             &matchPolicy,
+		SideEffects:             &sideEffects,
+		TimeoutSeconds:          &timeout,
+		AdmissionReviewVersions: []string{"v1", "v1beta1"},
+		NamespaceSelector:       namespaceSelector,
+		ObjectSelector:          objectSelector,
+	}
+}
+
+func getAdmiV1FailurePolicy(failurePolicy string) admiv1.FailurePolicyType {
+	policy := strings.ToLower(failurePolicy)
 	switch policy {
 	case "ignore":
 		return admiv1.Ignore
@@ -87,6 +87,48 @@ func TestCreateWebhookV1(t *testing.T) {
 	}, waitFor, tick, "Work queue isn't empty")
 }
 
+func TestCreateValidatingWebhookV1(t *testing.T) {
+	mockConfig := config.Mock(t)
+	mockConfig.SetWithoutSource("admission_controller.validate_tags.enabled", true)
+	f := newFixtureV1(t)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1Cfg)
+	f.populateSecretsCache(secret)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c := f.run(stopCh)
+
+	var webhook *admiv1.ValidatingWebhookConfiguration
+	require.Eventually(t, func() bool {
+		webhook, err = c.validatingWebhooksLister.Get(v1Cfg.getWebhookName())
+		return err == nil
+	}, waitFor, tick)
+
+	require.Len(t, webhook.Webhooks, 1)
+	validatingWebhook := webhook.Webhooks[0]
+	assert.Equal(t, "datadog.webhook.validate.tags", validatingWebhook.Name)
+	assert.Equal(t, "/validatetags", *validatingWebhook.ClientConfig.Service.Path)
+	assert.Equal(t, &metav1.LabelSelector{}, validatingWebhook.NamespaceSelector)
+	assert.Equal(t, admiv1.Ignore, *validatingWebhook.FailurePolicy)
+	assert.Equal(t, certificate.GetCABundle(secret.Data), validatingWebhook.ClientConfig.CABundle)
+	assert.Len(t, validatingWebhook.Rules, 3)
+
+	assert.Eventually(t, func() bool {
+		_, err := c.webhooksLister.Get(v1Cfg.getWebhookName())
+		return err == nil
+	}, waitFor, tick, "Mutating webhook isn't created")
+
+	assert.Eventually(t, func() bool {
+		return c.queue.Len() == 0
+	}, waitFor, tick, "Work queue isn't empty")
+}
+
 func TestUpdateOutdatedWebhookV1(t *testing.T) {
 	f := newFixtureV1(t)
 
@@ -1083,6 +1125,7 @@ func (f *fixtureV1) createController() (*ControllerV1, informers.SharedInformerF
 		f.client,
 		factory.Core().V1().Secrets(),
 		factory.Admissionregistration().V1().MutatingWebhookConfigurations(),
+		factory.Admissionregistration().V1().ValidatingWebhookConfigurations(),
 		func() bool { return true },
 		make(chan struct{}),
 		v1Cfg,
@@ -52,6 +52,9 @@ func NewControllerV1beta1(client kubernetes.Interface, secretInformer coreinform
 	controller.isLeaderNotif = isLeaderNotif
 	controller.mutatingWebhooks = mutatingWebhooks(wmeta)
 	controller.generateTemplates()
+	if len(validatingWebhooks()) > 0 {
+		log.Warnf("Validating webhooks are only supported with admissionregistration/v1, they won't be registered")
+	}
 
 	if _, err := secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    controller.handleSecret,
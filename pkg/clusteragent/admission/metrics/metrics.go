@@ -31,6 +31,8 @@ const (
 const (
 	StatusSuccess = "success"
 	StatusError   = "error"
+	StatusValid   = "valid"
+	StatusInvalid = "invalid"
 )
 
 // Telemetry metrics
@@ -50,6 +52,9 @@ var (
 	WebhooksReceived = telemetry.NewCounterWithOpts("admission_webhooks", "webhooks_received",
 		[]string{"mutation_type"}, "Number of mutation webhook requests received.",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	ValidationAttempts = telemetry.NewCounterWithOpts("admission_webhooks", "validation_attempts",
+		[]string{"validation_type", "mode", "kind", "status"}, "Number of object validation attempts by validation type, mode and kind",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 	GetOwnerCacheHit = telemetry.NewGaugeWithOpts("admission_webhooks", "owner_cache_hit",
 		[]string{"resource"}, "Number of cache hits while getting pod's owner object.",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
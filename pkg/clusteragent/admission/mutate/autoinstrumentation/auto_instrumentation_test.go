@@ -1788,7 +1788,7 @@ func TestInjectAutoInstrumentation(t *testing.T) {
 					ContainerName:  "pod",
 					DeploymentName: "test-app",
 					Namespace:      "ns",
-					Languages:      util.LanguageSet{util.Language("python"): struct{}{}, util.Language("java"): struct{}{}},
+					Languages:      util.LanguageSet{util.Language("python"): {}, util.Language("java"): {}},
 				},
 			},
 			wantErr: false,
@@ -1851,7 +1851,7 @@ func TestInjectAutoInstrumentation(t *testing.T) {
 					ContainerName:  "pod",
 					DeploymentName: "test-app",
 					Namespace:      "ns",
-					Languages:      util.LanguageSet{util.Language("python"): struct{}{}, util.Language("java"): struct{}{}},
+					Languages:      util.LanguageSet{util.Language("python"): {}, util.Language("java"): {}},
 				},
 			},
 			wantErr: false,
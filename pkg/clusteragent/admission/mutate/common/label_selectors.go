@@ -51,6 +51,17 @@ func DefaultLabelSelectors(useNamespaceSelector bool) (namespaceSelector, object
 	return nil, &labelSelector
 }
 
+// NamespaceLabelSelector returns a webhook namespace selector matching the
+// namespaces selected by labelSelector, adapted for AKS if needed
+func NamespaceLabelSelector(labelSelector metav1.LabelSelector) *metav1.LabelSelector {
+	if config.Datadog.GetBool("admission_controller.add_aks_selectors") {
+		namespaceSelector, _ := aksSelectors(true, *labelSelector.DeepCopy())
+		return namespaceSelector
+	}
+
+	return &labelSelector
+}
+
 // aksSelectors takes a label selector and builds a namespace and object
 // selector adapted for AKS. AKS adds automatically some selector requirements
 // if we don't, so we need to add them to avoid conflicts when updating the
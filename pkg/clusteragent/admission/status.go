@@ -47,6 +47,13 @@ func GetStatus(apiCl kubernetes.Interface) map[string]interface{} {
 		status["Webhooks"] = webhookStatus
 	}
 
+	validatingWebhookStatus, err := getValidatingWebhookStatus(webhookName, apiCl)
+	if err != nil {
+		status["ValidatingWebhookError"] = err.Error()
+	} else if validatingWebhookStatus != nil {
+		status["ValidatingWebhooks"] = validatingWebhookStatus
+	}
+
 	secretStatus, err := getSecretStatus(ns, secretName, apiCl)
 	if err != nil {
 		status["SecretError"] = err.Error()
@@ -61,6 +68,11 @@ var getWebhookStatus = func(string, kubernetes.Interface) (map[string]interface{
 	return nil, fmt.Errorf("admission controller not started")
 }
 
+// getValidatingWebhookStatus returns nil when no validating webhook is enabled
+var getValidatingWebhookStatus = func(string, kubernetes.Interface) (map[string]interface{}, error) {
+	return nil, nil
+}
+
 func getWebhookStatusV1beta1(name string, apiCl kubernetes.Interface) (map[string]interface{}, error) {
 	webhookStatus := make(map[string]interface{})
 	webhook, err := apiCl.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), name, metav1.GetOptions{})
@@ -135,6 +147,43 @@ func getWebhookStatusV1(name string, apiCl kubernetes.Interface) (map[string]int
 	return webhookStatus, nil
 }
 
+func getValidatingWebhookStatusV1(name string, apiCl kubernetes.Interface) (map[string]interface{}, error) {
+	webhookStatus := make(map[string]interface{})
+	webhook, err := apiCl.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return webhookStatus, err
+	}
+
+	webhookStatus["Name"] = webhook.GetName()
+	webhookStatus["CreatedAt"] = webhook.GetCreationTimestamp()
+
+	webhooksConfig := make(map[string]map[string]interface{})
+	webhookStatus["Webhooks"] = webhooksConfig
+	for _, w := range webhook.Webhooks {
+		webhooksConfig[w.Name] = make(map[string]interface{})
+		svc := w.ClientConfig.Service
+		if svc != nil {
+			port := "Port: None (default 443)"
+			path := "Path: None"
+			if svc.Port != nil {
+				port = fmt.Sprintf("Port: %d", *svc.Port)
+			}
+			if svc.Path != nil {
+				path = fmt.Sprintf("Path: %s", *svc.Path)
+			}
+			webhooksConfig[w.Name]["Service"] = fmt.Sprintf("%s/%s - %s - %s", svc.Namespace, svc.Name, port, path)
+		}
+		if w.NamespaceSelector != nil {
+			webhooksConfig[w.Name]["Namespace selector"] = w.NamespaceSelector.String()
+		}
+		for i, r := range w.Rules {
+			webhooksConfig[w.Name][fmt.Sprintf("Rule %d", i+1)] = fmt.Sprintf("Operations: %v - APIGroups: %v - APIVersions: %v - Resources: %v", r.Operations, r.Rule.APIGroups, r.Rule.APIVersions, r.Rule.Resources)
+		}
+		webhooksConfig[w.Name]["CA bundle digest"] = getDigest(w.ClientConfig.CABundle)
+	}
+	return webhookStatus, nil
+}
+
 func getSecretStatus(ns, name string, apiCl kubernetes.Interface) (map[string]interface{}, error) {
 	secretStatus := make(map[string]interface{})
 	secret, err := apiCl.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
@@ -0,0 +1,25 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package validate contains validating webhooks registered in the admission
+// controller.
+//
+// Validating webhooks intercept requests to the Kubernetes API server after
+// the mutating webhooks ran, and decide whether the object can be admitted
+// without modifying it. They can also attach warnings to the response, which
+// are displayed to the user, for instance by kubectl.
+//
+// Each validating webhook needs to implement the "ValidatingWebhook"
+// interface of the "webhook" package. It's similar to the "MutatingWebhook"
+// interface described in the "mutate" package, except that the webhook
+// defines its own rules, as it can apply to resources of any API group, and
+// that it provides a "ValidateFunc" instead of a "MutateFunc".
+//
+// Validating webhooks are registered in a ValidatingWebhookConfiguration
+// object with the same name as the MutatingWebhookConfiguration object. They
+// are only supported on clusters serving admissionregistration/v1.
+package validate
@@ -0,0 +1,366 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package unifiedservicetagging implements the webhook that checks that new
+// workloads are configured with the env, service and version unified service
+// tags
+package unifiedservicetagging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	admiv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	webhookName = "validate_tags"
+
+	tagLabelPrefix = "tags.datadoghq.com/"
+	tagEnvPrefix   = "DD_"
+
+	namespaceLabelsCacheKeyPrefix = "validate_tags_namespace_labels"
+	namespaceLabelsCacheTTL       = time.Minute
+)
+
+// Mode is what the webhook does with the workloads missing unified service tags
+type Mode string
+
+const (
+	// ModeWarn admits the workload and returns a warning to the user
+	ModeWarn Mode = "warn"
+	// ModeDeny rejects the workload
+	ModeDeny Mode = "deny"
+	// ModeDryRun admits the workload and only reports it in the logs and the
+	// telemetry
+	ModeDryRun Mode = "dryrun"
+)
+
+// Policy sets the validation mode of the namespaces matching a selector. An
+// empty selector matches every namespace.
+type Policy struct {
+	Mode              Mode                 `mapstructure:"mode"`
+	NamespaceSelector metav1.LabelSelector `mapstructure:"namespace_selector"`
+}
+
+// policy is a validated Policy
+type policy struct {
+	mode Mode
+	// selector is nil when the policy matches every namespace
+	selector labels.Selector
+}
+
+// Webhook is the webhook that validates the unified service tags of the
+// workloads. The API server runs every matching webhook, so a single webhook
+// is registered for all the namespaces and the mode of a workload is the one
+// of the first policy matching its namespace.
+type Webhook struct {
+	name         string
+	isEnabled    bool
+	endpoint     string
+	policies     []policy
+	requiredTags []string
+}
+
+// NewWebhook returns the unified service tagging validation webhook, or nil
+// if it is disabled or its policies are invalid. When no policy is
+// configured, the workloads of every namespace are validated with the
+// default mode.
+func NewWebhook() *Webhook {
+	if !config.Datadog.GetBool("admission_controller.validate_tags.enabled") {
+		return nil
+	}
+
+	policies, err := loadPolicies()
+	if err != nil {
+		log.Errorf("invalid policies for admission controller unified service tagging validation webhook: %v", err)
+		return nil
+	}
+
+	return &Webhook{
+		name:         webhookName,
+		isEnabled:    true,
+		endpoint:     config.Datadog.GetString("admission_controller.validate_tags.endpoint"),
+		policies:     policies,
+		requiredTags: config.Datadog.GetStringSlice("admission_controller.validate_tags.required_tags"),
+	}
+}
+
+func loadPolicies() ([]policy, error) {
+	var configPolicies []Policy
+	if err := config.Datadog.UnmarshalKey("admission_controller.validate_tags.policies", &configPolicies); err != nil {
+		return nil, err
+	}
+	if len(configPolicies) == 0 {
+		configPolicies = []Policy{{Mode: Mode(config.Datadog.GetString("admission_controller.validate_tags.mode"))}}
+	}
+
+	policies := make([]policy, 0, len(configPolicies))
+	for i, p := range configPolicies {
+		if !isValidMode(p.Mode) {
+			return nil, fmt.Errorf("invalid mode %q for policy %d, it must be one of %q, %q or %q", p.Mode, i, ModeWarn, ModeDeny, ModeDryRun)
+		}
+		var selector labels.Selector
+		if len(p.NamespaceSelector.MatchLabels) > 0 || len(p.NamespaceSelector.MatchExpressions) > 0 {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(&p.NamespaceSelector); err != nil {
+				return nil, fmt.Errorf("invalid namespace selector for policy %d: %w", i, err)
+			}
+		}
+		policies = append(policies, policy{mode: p.Mode, selector: selector})
+	}
+	return policies, nil
+}
+
+func isValidMode(mode Mode) bool {
+	return mode == ModeWarn || mode == ModeDeny || mode == ModeDryRun
+}
+
+// Name returns the name of the webhook
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// IsEnabled returns whether the webhook is enabled
+func (w *Webhook) IsEnabled() bool {
+	return w.isEnabled
+}
+
+// Endpoint returns the endpoint of the webhook
+func (w *Webhook) Endpoint() string {
+	return w.endpoint
+}
+
+// Rules returns the resources and operations for which the webhook should be
+// invoked
+func (w *Webhook) Rules() []admiv1.RuleWithOperations {
+	return []admiv1.RuleWithOperations{
+		rule("", "pods"),
+		rule("apps", "deployments", "replicasets", "statefulsets", "daemonsets"),
+		rule("batch", "jobs"),
+	}
+}
+
+func rule(apiGroup string, resources ...string) admiv1.RuleWithOperations {
+	return admiv1.RuleWithOperations{
+		Operations: []admiv1.OperationType{admiv1.Create},
+		Rule: admiv1.Rule{
+			APIGroups:   []string{apiGroup},
+			APIVersions: []string{"v1"},
+			Resources:   resources,
+		},
+	}
+}
+
+// LabelSelectors returns the label selectors that specify when the webhook
+// should be invoked. The policies are matched by the webhook itself.
+func (w *Webhook) LabelSelectors(_ bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector) {
+	return common.NamespaceLabelSelector(metav1.LabelSelector{}), nil
+}
+
+// ValidateFunc returns the function that validates the resources
+func (w *Webhook) ValidateFunc() admission.ValidatingWebhookFunc {
+	return w.validate
+}
+
+// validate checks that the workload has the required unified service tags
+func (w *Webhook) validate(request *admission.MutateRequest) (*admission.ValidationResult, error) {
+	mode, found, err := w.modeFor(request.Namespace, request.APIClient)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &admission.ValidationResult{Allowed: true}, nil
+	}
+
+	kind := request.Kind.Kind
+	name, missing, err := missingTags(kind, request.Raw, w.requiredTags)
+	if err != nil {
+		metrics.ValidationAttempts.Inc(webhookName, string(mode), kind, metrics.StatusError)
+		return nil, err
+	}
+	if len(missing) == 0 {
+		metrics.ValidationAttempts.Inc(webhookName, string(mode), kind, metrics.StatusValid)
+		return &admission.ValidationResult{Allowed: true}, nil
+	}
+	metrics.ValidationAttempts.Inc(webhookName, string(mode), kind, metrics.StatusInvalid)
+
+	labels := make([]string, 0, len(missing))
+	envs := make([]string, 0, len(missing))
+	for _, tag := range missing {
+		labels = append(labels, tagLabelPrefix+tag)
+		envs = append(envs, tagEnvPrefix+strings.ToUpper(tag))
+	}
+	message := fmt.Sprintf("%s %s/%s is missing the unified service tags %s: set the %s labels or the %s environment variables",
+		kind, request.Namespace, name, strings.Join(missing, ", "), strings.Join(labels, ", "), strings.Join(envs, ", "))
+
+	switch mode {
+	case ModeDeny:
+		return &admission.ValidationResult{Allowed: false, Message: message}, nil
+	case ModeWarn:
+		return &admission.ValidationResult{Allowed: true, Warnings: []string{message}}, nil
+	default:
+		log.Infof("Unified service tagging validation (dry run): %s", message)
+		return &admission.ValidationResult{Allowed: true}, nil
+	}
+}
+
+// modeFor returns the mode of the first policy matching a namespace, or false
+// if no policy matches it. The labels of the namespace are only read when a
+// policy before the matching one has a selector.
+func (w *Webhook) modeFor(namespace string, client kubernetes.Interface) (Mode, bool, error) {
+	var nsLabels labels.Set
+	for _, p := range w.policies {
+		if p.selector == nil {
+			return p.mode, true, nil
+		}
+		if nsLabels == nil {
+			var err error
+			if nsLabels, err = getNamespaceLabels(namespace, client); err != nil {
+				return "", false, err
+			}
+		}
+		if p.selector.Matches(nsLabels) {
+			return p.mode, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// getNamespaceLabels returns the labels of a namespace, from the cache if
+// possible
+func getNamespaceLabels(namespace string, client kubernetes.Interface) (labels.Set, error) {
+	cacheKey := cache.BuildAgentKey(namespaceLabelsCacheKeyPrefix, namespace)
+	if cached, hit := cache.Cache.Get(cacheKey); hit {
+		if nsLabels, ok := cached.(labels.Set); ok {
+			return nsLabels, nil
+		}
+	}
+
+	ns, err := client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	nsLabels := labels.Set(ns.Labels)
+	if nsLabels == nil {
+		nsLabels = labels.Set{}
+	}
+	cache.Cache.Set(cacheKey, nsLabels, namespaceLabelsCacheTTL)
+	return nsLabels, nil
+}
+
+// missingTags returns the name of a workload and the required tags it
+// doesn't set. Pods and replica sets created by a controller are validated
+// through their owner, so no tag is reported missing for them.
+func missingTags(kind string, raw []byte, requiredTags []string) (string, []string, error) {
+	var meta metav1.ObjectMeta
+	var template corev1.PodTemplateSpec
+	switch kind {
+	case "Pod":
+		var pod corev1.Pod
+		if err := json.Unmarshal(raw, &pod); err != nil {
+			return "", nil, fmt.Errorf("failed to decode raw object: %v", err)
+		}
+		if len(pod.OwnerReferences) > 0 {
+			return objectName(pod.ObjectMeta), nil, nil
+		}
+		meta = pod.ObjectMeta
+		template = corev1.PodTemplateSpec{Spec: pod.Spec}
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := json.Unmarshal(raw, &deployment); err != nil {
+			return "", nil, fmt.Errorf("failed to decode raw object: %v", err)
+		}
+		meta, template = deployment.ObjectMeta, deployment.Spec.Template
+	case "ReplicaSet":
+		var replicaSet appsv1.ReplicaSet
+		if err := json.Unmarshal(raw, &replicaSet); err != nil {
+			return "", nil, fmt.Errorf("failed to decode raw object: %v", err)
+		}
+		if len(replicaSet.OwnerReferences) > 0 {
+			return objectName(replicaSet.ObjectMeta), nil, nil
+		}
+		meta, template = replicaSet.ObjectMeta, replicaSet.Spec.Template
+	case "StatefulSet":
+		var statefulSet appsv1.StatefulSet
+		if err := json.Unmarshal(raw, &statefulSet); err != nil {
+			return "", nil, fmt.Errorf("failed to decode raw object: %v", err)
+		}
+		meta, template = statefulSet.ObjectMeta, statefulSet.Spec.Template
+	case "DaemonSet":
+		var daemonSet appsv1.DaemonSet
+		if err := json.Unmarshal(raw, &daemonSet); err != nil {
+			return "", nil, fmt.Errorf("failed to decode raw object: %v", err)
+		}
+		meta, template = daemonSet.ObjectMeta, daemonSet.Spec.Template
+	case "Job":
+		var job batchv1.Job
+		if err := json.Unmarshal(raw, &job); err != nil {
+			return "", nil, fmt.Errorf("failed to decode raw object: %v", err)
+		}
+		meta, template = job.ObjectMeta, job.Spec.Template
+	default:
+		return "", nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+
+	var missing []string
+	for _, tag := range requiredTags {
+		label := tagLabelPrefix + tag
+		if hasKey(label, meta.Labels, meta.Annotations, template.Labels, template.Annotations) {
+			continue
+		}
+		if len(template.Spec.Containers) > 0 && allContainersHaveEnv(template.Spec.Containers, tagEnvPrefix+strings.ToUpper(tag)) {
+			continue
+		}
+		missing = append(missing, tag)
+	}
+	return objectName(meta), missing, nil
+}
+
+func objectName(meta metav1.ObjectMeta) string {
+	if meta.Name != "" {
+		return meta.Name
+	}
+	return meta.GenerateName
+}
+
+// hasKey returns whether a non-empty value is set for key in one of the maps
+func hasKey(key string, maps ...map[string]string) bool {
+	for _, m := range maps {
+		if m[key] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// allContainersHaveEnv returns whether every container sets the env var name
+func allContainersHaveEnv(containers []corev1.Container, name string) bool {
+	for _, container := range containers {
+		index := common.EnvIndex(container.Env, name)
+		if index == -1 || (container.Env[index].Value == "" && container.Env[index].ValueFrom == nil) {
+			return false
+		}
+	}
+	return true
+}
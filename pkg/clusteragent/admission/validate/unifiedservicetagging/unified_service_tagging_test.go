@@ -0,0 +1,264 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package unifiedservicetagging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+var requiredTags = []string{"env", "service", "version"}
+
+func deployment(labels, templateLabels map[string]string, env ...corev1.EnvVar) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: templateLabels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "app", Env: env},
+						{Name: "proxy", Env: env},
+					},
+				},
+			},
+		},
+	}
+}
+
+func request(t *testing.T, kind string, obj interface{}) *admission.MutateRequest {
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return &admission.MutateRequest{Raw: raw, Namespace: "default", Kind: metav1.GroupVersionKind{Kind: kind}}
+}
+
+func TestMissingTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		obj     interface{}
+		missing []string
+	}{
+		{
+			name:    "labels on the deployment",
+			kind:    "Deployment",
+			obj:     deployment(map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/service": "web", "tags.datadoghq.com/version": "1.2"}, nil),
+			missing: nil,
+		},
+		{
+			name:    "labels on the pod template",
+			kind:    "Deployment",
+			obj:     deployment(map[string]string{"tags.datadoghq.com/env": "prod"}, map[string]string{"tags.datadoghq.com/service": "web"}),
+			missing: []string{"version"},
+		},
+		{
+			name: "env vars in every container",
+			kind: "Deployment",
+			obj: deployment(nil, nil,
+				corev1.EnvVar{Name: "DD_ENV", Value: "prod"},
+				corev1.EnvVar{Name: "DD_SERVICE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['app']"}}},
+				corev1.EnvVar{Name: "DD_VERSION", Value: ""},
+			),
+			missing: []string{"version"},
+		},
+		{
+			name: "annotations on the statefulset",
+			kind: "StatefulSet",
+			obj: &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+				Name:        "db",
+				Annotations: map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/service": "db", "tags.datadoghq.com/version": "14"},
+			}},
+			missing: nil,
+		},
+		{
+			name: "standalone replicaset",
+			kind: "ReplicaSet",
+			obj: &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name:   "web",
+				Labels: map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/service": "web"},
+			}},
+			missing: []string{"version"},
+		},
+		{
+			name: "replicaset created by a deployment",
+			kind: "ReplicaSet",
+			obj: &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name:            "web-abc",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web"}},
+			}},
+			missing: nil,
+		},
+		{
+			name: "labels on the daemonset",
+			kind: "DaemonSet",
+			obj: &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+				Name:   "agent",
+				Labels: map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/service": "agent", "tags.datadoghq.com/version": "7"},
+			}},
+			missing: nil,
+		},
+		{
+			name: "standalone pod",
+			kind: "Pod",
+			obj: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "debug", Labels: map[string]string{"tags.datadoghq.com/env": "dev"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "debug"}}},
+			},
+			missing: []string{"service", "version"},
+		},
+		{
+			name: "pod created by a controller",
+			kind: "Pod",
+			obj: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				GenerateName:    "web-",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc"}},
+			}},
+			missing: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.obj)
+			require.NoError(t, err)
+			_, missing, err := missingTags(tt.kind, raw, requiredTags)
+			require.NoError(t, err)
+			assert.Equal(t, tt.missing, missing)
+		})
+	}
+}
+
+func TestValidateModes(t *testing.T) {
+	obj := deployment(map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/service": "web"}, nil)
+	message := "Deployment default/web is missing the unified service tags version: set the tags.datadoghq.com/version labels or the DD_VERSION environment variables"
+
+	w := &Webhook{policies: []policy{{mode: ModeDeny}}, requiredTags: requiredTags}
+	result, err := w.validate(request(t, "Deployment", obj))
+	require.NoError(t, err)
+	assert.Equal(t, &admission.ValidationResult{Allowed: false, Message: message}, result)
+
+	w.policies[0].mode = ModeWarn
+	result, err = w.validate(request(t, "Deployment", obj))
+	require.NoError(t, err)
+	assert.Equal(t, &admission.ValidationResult{Allowed: true, Warnings: []string{message}}, result)
+
+	w.policies[0].mode = ModeDryRun
+	result, err = w.validate(request(t, "Deployment", obj))
+	require.NoError(t, err)
+	assert.Equal(t, &admission.ValidationResult{Allowed: true}, result)
+
+	obj.Labels["tags.datadoghq.com/version"] = "1.2"
+	w.policies[0].mode = ModeDeny
+	result, err = w.validate(request(t, "Deployment", obj))
+	require.NoError(t, err)
+	assert.Equal(t, &admission.ValidationResult{Allowed: true}, result)
+
+	_, err = w.validate(request(t, "CronJob", obj))
+	assert.EqualError(t, err, "unsupported kind CronJob")
+}
+
+func TestValidateFirstMatchingPolicy(t *testing.T) {
+	mockConfig := config.Mock(t)
+	mockConfig.SetWithoutSource("admission_controller.validate_tags.enabled", true)
+	mockConfig.SetWithoutSource("admission_controller.validate_tags.policies", []interface{}{
+		map[string]interface{}{
+			"mode":               "deny",
+			"namespace_selector": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "payments"}},
+		},
+		map[string]interface{}{
+			"mode":               "warn",
+			"namespace_selector": map[string]interface{}{"matchLabels": map[string]interface{}{"env": "prod"}},
+		},
+	})
+	w := NewWebhook()
+	require.NotNil(t, w)
+
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments", "env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sandbox"}},
+	)
+	obj := deployment(nil, nil)
+
+	validate := func(namespace string) *admission.ValidationResult {
+		req := request(t, "Deployment", obj)
+		req.Namespace = namespace
+		req.APIClient = client
+		result, err := w.validate(req)
+		require.NoError(t, err)
+		return result
+	}
+
+	// the namespace matches both policies, only the first one applies
+	result := validate("payments")
+	assert.False(t, result.Allowed)
+	assert.Empty(t, result.Warnings)
+
+	result = validate("shop")
+	assert.True(t, result.Allowed)
+	assert.Len(t, result.Warnings, 1)
+
+	// no policy matches the namespace
+	assert.Equal(t, &admission.ValidationResult{Allowed: true}, validate("sandbox"))
+
+	req := request(t, "Deployment", obj)
+	req.Namespace = "unknown"
+	req.APIClient = client
+	_, err := w.validate(req)
+	assert.ErrorContains(t, err, "failed to get namespace unknown")
+}
+
+func TestNewWebhook(t *testing.T) {
+	mockConfig := config.Mock(t)
+	assert.Nil(t, NewWebhook())
+
+	mockConfig.SetWithoutSource("admission_controller.validate_tags.enabled", true)
+	w := NewWebhook()
+	require.NotNil(t, w)
+	assert.Equal(t, "validate_tags", w.Name())
+	assert.Equal(t, "/validatetags", w.Endpoint())
+	assert.Equal(t, []policy{{mode: ModeWarn}}, w.policies)
+	nsSelector, objSelector := w.LabelSelectors(true)
+	assert.Equal(t, &metav1.LabelSelector{}, nsSelector)
+	assert.Nil(t, objSelector)
+
+	mockConfig.SetWithoutSource("admission_controller.validate_tags.policies", []interface{}{
+		map[string]interface{}{
+			"mode": "dryrun",
+			"namespace_selector": map[string]interface{}{"matchExpressions": []interface{}{
+				map[string]interface{}{"key": "team", "operator": "NotIn", "values": []interface{}{"payments"}},
+			}},
+		},
+		map[string]interface{}{"mode": "deny"},
+	})
+	w = NewWebhook()
+	require.NotNil(t, w)
+	require.Len(t, w.policies, 2)
+	assert.Equal(t, ModeDryRun, w.policies[0].mode)
+	assert.Equal(t, "team notin (payments)", w.policies[0].selector.String())
+	assert.Equal(t, policy{mode: ModeDeny}, w.policies[1])
+
+	// the webhook is registered for every namespace, adapted for AKS if needed
+	mockConfig.SetWithoutSource("admission_controller.add_aks_selectors", true)
+	nsSelector, _ = NewWebhook().LabelSelectors(true)
+	assert.Len(t, nsSelector.MatchExpressions, 3)
+
+	mockConfig.SetWithoutSource("admission_controller.validate_tags.policies", []interface{}{
+		map[string]interface{}{"mode": "block"},
+	})
+	assert.Nil(t, NewWebhook())
+}
@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1
+
+// LocalExternalMetricsPoint is the value of a metric at a given time
+type LocalExternalMetricsPoint struct {
+	Timestamp int64   `json:"ts"`
+	Value     float64 `json:"value"`
+}
+
+// LocalExternalMetricsSerie is a metric context forwarded by a node agent
+type LocalExternalMetricsSerie struct {
+	Name   string                      `json:"name"`
+	Host   string                      `json:"host"`
+	Tags   []string                    `json:"tags"`
+	Points []LocalExternalMetricsPoint `json:"points"`
+}
+
+// LocalExternalMetricsPayload is the body of the requests node agents send to
+// forward metrics to the Cluster Agent
+type LocalExternalMetricsPayload struct {
+	Series []LocalExternalMetricsSerie `json:"series"`
+}
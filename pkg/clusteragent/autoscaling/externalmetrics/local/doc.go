@@ -0,0 +1,10 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package local implements the in-cluster resolution of external metrics: node
+// agents forward the points of selected metrics to the Cluster Agent, which
+// aggregates them to answer DatadogMetric queries without calling the Datadog
+// API.
+package local
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package forwarder implements the node agent side of the in-cluster
+// resolution of external metrics: it forwards the series of the selected
+// metrics flushed by the aggregator to the Cluster Agent.
+package forwarder
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const postTimeout = 10 * time.Second
+
+// client defines the method to forward metrics to the Cluster Agent
+type client interface {
+	PostLocalExternalMetrics(ctx context.Context, payload *local.Payload) error
+}
+
+// Forwarder forwards the series of the metrics used by the Cluster Agent to
+// compute external metrics in the cluster. It implements
+// aggregator.SeriesForwarder.
+type Forwarder struct {
+	metricNames map[string]struct{}
+	payloads    chan *local.Payload
+	stopChan    chan struct{}
+	client      client
+}
+
+// NewForwarder returns a forwarder if the node agent is configured to forward
+// metrics to the Cluster Agent, nil otherwise
+func NewForwarder(cfg config.Reader) *Forwarder {
+	if flavor.GetFlavor() != flavor.DefaultAgent ||
+		!cfg.GetBool("cluster_agent.enabled") ||
+		!cfg.GetBool("external_metrics_provider.local.enabled") {
+		return nil
+	}
+
+	metricNames := cfg.GetStringSlice("external_metrics_provider.local.metric_names")
+	if len(metricNames) == 0 {
+		log.Warn("external_metrics_provider.local.enabled is set but external_metrics_provider.local.metric_names is empty, no metric will be forwarded to the Cluster Agent")
+		return nil
+	}
+
+	f := &Forwarder{
+		metricNames: make(map[string]struct{}, len(metricNames)),
+		// Only keep a few flushes while the Cluster Agent is unreachable
+		payloads: make(chan *local.Payload, 4),
+		stopChan: make(chan struct{}),
+	}
+	for _, name := range metricNames {
+		f.metricNames[name] = struct{}{}
+	}
+	return f
+}
+
+// sink copies the series of the forwarded metrics before appending them to
+// the serializer sink
+type sink struct {
+	metrics.SerieSink
+	metricNames map[string]struct{}
+	series      []local.Serie
+}
+
+// Append implements metrics.SerieSink
+func (s *sink) Append(serie *metrics.Serie) {
+	if _, found := s.metricNames[serie.Name]; found {
+		forwarded := local.Serie{
+			Name:   serie.Name,
+			Host:   serie.Host,
+			Tags:   make([]string, 0, serie.Tags.Len()),
+			Points: make([]local.Point, 0, len(serie.Points)),
+		}
+		serie.Tags.ForEach(func(tag string) {
+			forwarded.Tags = append(forwarded.Tags, tag)
+		})
+		for _, point := range serie.Points {
+			forwarded.Points = append(forwarded.Points, local.Point{Timestamp: int64(point.Ts), Value: point.Value})
+		}
+		s.series = append(s.series, forwarded)
+	}
+
+	s.SerieSink.Append(serie)
+}
+
+// Sink wraps the serializer sink of a flush, the copied series are queued
+// when the returned function is called
+func (f *Forwarder) Sink(seriesSink metrics.SerieSink) (metrics.SerieSink, func()) {
+	s := &sink{
+		SerieSink:   seriesSink,
+		metricNames: f.metricNames,
+	}
+	return s, func() { f.forward(s.series) }
+}
+
+// forward queues the series copied during a flush. The series are dropped if
+// the previous flushes are still being forwarded.
+func (f *Forwarder) forward(series []local.Serie) {
+	if len(series) == 0 {
+		return
+	}
+
+	select {
+	case f.payloads <- &local.Payload{Series: series}:
+	default:
+		log.Debugf("Dropping %d series to forward to the Cluster Agent, the previous ones are still being forwarded", len(series))
+	}
+}
+
+// Start forwards the queued series until Stop is called
+func (f *Forwarder) Start() {
+	go f.run()
+}
+
+func (f *Forwarder) run() {
+	for {
+		select {
+		case <-f.stopChan:
+			return
+		case payload := <-f.payloads:
+			if err := f.post(payload); err != nil {
+				log.Debugf("Unable to forward %d series to the Cluster Agent: %v", len(payload.Series), err)
+			}
+		}
+	}
+}
+
+func (f *Forwarder) post(payload *local.Payload) error {
+	if f.client == nil {
+		dcaClient, err := clusteragent.GetClusterAgentClient()
+		if err != nil {
+			return err
+		}
+		f.client = dcaClient
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postTimeout)
+	defer cancel()
+	return f.client.PostLocalExternalMetrics(ctx, payload)
+}
+
+// Stop stops forwarding the queued series
+func (f *Forwarder) Stop() {
+	close(f.stopChan)
+}
@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package forwarder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/tagset"
+)
+
+type mockClient struct {
+	payloads chan *local.Payload
+}
+
+func (c *mockClient) PostLocalExternalMetrics(_ context.Context, payload *local.Payload) error {
+	c.payloads <- payload
+	return nil
+}
+
+func TestNewForwarder(t *testing.T) {
+	cfg := config.Mock(t)
+	assert.Nil(t, NewForwarder(cfg))
+
+	cfg.SetWithoutSource("cluster_agent.enabled", true)
+	cfg.SetWithoutSource("external_metrics_provider.local.enabled", true)
+	assert.Nil(t, NewForwarder(cfg))
+
+	cfg.SetWithoutSource("external_metrics_provider.local.metric_names", []string{"requests"})
+	f := NewForwarder(cfg)
+	require.NotNil(t, f)
+	assert.Equal(t, map[string]struct{}{"requests": {}}, f.metricNames)
+}
+
+func TestForwarder(t *testing.T) {
+	client := &mockClient{payloads: make(chan *local.Payload)}
+	f := &Forwarder{
+		metricNames: map[string]struct{}{"requests": {}},
+		payloads:    make(chan *local.Payload, 1),
+		stopChan:    make(chan struct{}),
+		client:      client,
+	}
+
+	var series metrics.Series
+	sink, forward := f.Sink(&series)
+	sink.Append(&metrics.Serie{
+		Name:   "requests",
+		Host:   "node-1",
+		Tags:   tagset.CompositeTagsFromSlice([]string{"kube_deployment:web", "pod_name:web-1"}),
+		Points: []metrics.Point{{Ts: 1000, Value: 12}},
+	})
+	sink.Append(&metrics.Serie{Name: "cpu", Points: []metrics.Point{{Ts: 1000, Value: 1}}})
+
+	// All the series go to the serializer
+	assert.Len(t, series, 2)
+
+	forward()
+	// The payload queue is full, the series of the next flush are dropped
+	forward()
+
+	f.Start()
+	defer f.Stop()
+
+	payload := <-client.payloads
+	assert.Equal(t, &local.Payload{Series: []local.Serie{{
+		Name:   "requests",
+		Host:   "node-1",
+		Tags:   []string{"kube_deployment:web", "pod_name:web-1"},
+		Points: []local.Point{{Timestamp: 1000, Value: 12}},
+	}}}, payload)
+	assert.Empty(t, f.payloads)
+}
@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package local
+
+import (
+	"regexp"
+	"strings"
+)
+
+var queryRegexp = regexp.MustCompile(`^\s*(avg|sum|max):([A-Za-z][A-Za-z0-9_.]*)\{([^{}]*)\}\s*$`)
+
+// Query is a DatadogMetric query that can be resolved from the forwarded
+// metrics
+type Query struct {
+	// Aggregator is the space aggregation of the query: avg, sum or max
+	Aggregator string
+	// Metric is the name of the queried metric
+	Metric string
+	// Tags are the tags the series must have, usually selecting pods with
+	// tags like kube_namespace, kube_deployment or pod labels
+	Tags []string
+}
+
+// ParseQuery parses the queries made of an aggregator, a metric name and tags,
+// like avg:nginx.net.request_per_s{kube_namespace:prod,kube_deployment:web}.
+// It returns false if the query uses other features of the Datadog query
+// language, as it can only be resolved by the Datadog API.
+func ParseQuery(query string) (Query, bool) {
+	matches := queryRegexp.FindStringSubmatch(query)
+	if matches == nil {
+		return Query{}, false
+	}
+
+	q := Query{
+		Aggregator: matches[1],
+		Metric:     matches[2],
+	}
+	for _, tag := range strings.Split(matches[3], ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		// Negations, wildcards and boolean operators are not supported
+		if strings.HasPrefix(tag, "-") || strings.HasPrefix(tag, "!") || strings.ContainsAny(tag, "* \t") {
+			return Query{}, false
+		}
+		q.Tags = append(q.Tags, tag)
+	}
+
+	return q, true
+}
@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package local
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Query
+		ok       bool
+	}{
+		{
+			query:    "avg:nginx.net.request_per_s{kube_namespace:prod,kube_deployment:web}",
+			expected: Query{Aggregator: "avg", Metric: "nginx.net.request_per_s", Tags: []string{"kube_namespace:prod", "kube_deployment:web"}},
+			ok:       true,
+		},
+		{
+			query:    " max:queue.depth{*} ",
+			expected: Query{Aggregator: "max", Metric: "queue.depth"},
+			ok:       true,
+		},
+		{
+			query:    "sum:requests{ app:web-frontend , env:prod }",
+			expected: Query{Aggregator: "sum", Metric: "requests", Tags: []string{"app:web-frontend", "env:prod"}},
+			ok:       true,
+		},
+		{query: "min:queue.depth{*}"},
+		{query: "avg:queue.depth{*}.rollup(60)"},
+		{query: "avg:queue.depth{!env:prod}"},
+		{query: "avg:queue.depth{service:web*}"},
+		{query: "avg:queue.depth{env:prod AND service:web}"},
+		{query: "avg:queue.depth{env:prod} / avg:queue.size{env:prod}"},
+		{query: "avg:queue.depth{env:prod} by {pod_name}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			query, ok := ParseQuery(test.query)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, query)
+		})
+	}
+}
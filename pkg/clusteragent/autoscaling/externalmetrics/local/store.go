@@ -0,0 +1,223 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package local
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	globalStore     *Store
+	globalStoreOnce sync.Once
+)
+
+// GetStore returns the store of the Cluster Agent, or nil when the local
+// resolution of external metrics is disabled
+func GetStore() *Store {
+	globalStoreOnce.Do(func() {
+		if !config.Datadog.GetBool("external_metrics_provider.local.enabled") {
+			return
+		}
+		globalStore = NewStore(
+			config.Datadog.GetStringSlice("external_metrics_provider.local.metric_names"),
+			config.Datadog.GetDuration("external_metrics_provider.local.retention")*time.Second,
+			config.Datadog.GetInt("external_metrics_provider.local.max_series"),
+		)
+	})
+	return globalStore
+}
+
+// Store keeps the points forwarded by the node agents for a retention period
+type Store struct {
+	mutex       sync.RWMutex
+	series      map[string]*storedSerie
+	metricNames map[string]struct{}
+	retention   time.Duration
+	maxSeries   int
+}
+
+type storedSerie struct {
+	name   string
+	tags   map[string]struct{}
+	points []Point // sorted by timestamp
+}
+
+// NewStore returns a new store keeping the points of the given metrics, for
+// at most maxSeries series
+func NewStore(metricNames []string, retention time.Duration, maxSeries int) *Store {
+	names := make(map[string]struct{}, len(metricNames))
+	for _, name := range metricNames {
+		names[name] = struct{}{}
+	}
+
+	return &Store{
+		series:      make(map[string]*storedSerie),
+		metricNames: names,
+		retention:   retention,
+		maxSeries:   maxSeries,
+	}
+}
+
+// Handles returns whether the points of a metric are kept by the store
+func (s *Store) Handles(metricName string) bool {
+	_, found := s.metricNames[metricName]
+	return found
+}
+
+// Add stores the points of the series of the handled metrics, and drops the
+// points older than the retention period. The series that aren't stored yet
+// are dropped once the store holds maxSeries series.
+func (s *Store) Add(series []Serie, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cutoff := now.Add(-s.retention).Unix()
+	for key, stored := range s.series {
+		i := sort.Search(len(stored.points), func(i int) bool { return stored.points[i].Timestamp >= cutoff })
+		stored.points = stored.points[i:]
+		if len(stored.points) == 0 {
+			delete(s.series, key)
+		}
+	}
+
+	dropped := 0
+	for _, serie := range series {
+		if !s.Handles(serie.Name) || !hasPointSince(serie, cutoff) {
+			continue
+		}
+
+		key := serieKey(serie)
+		stored, found := s.series[key]
+		if !found {
+			if len(s.series) >= s.maxSeries {
+				dropped++
+				continue
+			}
+			stored = &storedSerie{
+				name: serie.Name,
+				tags: make(map[string]struct{}, len(serie.Tags)),
+			}
+			for _, tag := range serie.Tags {
+				stored.tags[tag] = struct{}{}
+			}
+			s.series[key] = stored
+		}
+
+		for _, point := range serie.Points {
+			if point.Timestamp >= cutoff {
+				stored.points = append(stored.points, point)
+			}
+		}
+		sort.SliceStable(stored.points, func(i, j int) bool { return stored.points[i].Timestamp < stored.points[j].Timestamp })
+	}
+
+	if dropped > 0 {
+		log.Warnf("Dropped %d series forwarded by the node agents, the store already holds %d series. Consider increasing external_metrics_provider.local.max_series", dropped, s.maxSeries)
+	}
+}
+
+// Query aggregates the points of the series matching a query over a time
+// window ending now. The points of each serie are averaged, then the values of
+// the series are aggregated with the aggregator of the query. It returns the
+// value along with the time of the most recent point.
+func (s *Store) Query(query Query, window time.Duration, now time.Time) (float64, time.Time, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	cutoff := now.Add(-window).Unix()
+	var values []float64
+	var latest int64
+	for _, stored := range s.series {
+		if stored.name != query.Metric || !stored.hasTags(query.Tags) {
+			continue
+		}
+
+		sum, count := 0.0, 0
+		for _, point := range stored.points {
+			if point.Timestamp < cutoff || point.Timestamp > now.Unix() {
+				continue
+			}
+			sum += point.Value
+			count++
+			if point.Timestamp > latest {
+				latest = point.Timestamp
+			}
+		}
+		if count > 0 {
+			values = append(values, sum/float64(count))
+		}
+	}
+
+	if len(values) == 0 {
+		return 0, time.Time{}, fmt.Errorf("no point of %s with tags %v forwarded by the node agents in the last %s", query.Metric, query.Tags, window)
+	}
+
+	value, err := aggregate(query.Aggregator, values)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return value, time.Unix(latest, 0).UTC(), nil
+}
+
+func (s *storedSerie) hasTags(tags []string) bool {
+	for _, tag := range tags {
+		if _, found := s.tags[tag]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+func aggregate(aggregator string, values []float64) (float64, error) {
+	switch aggregator {
+	case "avg":
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values)), nil
+	case "sum":
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum, nil
+	case "max":
+		max := math.Inf(-1)
+		for _, v := range values {
+			max = math.Max(max, v)
+		}
+		return max, nil
+	default:
+		return 0, fmt.Errorf("unsupported aggregator %s", aggregator)
+	}
+}
+
+func hasPointSince(serie Serie, cutoff int64) bool {
+	for _, point := range serie.Points {
+		if point.Timestamp >= cutoff {
+			return true
+		}
+	}
+	return false
+}
+
+// serieKey identifies the context of a serie on the node it's forwarded from,
+// so that the series of different nodes are aggregated separately
+func serieKey(serie Serie) string {
+	tags := make([]string, len(serie.Tags))
+	copy(tags, serie.Tags)
+	sort.Strings(tags)
+	return serie.Host + ":" + serie.Name + "{" + strings.Join(tags, ",") + "}"
+}
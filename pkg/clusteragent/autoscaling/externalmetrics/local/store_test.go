@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	now := time.Unix(10000, 0)
+	store := NewStore([]string{"requests"}, 10*time.Minute, 100)
+
+	store.Add([]Serie{
+		{
+			Name:   "requests",
+			Tags:   []string{"kube_deployment:web", "pod_name:web-1"},
+			Points: []Point{{Timestamp: 9900, Value: 10}, {Timestamp: 9960, Value: 20}},
+		},
+		{
+			Name:   "requests",
+			Tags:   []string{"pod_name:web-2", "kube_deployment:web"},
+			Points: []Point{{Timestamp: 9970, Value: 40}},
+		},
+		{
+			Name:   "requests",
+			Tags:   []string{"kube_deployment:api", "pod_name:api-1"},
+			Points: []Point{{Timestamp: 9970, Value: 100}},
+		},
+		{
+			Name:   "other",
+			Tags:   []string{"kube_deployment:web"},
+			Points: []Point{{Timestamp: 9970, Value: 1000}},
+		},
+	}, now)
+	// Same context as web-1, with tags in a different order
+	store.Add([]Serie{{
+		Name:   "requests",
+		Tags:   []string{"pod_name:web-1", "kube_deployment:web"},
+		Points: []Point{{Timestamp: 9990, Value: 30}},
+	}}, now)
+	assert.Len(t, store.series, 3)
+
+	web := []string{"kube_deployment:web"}
+	tests := []struct {
+		query  Query
+		window time.Duration
+		value  float64
+		latest int64
+	}{
+		// web-1 averages to 20 over 5 minutes and 25 over 1 minute, web-2 to 40
+		{query: Query{Aggregator: "avg", Metric: "requests", Tags: web}, window: 5 * time.Minute, value: 30, latest: 9990},
+		{query: Query{Aggregator: "sum", Metric: "requests", Tags: web}, window: 5 * time.Minute, value: 60, latest: 9990},
+		{query: Query{Aggregator: "max", Metric: "requests", Tags: web}, window: 5 * time.Minute, value: 40, latest: 9990},
+		{query: Query{Aggregator: "sum", Metric: "requests", Tags: web}, window: time.Minute, value: 65, latest: 9990},
+		{query: Query{Aggregator: "sum", Metric: "requests"}, window: time.Minute, value: 165, latest: 9990},
+	}
+	for _, test := range tests {
+		value, latest, err := store.Query(test.query, test.window, now)
+		require.NoError(t, err)
+		assert.Equal(t, test.value, value)
+		assert.Equal(t, time.Unix(test.latest, 0).UTC(), latest)
+	}
+
+	_, _, err := store.Query(Query{Aggregator: "avg", Metric: "other"}, time.Minute, now)
+	assert.EqualError(t, err, "no point of other with tags [] forwarded by the node agents in the last 1m0s")
+
+	// Points older than the retention period are dropped
+	store.Add(nil, now.Add(10*time.Minute).Add(-15*time.Second))
+	require.Len(t, store.series, 1)
+	value, _, err := store.Query(Query{Aggregator: "sum", Metric: "requests"}, time.Hour, now.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, value)
+}
+
+func TestStoreHosts(t *testing.T) {
+	now := time.Unix(10000, 0)
+	store := NewStore([]string{"requests"}, 10*time.Minute, 100)
+
+	// The same context is reported by the agents of two nodes
+	store.Add([]Serie{
+		{Name: "requests", Host: "node-1", Tags: []string{"kube_deployment:web"}, Points: []Point{{Timestamp: 9990, Value: 10}}},
+		{Name: "requests", Host: "node-2", Tags: []string{"kube_deployment:web"}, Points: []Point{{Timestamp: 9990, Value: 30}}},
+	}, now)
+	assert.Len(t, store.series, 2)
+
+	value, _, err := store.Query(Query{Aggregator: "sum", Metric: "requests", Tags: []string{"kube_deployment:web"}}, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, value)
+
+	value, _, err = store.Query(Query{Aggregator: "avg", Metric: "requests", Tags: []string{"kube_deployment:web"}}, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, value)
+}
+
+func TestStoreMaxSeries(t *testing.T) {
+	now := time.Unix(10000, 0)
+	store := NewStore([]string{"requests"}, 10*time.Minute, 2)
+
+	store.Add([]Serie{
+		{Name: "requests", Tags: []string{"pod_name:web-1"}, Points: []Point{{Timestamp: 9990, Value: 10}}},
+		{Name: "requests", Tags: []string{"pod_name:web-2"}, Points: []Point{{Timestamp: 9990, Value: 20}}},
+		{Name: "requests", Tags: []string{"pod_name:web-3"}, Points: []Point{{Timestamp: 9990, Value: 40}}},
+	}, now)
+	assert.Len(t, store.series, 2)
+
+	// The points of the stored series are still added
+	store.Add([]Serie{
+		{Name: "requests", Tags: []string{"pod_name:web-1"}, Points: []Point{{Timestamp: 9995, Value: 30}}},
+	}, now)
+	value, _, err := store.Query(Query{Aggregator: "sum", Metric: "requests"}, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, value)
+
+	// New series are stored again once the expired ones are dropped
+	later := now.Add(10 * time.Minute)
+	store.Add([]Serie{
+		{Name: "requests", Tags: []string{"pod_name:web-3"}, Points: []Point{{Timestamp: later.Unix(), Value: 40}}},
+	}, later)
+	value, _, err = store.Query(Query{Aggregator: "sum", Metric: "requests"}, time.Minute, later)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, value)
+}
@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package local
+
+import (
+	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
+)
+
+type (
+	// Point is an alias to apiv1.LocalExternalMetricsPoint
+	Point = apiv1.LocalExternalMetricsPoint
+	// Serie is an alias to apiv1.LocalExternalMetricsSerie
+	Serie = apiv1.LocalExternalMetricsSerie
+	// Payload is an alias to apiv1.LocalExternalMetricsPayload
+	Payload = apiv1.LocalExternalMetricsPayload
+)
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/model"
 	"github.com/DataDog/datadog-agent/pkg/util/backoff"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
@@ -39,16 +40,20 @@ type MetricsRetriever struct {
 	splitBatchBackoffOnErrors bool
 	processor                 autoscalers.ProcessorInterface
 	store                     *DatadogMetricsInternalStore
+	localStore                *local.Store
 	isLeader                  func() bool
 }
 
-// NewMetricsRetriever returns a new MetricsRetriever
-func NewMetricsRetriever(refreshPeriod, metricsMaxAge int64, processor autoscalers.ProcessorInterface, isLeader func() bool, store *DatadogMetricsInternalStore, splitBatchBackoffOnErrors bool) (*MetricsRetriever, error) {
+// NewMetricsRetriever returns a new MetricsRetriever. localStore holds the
+// metrics forwarded by the node agents, it is nil when all the queries are
+// resolved by the Datadog API.
+func NewMetricsRetriever(refreshPeriod, metricsMaxAge int64, processor autoscalers.ProcessorInterface, isLeader func() bool, store *DatadogMetricsInternalStore, localStore *local.Store, splitBatchBackoffOnErrors bool) (*MetricsRetriever, error) {
 	return &MetricsRetriever{
 		refreshPeriod:             refreshPeriod,
 		metricsMaxAge:             metricsMaxAge,
 		processor:                 processor,
 		store:                     store,
+		localStore:                localStore,
 		isLeader:                  isLeader,
 		splitBatchBackoffOnErrors: splitBatchBackoffOnErrors,
 	}, nil
@@ -113,6 +118,13 @@ func (mr *MetricsRetriever) retrieveMetricsValuesSlice(datadogMetrics []model.Da
 		return
 	}
 
+	if mr.localStore != nil {
+		datadogMetrics = mr.retrieveLocalMetricsValues(datadogMetrics)
+		if len(datadogMetrics) == 0 {
+			return
+		}
+	}
+
 	queriesByTimeWindow := getBatchedQueriesByTimeWindow(datadogMetrics)
 	resultsByTimeWindow := make(map[time.Duration]map[string]autoscalers.Point)
 	globalError := false
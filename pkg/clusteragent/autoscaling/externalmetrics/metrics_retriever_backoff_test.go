@@ -88,7 +88,7 @@ func (f *metricsFixtureWithBackoff) runWithBackoff(t *testing.T, testTime time.T
 		errIndex:        0,
 		extQueryCounter: 0,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, true)
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, nil, true)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 
@@ -141,7 +141,7 @@ func (f *metricsFixtureWithBackoff) runQueryOnly(t *testing.T) {
 		errIndex:        0,
 		extQueryCounter: 0,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, true)
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, nil, true)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 	assert.Equal(t, f.extQueryCount, mockedProcessor.extQueryCounter)
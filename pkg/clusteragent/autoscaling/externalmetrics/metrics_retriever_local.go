@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package externalmetrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/model"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// retrieveLocalMetricsValues resolves the DatadogMetrics querying a metric
+// forwarded by the node agents from the local store, and returns the other
+// ones, which are resolved by the Datadog API.
+func (mr *MetricsRetriever) retrieveLocalMetricsValues(datadogMetrics []model.DatadogMetricInternal) []model.DatadogMetricInternal {
+	remoteMetrics := make([]model.DatadogMetricInternal, 0, len(datadogMetrics))
+	currentTime := time.Now().UTC()
+
+	for _, datadogMetric := range datadogMetrics {
+		query, ok := local.ParseQuery(datadogMetric.Query())
+		if !ok || !mr.localStore.Handles(query.Metric) {
+			remoteMetrics = append(remoteMetrics, datadogMetric)
+			continue
+		}
+
+		datadogMetricFromStore := mr.store.LockRead(datadogMetric.ID, false)
+		if datadogMetricFromStore == nil {
+			// This metric is not in the store anymore, discard it
+			log.Debugf("Discarding local results for DatadogMetric: %s as not present in store anymore", datadogMetric.ID)
+			continue
+		}
+
+		timeWindow := datadogMetric.GetTimeWindow()
+		if timeWindow == 0 {
+			timeWindow = autoscalers.GetDefaultTimeWindow()
+		}
+
+		value, dataTime, err := mr.localStore.Query(query, timeWindow, currentTime)
+		if err != nil {
+			log.Debugf("Unable to resolve DatadogMetric %s locally: %v", datadogMetric.ID, err)
+			datadogMetricFromStore.Valid = false
+			datadogMetricFromStore.Error = fmt.Errorf(invalidMetricErrorMessage, err, datadogMetric.Query())
+		} else {
+			datadogMetricFromStore.Retries = 0
+			datadogMetricFromStore.Value = value
+			datadogMetricFromStore.DataTime = dataTime
+
+			// If we get a valid but old metric, flag it as invalid
+			maxAge := datadogMetric.MaxAge
+			if maxAge == 0 {
+				maxAge = time.Duration(mr.metricsMaxAge) * time.Second
+			}
+
+			if currentTime.Sub(dataTime) <= maxAge {
+				datadogMetricFromStore.Valid = true
+				datadogMetricFromStore.Error = nil
+			} else {
+				datadogMetricFromStore.Valid = false
+				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, datadogMetric.Query())
+			}
+		}
+		datadogMetricFromStore.UpdateTime = currentTime
+
+		mr.store.UnlockSet(datadogMetric.ID, *datadogMetricFromStore, metricRetrieverStoreID)
+	}
+
+	return remoteMetrics
+}
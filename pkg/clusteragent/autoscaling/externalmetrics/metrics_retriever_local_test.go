@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package externalmetrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/local"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/externalmetrics/model"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+)
+
+func TestRetrieveLocalMetrics(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	localStore := local.NewStore([]string{"requests", "queue.depth"}, 10*time.Minute, 100)
+	localStore.Add([]local.Serie{
+		{Name: "requests", Tags: []string{"kube_deployment:web", "pod_name:web-1"}, Points: []local.Point{{Timestamp: now.Unix() - 5, Value: 10}}},
+		{Name: "requests", Tags: []string{"kube_deployment:web", "pod_name:web-2"}, Points: []local.Point{{Timestamp: now.Unix() - 10, Value: 30}}},
+		{Name: "queue.depth", Tags: []string{"queue:jobs"}, Points: []local.Point{{Timestamp: now.Unix() - 120, Value: 7}}},
+	}, now)
+
+	queries := map[string]string{
+		"local":     "sum:requests{kube_deployment:web}",
+		"outdated":  "max:queue.depth{queue:jobs}",
+		"no-points": "avg:requests{kube_deployment:api}",
+		"remote":    "avg:requests{kube_deployment:web}.rollup(60)",
+		"other":     "avg:cpu{kube_deployment:web}",
+	}
+	store := NewDatadogMetricsInternalStore()
+	for id, query := range queries {
+		ddm := model.DatadogMetricInternal{ID: id, Active: true, TimeWindow: 5 * time.Minute}
+		ddm.SetQueries(query)
+		store.Set(id, ddm, "utest")
+	}
+
+	processor := mockedProcessor{points: map[string]autoscalers.Point{
+		queries["remote"]: {Value: 15, Timestamp: now.Unix(), Valid: true},
+		queries["other"]:  {Value: 0.5, Timestamp: now.Unix(), Valid: true},
+	}}
+	metricsRetriever, err := NewMetricsRetriever(0, 30, &processor, getIsLeaderFunction(true), &store, localStore, false)
+	require.NoError(t, err)
+	metricsRetriever.retrieveMetricsValues()
+
+	ddm := store.Get("local")
+	assert.True(t, ddm.Valid)
+	assert.NoError(t, ddm.Error)
+	assert.Equal(t, 40.0, ddm.Value)
+	assert.Equal(t, now.Add(-5*time.Second), ddm.DataTime)
+
+	ddm = store.Get("outdated")
+	assert.False(t, ddm.Valid)
+	assert.Equal(t, 7.0, ddm.Value)
+	assert.EqualError(t, ddm.Error, fmt.Sprintf(invalidMetricOutdatedErrorMessage, queries["outdated"]))
+
+	ddm = store.Get("no-points")
+	assert.False(t, ddm.Valid)
+	assert.EqualError(t, ddm.Error, "no point of requests with tags [kube_deployment:api] forwarded by the node agents in the last 5m0s, query was: avg:requests{kube_deployment:api}")
+
+	for _, id := range []string{"remote", "other"} {
+		ddm = store.Get(id)
+		assert.True(t, ddm.Valid, id)
+		assert.Equal(t, processor.points[queries[id]].Value, ddm.Value, id)
+	}
+}
@@ -78,7 +78,7 @@ func (f *metricsFixture) run(t *testing.T, testTime time.Time) {
 		points: f.queryResults,
 		err:    f.queryError,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, false)
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, nil, false)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 
@@ -60,8 +60,9 @@ func newChecksDistribution(workersPerRunner map[string]int) checksDistribution {
 // several options, it gives preference to preferredRunner. If preferredRunner
 // is not among the runners with the lowest utilization, it gives precedence to
 // the runner with the lowest number of checks deployed. excludeRunner can be set
-// to avoid assigning a check to a specific runner.
-func (distribution *checksDistribution) leastBusyRunner(preferredRunner string, excludeRunner string) string {
+// to avoid assigning a check to a specific runner. When allowedRunners is not
+// nil, only the runners it contains are considered.
+func (distribution *checksDistribution) leastBusyRunner(preferredRunner string, excludeRunner string, allowedRunners map[string]struct{}) string {
 	leastBusyRunner := ""
 	minUtilization := 0.0
 	numChecksLeastBusyRunner := 0
@@ -72,6 +73,10 @@ func (distribution *checksDistribution) leastBusyRunner(preferredRunner string,
 			continue
 		}
 
+		if !isAllowed(allowedRunners, runnerName) {
+			continue
+		}
+
 		runnerUtilization := runnerStatus.utilization()
 		runnerNumChecks := runnerStatus.NumChecks
 
@@ -90,8 +95,8 @@ func (distribution *checksDistribution) leastBusyRunner(preferredRunner string,
 	return leastBusyRunner
 }
 
-func (distribution *checksDistribution) addToLeastBusy(checkID string, workersNeeded float64, preferredRunner string, excludeRunner string) {
-	leastBusy := distribution.leastBusyRunner(preferredRunner, excludeRunner)
+func (distribution *checksDistribution) addToLeastBusy(checkID string, workersNeeded float64, preferredRunner string, excludeRunner string, allowedRunners map[string]struct{}) {
+	leastBusy := distribution.leastBusyRunner(preferredRunner, excludeRunner, allowedRunners)
 	if leastBusy == "" {
 		return
 	}
@@ -27,8 +27,9 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 	defer d.store.RUnlock()
 
 	response := types.StateResponse{
-		Warmup:   !d.store.active,
-		Dangling: makeConfigArray(d.store.danglingConfigs),
+		Warmup:     !d.store.active,
+		Dangling:   makeConfigArray(d.store.danglingConfigs),
+		Placements: d.getPlacements(),
 	}
 	for _, node := range d.store.nodes {
 		n := types.StateNodeResponse{
@@ -95,6 +96,7 @@ func (d *dispatcher) removeConfig(digest string) {
 	delete(d.store.digestToNode, digest)
 	delete(d.store.digestToConfig, digest)
 	delete(d.store.danglingConfigs, digest)
+	delete(d.store.placements, digest)
 
 	// This is a list because each instance in a config has its own check ID and
 	// all of them need to be deleted.
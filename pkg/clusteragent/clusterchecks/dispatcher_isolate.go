@@ -35,6 +35,7 @@ func (d *dispatcher) isolateCheck(isolateCheckID string) types.IsolateResponse {
 	}
 
 	proposedDistribution := newChecksDistribution(currentDistribution.runnerWorkers())
+	placements := d.placementSnapshot()
 
 	for _, checkID := range currentDistribution.checksSortedByWorkersNeeded() {
 		if checkID == isolateCheckID {
@@ -50,6 +51,7 @@ func (d *dispatcher) isolateCheck(isolateCheckID string) types.IsolateResponse {
 			workersNeededForCheck,
 			runnerForCheck,
 			isolateNode,
+			placements.candidates(checkID, proposedDistribution),
 		)
 	}
 
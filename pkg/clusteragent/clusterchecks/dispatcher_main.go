@@ -161,15 +161,16 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
-	target := d.getNodeToScheduleCheck()
+	target, reason := d.getNodeToScheduleCheck(config)
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
-		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		log.Warnf("No available node to dispatch %s:%s on (%s), will retry later", config.Name, config.Digest(), reason)
 	} else {
-		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, config.Digest(), target)
+		log.Infof("Dispatching configuration %s:%s to node %s: %s", config.Name, config.Digest(), target, reason)
 	}
 
 	d.addConfig(config, target)
+	d.setPlacement(config.Digest(), reason)
 }
 
 // remove deletes a given configuration
@@ -51,6 +51,8 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 	node := d.store.getOrCreateNodeStore(nodeName, clientIP)
 	d.store.Unlock()
 
+	d.updateNodeLabels(node, status.NodeName)
+
 	node.Lock()
 	defer node.Unlock()
 	node.heartbeat = timestampNow()
@@ -76,8 +78,10 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 	return false
 }
 
-// getNodeToScheduleCheck returns the node where a new check should be scheduled
-
+// getNodeToScheduleCheck returns the node where a new check should be
+// scheduled, among the ones matching its placement constraints, along with
+// the reason of the choice.
+//
 // Advanced dispatching relies on the check stats fetched from the cluster check
 // runners API to distribute the checks. The stats are only updated when the
 // checks are rebalanced, they are not updated every time a check is scheduled.
@@ -88,20 +92,38 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 //
 // On the other hand, when advanced dispatching is not used, we can pick the
 // node with fewer checks. It's because the number of checks is kept up to date.
-func (d *dispatcher) getNodeToScheduleCheck() string {
+func (d *dispatcher) getNodeToScheduleCheck(config integration.Config) (string, string) {
+	candidates, reasons := d.placementCandidates(config)
+	if candidates != nil && len(candidates) == 0 {
+		return "", formatReasons(reasons)
+	}
+
+	var node string
 	if d.advancedDispatching {
-		return d.getRandomNode()
+		node = d.getRandomNode(candidates)
+		reasons = append(reasons, "random node")
+	} else {
+		node = d.getNodeWithLessChecks(candidates)
+		reasons = append(reasons, "node with the fewest checks")
+	}
+	if node == "" {
+		return "", "no node available"
 	}
 
-	return d.getNodeWithLessChecks()
+	return node, formatReasons(reasons)
 }
 
-func (d *dispatcher) getRandomNode() string {
+// getRandomNode returns a random node among the allowed ones, or among all
+// the nodes if allowed is nil
+func (d *dispatcher) getRandomNode(allowed map[string]struct{}) string {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
 	var nodes []string
 	for name := range d.store.nodes {
+		if !isAllowed(allowed, name) {
+			continue
+		}
 		nodes = append(nodes, name)
 	}
 
@@ -112,7 +134,9 @@ func (d *dispatcher) getRandomNode() string {
 	return nodes[rand.Intn(len(nodes))]
 }
 
-func (d *dispatcher) getNodeWithLessChecks() string {
+// getNodeWithLessChecks returns the allowed node with the fewest checks, all
+// the nodes being allowed if allowed is nil
+func (d *dispatcher) getNodeWithLessChecks(allowed map[string]struct{}) string {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
@@ -120,6 +144,9 @@ func (d *dispatcher) getNodeWithLessChecks() string {
 	minNumChecks := 0
 
 	for name, store := range d.store.nodes {
+		if !isAllowed(allowed, name) {
+			continue
+		}
 		if selectedNode == "" || len(store.digestToConfig) < minNumChecks {
 			selectedNode = name
 			minNumChecks = len(store.digestToConfig)
@@ -152,6 +179,7 @@ func (d *dispatcher) expireNodes() {
 				log.Debugf("Adding %s:%s as a dangling Cluster Check config", config.Name, digest)
 				d.store.danglingConfigs[digest] = config
 				danglingConfigs.Inc(le.JoinLeaderValue)
+				d.store.placements[digest] = fmt.Sprintf("node %s expired", name)
 
 				// TODO: Use partial label matching when it becomes available:
 				// Replace the loop by a single function call (delete by node name).
@@ -0,0 +1,297 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// nodeLabelsRefreshInterval is how often the labels of the Kubernetes node of
+// an agent are resolved again, so that relabelled nodes are taken into account
+const nodeLabelsRefreshInterval = 5 * time.Minute
+
+// nodesForPlacement returns the nodes, among the ones of nodeLabels, where a
+// config can be dispatched according to its placement constraints, along
+// with the reasons of the choice. groupNodes holds the nodes running another
+// config of the anti-affinity group of the config.
+//
+// The constraints are applied in order:
+//   - the required node labels are mandatory, no node is returned when no
+//     node has them
+//   - the nodes not running a config of the anti-affinity group are kept
+//     if there are some
+//   - the nodes matching the most preferred node labels are kept if some
+//     match at least one
+func nodesForPlacement(placement *integration.Placement, nodeLabels map[string]map[string]string, groupNodes map[string]struct{}) (map[string]struct{}, []string) {
+	candidates := make(map[string]struct{}, len(nodeLabels))
+	for node := range nodeLabels {
+		candidates[node] = struct{}{}
+	}
+	var reasons []string
+
+	if len(placement.RequiredNodeLabels) > 0 {
+		required := formatLabels(placement.RequiredNodeLabels)
+		for node := range candidates {
+			if matchingLabels(nodeLabels[node], placement.RequiredNodeLabels) < len(placement.RequiredNodeLabels) {
+				delete(candidates, node)
+			}
+		}
+		if len(candidates) == 0 {
+			return candidates, []string{"no node has the required labels " + required}
+		}
+		reasons = append(reasons, "node has the required labels "+required)
+	}
+
+	if group := placement.AntiAffinityGroup; group != "" {
+		free := make(map[string]struct{}, len(candidates))
+		for node := range candidates {
+			if _, found := groupNodes[node]; !found {
+				free[node] = struct{}{}
+			}
+		}
+		if len(free) > 0 {
+			candidates = free
+			reasons = append(reasons, fmt.Sprintf("no other check of anti-affinity group %s on the node", group))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("all eligible nodes run a check of anti-affinity group %s", group))
+		}
+	}
+
+	if len(placement.PreferredNodeLabels) > 0 {
+		best := 0
+		preferred := map[string]struct{}{}
+		for node := range candidates {
+			matching := matchingLabels(nodeLabels[node], placement.PreferredNodeLabels)
+			if matching == 0 || matching < best {
+				continue
+			}
+			if matching > best {
+				best = matching
+				preferred = map[string]struct{}{}
+			}
+			preferred[node] = struct{}{}
+		}
+		if best > 0 {
+			candidates = preferred
+			reasons = append(reasons, fmt.Sprintf("node has %d/%d preferred labels %s", best, len(placement.PreferredNodeLabels), formatLabels(placement.PreferredNodeLabels)))
+		} else {
+			reasons = append(reasons, "no eligible node has the preferred labels "+formatLabels(placement.PreferredNodeLabels))
+		}
+	}
+
+	return candidates, reasons
+}
+
+// matchingLabels returns how many of the expected labels are set on a node
+func matchingLabels(nodeLabels, expected map[string]string) int {
+	matching := 0
+	for k, v := range expected {
+		if value, found := nodeLabels[k]; found && value == v {
+			matching++
+		}
+	}
+	return matching
+}
+
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func formatReasons(reasons []string) string {
+	return strings.Join(reasons, "; ")
+}
+
+// placementCandidates returns the nodes where a config can be dispatched and
+// the reasons of the choice. The returned nodes are nil when the config has
+// no placement constraints.
+func (d *dispatcher) placementCandidates(config integration.Config) (map[string]struct{}, []string) {
+	if config.Placement == nil {
+		return nil, nil
+	}
+
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	var groupNodes map[string]struct{}
+	if group := config.Placement.AntiAffinityGroup; group != "" {
+		groupNodes = make(map[string]struct{})
+		digest := config.Digest()
+		for otherDigest, other := range d.store.digestToConfig {
+			if otherDigest == digest || other.Placement == nil || other.Placement.AntiAffinityGroup != group {
+				continue
+			}
+			if node := d.store.digestToNode[otherDigest]; node != "" {
+				groupNodes[node] = struct{}{}
+			}
+		}
+	}
+
+	return nodesForPlacement(config.Placement, d.store.nodesLabels(), groupNodes)
+}
+
+// nodesLabels returns the labels of the nodes known to the store. The store
+// lock must be held by the caller.
+func (s *clusterStore) nodesLabels() map[string]map[string]string {
+	labels := make(map[string]map[string]string, len(s.nodes))
+	for name, node := range s.nodes {
+		node.RLock()
+		labels[name] = node.nodeLabels
+		node.RUnlock()
+	}
+	return labels
+}
+
+// setPlacement records why a config was dispatched to its node, or why it
+// couldn't be
+func (d *dispatcher) setPlacement(digest, reason string) {
+	d.store.Lock()
+	defer d.store.Unlock()
+	d.store.placements[digest] = reason
+}
+
+// getPlacements returns the placement reasons of the configs. The store lock
+// must be held by the caller.
+func (d *dispatcher) getPlacements() []types.PlacementResponse {
+	placements := make([]types.PlacementResponse, 0, len(d.store.placements))
+	for digest, reason := range d.store.placements {
+		placements = append(placements, types.PlacementResponse{
+			CheckName: d.store.digestToConfig[digest].Name,
+			Digest:    digest,
+			Node:      d.store.digestToNode[digest],
+			Reason:    reason,
+		})
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].CheckName == placements[j].CheckName {
+			return placements[i].Digest < placements[j].Digest
+		}
+		return placements[i].CheckName < placements[j].CheckName
+	})
+	return placements
+}
+
+// updateNodeLabels resolves the labels of the Kubernetes node an agent runs
+// on when it reports a new node name or when they are outdated. The configs
+// of the node whose required labels aren't set anymore are dispatched again.
+func (d *dispatcher) updateNodeLabels(node *nodeStore, kubeNodeName string) {
+	if kubeNodeName == "" {
+		return
+	}
+
+	node.RLock()
+	upToDate := node.kubeNodeName == kubeNodeName && node.nodeLabels != nil && time.Since(node.nodeLabelsUpdate) < nodeLabelsRefreshInterval
+	node.RUnlock()
+	if upToDate {
+		return
+	}
+
+	labels, err := getNodeLabels(kubeNodeName)
+	if err != nil {
+		log.Debugf("Cannot get the labels of node %s, the placement constraints of the cluster checks won't match agent %s: %v", kubeNodeName, node.name, err)
+		return
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	var misplaced []integration.Config
+	node.Lock()
+	if node.nodeLabels != nil && !maps.Equal(node.nodeLabels, labels) {
+		for _, config := range node.digestToConfig {
+			if config.Placement != nil && matchingLabels(labels, config.Placement.RequiredNodeLabels) < len(config.Placement.RequiredNodeLabels) {
+				misplaced = append(misplaced, config)
+			}
+		}
+	}
+	node.kubeNodeName = kubeNodeName
+	node.nodeLabels = labels
+	node.nodeLabelsUpdate = time.Now()
+	node.Unlock()
+
+	for _, config := range misplaced {
+		log.Infof("Node %s doesn't have the labels required by %s:%s anymore, dispatching it again", kubeNodeName, config.Name, config.Digest())
+		d.removeConfig(config.Digest())
+	}
+	d.reschedule(misplaced)
+}
+
+// placementSnapshot holds the state needed to honor the placement constraints
+// of the checks when building a new checks distribution
+type placementSnapshot struct {
+	nodeLabels map[string]map[string]string
+	digests    map[string]string // digest of the config of each check ID
+	configs    map[string]integration.Config
+}
+
+func (d *dispatcher) placementSnapshot() placementSnapshot {
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	snapshot := placementSnapshot{
+		nodeLabels: d.store.nodesLabels(),
+		digests:    make(map[string]string, len(d.store.idToDigest)),
+		configs:    make(map[string]integration.Config, len(d.store.digestToConfig)),
+	}
+	for id, digest := range d.store.idToDigest {
+		snapshot.digests[string(id)] = digest
+	}
+	for digest, config := range d.store.digestToConfig {
+		snapshot.configs[digest] = config
+	}
+	return snapshot
+}
+
+// candidates returns the runners a check can be placed on in a distribution,
+// or nil if the check has no placement constraints
+func (s placementSnapshot) candidates(checkID string, distribution checksDistribution) map[string]struct{} {
+	digest := s.digests[checkID]
+	config, found := s.configs[digest]
+	if !found || config.Placement == nil {
+		return nil
+	}
+
+	var groupNodes map[string]struct{}
+	if group := config.Placement.AntiAffinityGroup; group != "" {
+		groupNodes = make(map[string]struct{})
+		for otherID, status := range distribution.Checks {
+			otherDigest := s.digests[otherID]
+			if otherDigest == digest {
+				continue
+			}
+			if other := s.configs[otherDigest]; other.Placement != nil && other.Placement.AntiAffinityGroup == group {
+				groupNodes[status.Runner] = struct{}{}
+			}
+		}
+	}
+
+	candidates, _ := nodesForPlacement(config.Placement, s.nodeLabels, groupNodes)
+	return candidates
+}
+
+// isAllowed returns whether a node belongs to the allowed ones, all the
+// nodes being allowed if allowed is nil
+func isAllowed(allowed map[string]struct{}, node string) bool {
+	if allowed == nil {
+		return true
+	}
+	_, found := allowed[node]
+	return found
+}
@@ -0,0 +1,246 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func TestNodesForPlacement(t *testing.T) {
+	nodeLabels := map[string]map[string]string{
+		"runner-a1": {"zone": "a", "pool": "checks"},
+		"runner-a2": {"zone": "a"},
+		"runner-b1": {"zone": "b", "pool": "checks"},
+		"runner-x":  nil,
+	}
+
+	tests := []struct {
+		name       string
+		placement  integration.Placement
+		groupNodes map[string]struct{}
+		expected   map[string]struct{}
+		reasons    []string
+	}{
+		{
+			name:      "required labels",
+			placement: integration.Placement{RequiredNodeLabels: map[string]string{"zone": "a"}},
+			expected:  map[string]struct{}{"runner-a1": {}, "runner-a2": {}},
+			reasons:   []string{"node has the required labels zone=a"},
+		},
+		{
+			name:      "required labels not found",
+			placement: integration.Placement{RequiredNodeLabels: map[string]string{"zone": "c"}},
+			expected:  map[string]struct{}{},
+			reasons:   []string{"no node has the required labels zone=c"},
+		},
+		{
+			name:      "preferred labels",
+			placement: integration.Placement{PreferredNodeLabels: map[string]string{"zone": "a", "pool": "checks"}},
+			expected:  map[string]struct{}{"runner-a1": {}},
+			reasons:   []string{"node has 2/2 preferred labels pool=checks,zone=a"},
+		},
+		{
+			name:      "preferred labels not found",
+			placement: integration.Placement{PreferredNodeLabels: map[string]string{"zone": "c"}},
+			expected:  map[string]struct{}{"runner-a1": {}, "runner-a2": {}, "runner-b1": {}, "runner-x": {}},
+			reasons:   []string{"no eligible node has the preferred labels zone=c"},
+		},
+		{
+			name: "anti-affinity then preferred labels",
+			placement: integration.Placement{
+				RequiredNodeLabels:  map[string]string{"pool": "checks"},
+				PreferredNodeLabels: map[string]string{"zone": "a"},
+				AntiAffinityGroup:   "db",
+			},
+			groupNodes: map[string]struct{}{"runner-a1": {}},
+			expected:   map[string]struct{}{"runner-b1": {}},
+			reasons: []string{
+				"node has the required labels pool=checks",
+				"no other check of anti-affinity group db on the node",
+				"no eligible node has the preferred labels zone=a",
+			},
+		},
+		{
+			name: "anti-affinity cannot be satisfied",
+			placement: integration.Placement{
+				RequiredNodeLabels: map[string]string{"zone": "b"},
+				AntiAffinityGroup:  "db",
+			},
+			groupNodes: map[string]struct{}{"runner-b1": {}},
+			expected:   map[string]struct{}{"runner-b1": {}},
+			reasons: []string{
+				"node has the required labels zone=b",
+				"all eligible nodes run a check of anti-affinity group db",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates, reasons := nodesForPlacement(&test.placement, nodeLabels, test.groupNodes)
+			assert.Equal(t, test.expected, candidates)
+			assert.Equal(t, test.reasons, reasons)
+		})
+	}
+}
+
+func mockNodeLabels(t *testing.T, labels map[string]map[string]string) {
+	original := getNodeLabels
+	getNodeLabels = func(nodeName string) (map[string]string, error) {
+		nodeLabels, found := labels[nodeName]
+		if !found {
+			return nil, fmt.Errorf("node %s not found", nodeName)
+		}
+		return nodeLabels, nil
+	}
+	t.Cleanup(func() { getNodeLabels = original })
+}
+
+func TestProcessNodeStatusNodeLabels(t *testing.T) {
+	mockNodeLabels(t, map[string]map[string]string{
+		"kube-node-1": {"zone": "a"},
+		"kube-node-2": nil,
+	})
+	dispatcher := newDispatcher()
+
+	dispatcher.processNodeStatus("runner1", "10.0.0.1", types.NodeStatus{})
+	dispatcher.processNodeStatus("runner2", "10.0.0.2", types.NodeStatus{NodeName: "kube-node-1"})
+	dispatcher.processNodeStatus("runner3", "10.0.0.3", types.NodeStatus{NodeName: "kube-node-2"})
+	dispatcher.processNodeStatus("runner4", "10.0.0.4", types.NodeStatus{NodeName: "unknown"})
+
+	assert.Equal(t, map[string]map[string]string{
+		"runner1": nil,
+		"runner2": {"zone": "a"},
+		"runner3": {},
+		"runner4": nil,
+	}, dispatcher.store.nodesLabels())
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestUpdateNodeLabelsRefresh(t *testing.T) {
+	labels := map[string]map[string]string{
+		"kube-node-1": {"zone": "a"},
+		"kube-node-2": {"zone": "a"},
+	}
+	mockNodeLabels(t, labels)
+	dispatcher := newDispatcher()
+	dispatcher.processNodeStatus("runner1", "10.0.0.1", types.NodeStatus{NodeName: "kube-node-1"})
+	dispatcher.processNodeStatus("runner2", "10.0.0.2", types.NodeStatus{NodeName: "kube-node-2"})
+
+	config := generateIntegration("zone-a")
+	config.Placement = &integration.Placement{RequiredNodeLabels: map[string]string{"zone": "a"}}
+	dispatcher.Schedule([]integration.Config{config})
+	digest := config.Digest()
+	initialNode := dispatcher.store.digestToNode[digest]
+	require.NotEmpty(t, initialNode)
+	otherNode, otherKubeNode := "runner2", "kube-node-2"
+	initialKubeNode := "kube-node-1"
+	if initialNode == "runner2" {
+		otherNode, otherKubeNode, initialKubeNode = "runner1", "kube-node-1", "kube-node-2"
+	}
+
+	// the node is relabelled, the cached labels are used until they expire
+	labels[initialKubeNode] = map[string]string{"zone": "b"}
+	dispatcher.processNodeStatus(initialNode, "", types.NodeStatus{NodeName: initialKubeNode})
+	assert.Equal(t, initialNode, dispatcher.store.digestToNode[digest])
+
+	node, _ := dispatcher.store.getNodeStore(initialNode)
+	node.nodeLabelsUpdate = time.Now().Add(-nodeLabelsRefreshInterval)
+	dispatcher.processNodeStatus(initialNode, "", types.NodeStatus{NodeName: initialKubeNode})
+	assert.Equal(t, map[string]string{"zone": "b"}, dispatcher.store.nodesLabels()[initialNode])
+	assert.Equal(t, otherNode, dispatcher.store.digestToNode[digest])
+
+	// no node has the required labels anymore
+	labels[otherKubeNode] = map[string]string{"zone": "b"}
+	node, _ = dispatcher.store.getNodeStore(otherNode)
+	node.nodeLabelsUpdate = time.Time{}
+	dispatcher.processNodeStatus(otherNode, "", types.NodeStatus{NodeName: otherKubeNode})
+	assert.Empty(t, dispatcher.store.digestToNode[digest])
+	assert.Contains(t, dispatcher.store.danglingConfigs, digest)
+	node.RLock()
+	assert.Empty(t, node.digestToConfig)
+	node.RUnlock()
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestDispatchWithPlacement(t *testing.T) {
+	mockNodeLabels(t, map[string]map[string]string{
+		"kube-node-a1": {"zone": "a"},
+		"kube-node-a2": {"zone": "a"},
+		"kube-node-b1": {"zone": "b"},
+	})
+	dispatcher := newDispatcher()
+	dispatcher.processNodeStatus("runner-a1", "10.0.0.1", types.NodeStatus{NodeName: "kube-node-a1"})
+	dispatcher.processNodeStatus("runner-a2", "10.0.0.2", types.NodeStatus{NodeName: "kube-node-a2"})
+	dispatcher.processNodeStatus("runner-b1", "10.0.0.3", types.NodeStatus{NodeName: "kube-node-b1"})
+
+	replica := func(name string) integration.Config {
+		config := generateIntegration(name)
+		config.Placement = &integration.Placement{
+			RequiredNodeLabels: map[string]string{"zone": "a"},
+			AntiAffinityGroup:  "db",
+		}
+		return config
+	}
+	unschedulable := generateIntegration("unschedulable")
+	unschedulable.Placement = &integration.Placement{RequiredNodeLabels: map[string]string{"zone": "c"}}
+	preferred := generateIntegration("preferred")
+	preferred.Placement = &integration.Placement{PreferredNodeLabels: map[string]string{"zone": "b"}}
+
+	db1, db2 := replica("db-1"), replica("db-2")
+	dispatcher.Schedule([]integration.Config{db1, db2, unschedulable, preferred})
+
+	db1Node := dispatcher.store.digestToNode[db1.Digest()]
+	db2Node := dispatcher.store.digestToNode[db2.Digest()]
+	assert.Contains(t, []string{"runner-a1", "runner-a2"}, db1Node)
+	assert.Contains(t, []string{"runner-a1", "runner-a2"}, db2Node)
+	assert.NotEqual(t, db1Node, db2Node)
+	assert.Equal(t, "runner-b1", dispatcher.store.digestToNode[preferred.Digest()])
+	assert.Contains(t, dispatcher.store.danglingConfigs, unschedulable.Digest())
+
+	state, err := dispatcher.getState()
+	require.NoError(t, err)
+	require.Len(t, state.Placements, 4)
+	placements := make(map[string]types.PlacementResponse, len(state.Placements))
+	for _, placement := range state.Placements {
+		placements[placement.CheckName] = placement
+	}
+	assert.Equal(t, "node has the required labels zone=a; no other check of anti-affinity group db on the node; node with the fewest checks", placements["db-2"].Reason)
+	assert.Equal(t, db2Node, placements["db-2"].Node)
+	assert.Equal(t, types.PlacementResponse{
+		CheckName: "unschedulable",
+		Digest:    unschedulable.Digest(),
+		Reason:    "no node has the required labels zone=c",
+	}, placements["unschedulable"])
+	assert.Equal(t, "node has 1/1 preferred labels zone=b; node with the fewest checks", placements["preferred"].Reason)
+
+	dispatcher.Unschedule([]integration.Config{unschedulable})
+	state, err = dispatcher.getState()
+	require.NoError(t, err)
+	assert.Len(t, state.Placements, 3)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestPickNodeWithPlacement(t *testing.T) {
+	diffMap := map[string]int{"node1": 10, "node2": -5, "node3": 0}
+
+	assert.Equal(t, "node2", pickNode(diffMap, "node1", nil))
+	assert.Equal(t, "node3", pickNode(diffMap, "node1", map[string]struct{}{"node1": {}, "node3": {}}))
+	assert.Equal(t, "", pickNode(diffMap, "node1", map[string]struct{}{"node1": {}}))
+}
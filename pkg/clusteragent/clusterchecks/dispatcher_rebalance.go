@@ -120,12 +120,13 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 // if it satisfies the following
 // Diff(Ni) < Diff(Nj) (for each j != i, 0 <= j < len(nodes))
 // where Diff(N) is the difference between the busyness on N and the total average busyness.
-func pickNode(diffMap map[string]int, sourceNode string) string {
+// When allowedNodes is not nil, only the nodes it contains can be picked.
+func pickNode(diffMap map[string]int, sourceNode string, allowedNodes map[string]struct{}) string {
 	firstItr := true
 	minDiff := 0
 	pickedNode := ""
 	for _, node := range orderedKeys(diffMap) {
-		if node == sourceNode {
+		if node == sourceNode || !isAllowed(allowedNodes, node) {
 			continue
 		}
 		if diffMap[node] < minDiff || firstItr {
@@ -166,6 +167,9 @@ func (d *dispatcher) moveCheck(src, dest, checkID string) error {
 	d.removeConfig(digest)
 	d.addConfig(config, dest)
 
+	_, reasons := d.placementCandidates(config)
+	d.setPlacement(digest, formatReasons(append(reasons, "rebalanced from "+src)))
+
 	log.Debugf("Check %s moved from %s to %s", checkID, src, dest)
 
 	return nil
@@ -211,7 +215,13 @@ func (d *dispatcher) rebalanceUsingBusyness() []types.RebalanceResponse {
 				break
 			}
 
-			destNodeName := pickNode(diffMap, sourceNodeName)
+			config, _ := d.getConfigAndDigest(checkID)
+			candidates, _ := d.placementCandidates(config)
+			destNodeName := pickNode(diffMap, sourceNodeName, candidates)
+			if destNodeName == "" {
+				log.Debugf("No node matches the placement constraints to move check %s from node %s", checkID, sourceNodeName)
+				break
+			}
 			sourceDiff := diffMap[sourceNodeName]
 			destDiff := diffMap[destNodeName]
 
@@ -310,6 +320,7 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 	currentChecksDistribution := d.currentDistribution()
 
 	proposedDistribution := newChecksDistribution(currentChecksDistribution.runnerWorkers())
+	placements := d.placementSnapshot()
 
 	// First all the checks that are excluded from rebalancing are added to the
 	// same runner where they are currently running.
@@ -329,6 +340,7 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 				currentChecksDistribution.workersNeededForCheck(checkID),
 				currentChecksDistribution.runnerForCheck(checkID),
 				"",
+				placements.candidates(checkID, proposedDistribution),
 			)
 		}
 	}
@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package clusterchecks
+
+import "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+
+// getNodeLabels returns the labels of a Kubernetes node, overridden in tests
+var getNodeLabels = func(nodeName string) (map[string]string, error) {
+	client, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.NodeLabels(nodeName)
+}
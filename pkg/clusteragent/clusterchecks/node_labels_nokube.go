@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && !kubeapiserver
+
+package clusterchecks
+
+import "errors"
+
+// getNodeLabels returns the labels of a Kubernetes node, overridden in tests
+var getNodeLabels = func(string) (map[string]string, error) {
+	return nil, errors.New("cannot get node labels without the kubeapiserver build tag")
+}
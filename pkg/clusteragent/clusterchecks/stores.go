@@ -10,6 +10,7 @@ package clusterchecks
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
@@ -30,6 +31,7 @@ type clusterStore struct {
 	danglingConfigs  map[string]integration.Config            // Configs we could not dispatch to any node
 	endpointsConfigs map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
 	idToDigest       map[checkid.ID]string                    // link check IDs to check configs
+	placements       map[string]string                        // Reason why a config is on its node, or dangling
 }
 
 func newClusterStore() *clusterStore {
@@ -47,6 +49,7 @@ func (s *clusterStore) reset() {
 	s.danglingConfigs = make(map[string]integration.Config)
 	s.endpointsConfigs = make(map[string]map[string]integration.Config)
 	s.idToDigest = make(map[checkid.ID]string)
+	s.placements = make(map[string]string)
 }
 
 // getNodeStore retrieves the store struct for a given node name, if it exists
@@ -89,6 +92,9 @@ type nodeStore struct {
 	clcRunnerStats   types.CLCRunnersStats
 	busyness         int
 	workers          int
+	kubeNodeName     string            // Kubernetes node the agent runs on
+	nodeLabels       map[string]string // Labels of the Kubernetes node, nil if unknown
+	nodeLabelsUpdate time.Time         // Last time the labels of the Kubernetes node were resolved
 }
 
 func newNodeStore(name, clientIP string) *nodeStore {
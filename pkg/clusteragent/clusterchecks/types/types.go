@@ -19,7 +19,8 @@ const (
 
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
-	LastChange int64 `json:"last_change"`
+	LastChange int64  `json:"last_change"`
+	NodeName   string `json:"node_name,omitempty"` // Kubernetes node the agent runs on, if known
 }
 
 // StatusResponse holds the DCA response for a status report
@@ -59,6 +60,16 @@ type StateResponse struct {
 	Warmup     bool                 `json:"warmup"`
 	Nodes      []StateNodeResponse  `json:"nodes"`
 	Dangling   []integration.Config `json:"dangling"`
+	Placements []PlacementResponse  `json:"placements,omitempty"`
+}
+
+// PlacementResponse holds the reason why a configuration was dispatched to a
+// node, or why it couldn't be
+type PlacementResponse struct {
+	CheckName string `json:"check_name"`
+	Digest    string `json:"digest"`
+	Node      string `json:"node"` // Empty for dangling configurations
+	Reason    string `json:"reason"`
 }
 
 // StateNodeResponse is a chunk of StateResponse
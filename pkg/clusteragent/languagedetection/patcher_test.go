@@ -162,7 +162,7 @@ func TestRun(t *testing.T) {
 				ID:   "test-namespace/test-deployment",
 			},
 			DetectedLanguages: map[langUtil.Container]langUtil.LanguageSet{
-				*langUtil.NewContainer("some-cont"):            {"java": {}, "python": {}},
+				*langUtil.NewContainer("some-cont"):            {"java": {Version: "17.0.8"}, "python": {}},
 				*langUtil.NewInitContainer("python-ruby-init"): {"ruby": {}, "python": {}},
 			},
 		},
@@ -172,6 +172,7 @@ func TestRun(t *testing.T) {
 
 	expectedAnnotations := map[string]string{
 		"internal.dd.datadoghq.com/some-cont.detected_langs":             "java,python",
+		"internal.dd.datadoghq.com/some-cont.detected_lang_versions":     "java:17.0.8",
 		"internal.dd.datadoghq.com/init.python-ruby-init.detected_langs": "python,ruby",
 		"annotationkey1": "annotationvalue1",
 		"annotationkey2": "annotationvalue2",
@@ -219,7 +220,7 @@ func TestRun(t *testing.T) {
 				ID:   "test-namespace/test-deployment",
 			},
 			InjectableLanguages: map[langUtil.Container]langUtil.LanguageSet{
-				*langUtil.NewContainer("some-cont"):            {"java": {}, "python": {}},
+				*langUtil.NewContainer("some-cont"):            {"java": {Version: "17.0.8"}, "python": {}},
 				*langUtil.NewInitContainer("python-ruby-init"): {"ruby": {}, "python": {}},
 			},
 		},
@@ -235,10 +236,10 @@ func TestRun(t *testing.T) {
 			}
 
 			return reflect.DeepEqual(deployment.InjectableLanguages, langUtil.ContainersLanguages{
-				*langUtil.NewContainer("some-cont"):            {"java": {}, "python": {}},
+				*langUtil.NewContainer("some-cont"):            {"java": {Version: "17.0.8"}, "python": {}},
 				*langUtil.NewInitContainer("python-ruby-init"): {"ruby": {}, "python": {}},
 			}) && reflect.DeepEqual(deployment.DetectedLanguages, langUtil.ContainersLanguages{
-				*langUtil.NewContainer("some-cont"):            {"java": {}, "python": {}},
+				*langUtil.NewContainer("some-cont"):            {"java": {Version: "17.0.8"}, "python": {}},
 				*langUtil.NewInitContainer("python-ruby-init"): {"ruby": {}, "python": {}},
 			})
 		},
@@ -0,0 +1,534 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ignoredFields are fields of the manifests that are populated or rewritten by
+// the API server, and are not drift.
+var ignoredFields = map[string]struct{}{
+	"status":                     {},
+	"metadata.namespace":         {},
+	"metadata.creationTimestamp": {},
+}
+
+var hpaResource = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+
+// objectDrift describes an object of a release that differs from its
+// definition in the manifest of the release
+type objectDrift struct {
+	kind      string
+	namespace string
+	name      string
+	// missing is true when the object doesn't exist in the cluster
+	missing bool
+	// fields are the paths of the fields with a different value
+	fields []string
+}
+
+func (od objectDrift) String() string {
+	name := od.name
+	if od.namespace != "" {
+		name = od.namespace + "/" + od.name
+	}
+
+	if od.missing {
+		return fmt.Sprintf("%s %s: missing", od.kind, name)
+	}
+	return fmt.Sprintf("%s %s: %s", od.kind, name, strings.Join(od.fields, ", "))
+}
+
+// releaseDrift is the drift detected on a revision of a release
+type releaseDrift struct {
+	revision int
+	objects  []objectDrift
+}
+
+// driftDetector compares the objects in the manifest of a release with the
+// live objects in the cluster
+type driftDetector struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+func newDriftDetector(client dynamic.Interface, mapper meta.RESTMapper) *driftDetector {
+	return &driftDetector{
+		client: client,
+		mapper: mapper,
+	}
+}
+
+// reset discards the cached API resources, to discover the resources that
+// have been added to the cluster since the previous detection
+func (dd *driftDetector) reset() {
+	if mapper, ok := dd.mapper.(meta.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+}
+
+// detect returns the objects of the release that drifted from its manifest.
+// Only the fields set in the manifest are compared, so the fields populated by
+// the API server or by controllers are ignored.
+func (dd *driftDetector) detect(ctx context.Context, rel *release) ([]objectDrift, error) {
+	objects, err := parseManifest(rel.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the manifest: %w", err)
+	}
+
+	// the objects scaled by the HPAs, listed once per namespace
+	hpaTargets := make(map[string]map[string]struct{})
+
+	var drifts []objectDrift
+	for _, desired := range objects {
+		gvk := desired.GroupVersionKind()
+		mapping, err := dd.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			log.Debugf("Cannot find the resource of %s in release %s: %v", gvk, rel.namespacedName(), err)
+			continue
+		}
+
+		namespace := ""
+		var resourceClient dynamic.ResourceInterface = dd.client.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			namespace = desired.GetNamespace()
+			if namespace == "" {
+				namespace = rel.Namespace
+			}
+			resourceClient = dd.client.Resource(mapping.Resource).Namespace(namespace)
+		}
+
+		drift := objectDrift{
+			kind:      desired.GetKind(),
+			namespace: namespace,
+			name:      desired.GetName(),
+		}
+
+		live, err := resourceClient.Get(ctx, desired.GetName(), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			drift.missing = true
+			drifts = append(drifts, drift)
+			continue
+		}
+		if k8serrors.IsForbidden(err) {
+			// The RBAC of the agent doesn't need to grant access to every kind
+			// of object that a chart can deploy
+			log.Debugf("Cannot get %s %s/%s in release %s: %v", drift.kind, namespace, drift.name, rel.namespacedName(), err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot get %s %s/%s: %w", drift.kind, namespace, drift.name, err)
+		}
+
+		dd.removeManagedFields(ctx, desired, namespace, hpaTargets)
+		if drift.fields = diffFields("", desired.Object, live.Object); len(drift.fields) > 0 {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	return drifts, nil
+}
+
+// removeManagedFields removes from a desired object the fields that are
+// expected to differ in the cluster: the stringData of Secrets, which the API
+// server merges into data, and the replicas of the objects scaled by an HPA
+func (dd *driftDetector) removeManagedFields(ctx context.Context, desired *unstructured.Unstructured, namespace string, hpaTargets map[string]map[string]struct{}) {
+	groupKind := desired.GroupVersionKind().GroupKind()
+	if groupKind == (schema.GroupKind{Kind: "Secret"}) {
+		unstructured.RemoveNestedField(desired.Object, "stringData")
+	}
+
+	if _, found, _ := unstructured.NestedFieldNoCopy(desired.Object, "spec", "replicas"); !found || namespace == "" {
+		return
+	}
+
+	targets, listed := hpaTargets[namespace]
+	if !listed {
+		targets = dd.listHPATargets(ctx, namespace)
+		hpaTargets[namespace] = targets
+	}
+	if _, scaled := targets[scaleTargetKey(groupKind, desired.GetName())]; scaled {
+		unstructured.RemoveNestedField(desired.Object, "spec", "replicas")
+	}
+}
+
+// listHPATargets returns the objects scaled by the HPAs of a namespace
+func (dd *driftDetector) listHPATargets(ctx context.Context, namespace string) map[string]struct{} {
+	targets := make(map[string]struct{})
+
+	hpas, err := dd.client.Resource(hpaResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Debugf("Cannot list the HPAs of namespace %s, the replicas of the objects they scale are compared: %v", namespace, err)
+		return targets
+	}
+
+	for _, hpa := range hpas.Items {
+		apiVersion, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "apiVersion")
+		kind, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "name")
+		groupVersion, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			continue
+		}
+		targets[scaleTargetKey(schema.GroupKind{Group: groupVersion.Group, Kind: kind}, name)] = struct{}{}
+	}
+	return targets
+}
+
+func scaleTargetKey(groupKind schema.GroupKind, name string) string {
+	return groupKind.String() + "/" + name
+}
+
+// checkDrift runs the drift detection when the detection interval has elapsed,
+// and reports the drift of the latest revision of every deployed release
+func (hc *HelmCheck) checkDrift(sender sender.Sender) {
+	if time.Since(hc.lastDriftCheck) >= hc.getDriftDetectionInterval() {
+		ctx, cancel := context.WithTimeout(context.Background(), driftDetectionPassTimeout)
+		hc.detectDrift(ctx)
+		cancel()
+		hc.lastDriftCheck = time.Now()
+	}
+
+	for _, storageDriver := range []helmStorage{k8sConfigmaps, k8sSecrets} {
+		for _, taggedRel := range hc.store.getLatestRevisions(storageDriver) {
+			name := taggedRel.release.namespacedName()
+			drift, found := hc.drifts[storageDriver][name]
+			if !found {
+				continue
+			}
+
+			// The drift was detected on a previous revision, it doesn't apply
+			// to the one deployed since
+			if drift.revision != taggedRel.release.Version {
+				delete(hc.drifts[storageDriver], name)
+				continue
+			}
+
+			tags := releaseTags(taggedRel)
+
+			drifted := 0.0
+			if len(drift.objects) > 0 {
+				drifted = 1
+			}
+			sender.Gauge("helm.release.drifted", drifted, "", tags)
+			sender.Gauge("helm.release.drifted_objects", float64(len(drift.objects)), "", tags)
+		}
+	}
+}
+
+// detectDrift compares the manifest of the latest revision of every deployed
+// release with the live objects, and generates an event when the drift of a
+// release changes. The releases not compared before the deadline of ctx keep
+// the drift detected by the previous pass.
+func (hc *HelmCheck) detectDrift(ctx context.Context) {
+	// Discover the resources of the CRDs installed since the last detection
+	hc.driftDetector.reset()
+
+	skipped := 0
+	for _, storageDriver := range []helmStorage{k8sConfigmaps, k8sSecrets} {
+		previousDrifts := hc.drifts[storageDriver]
+		currentDrifts := make(map[namespacedName]releaseDrift)
+
+		for _, taggedRel := range hc.store.getLatestRevisions(storageDriver) {
+			rel := taggedRel.release
+			if rel.Info == nil || rel.Info.Status != helmStatusDeployed || rel.Manifest == "" {
+				continue
+			}
+
+			previous, detectedBefore := previousDrifts[rel.namespacedName()]
+			if detectedBefore && previous.revision != rel.Version {
+				previous, detectedBefore = releaseDrift{}, false
+			}
+
+			if ctx.Err() != nil {
+				skipped++
+				if detectedBefore {
+					currentDrifts[rel.namespacedName()] = previous
+				}
+				continue
+			}
+
+			releaseCtx, cancel := context.WithTimeout(ctx, driftDetectionTimeout)
+			drifts, err := hc.driftDetector.detect(releaseCtx, rel)
+			cancel()
+			if err != nil {
+				log.Warnf("Cannot detect drift of Helm release %s: %v", rel.namespacedName(), err)
+				if detectedBefore {
+					currentDrifts[rel.namespacedName()] = previous
+				}
+				continue
+			}
+			currentDrifts[rel.namespacedName()] = releaseDrift{revision: rel.Version, objects: drifts}
+
+			if driftSummary(drifts) == driftSummary(previous.objects) {
+				continue
+			}
+
+			if len(drifts) > 0 {
+				hc.eventsManager.addEventForDrift(rel, drifts, releaseTags(taggedRel))
+			} else if detectedBefore {
+				hc.eventsManager.addEventForResolvedDrift(rel, releaseTags(taggedRel))
+			}
+		}
+
+		hc.drifts[storageDriver] = currentDrifts
+	}
+
+	if skipped > 0 {
+		log.Warnf("Drift detection of Helm releases timed out, %d releases were not compared", skipped)
+	}
+}
+
+func releaseTags(taggedRel *taggedRelease) []string {
+	tags := make([]string, 0, len(taggedRel.commonTags)+len(taggedRel.tagsForMetricsAndEvents))
+	tags = append(tags, taggedRel.commonTags...)
+	return append(tags, taggedRel.tagsForMetricsAndEvents...)
+}
+
+func driftSummary(drifts []objectDrift) string {
+	lines := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		lines = append(lines, drift.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseManifest returns the objects of a manifest rendered by Helm, made of
+// several YAML documents
+func parseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+
+	decoder := yaml.NewYAMLToJSONDecoder(strings.NewReader(manifest))
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		// documents with only comments, e.g. templates disabled by a condition
+		if len(obj) == 0 {
+			continue
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+		if u.IsList() {
+			if err := u.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if u.GetKind() == "" || u.GetName() == "" {
+			continue
+		}
+
+		objects = append(objects, u)
+	}
+
+	return objects, nil
+}
+
+// diffFields returns the paths of the fields set in desired that have a
+// different value in live
+func diffFields(path string, desired, live interface{}) []string {
+	if _, ignored := ignoredFields[path]; ignored {
+		return nil
+	}
+
+	switch desiredValue := desired.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			if live == nil && len(desiredValue) == 0 {
+				return nil
+			}
+			return []string{path}
+		}
+
+		var fields []string
+		for _, key := range sortedKeys(desiredValue) {
+			fields = append(fields, diffFields(joinPath(path, key), desiredValue[key], liveValue[key])...)
+		}
+		return fields
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			if live == nil && len(desiredValue) == 0 {
+				return nil
+			}
+			return []string{path}
+		}
+		return diffLists(path, desiredValue, liveValue)
+	default:
+		if live == nil && isZero(desired) {
+			// the API server omits the fields set to their zero value
+			return nil
+		}
+		if !equalScalars(desired, live) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// diffLists compares the elements of two lists. The elements of lists of
+// named objects, like containers or env vars, are matched by name, as
+// admission controllers may insert elements. The other lists are compared
+// element by element.
+func diffLists(path string, desired, live []interface{}) []string {
+	var fields []string
+
+	if liveByName, ok := indexByName(live); ok {
+		if _, ok := indexByName(desired); ok {
+			for _, element := range desired {
+				name := element.(map[string]interface{})["name"].(string)
+				fields = append(fields, diffFields(fmt.Sprintf("%s[name=%s]", path, name), element, liveByName[name])...)
+			}
+			return fields
+		}
+	}
+
+	if len(desired) != len(live) {
+		return []string{path}
+	}
+	for i := range desired {
+		fields = append(fields, diffFields(fmt.Sprintf("%s[%d]", path, i), desired[i], live[i])...)
+	}
+	return fields
+}
+
+// indexByName returns the elements of a list indexed by their name, if all
+// of them are objects with a name
+func indexByName(list []interface{}) (map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+
+	res := make(map[string]interface{}, len(list))
+	for _, element := range list {
+		obj, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := obj["name"].(string)
+		if !ok {
+			return nil, false
+		}
+		res[name] = obj
+	}
+	return res, true
+}
+
+// equalScalars compares two values decoded from JSON. Numbers are compared
+// regardless of their type, and resource quantities regardless of their
+// format, as the API server normalizes them (e.g. "1000m" becomes "1").
+func equalScalars(a, b interface{}) bool {
+	if a == b {
+		return true
+	}
+
+	aNumber, aIsNumber := toFloat(a)
+	bNumber, bIsNumber := toFloat(b)
+	if aIsNumber && bIsNumber {
+		return aNumber == bNumber
+	}
+
+	aQuantity, aIsQuantity := toQuantity(a)
+	bQuantity, bIsQuantity := toQuantity(b)
+	return aIsQuantity && bIsQuantity && aQuantity.Cmp(bQuantity) == 0
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func toQuantity(value interface{}) (resource.Quantity, bool) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	default:
+		number, ok := toFloat(value)
+		if !ok {
+			return resource.Quantity{}, false
+		}
+		s = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	quantity, err := resource.ParseQuantity(s)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return quantity, true
+}
+
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	default:
+		number, ok := toFloat(value)
+		return ok && number == 0
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
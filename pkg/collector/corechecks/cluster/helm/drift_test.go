@@ -0,0 +1,515 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package helm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+)
+
+const testManifest = `---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  level: info
+---
+# Source: app/templates/disabled.yaml
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app:1.0
+          resources:
+            limits:
+              cpu: 1000m
+              memory: 1Gi
+          env:
+            - name: LEVEL
+              value: info
+`
+
+func TestParseManifest(t *testing.T) {
+	objects, err := parseManifest(testManifest)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	assert.Equal(t, "ConfigMap", objects[0].GetKind())
+	assert.Equal(t, "app-config", objects[0].GetName())
+	assert.Equal(t, "Deployment", objects[1].GetKind())
+	assert.Equal(t, "apps/v1", objects[1].GetAPIVersion())
+
+	objects, err = parseManifest(`
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: ServiceAccount
+    metadata:
+      name: sa-1
+  - apiVersion: v1
+    kind: ServiceAccount
+    metadata:
+      name: sa-2
+`)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "sa-1", objects[0].GetName())
+	assert.Equal(t, "sa-2", objects[1].GetName())
+
+	_, err = parseManifest("kind: [")
+	assert.Error(t, err)
+}
+
+func TestDiffFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  map[string]interface{}
+		live     map[string]interface{}
+		expected []string
+	}{
+		{
+			name:    "fields added by the API server are ignored",
+			desired: map[string]interface{}{"metadata": map[string]interface{}{"name": "app"}},
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":              "app",
+					"namespace":         "default",
+					"uid":               "1234",
+					"creationTimestamp": "2023-01-01T00:00:00Z",
+				},
+				"status": map[string]interface{}{"replicas": int64(2)},
+			},
+		},
+		{
+			name:     "changed value",
+			desired:  map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(2)}},
+			live:     map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(5)}},
+			expected: []string{"spec.replicas"},
+		},
+		{
+			name:    "numbers of different types",
+			desired: map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(2)}},
+			live:    map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(2)}},
+		},
+		{
+			name:    "normalized quantities",
+			desired: map[string]interface{}{"limits": map[string]interface{}{"cpu": "1000m", "memory": float64(1073741824)}},
+			live:    map[string]interface{}{"limits": map[string]interface{}{"cpu": "1", "memory": "1Gi"}},
+		},
+		{
+			name:    "zero values omitted by the API server",
+			desired: map[string]interface{}{"spec": map[string]interface{}{"paused": false, "labels": map[string]interface{}{}}},
+			live:    map[string]interface{}{"spec": map[string]interface{}{}},
+		},
+		{
+			name:    "named elements matched by name",
+			desired: map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "app", "image": "app:1.0"}}},
+			live: map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "sidecar", "image": "sidecar:1.0"},
+				map[string]interface{}{"name": "app", "image": "app:1.1"},
+			}},
+			expected: []string{"containers[name=app].image"},
+		},
+		{
+			name:     "removed named element",
+			desired:  map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "app"}}},
+			live:     map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "sidecar"}}},
+			expected: []string{"containers[name=app]"},
+		},
+		{
+			name:     "lists compared by index",
+			desired:  map[string]interface{}{"args": []interface{}{"--verbose", "--port=80"}},
+			live:     map[string]interface{}{"args": []interface{}{"--verbose", "--port=8080"}},
+			expected: []string{"args[1]"},
+		},
+		{
+			name:     "lists of different lengths",
+			desired:  map[string]interface{}{"args": []interface{}{"--verbose"}},
+			live:     map[string]interface{}{"args": []interface{}{"--verbose", "--debug"}},
+			expected: []string{"args"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, diffFields("", test.desired, test.live))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	detector := newTestDriftDetector(
+		newUnstructured("apps/v1", "Deployment", "default", "app", map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{"app": "app"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "app",
+								"image": "app:1.0",
+								"resources": map[string]interface{}{
+									"limits": map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+								},
+								"env": []interface{}{
+									map[string]interface{}{"name": "LEVEL", "value": "debug"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}),
+	)
+
+	rel := &release{
+		Name:      "app",
+		Namespace: "default",
+		Manifest:  testManifest,
+	}
+
+	drifts, err := detector.detect(context.Background(), rel)
+	require.NoError(t, err)
+
+	assert.Equal(t, []objectDrift{
+		{
+			kind:      "ConfigMap",
+			namespace: "default",
+			name:      "app-config",
+			missing:   true,
+		},
+		{
+			kind:      "Deployment",
+			namespace: "default",
+			name:      "app",
+			fields: []string{
+				"spec.replicas",
+				"spec.template.spec.containers[name=app].env[name=LEVEL].value",
+			},
+		},
+	}, drifts)
+}
+
+func TestDetectIgnoresManagedFields(t *testing.T) {
+	detector := newTestDriftDetector(
+		newUnstructured("v1", "Secret", "default", "app-secret", map[string]interface{}{
+			"data": map[string]interface{}{"password": "aHVudGVyMg=="},
+		}),
+		newUnstructured("apps/v1", "Deployment", "default", "app", map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(5)},
+		}),
+		newUnstructured("apps/v1", "Deployment", "default", "worker", map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(5)},
+		}),
+		newUnstructured("autoscaling/v2", "HorizontalPodAutoscaler", "default", "app", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"scaleTargetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "app"},
+			},
+		}),
+	)
+
+	rel := &release{
+		Name:      "app",
+		Namespace: "default",
+		Manifest: `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+stringData:
+  password: hunter2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+`,
+	}
+
+	drifts, err := detector.detect(context.Background(), rel)
+	require.NoError(t, err)
+
+	// only the replicas of the deployment that isn't scaled by an HPA drift
+	assert.Equal(t, []objectDrift{
+		{
+			kind:      "Deployment",
+			namespace: "default",
+			name:      "worker",
+			fields:    []string{"spec.replicas"},
+		},
+	}, drifts)
+}
+
+func TestCheckDrift(t *testing.T) {
+	check := newCheck().(*HelmCheck)
+	check.instance.DriftDetection = true
+	check.drifts = make(map[helmStorage]map[namespacedName]releaseDrift)
+
+	rel := release{
+		Name: "app",
+		Info: &info{
+			Status: "deployed",
+		},
+		Chart: &chart{
+			Metadata: &metadata{
+				Name:       "app",
+				Version:    "1.0.0",
+				AppVersion: "1",
+			},
+		},
+		Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  level: info
+`,
+		Version:   1,
+		Namespace: "default",
+	}
+
+	encodedRel, err := encodeRelease(&rel)
+	require.NoError(t, err)
+	check.addRelease(encodedRel, metav1.NewTime(time.Now()), k8sSecrets)
+
+	configMap := newUnstructured("v1", "ConfigMap", "default", "app-config", map[string]interface{}{
+		"data": map[string]interface{}{"level": "debug"},
+	})
+	check.driftDetector = newTestDriftDetector(configMap)
+
+	mockedSender := mocksender.NewMockSender(CheckName)
+	mockedSender.SetupAcceptAll()
+
+	// The config map was edited
+	check.checkDrift(mockedSender)
+	check.eventsManager.sendEvents(mockedSender)
+
+	expectedTags := check.allTags(&rel, k8sSecrets, true)
+	mockedSender.AssertMetric(t, "Gauge", "helm.release.drifted", 1, "", expectedTags)
+	mockedSender.AssertMetric(t, "Gauge", "helm.release.drifted_objects", 1, "", expectedTags)
+
+	expectedEvent := eventForRelease(
+		&rel,
+		"Helm release \"app\" (revision 1) in \"default\" namespace has drifted from its manifest:\n- ConfigMap default/app-config: data.level",
+		append(expectedTags, "drifted_kind:ConfigMap"),
+	)
+	expectedEvent.AlertType = event.EventAlertTypeWarning
+	mockedSender.AssertEvent(t, expectedEvent, 10*time.Second)
+
+	// The detection interval hasn't elapsed, the previous result is reported
+	// without generating a new event
+	mockedSender.ResetCalls()
+	check.checkDrift(mockedSender)
+	check.eventsManager.sendEvents(mockedSender)
+	mockedSender.AssertMetric(t, "Gauge", "helm.release.drifted", 1, "", expectedTags)
+	mockedSender.AssertNumberOfCalls(t, "Event", 0)
+
+	// The config map was restored
+	require.NoError(t, unstructured.SetNestedField(configMap.Object, "info", "data", "level"))
+	check.driftDetector = newTestDriftDetector(configMap)
+	check.lastDriftCheck = time.Time{}
+
+	mockedSender.ResetCalls()
+	check.checkDrift(mockedSender)
+	check.eventsManager.sendEvents(mockedSender)
+	mockedSender.AssertMetric(t, "Gauge", "helm.release.drifted", 0, "", expectedTags)
+	mockedSender.AssertMetric(t, "Gauge", "helm.release.drifted_objects", 0, "", expectedTags)
+	mockedSender.AssertEvent(
+		t,
+		eventForRelease(&rel, "Helm release \"app\" (revision 1) in \"default\" namespace matches its manifest again.", expectedTags),
+		10*time.Second,
+	)
+}
+
+func TestAddRelease_keepsManifestOnlyForDriftDetection(t *testing.T) {
+	rel := release{
+		Name:      "app",
+		Info:      &info{Status: "deployed"},
+		Manifest:  "kind: ConfigMap",
+		Version:   1,
+		Namespace: "default",
+	}
+	encodedRel, err := encodeRelease(&rel)
+	require.NoError(t, err)
+
+	check := newCheck().(*HelmCheck)
+	check.addRelease(encodedRel, metav1.NewTime(time.Now()), k8sSecrets)
+	assert.Empty(t, check.store.get("default/app", 1, k8sSecrets).release.Manifest)
+
+	check = newCheck().(*HelmCheck)
+	check.instance.DriftDetection = true
+	check.addRelease(encodedRel, metav1.NewTime(time.Now()), k8sSecrets)
+	assert.Equal(t, "kind: ConfigMap", check.store.get("default/app", 1, k8sSecrets).release.Manifest)
+}
+
+func newTestDriftDetector(objects ...runtime.Object) *driftDetector {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	listKinds := map[schema.GroupVersionResource]string{hpaResource: "HorizontalPodAutoscalerList"}
+	return newDriftDetector(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...), mapper)
+}
+
+func newUnstructured(apiVersion, kind, namespace, name string, content map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestCheckDriftNewRevision(t *testing.T) {
+	check := newCheck().(*HelmCheck)
+	check.instance.DriftDetection = true
+	check.drifts = make(map[helmStorage]map[namespacedName]releaseDrift)
+
+	rel := release{
+		Name: "app",
+		Info: &info{
+			Status: "deployed",
+		},
+		Chart: &chart{
+			Metadata: &metadata{
+				Name:       "app",
+				Version:    "1.0.0",
+				AppVersion: "1",
+			},
+		},
+		Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  level: info
+`,
+		Version:   1,
+		Namespace: "default",
+	}
+
+	encodedRel, err := encodeRelease(&rel)
+	require.NoError(t, err)
+	check.addRelease(encodedRel, metav1.NewTime(time.Now()), k8sSecrets)
+
+	configMap := newUnstructured("v1", "ConfigMap", "default", "app-config", map[string]interface{}{
+		"data": map[string]interface{}{"level": "debug"},
+	})
+	check.driftDetector = newTestDriftDetector(configMap)
+
+	mockedSender := mocksender.NewMockSender(CheckName)
+	mockedSender.SetupAcceptAll()
+
+	check.checkDrift(mockedSender)
+	mockedSender.AssertMetric(t, "Gauge", "helm.release.drifted", 1, "", check.allTags(&rel, k8sSecrets, true))
+
+	// A new revision is deployed before the next detection, the drift of the
+	// previous revision is not reported for it
+	upgradedRel := rel
+	upgradedRel.Version = 2
+	encodedRel, err = encodeRelease(&upgradedRel)
+	require.NoError(t, err)
+	check.addRelease(encodedRel, metav1.NewTime(time.Now()), k8sSecrets)
+
+	mockedSender.ResetCalls()
+	check.checkDrift(mockedSender)
+	mockedSender.AssertNotCalled(t, "Gauge", "helm.release.drifted", mock.Anything, mock.Anything, mock.Anything)
+	assert.NotContains(t, check.drifts[k8sSecrets], rel.namespacedName())
+}
+
+func TestDetectDriftDeadline(t *testing.T) {
+	check := newCheck().(*HelmCheck)
+	check.instance.DriftDetection = true
+
+	rel := release{
+		Name: "app",
+		Info: &info{
+			Status: "deployed",
+		},
+		Chart: &chart{
+			Metadata: &metadata{
+				Name:       "app",
+				Version:    "1.0.0",
+				AppVersion: "1",
+			},
+		},
+		Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  level: info
+`,
+		Version:   1,
+		Namespace: "default",
+	}
+
+	encodedRel, err := encodeRelease(&rel)
+	require.NoError(t, err)
+	check.addRelease(encodedRel, metav1.NewTime(time.Now()), k8sSecrets)
+
+	previous := releaseDrift{
+		revision: 1,
+		objects:  []objectDrift{{kind: "ConfigMap", namespace: "default", name: "app-config", fields: []string{"data.level"}}},
+	}
+	check.drifts = map[helmStorage]map[namespacedName]releaseDrift{
+		k8sSecrets: {rel.namespacedName(): previous},
+	}
+
+	// The config map was restored, but the deadline of the pass has expired
+	configMap := newUnstructured("v1", "ConfigMap", "default", "app-config", map[string]interface{}{
+		"data": map[string]interface{}{"level": "info"},
+	})
+	check.driftDetector = newTestDriftDetector(configMap)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	check.detectDrift(ctx)
+
+	assert.Equal(t, previous, check.drifts[k8sSecrets][rel.namespacedName()])
+}
@@ -9,6 +9,8 @@ package helm
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,8 +19,9 @@ import (
 )
 
 const (
-	eventTitle       = "Event on Helm release"
-	helmStatusFailed = "failed"
+	eventTitle         = "Event on Helm release"
+	helmStatusFailed   = "failed"
+	helmStatusDeployed = "deployed"
 )
 
 type eventsManager struct {
@@ -46,6 +49,17 @@ func (em *eventsManager) addEventForUpdatedRelease(old *release, updated *releas
 	em.storeEvent(event)
 }
 
+func (em *eventsManager) addEventForDrift(rel *release, drifts []objectDrift, tags []string) {
+	driftEvent := eventForRelease(rel, textForDrift(rel, drifts), append(tags, driftedKindTags(drifts)...))
+	driftEvent.AlertType = event.EventAlertTypeWarning
+	em.storeEvent(driftEvent)
+}
+
+func (em *eventsManager) addEventForResolvedDrift(rel *release, tags []string) {
+	event := eventForRelease(rel, textForResolvedDrift(rel), tags)
+	em.storeEvent(event)
+}
+
 func (em *eventsManager) sendEvents(sender sender.Sender) {
 	em.eventsMutex.Lock()
 	eventsToSend := em.events
@@ -115,6 +129,41 @@ func textForChangedStatus(previousRelStatus string, updatedRelease *release) str
 		updatedRelease.Info.Status)
 }
 
+func textForDrift(rel *release, drifts []objectDrift) string {
+	lines := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		lines = append(lines, fmt.Sprintf("- %s", drift))
+	}
+
+	return fmt.Sprintf("Helm release %q (revision %d) in %q namespace has drifted from its manifest:\n%s",
+		rel.Name,
+		rel.Version,
+		rel.Namespace,
+		strings.Join(lines, "\n"))
+}
+
+func textForResolvedDrift(rel *release) string {
+	return fmt.Sprintf("Helm release %q (revision %d) in %q namespace matches its manifest again.",
+		rel.Name,
+		rel.Version,
+		rel.Namespace)
+}
+
+func driftedKindTags(drifts []objectDrift) []string {
+	kinds := make(map[string]struct{})
+	for _, drift := range drifts {
+		kinds[drift.kind] = struct{}{}
+	}
+
+	tags := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		tags = append(tags, fmt.Sprintf("drifted_kind:%s", kind))
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
 func alertType(releaseStatus string) event.EventAlertType {
 	if releaseStatus == helmStatusFailed {
 		return event.EventAlertTypeError
@@ -19,7 +19,9 @@ import (
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
@@ -42,7 +44,12 @@ const (
 	maximumWaitForAPIServer = 10 * time.Second
 	defaultExtraSyncTimeout = 120 * time.Second
 	defaultResyncInterval   = 10 * time.Minute
-	labelSelector           = "owner=helm"
+	defaultDriftInterval    = 5 * time.Minute
+	driftDetectionTimeout   = 30 * time.Second
+	// driftDetectionPassTimeout bounds the time spent comparing all the
+	// releases, since the detection runs in the check's Run
+	driftDetectionPassTimeout = 2 * time.Minute
+	labelSelector             = "owner=helm"
 )
 
 type helmStorage string
@@ -67,6 +74,9 @@ type HelmCheck struct {
 	startTS           time.Time
 	once              sync.Once
 	informersStopCh   chan struct{}
+	driftDetector     *driftDetector
+	lastDriftCheck    time.Time
+	drifts            map[helmStorage]map[namespacedName]releaseDrift
 }
 
 type checkConfig struct {
@@ -74,6 +84,8 @@ type checkConfig struct {
 	HelmValuesAsTags               map[string]string `yaml:"helm_values_as_tags"`
 	ExtraSyncTimeoutSeconds        int               `yaml:"extra_sync_timeout_seconds"`
 	InformersResyncIntervalMinutes int               `yaml:"informers_resync_interval_minutes"`
+	DriftDetection                 bool              `yaml:"drift_detection"`
+	DriftDetectionIntervalMinutes  int               `yaml:"drift_detection_interval_minutes"`
 }
 
 // Parse parses the config and sets default values
@@ -122,6 +134,13 @@ func (hc *HelmCheck) Configure(senderManager sender.SenderManager, integrationCo
 	}
 
 	hc.setSharedInformerFactory(apiClient)
+
+	if hc.instance.DriftDetection {
+		mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(apiClient.Cl.Discovery()))
+		hc.driftDetector = newDriftDetector(apiClient.DynamicCl, mapper)
+		hc.drifts = make(map[helmStorage]map[namespacedName]releaseDrift)
+	}
+
 	hc.startTS = time.Now()
 	hc.informersStopCh = make(chan struct{})
 
@@ -163,7 +182,11 @@ func (hc *HelmCheck) Run() error {
 		}
 	}
 
-	if hc.instance.CollectEvents {
+	if hc.instance.DriftDetection {
+		hc.checkDrift(sender)
+	}
+
+	if hc.instance.CollectEvents || hc.instance.DriftDetection {
 		hc.eventsManager.sendEvents(sender)
 	}
 
@@ -430,6 +453,12 @@ func (hc *HelmCheck) addRelease(encodedRelease string, creationTS metav1.Time, s
 		decodedRelease.Chart.Values = nil
 	}
 
+	// Same for the manifest, which is only needed to detect drift on the
+	// deployed revision.
+	if !hc.instance.DriftDetection || decodedRelease.Info == nil || decodedRelease.Info.Status != helmStatusDeployed {
+		decodedRelease.Manifest = ""
+	}
+
 	hc.store.add(decodedRelease, storageDriver, genericTags, tagsMetricsAndEvents)
 }
 
@@ -493,6 +522,13 @@ func (hc *HelmCheck) getExtraSyncTimeout() time.Duration {
 	return defaultExtraSyncTimeout
 }
 
+func (hc *HelmCheck) getDriftDetectionInterval() time.Duration {
+	if hc.instance != nil && hc.instance.DriftDetectionIntervalMinutes > 0 {
+		return time.Duration(hc.instance.DriftDetectionIntervalMinutes) * time.Minute
+	}
+	return defaultDriftInterval
+}
+
 func (hc *HelmCheck) getInformersResyncPeriod() time.Duration {
 	if hc.instance != nil && hc.instance.InformersResyncIntervalMinutes > 0 {
 		return time.Duration(hc.instance.InformersResyncIntervalMinutes) * time.Minute
@@ -31,9 +31,11 @@ type release struct {
 	Chart *chart `json:"chart,omitempty"`
 	// Config is the set of extra Values added to the chart.
 	// These values override the default values inside of the chart.
-	Config    map[string]interface{} `json:"config,omitempty"`
-	Version   int                    `json:"version,omitempty"`
-	Namespace string                 `json:"namespace,omitempty"`
+	Config map[string]interface{} `json:"config,omitempty"`
+	// Manifest is the string representation of the rendered template.
+	Manifest  string `json:"manifest,omitempty"`
+	Version   int    `json:"version,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type namespacedName string
@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package customresources
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+)
+
+// CustomResourceStateMetric is a metric generated from the fields of a
+// custom resource
+type CustomResourceStateMetric struct {
+	// KSMName is the name of the metric family generated by kube-state-metrics
+	KSMName string
+	// Name is the name of the metric without the kubernetes_state prefix
+	Name string
+}
+
+// NewCustomResourceStateFactories returns a metric family generator factory
+// for each resource of a kube-state-metrics custom resource state
+// configuration, along with the metrics they generate. The resources are
+// listed and watched with the dynamic client of the Cluster Agent.
+func NewCustomResourceStateFactories(client *apiserver.APIClient, config customresourcestate.Metrics) ([]customresource.RegistryFactory, []CustomResourceStateMetric, error) {
+	factories := make([]customresource.RegistryFactory, 0, len(config.Spec.Resources))
+	var metrics []CustomResourceStateMetric
+	names := make(map[string]struct{}, len(config.Spec.Resources))
+	for _, resource := range config.Spec.Resources {
+		factory, err := customresourcestate.NewCustomResourceMetrics(resource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid custom resource state configuration for %s: %w", resource.GroupVersionKind, err)
+		}
+		if _, found := names[factory.Name()]; found {
+			return nil, nil, fmt.Errorf("found multiple custom resource state configurations for the resource %s", factory.Name())
+		}
+		names[factory.Name()] = struct{}{}
+
+		factories = append(factories, &customResourceStateFactory{
+			RegistryFactory: factory,
+			client: client.DynamicInformerCl.Resource(schema.GroupVersionResource{
+				Group:    resource.GroupVersionKind.Group,
+				Version:  resource.GroupVersionKind.Version,
+				Resource: resource.GetResourceName(),
+			}),
+		})
+		metrics = append(metrics, customResourceStateMetrics(resource)...)
+	}
+	return factories, metrics, nil
+}
+
+// customResourceStateMetrics returns the metrics generated for a resource.
+// Their names are built from the metric name prefix of the resource, without
+// the kube_ prefix, and from the name of the metric.
+// Example: kube_customresource_ready => customresource.ready
+func customResourceStateMetrics(resource customresourcestate.Resource) []CustomResourceStateMetric {
+	prefix := resource.GetMetricNamePrefix()
+	metrics := make([]CustomResourceStateMetric, 0, len(resource.Metrics))
+	for _, m := range resource.Metrics {
+		if prefix == "" {
+			metrics = append(metrics, CustomResourceStateMetric{KSMName: m.Name, Name: m.Name})
+			continue
+		}
+		metrics = append(metrics, CustomResourceStateMetric{
+			KSMName: prefix + "_" + m.Name,
+			Name:    strings.TrimPrefix(prefix, "kube_") + "." + m.Name,
+		})
+	}
+	return metrics
+}
+
+// customResourceStateFactory is a custom resource state factory using the
+// dynamic client of the Cluster Agent instead of creating its own
+type customResourceStateFactory struct {
+	customresource.RegistryFactory
+	client dynamic.NamespaceableResourceInterface
+}
+
+//nolint:revive // TODO(CINT) Fix revive linter
+func (f *customResourceStateFactory) CreateClient(cfg *rest.Config) (interface{}, error) {
+	return f.client, nil
+}
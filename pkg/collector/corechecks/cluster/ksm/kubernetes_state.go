@@ -43,6 +43,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
@@ -146,6 +147,36 @@ type KSMConfig struct {
 	// Can be useful when running the check as cluster check
 	LeaderSkip bool `yaml:"skip_leader_election"`
 
+	// CustomResource defines metrics generated from the fields of custom resources.
+	// It uses the format of the kube-state-metrics custom resource state configuration.
+	// Metrics are named kubernetes_state.<metricNamePrefix without kube_>.<name>.
+	// Example: Report the available replicas and the phase of Argo Rollouts.
+	// custom_resource:
+	//   spec:
+	//     resources:
+	//       - groupVersionKind:
+	//           group: argoproj.io
+	//           version: v1alpha1
+	//           kind: Rollout
+	//         metricNamePrefix: kube_argo_rollout
+	//         labelsFromPath:
+	//           namespace: [metadata, namespace]
+	//           rollout: [metadata, name]
+	//         metrics:
+	//           - name: replicas_available
+	//             each:
+	//               type: Gauge
+	//               gauge:
+	//                 path: [status, availableReplicas]
+	//           - name: phase
+	//             each:
+	//               type: StateSet
+	//               stateSet:
+	//                 path: [status, phase]
+	//                 labelName: phase
+	//                 list: [Healthy, Progressing, Degraded, Paused]
+	CustomResource customresourcestate.Metrics `yaml:"custom_resource"`
+
 	// Private field containing the label joins configuration built from `LabelJoins`, `LabelsAsTags` and `AnnotationsAsTags`.
 	labelJoins map[string]*joinsConfig
 }
@@ -314,7 +345,10 @@ func (k *KSMCheck) Configure(senderManager sender.SenderManager, integrationConf
 
 	// configure custom resources required for extended features and
 	// compatibility across deprecated/removed versions of APIs
-	cr := k.discoverCustomResources(c, collectors, resources)
+	cr, err := k.discoverCustomResources(c, collectors, resources)
+	if err != nil {
+		return err
+	}
 	builder.WithGenerateCustomResourceStoresFunc(builder.GenerateCustomResourceStoresFunc)
 	builder.WithCustomResourceStoreFactories(cr.factories...)
 	builder.WithCustomResourceClients(cr.clients)
@@ -384,7 +418,7 @@ type customResources struct {
 	clients    map[string]interface{}
 }
 
-func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []string, resources []*v1.APIResourceList) customResources {
+func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []string, resources []*v1.APIResourceList) (customResources, error) {
 	// automatically add extended collectors if their standard ones are
 	// enabled
 	for _, c := range collectors {
@@ -404,6 +438,17 @@ func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []
 
 	factories = manageResourcesReplacement(c, factories, resources)
 
+	// custom resource state collectors are enabled as soon as they're
+	// configured
+	crsFactories, err := k.customResourceStateFactories(c, factories)
+	if err != nil {
+		return customResources{}, err
+	}
+	for _, f := range crsFactories {
+		collectors = append(collectors, f.Name())
+	}
+	factories = append(factories, crsFactories...)
+
 	clients := make(map[string]interface{}, len(factories))
 	for _, f := range factories {
 		client, _ := f.CreateClient(nil)
@@ -414,7 +459,39 @@ func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []
 		collectors: collectors,
 		clients:    clients,
 		factories:  factories,
+	}, nil
+}
+
+// customResourceStateFactories returns the factories of the custom resources
+// configured with custom_resource and maps the names of their metrics
+func (k *KSMCheck) customResourceStateFactories(c *apiserver.APIClient, factories []customresource.RegistryFactory) ([]customresource.RegistryFactory, error) {
+	crsFactories, metrics, err := customresources.NewCustomResourceStateFactories(c, k.instance.CustomResource)
+	if err != nil {
+		return nil, err
+	}
+
+	// a custom resource state factory would replace the factory or the
+	// kube-state-metrics collector of the same name
+	reserved := make(map[string]struct{}, len(factories))
+	for _, f := range factories {
+		reserved[f.Name()] = struct{}{}
+	}
+	for _, r := range options.DefaultResources.AsSlice() {
+		reserved[r] = struct{}{}
+	}
+	for _, f := range crsFactories {
+		if _, found := reserved[f.Name()]; found {
+			return nil, fmt.Errorf("custom resource %s is already collected by a built-in collector", f.Name())
+		}
+	}
+
+	for _, m := range metrics {
+		if _, found := k.metricNamesMapper[m.KSMName]; found {
+			return nil, fmt.Errorf("custom resource metric %s conflicts with a built-in metric", m.KSMName)
+		}
+		k.metricNamesMapper[m.KSMName] = m.Name
 	}
+	return crsFactories, nil
 }
 
 func manageResourcesReplacement(c *apiserver.APIClient, factories []customresource.RegistryFactory, resources []*v1.APIResourceList) []customresource.RegistryFactory {
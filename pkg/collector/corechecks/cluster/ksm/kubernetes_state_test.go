@@ -14,7 +14,12 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
@@ -22,6 +27,7 @@ import (
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	ksmstore "github.com/DataDog/datadog-agent/pkg/kubestatemetrics/store"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 )
 
 type metricsExpected struct {
@@ -1715,3 +1721,103 @@ func TestOwnerTags(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomResourceState(t *testing.T) {
+	instance := &KSMConfig{LabelsMapper: defaultLabelsMapper()}
+	require.NoError(t, instance.parse([]byte(`
+custom_resource:
+  spec:
+    resources:
+      - groupVersionKind:
+          group: argoproj.io
+          version: v1alpha1
+          kind: Rollout
+        metricNamePrefix: kube_argo_rollout
+        labelsFromPath:
+          namespace: [metadata, namespace]
+          rollout: [metadata, name]
+        metrics:
+          - name: replicas_available
+            each:
+              type: Gauge
+              gauge:
+                path: [status, availableReplicas]
+          - name: phase
+            each:
+              type: StateSet
+              stateSet:
+                path: [status, phase]
+                labelName: phase
+                list: [Healthy, Degraded]
+      - groupVersionKind:
+          group: cert-manager.io
+          version: v1
+          kind: Certificate
+        metrics:
+          - name: certificate_info
+            each:
+              type: Info
+              info:
+                labelsFromPath:
+                  issuer: [spec, issuerRef, name]
+`)))
+
+	k := newKSMCheck(core.NewCheckBase(CheckName), instance)
+	client := &apiserver.APIClient{DynamicInformerCl: fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())}
+	factories, err := k.customResourceStateFactories(client, nil)
+	require.NoError(t, err)
+	require.Len(t, factories, 2)
+	assert.Equal(t, "rollouts", factories[0].Name())
+	assert.Equal(t, "certificates", factories[1].Name())
+	assert.Equal(t, "argo_rollout.replicas_available", k.metricNamesMapper["kube_argo_rollout_replicas_available"])
+	assert.Equal(t, "customresource.certificate_info", k.metricNamesMapper["kube_customresource_certificate_info"])
+
+	store := ksmstore.NewMetricsStore(generator.ComposeMetricGenFuncs(factories[0].MetricFamilyGenerators(nil, nil)), "*unstructured.Unstructured")
+	require.NoError(t, store.Add(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "prod", "uid": "a37a24ae-9dc7-4bd2-9a5f-d2b6e8fa1c3b"},
+		"status":     map[string]interface{}{"availableReplicas": int64(3), "phase": "Healthy"},
+	}}))
+
+	mocked := mocksender.NewMockSender(k.ID())
+	mocked.SetupAcceptAll()
+	k.processMetrics(mocked, store.Push(ksmstore.GetAllFamilies, ksmstore.GetAllMetrics), newLabelJoiner(nil), time.Now())
+
+	gvkTags := []string{"customresource_group:argoproj.io", "customresource_version:v1alpha1", "customresource_kind:Rollout"}
+	mocked.AssertMetric(t, "Gauge", "kubernetes_state.argo_rollout.replicas_available", 3, "", append([]string{"kube_namespace:prod", "rollout:web"}, gvkTags...))
+	mocked.AssertMetric(t, "Gauge", "kubernetes_state.argo_rollout.phase", 1, "", append([]string{"kube_namespace:prod", "rollout:web", "phase:Healthy"}, gvkTags...))
+	mocked.AssertMetric(t, "Gauge", "kubernetes_state.argo_rollout.phase", 0, "", append([]string{"kube_namespace:prod", "rollout:web", "phase:Degraded"}, gvkTags...))
+	mocked.AssertNumberOfCalls(t, "Gauge", 3)
+}
+
+func TestCustomResourceStateConflicts(t *testing.T) {
+	client := &apiserver.APIClient{DynamicInformerCl: fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())}
+
+	instance := &KSMConfig{}
+	require.NoError(t, instance.parse([]byte(`
+custom_resource:
+  spec:
+    resources:
+      - groupVersionKind: {group: example.com, version: v1, kind: Pod}
+        metrics:
+          - name: ready
+            each: {type: Gauge, gauge: {path: [status, ready]}}
+`)))
+	_, err := newKSMCheck(core.NewCheckBase(CheckName), instance).customResourceStateFactories(client, nil)
+	assert.EqualError(t, err, "custom resource pods is already collected by a built-in collector")
+
+	instance = &KSMConfig{}
+	require.NoError(t, instance.parse([]byte(`
+custom_resource:
+  spec:
+    resources:
+      - groupVersionKind: {group: example.com, version: v1, kind: Widget}
+        metricNamePrefix: kube_pod
+        metrics:
+          - name: status_ready
+            each: {type: Gauge, gauge: {path: [status, ready]}}
+`)))
+	_, err = newKSMCheck(core.NewCheckBase(CheckName), instance).customResourceStateFactories(client, nil)
+	assert.EqualError(t, err, "custom resource metric kube_pod_status_ready conflicts with a built-in metric")
+}
@@ -87,6 +87,11 @@ type KubeASConfig struct {
 	// CollectedEventTypes specifies which events to collect.
 	// Only effective when UnbundleEvents = true
 	CollectedEventTypes []collectedEventType `yaml:"collected_event_types"`
+
+	// CollectLifecycleEvents enables the collection of OOM kills, crash
+	// loops, evictions and node condition changes from the pod and node
+	// informers, independently of the Kubernetes events.
+	CollectLifecycleEvents bool `yaml:"collect_lifecycle_events"`
 }
 
 type collectedEventType struct {
@@ -111,6 +116,7 @@ type KubeASCheck struct {
 	core.CheckBase
 	instance        *KubeASConfig
 	eventCollection eventCollection
+	lifecycleEvents *lifecycleEventCollector
 	ac              *apiserver.APIClient
 	oshiftAPILevel  apiserver.OpenShiftAPILevel
 }
@@ -173,6 +179,10 @@ func (k *KubeASCheck) Configure(senderManager sender.SenderManager, integrationC
 		k.eventCollection.Transformer = newBundledTransformer(clusterName)
 	}
 
+	if k.instance.CollectLifecycleEvents {
+		k.lifecycleEvents = newLifecycleEventCollector(clusterName, k.instance.MaxEventCollection)
+	}
+
 	return nil
 }
 
@@ -200,6 +210,9 @@ func (k *KubeASCheck) Run() error {
 			if errLeader == apiserver.ErrNotLeader {
 				// Only the leader can instantiate the apiserver client.
 				log.Debugf("Not leader (leader is %q). Skipping the Kubernetes API Server check", leader)
+				if k.lifecycleEvents != nil {
+					k.lifecycleEvents.stop()
+				}
 				return nil
 			}
 
@@ -247,6 +260,17 @@ func (k *KubeASCheck) Run() error {
 		}
 	}
 
+	if k.lifecycleEvents != nil {
+		err = k.lifecycleEvents.start(k.ac.InformerFactory, k.ac.InformersStopCh())
+		if err != nil {
+			k.Warnf("Could not start the collection of pod and node lifecycle events: %s", err.Error()) //nolint:errcheck
+		}
+
+		for _, event := range k.lifecycleEvents.flush() {
+			sender.Event(event)
+		}
+	}
+
 	if k.instance.CollectEvent {
 		events, err := k.eventCollectionCheck()
 		if err != nil {
@@ -261,6 +285,14 @@ func (k *KubeASCheck) Run() error {
 	return nil
 }
 
+// Cancel stops the lifecycle events collection. The shared informers keep running.
+func (k *KubeASCheck) Cancel() {
+	if k.lifecycleEvents != nil {
+		k.lifecycleEvents.stop()
+	}
+	k.CheckBase.Cancel()
+}
+
 func (k *KubeASCheck) eventCollectionCheck() ([]event.Event, error) {
 	resVer, lastTime, err := k.ac.GetTokenFromConfigmap(eventTokenKey)
 	if err != nil {
@@ -0,0 +1,437 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/tagset"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	oomKilledReason        = "OOMKilled"
+	crashLoopBackOffReason = "CrashLoopBackOff"
+	evictedReason          = "Evicted"
+	notReadyReason         = "NotReady"
+)
+
+var (
+	// the kubelet reports the resource it reclaims when evicting a pod,
+	// e.g. "The node was low on resource: memory. Threshold quantity: ..."
+	evictionResourceRegex = regexp.MustCompile(`low on resource: ([a-z-]+)`)
+	// or the condition of the node when it rejects a pod,
+	// e.g. "The node had condition: [DiskPressure]. "
+	evictionConditionRegex = regexp.MustCompile(`had condition: \[(\w+)\]`)
+
+	// evictionResourceConditions maps the resources reclaimed by the kubelet to the node condition they cause
+	evictionResourceConditions = map[string]v1.NodeConditionType{
+		"memory":            v1.NodeMemoryPressure,
+		"ephemeral-storage": v1.NodeDiskPressure,
+		"inodes":            v1.NodeDiskPressure,
+		"pids":              v1.NodePIDPressure,
+	}
+
+	pressureConditions = []v1.NodeConditionType{
+		v1.NodeMemoryPressure,
+		v1.NodeDiskPressure,
+		v1.NodePIDPressure,
+	}
+)
+
+// lifecycleEventCollector builds events for the operationally critical transitions of pods
+// and nodes: OOM kills, crash loops, evictions and node conditions. They are computed from the
+// pod and node informers rather than from Kubernetes events, which the API server routinely
+// drops or deduplicates.
+type lifecycleEventCollector struct {
+	clusterName string
+	maxEvents   int
+
+	mu      sync.Mutex
+	events  []event.Event
+	dropped int
+	// registrations are the handlers added to the informers, nil while the collection is stopped
+	registrations []informerRegistration
+}
+
+type informerRegistration struct {
+	informer     cache.SharedInformer
+	registration cache.ResourceEventHandlerRegistration
+}
+
+func newLifecycleEventCollector(clusterName string, maxEvents int) *lifecycleEventCollector {
+	return &lifecycleEventCollector{
+		clusterName: clusterName,
+		maxEvents:   maxEvents,
+	}
+}
+
+// start adds the handlers to the pod and node informers of the shared informer factory,
+// and starts the informers that aren't running yet until stopCh is closed. It does
+// nothing if the collection is already started.
+func (c *lifecycleEventCollector) start(factory informers.SharedInformerFactory, stopCh <-chan struct{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.registrations != nil {
+		return nil
+	}
+
+	registrations, err := c.register(factory.Core().V1().Pods().Informer(), factory.Core().V1().Nodes().Informer())
+	if err != nil {
+		return err
+	}
+	c.registrations = registrations
+
+	factory.Start(stopCh)
+	return nil
+}
+
+// stop removes the handlers from the informers and drops the buffered events. The shared
+// informers keep running for the other consumers of the factory.
+func (c *lifecycleEventCollector) stop() {
+	c.mu.Lock()
+	registrations := c.registrations
+	c.registrations = nil
+	c.events = nil
+	c.dropped = 0
+	c.mu.Unlock()
+
+	removeHandlers(registrations)
+}
+
+func removeHandlers(registrations []informerRegistration) {
+	for _, r := range registrations {
+		if err := r.informer.RemoveEventHandler(r.registration); err != nil {
+			log.Debugf("Cannot remove lifecycle events handler: %v", err)
+		}
+	}
+}
+
+// register adds the handlers computing the transitions of the pods and nodes. Objects
+// listed when the informers start are ignored: only updates are compared.
+func (c *lifecycleEventCollector) register(podInformer, nodeInformer cache.SharedInformer) ([]informerRegistration, error) {
+	podRegistration, err := podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, oldOK := oldObj.(*v1.Pod)
+			newPod, newOK := newObj.(*v1.Pod)
+			if !oldOK || !newOK {
+				log.Debugf("Expected pods, got %T and %T", oldObj, newObj)
+				return
+			}
+			c.add(podLifecycleEvents(c.clusterName, oldPod, newPod))
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot add pod event handler: %w", err)
+	}
+	registrations := []informerRegistration{{informer: podInformer, registration: podRegistration}}
+
+	nodeRegistration, err := nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, oldOK := oldObj.(*v1.Node)
+			newNode, newOK := newObj.(*v1.Node)
+			if !oldOK || !newOK {
+				log.Debugf("Expected nodes, got %T and %T", oldObj, newObj)
+				return
+			}
+			c.add(nodeLifecycleEvents(c.clusterName, oldNode, newNode))
+		},
+	})
+	if err != nil {
+		removeHandlers(registrations)
+		return nil, fmt.Errorf("cannot add node event handler: %w", err)
+	}
+
+	return append(registrations, informerRegistration{informer: nodeInformer, registration: nodeRegistration}), nil
+}
+
+// add buffers events until the next check run. The oldest events are dropped
+// when more than maxEvents are buffered.
+func (c *lifecycleEventCollector) add(events []event.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// events computed while the handlers are being removed are dropped
+	if c.registrations == nil {
+		return
+	}
+
+	c.events = append(c.events, events...)
+	if overflow := len(c.events) - c.maxEvents; overflow > 0 {
+		c.events = c.events[overflow:]
+		c.dropped += overflow
+	}
+}
+
+// flush returns the events buffered since the last flush
+func (c *lifecycleEventCollector) flush() []event.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dropped > 0 {
+		log.Warnf("Dropped %d pod and node lifecycle events, consider increasing max_events_per_run", c.dropped)
+		c.dropped = 0
+	}
+
+	events := c.events
+	c.events = nil
+	return events
+}
+
+// podLifecycleEvents returns the events for the transitions between two states of a pod
+func podLifecycleEvents(clusterName string, oldPod, newPod *v1.Pod) []event.Event {
+	var events []event.Event
+
+	if newPod.Status.Reason == evictedReason && oldPod.Status.Reason != evictedReason {
+		events = append(events, newPodEvent(clusterName, newPod, "", evictedReason, newPod.Status.Message, evictionTags(newPod.Status.Message)...))
+	}
+
+	oldStatuses := make(map[string]*v1.ContainerStatus, len(oldPod.Status.InitContainerStatuses)+len(oldPod.Status.ContainerStatuses))
+	for _, statuses := range [][]v1.ContainerStatus{oldPod.Status.InitContainerStatuses, oldPod.Status.ContainerStatuses} {
+		for i := range statuses {
+			oldStatuses[statuses[i].Name] = &statuses[i]
+		}
+	}
+
+	for _, statuses := range [][]v1.ContainerStatus{newPod.Status.InitContainerStatuses, newPod.Status.ContainerStatuses} {
+		for i := range statuses {
+			newStatus := &statuses[i]
+			oldStatus := oldStatuses[newStatus.Name]
+
+			if terminated := lastTermination(newStatus); terminated != nil && terminated.Reason == oomKilledReason && !sameTermination(terminated, lastTermination(oldStatus)) {
+				text := fmt.Sprintf("Container %s was OOM killed with exit code %d", newStatus.Name, terminated.ExitCode)
+				events = append(events, newPodEvent(clusterName, newPod, newStatus.Name, oomKilledReason, text))
+			}
+
+			if isCrashLooping(newStatus) && !isCrashLooping(oldStatus) {
+				text := fmt.Sprintf("Container %s is in CrashLoopBackOff after %d restarts: %s", newStatus.Name, newStatus.RestartCount, newStatus.State.Waiting.Message)
+				events = append(events, newPodEvent(clusterName, newPod, newStatus.Name, crashLoopBackOffReason, text))
+			}
+		}
+	}
+
+	return events
+}
+
+// lastTermination returns the current termination of a container if it is terminated,
+// and its last termination otherwise
+func lastTermination(status *v1.ContainerStatus) *v1.ContainerStateTerminated {
+	if status == nil {
+		return nil
+	}
+	if status.State.Terminated != nil {
+		return status.State.Terminated
+	}
+	return status.LastTerminationState.Terminated
+}
+
+func sameTermination(a, b *v1.ContainerStateTerminated) bool {
+	return b != nil && a.ContainerID == b.ContainerID && a.FinishedAt.Equal(&b.FinishedAt)
+}
+
+func isCrashLooping(status *v1.ContainerStatus) bool {
+	return status != nil && status.State.Waiting != nil && status.State.Waiting.Reason == crashLoopBackOffReason
+}
+
+// evictionTags returns the tags describing the pressure that caused an eviction
+func evictionTags(message string) []string {
+	var tags []string
+	if matches := evictionResourceRegex.FindStringSubmatch(message); matches != nil {
+		tags = append(tags, fmt.Sprintf("eviction_resource:%s", matches[1]))
+		if condition, found := evictionResourceConditions[matches[1]]; found {
+			tags = append(tags, fmt.Sprintf("node_condition:%s", condition))
+		}
+	} else if matches := evictionConditionRegex.FindStringSubmatch(message); matches != nil {
+		tags = append(tags, fmt.Sprintf("node_condition:%s", matches[1]))
+	}
+	return tags
+}
+
+func newPodEvent(clusterName string, pod *v1.Pod, containerName string, reason string, text string, extraTags ...string) event.Event {
+	involvedObject := v1.ObjectReference{
+		Kind:      podKind,
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		UID:       pod.UID,
+	}
+
+	tagsAccumulator := tagset.NewHashlessTagsAccumulator()
+	tagsAccumulator.Append(getInvolvedObjectTags(involvedObject)...)
+	tagsAccumulator.Append(getOwnerTags(pod.OwnerReferences)...)
+	tagsAccumulator.Append(fmt.Sprintf("event_reason:%s", reason))
+	tagsAccumulator.Append(extraTags...)
+	if containerName != "" {
+		tagsAccumulator.Append(fmt.Sprintf("kube_container_name:%s", containerName))
+	}
+	if pod.Spec.NodeName != "" {
+		tagsAccumulator.Append(fmt.Sprintf("kube_node:%s", pod.Spec.NodeName))
+	}
+	tagsAccumulator.SortUniq()
+
+	title := fmt.Sprintf("%s: %s", buildReadableKey(involvedObject), reason)
+	if containerName != "" {
+		title = fmt.Sprintf("%s: %s (%s)", buildReadableKey(involvedObject), reason, containerName)
+	}
+
+	emittedEvents.Inc(podKind, v1.EventTypeWarning)
+
+	return event.Event{
+		Title:          title,
+		Priority:       event.EventPriorityNormal,
+		Host:           nodeHostname(clusterName, pod.Spec.NodeName),
+		SourceTypeName: "kubernetes",
+		EventType:      CheckName,
+		Ts:             time.Now().Unix(),
+		Tags:           tagsAccumulator.Get(),
+		AggregationKey: fmt.Sprintf("kubernetes_apiserver:%s", pod.UID),
+		AlertType:      event.EventAlertTypeWarning,
+		Text:           text,
+	}
+}
+
+// getOwnerTags returns the tags of the workloads owning a pod, including the
+// deployment of a replicaset and the cronjob of a job
+func getOwnerTags(owners []metav1.OwnerReference) []string {
+	var tags []string
+	for _, owner := range owners {
+		tags = append(tags,
+			fmt.Sprintf("%s:%s", kubernetes.OwnerRefKindTagName, owner.Kind),
+			fmt.Sprintf("%s:%s", kubernetes.OwnerRefNameTagName, owner.Name),
+		)
+		if kindTag := getKindTag(owner.Kind, owner.Name); kindTag != "" {
+			tags = append(tags, kindTag)
+		}
+
+		switch owner.Kind {
+		case kubernetes.ReplicaSetKind:
+			if deployment := kubernetes.ParseDeploymentForReplicaSet(owner.Name); deployment != "" {
+				tags = append(tags, getKindTag(kubernetes.DeploymentKind, deployment))
+			}
+		case kubernetes.JobKind:
+			if cronjob, _ := kubernetes.ParseCronJobForJob(owner.Name); cronjob != "" {
+				tags = append(tags, getKindTag(kubernetes.CronJobKind, cronjob))
+			}
+		}
+	}
+	return tags
+}
+
+// nodeLifecycleEvents returns the events for the changes of the pressure and ready conditions of a node
+func nodeLifecycleEvents(clusterName string, oldNode, newNode *v1.Node) []event.Event {
+	var events []event.Event
+
+	for _, conditionType := range pressureConditions {
+		oldStatus := getNodeConditionStatus(oldNode, conditionType)
+		newStatus := getNodeConditionStatus(newNode, conditionType)
+		if oldStatus == newStatus || oldStatus == "" {
+			continue
+		}
+
+		switch {
+		case newStatus == v1.ConditionTrue:
+			events = append(events, newNodeEvent(clusterName, newNode, conditionType, string(conditionType), event.EventAlertTypeWarning))
+		case oldStatus == v1.ConditionTrue:
+			events = append(events, newNodeEvent(clusterName, newNode, conditionType, "No"+string(conditionType), event.EventAlertTypeSuccess))
+		}
+	}
+
+	oldReady := getNodeConditionStatus(oldNode, v1.NodeReady)
+	newReady := getNodeConditionStatus(newNode, v1.NodeReady)
+	switch {
+	case oldReady == v1.ConditionTrue && newReady != v1.ConditionTrue:
+		events = append(events, newNodeEvent(clusterName, newNode, v1.NodeReady, notReadyReason, event.EventAlertTypeError))
+	case oldReady != "" && oldReady != v1.ConditionTrue && newReady == v1.ConditionTrue:
+		events = append(events, newNodeEvent(clusterName, newNode, v1.NodeReady, string(v1.NodeReady), event.EventAlertTypeSuccess))
+	}
+
+	return events
+}
+
+func getNodeConditionStatus(node *v1.Node, conditionType v1.NodeConditionType) v1.ConditionStatus {
+	if condition := getNodeCondition(node, conditionType); condition != nil {
+		return condition.Status
+	}
+	return ""
+}
+
+func getNodeCondition(node *v1.Node, conditionType v1.NodeConditionType) *v1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == conditionType {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func newNodeEvent(clusterName string, node *v1.Node, conditionType v1.NodeConditionType, reason string, alertType event.EventAlertType) event.Event {
+	involvedObject := v1.ObjectReference{
+		Kind: nodeKind,
+		Name: node.Name,
+		UID:  node.UID,
+	}
+
+	tagsAccumulator := tagset.NewHashlessTagsAccumulator()
+	tagsAccumulator.Append(getInvolvedObjectTags(involvedObject)...)
+	tagsAccumulator.Append(
+		fmt.Sprintf("kube_node:%s", node.Name),
+		fmt.Sprintf("event_reason:%s", reason),
+		fmt.Sprintf("node_condition:%s", conditionType),
+	)
+	tagsAccumulator.SortUniq()
+
+	ts := time.Now()
+	text := ""
+	if condition := getNodeCondition(node, conditionType); condition != nil {
+		if !condition.LastTransitionTime.IsZero() {
+			ts = condition.LastTransitionTime.Time
+		}
+		text = condition.Message
+	}
+
+	eventType := v1.EventTypeNormal
+	if alertType == event.EventAlertTypeWarning || alertType == event.EventAlertTypeError {
+		eventType = v1.EventTypeWarning
+	}
+	emittedEvents.Inc(nodeKind, eventType)
+
+	return event.Event{
+		Title:          fmt.Sprintf("%s: %s", buildReadableKey(involvedObject), reason),
+		Priority:       event.EventPriorityNormal,
+		Host:           nodeHostname(clusterName, node.Name),
+		SourceTypeName: "kubernetes",
+		EventType:      CheckName,
+		Ts:             ts.Unix(),
+		Tags:           tagsAccumulator.Get(),
+		AggregationKey: fmt.Sprintf("kubernetes_apiserver:%s", node.UID),
+		AlertType:      alertType,
+		Text:           text,
+	}
+}
+
+// nodeHostname returns the hostname of a node, as reported by the agent running on it
+func nodeHostname(clusterName, nodeName string) string {
+	if nodeName == "" || clusterName == "" {
+		return nodeName
+	}
+	return nodeName + "-" + clusterName
+}
@@ -0,0 +1,395 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+)
+
+func newTestPod(containerStatuses ...v1.ContainerStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       "foobar",
+			Name:      "redis-5d4b9c7f8-abcde",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "redis-5d4b9c7f8"},
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName: "node-1",
+		},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			ContainerStatuses: containerStatuses,
+		},
+	}
+}
+
+func newTestNode(conditions ...v1.NodeCondition) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:  "node-uid",
+			Name: "node-1",
+		},
+		Status: v1.NodeStatus{
+			Conditions: conditions,
+		},
+	}
+}
+
+func TestPodLifecycleEvents(t *testing.T) {
+	finishedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	running := v1.ContainerStatus{
+		Name:  "redis",
+		State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+	}
+	oomKilled := v1.ContainerStatus{
+		Name: "redis",
+		State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+			Reason:      "OOMKilled",
+			ExitCode:    137,
+			ContainerID: "containerd://1",
+			FinishedAt:  finishedAt,
+		}},
+	}
+	restartedAfterOOMKill := v1.ContainerStatus{
+		Name:                 "redis",
+		State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+		LastTerminationState: oomKilled.State,
+		RestartCount:         1,
+	}
+	crashLooping := v1.ContainerStatus{
+		Name: "redis",
+		State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+			Reason:  "CrashLoopBackOff",
+			Message: "back-off 10s restarting failed container",
+		}},
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+			Reason:      "Error",
+			ExitCode:    1,
+			ContainerID: "containerd://2",
+		}},
+		RestartCount: 2,
+	}
+
+	evicted := newTestPod(running)
+	evicted.Status.Phase = v1.PodFailed
+	evicted.Status.Reason = "Evicted"
+	evicted.Status.Message = "The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi."
+
+	rejected := newTestPod()
+	rejected.Status.Phase = v1.PodFailed
+	rejected.Status.Reason = "Evicted"
+	rejected.Status.Message = "The node had condition: [DiskPressure]. "
+
+	podTags := []string{
+		"kube_deployment:redis",
+		"kube_kind:Pod",
+		"kube_name:redis-5d4b9c7f8-abcde",
+		"kube_namespace:default",
+		"kube_node:node-1",
+		"kube_ownerref_kind:ReplicaSet",
+		"kube_ownerref_name:redis-5d4b9c7f8",
+		"kube_replica_set:redis-5d4b9c7f8",
+		"kubernetes_kind:Pod",
+		"name:redis-5d4b9c7f8-abcde",
+		"namespace:default",
+		"pod_name:redis-5d4b9c7f8-abcde",
+	}
+
+	tests := []struct {
+		name          string
+		oldPod        *v1.Pod
+		newPod        *v1.Pod
+		expectedTitle []string
+		expectedTags  [][]string
+		expectedText  []string
+	}{
+		{
+			name:   "no transition",
+			oldPod: newTestPod(running),
+			newPod: newTestPod(running),
+		},
+		{
+			name:          "container is OOM killed",
+			oldPod:        newTestPod(running),
+			newPod:        newTestPod(oomKilled),
+			expectedTitle: []string{"Pod default/redis-5d4b9c7f8-abcde: OOMKilled (redis)"},
+			expectedTags:  [][]string{append([]string{"event_reason:OOMKilled", "kube_container_name:redis"}, podTags...)},
+			expectedText:  []string{"Container redis was OOM killed with exit code 137"},
+		},
+		{
+			name:   "container restarts after being OOM killed",
+			oldPod: newTestPod(oomKilled),
+			newPod: newTestPod(restartedAfterOOMKill),
+		},
+		{
+			name:          "container is OOM killed before the pod is seen again",
+			oldPod:        newTestPod(running),
+			newPod:        newTestPod(restartedAfterOOMKill),
+			expectedTitle: []string{"Pod default/redis-5d4b9c7f8-abcde: OOMKilled (redis)"},
+			expectedTags:  [][]string{append([]string{"event_reason:OOMKilled", "kube_container_name:redis"}, podTags...)},
+			expectedText:  []string{"Container redis was OOM killed with exit code 137"},
+		},
+		{
+			name:          "container starts crash looping",
+			oldPod:        newTestPod(running),
+			newPod:        newTestPod(crashLooping),
+			expectedTitle: []string{"Pod default/redis-5d4b9c7f8-abcde: CrashLoopBackOff (redis)"},
+			expectedTags:  [][]string{append([]string{"event_reason:CrashLoopBackOff", "kube_container_name:redis"}, podTags...)},
+			expectedText:  []string{"Container redis is in CrashLoopBackOff after 2 restarts: back-off 10s restarting failed container"},
+		},
+		{
+			name:   "container keeps crash looping",
+			oldPod: newTestPod(crashLooping),
+			newPod: newTestPod(crashLooping),
+		},
+		{
+			name:          "pod is evicted by the kubelet",
+			oldPod:        newTestPod(running),
+			newPod:        evicted,
+			expectedTitle: []string{"Pod default/redis-5d4b9c7f8-abcde: Evicted"},
+			expectedTags:  [][]string{append([]string{"event_reason:Evicted", "eviction_resource:memory", "node_condition:MemoryPressure"}, podTags...)},
+			expectedText:  []string{evicted.Status.Message},
+		},
+		{
+			name:          "pod is rejected by a node under pressure",
+			oldPod:        newTestPod(),
+			newPod:        rejected,
+			expectedTitle: []string{"Pod default/redis-5d4b9c7f8-abcde: Evicted"},
+			expectedTags:  [][]string{append([]string{"event_reason:Evicted", "node_condition:DiskPressure"}, podTags...)},
+			expectedText:  []string{rejected.Status.Message},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := podLifecycleEvents("test-cluster", tt.oldPod, tt.newPod)
+			require.Len(t, events, len(tt.expectedTitle))
+			for i, ev := range events {
+				assert.Equal(t, tt.expectedTitle[i], ev.Title)
+				assert.ElementsMatch(t, tt.expectedTags[i], ev.Tags)
+				assert.Equal(t, tt.expectedText[i], ev.Text)
+				assert.Equal(t, "node-1-test-cluster", ev.Host)
+				assert.Equal(t, "kubernetes_apiserver:foobar", ev.AggregationKey)
+				assert.Equal(t, event.EventAlertTypeWarning, ev.AlertType)
+				assert.Equal(t, CheckName, ev.EventType)
+			}
+		})
+	}
+}
+
+func TestGetOwnerTags(t *testing.T) {
+	assert.ElementsMatch(t, []string{
+		"kube_ownerref_kind:Job",
+		"kube_ownerref_name:backup-28712345",
+		"kube_job:backup-28712345",
+		"kube_cronjob:backup",
+	}, getOwnerTags([]metav1.OwnerReference{{Kind: "Job", Name: "backup-28712345"}}))
+
+	assert.ElementsMatch(t, []string{
+		"kube_ownerref_kind:DaemonSet",
+		"kube_ownerref_name:fluentd",
+		"kube_daemon_set:fluentd",
+	}, getOwnerTags([]metav1.OwnerReference{{Kind: "DaemonSet", Name: "fluentd"}}))
+
+	assert.Empty(t, getOwnerTags(nil))
+}
+
+func TestNodeLifecycleEvents(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	condition := func(conditionType v1.NodeConditionType, status v1.ConditionStatus) v1.NodeCondition {
+		return v1.NodeCondition{
+			Type:               conditionType,
+			Status:             status,
+			LastTransitionTime: transitionTime,
+			Message:            string(conditionType) + " is " + string(status),
+		}
+	}
+
+	tests := []struct {
+		name              string
+		oldNode           *v1.Node
+		newNode           *v1.Node
+		expectedTitle     []string
+		expectedReason    []string
+		expectedCondition []string
+		expectedAlertType []event.EventAlertType
+	}{
+		{
+			name:    "no transition",
+			oldNode: newTestNode(condition(v1.NodeReady, v1.ConditionTrue), condition(v1.NodeMemoryPressure, v1.ConditionFalse)),
+			newNode: newTestNode(condition(v1.NodeReady, v1.ConditionTrue), condition(v1.NodeMemoryPressure, v1.ConditionFalse)),
+		},
+		{
+			name:              "node is under memory pressure",
+			oldNode:           newTestNode(condition(v1.NodeReady, v1.ConditionTrue), condition(v1.NodeMemoryPressure, v1.ConditionFalse)),
+			newNode:           newTestNode(condition(v1.NodeReady, v1.ConditionTrue), condition(v1.NodeMemoryPressure, v1.ConditionTrue)),
+			expectedTitle:     []string{"Node node-1: MemoryPressure"},
+			expectedReason:    []string{"MemoryPressure"},
+			expectedCondition: []string{"MemoryPressure"},
+			expectedAlertType: []event.EventAlertType{event.EventAlertTypeWarning},
+		},
+		{
+			name:              "disk and pid pressures are resolved",
+			oldNode:           newTestNode(condition(v1.NodeDiskPressure, v1.ConditionTrue), condition(v1.NodePIDPressure, v1.ConditionTrue)),
+			newNode:           newTestNode(condition(v1.NodeDiskPressure, v1.ConditionFalse), condition(v1.NodePIDPressure, v1.ConditionFalse)),
+			expectedTitle:     []string{"Node node-1: NoDiskPressure", "Node node-1: NoPIDPressure"},
+			expectedReason:    []string{"NoDiskPressure", "NoPIDPressure"},
+			expectedCondition: []string{"DiskPressure", "PIDPressure"},
+			expectedAlertType: []event.EventAlertType{event.EventAlertTypeSuccess, event.EventAlertTypeSuccess},
+		},
+		{
+			name:              "node becomes not ready",
+			oldNode:           newTestNode(condition(v1.NodeReady, v1.ConditionTrue)),
+			newNode:           newTestNode(condition(v1.NodeReady, v1.ConditionUnknown)),
+			expectedTitle:     []string{"Node node-1: NotReady"},
+			expectedReason:    []string{"NotReady"},
+			expectedCondition: []string{"Ready"},
+			expectedAlertType: []event.EventAlertType{event.EventAlertTypeError},
+		},
+		{
+			name:              "node becomes ready again",
+			oldNode:           newTestNode(condition(v1.NodeReady, v1.ConditionFalse)),
+			newNode:           newTestNode(condition(v1.NodeReady, v1.ConditionTrue)),
+			expectedTitle:     []string{"Node node-1: Ready"},
+			expectedReason:    []string{"Ready"},
+			expectedCondition: []string{"Ready"},
+			expectedAlertType: []event.EventAlertType{event.EventAlertTypeSuccess},
+		},
+		{
+			name:    "node reports its conditions for the first time",
+			oldNode: newTestNode(),
+			newNode: newTestNode(condition(v1.NodeReady, v1.ConditionTrue), condition(v1.NodeMemoryPressure, v1.ConditionTrue)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := nodeLifecycleEvents("test-cluster", tt.oldNode, tt.newNode)
+			require.Len(t, events, len(tt.expectedTitle))
+			for i, ev := range events {
+				assert.Equal(t, tt.expectedTitle[i], ev.Title)
+				assert.ElementsMatch(t, []string{
+					"event_reason:" + tt.expectedReason[i],
+					"node_condition:" + tt.expectedCondition[i],
+					"kube_kind:Node",
+					"kube_name:node-1",
+					"kube_node:node-1",
+					"kubernetes_kind:Node",
+					"name:node-1",
+				}, ev.Tags)
+				assert.Equal(t, tt.expectedAlertType[i], ev.AlertType)
+				assert.Equal(t, transitionTime.Unix(), ev.Ts)
+				assert.Equal(t, "node-1-test-cluster", ev.Host)
+				assert.Equal(t, "kubernetes_apiserver:node-uid", ev.AggregationKey)
+				assert.Equal(t, tt.expectedCondition[i]+" is "+string(getNodeConditionStatus(tt.newNode, v1.NodeConditionType(tt.expectedCondition[i]))), ev.Text)
+			}
+		})
+	}
+}
+
+func TestLifecycleEventCollectorMaxEvents(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	collector := newLifecycleEventCollector("test-cluster", 2)
+	require.NoError(t, collector.start(informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0), stopCh))
+	defer collector.stop()
+
+	collector.add([]event.Event{{Title: "1"}, {Title: "2"}})
+	collector.add([]event.Event{{Title: "3"}})
+
+	assert.Equal(t, []event.Event{{Title: "2"}, {Title: "3"}}, collector.flush())
+	assert.Empty(t, collector.flush())
+}
+
+func TestLifecycleEventCollectorInformers(t *testing.T) {
+	pod := newTestPod(v1.ContainerStatus{
+		Name:  "redis",
+		State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+	})
+	node := newTestNode(v1.NodeCondition{Type: v1.NodeReady, Status: v1.ConditionTrue})
+
+	client := fake.NewSimpleClientset(pod, node)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	collector := newLifecycleEventCollector("test-cluster", 10)
+	require.NoError(t, collector.start(factory, stopCh))
+	defer collector.stop()
+
+	podInformer := factory.Core().V1().Pods().Informer()
+	nodeInformer := factory.Core().V1().Nodes().Informer()
+	require.True(t, cache.WaitForCacheSync(stopCh, podInformer.HasSynced, nodeInformer.HasSynced))
+
+	// objects listed when the informers start don't generate events
+	assert.Empty(t, collector.flush())
+
+	pod = pod.DeepCopy()
+	pod.Status.ContainerStatuses[0].State = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	_, err := client.CoreV1().Pods(pod.Namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	node = node.DeepCopy()
+	node.Status.Conditions[0].Status = v1.ConditionFalse
+	_, err = client.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	var titles []string
+	assert.Eventually(t, func() bool {
+		for _, ev := range collector.flush() {
+			titles = append(titles, ev.Title)
+		}
+		return len(titles) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"Pod default/redis-5d4b9c7f8-abcde: OOMKilled (redis)", "Node node-1: NotReady"}, titles)
+
+	// once stopped, e.g. when the leadership is lost or the check is cancelled, the updates
+	// don't generate events anymore while the shared informers keep running
+	collector.add([]event.Event{{Title: "buffered"}})
+	collector.stop()
+	assert.Empty(t, collector.flush())
+
+	node = node.DeepCopy()
+	node.Status.Conditions[0].Status = v1.ConditionTrue
+	_, err = client.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Never(t, func() bool { return len(collector.flush()) > 0 }, 500*time.Millisecond, 10*time.Millisecond)
+
+	assert.False(t, podInformer.IsStopped())
+	assert.False(t, nodeInformer.IsStopped())
+
+	// the handlers are added again when the collection restarts
+	require.NoError(t, collector.start(factory, stopCh))
+	node = node.DeepCopy()
+	node.Status.Conditions[0].Status = v1.ConditionFalse
+	_, err = client.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	titles = nil
+	assert.Eventually(t, func() bool {
+		for _, ev := range collector.flush() {
+			titles = append(titles, ev.Title)
+		}
+		return len(titles) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"Node node-1: NotReady"}, titles)
+}
@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+)
+
+// submitConntrackMetrics reports the usage of the netfilter connection
+// tracking table. The files are missing when the nf_conntrack module isn't
+// loaded.
+func submitConntrackMetrics(sender sender.Sender, procfsPath string) error {
+	netfilterPath := filepath.Join(procfsPath, "sys", "net", "netfilter")
+	count, err := readConntrackValue(filepath.Join(netfilterPath, "nf_conntrack_count"))
+	if err != nil {
+		return err
+	}
+	max, err := readConntrackValue(filepath.Join(netfilterPath, "nf_conntrack_max"))
+	if err != nil {
+		return err
+	}
+
+	sender.Gauge("system.net.conntrack.count", count, "", nil)
+	sender.Gauge("system.net.conntrack.max", max, "", nil)
+	if max > 0 {
+		sender.Gauge("system.net.conntrack.pct_used", count/max, "", nil)
+	}
+	return nil
+}
+
+func readConntrackValue(path string) (float64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(value), nil
+}
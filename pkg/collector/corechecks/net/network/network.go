@@ -24,6 +24,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
 )
@@ -182,6 +183,14 @@ func (c *NetworkCheck) Run() error {
 		submitConnectionsMetrics(sender, "tcp6", tcpStateMetricsSuffixMapping, connectionsStats)
 	}
 
+	procfsPath := "/proc"
+	if config.Datadog.IsSet("procfs_path") {
+		procfsPath = config.Datadog.GetString("procfs_path")
+	}
+	if err := submitConntrackMetrics(sender, procfsPath); err != nil {
+		log.Debugf("network.Check could not read the conntrack stats: %s", err)
+	}
+
 	sender.Commit()
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 type fakeNetworkStats struct {
@@ -516,3 +517,38 @@ excluded_interface_re: "eth[0-9]"
 	mockSender.AssertCalled(t, "Rate", "system.net.packets_out.drop", float64(32), "", lo0Tags)
 	mockSender.AssertCalled(t, "Rate", "system.net.packets_out.error", float64(33), "", lo0Tags)
 }
+
+func TestConntrackMetrics(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", "testfiles/proc")
+	networkCheck := NetworkCheck{
+		net: &fakeNetworkStats{},
+	}
+
+	mockSender := mocksender.NewMockSender(networkCheck.ID())
+	networkCheck.Configure(mockSender.GetSenderManager(), integration.FakeConfigHash, []byte(``), []byte(``), "test")
+	mockSender.SetupAcceptAll()
+
+	err := networkCheck.Run()
+	assert.Nil(t, err)
+
+	mockSender.AssertCalled(t, "Gauge", "system.net.conntrack.count", float64(4096), "", []string(nil))
+	mockSender.AssertCalled(t, "Gauge", "system.net.conntrack.max", float64(262144), "", []string(nil))
+	mockSender.AssertCalled(t, "Gauge", "system.net.conntrack.pct_used", float64(4096)/float64(262144), "", []string(nil))
+}
+
+func TestConntrackMetricsMissing(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", t.TempDir())
+	networkCheck := NetworkCheck{
+		net: &fakeNetworkStats{},
+	}
+
+	mockSender := mocksender.NewMockSender(networkCheck.ID())
+	networkCheck.Configure(mockSender.GetSenderManager(), integration.FakeConfigHash, []byte(``), []byte(``), "test")
+	mockSender.SetupAcceptAll()
+
+	err := networkCheck.Run()
+	assert.Nil(t, err)
+
+	mockSender.AssertNotCalled(t, "Gauge", "system.net.conntrack.count", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertNotCalled(t, "Gauge", "system.net.conntrack.max", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,202 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package probe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultTimeout        = 10 * time.Second
+	defaultStatusCodes    = `(1|2|3)\d\d`
+	defaultDaysWarning    = 14
+	defaultDaysCritical   = 7
+	defaultMaxContentSize = 1 << 20
+
+	probeTypeHTTP = "http"
+	probeTypeTCP  = "tcp"
+	probeTypeTLS  = "tls"
+)
+
+type instanceConfig struct {
+	Name    string   `yaml:"name"`
+	Targets []string `yaml:"targets"`
+	Timeout float64  `yaml:"timeout"`
+
+	// HTTP options
+	Method              string            `yaml:"method"`
+	Headers             map[string]string `yaml:"headers"`
+	Data                string            `yaml:"data"`
+	StatusCodes         string            `yaml:"http_response_status_code"`
+	ContentMatch        string            `yaml:"content_match"`
+	ReverseContentMatch bool              `yaml:"reverse_content_match"`
+	AllowRedirects      *bool             `yaml:"allow_redirects"`
+	Proxy               string            `yaml:"proxy"`
+	SkipProxy           bool              `yaml:"skip_proxy"`
+
+	// TLS options
+	TLSVerify                  *bool  `yaml:"tls_verify"`
+	TLSCACert                  string `yaml:"tls_ca_cert"`
+	TLSServerName              string `yaml:"tls_server_name"`
+	CheckCertificateExpiration *bool  `yaml:"check_certificate_expiration"`
+	DaysWarning                int    `yaml:"days_warning"`
+	DaysCritical               int    `yaml:"days_critical"`
+}
+
+// target is an endpoint to probe
+type target struct {
+	raw       string
+	probeType string
+	// url is set for HTTP targets, address for TCP and TLS ones
+	url     *url.URL
+	address string
+}
+
+// checkConfig is the parsed and validated configuration of an instance
+type checkConfig struct {
+	name    string
+	targets []*target
+	timeout time.Duration
+
+	method              string
+	headers             map[string]string
+	data                string
+	statusCodes         *regexp.Regexp
+	contentMatch        *regexp.Regexp
+	reverseContentMatch bool
+	allowRedirects      bool
+	proxy               *url.URL
+	skipProxy           bool
+
+	tlsConfig        *tls.Config
+	checkCertificate bool
+	daysWarning      int
+	daysCritical     int
+}
+
+func parseConfig(data []byte) (*checkConfig, error) {
+	var instance instanceConfig
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	c := &checkConfig{
+		name:                instance.Name,
+		timeout:             defaultTimeout,
+		method:              strings.ToUpper(instance.Method),
+		headers:             instance.Headers,
+		data:                instance.Data,
+		reverseContentMatch: instance.ReverseContentMatch,
+		allowRedirects:      instance.AllowRedirects == nil || *instance.AllowRedirects,
+		skipProxy:           instance.SkipProxy,
+		checkCertificate:    instance.CheckCertificateExpiration == nil || *instance.CheckCertificateExpiration,
+		daysWarning:         instance.DaysWarning,
+		daysCritical:        instance.DaysCritical,
+	}
+
+	if len(instance.Targets) == 0 {
+		return nil, errors.New("at least one target must be set")
+	}
+	for _, raw := range instance.Targets {
+		t, err := parseTarget(raw)
+		if err != nil {
+			return nil, err
+		}
+		c.targets = append(c.targets, t)
+	}
+
+	if instance.Timeout > 0 {
+		c.timeout = time.Duration(instance.Timeout * float64(time.Second))
+	}
+	if c.method == "" {
+		c.method = "GET"
+	}
+
+	statusCodes := instance.StatusCodes
+	if statusCodes == "" {
+		statusCodes = defaultStatusCodes
+	}
+	var err error
+	if c.statusCodes, err = regexp.Compile("^(?:" + statusCodes + ")$"); err != nil {
+		return nil, fmt.Errorf("invalid http_response_status_code %q: %w", statusCodes, err)
+	}
+	if instance.ContentMatch != "" {
+		if c.contentMatch, err = regexp.Compile(instance.ContentMatch); err != nil {
+			return nil, fmt.Errorf("invalid content_match %q: %w", instance.ContentMatch, err)
+		}
+	}
+	if instance.Proxy != "" {
+		if c.proxy, err = url.Parse(instance.Proxy); err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", instance.Proxy, err)
+		}
+	}
+
+	c.tlsConfig = &tls.Config{
+		InsecureSkipVerify: instance.TLSVerify != nil && !*instance.TLSVerify,
+		ServerName:         instance.TLSServerName,
+	}
+	if instance.TLSCACert != "" {
+		caCert, err := os.ReadFile(instance.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA certificate: %w", err)
+		}
+		c.tlsConfig.RootCAs = x509.NewCertPool()
+		if !c.tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in %s", instance.TLSCACert)
+		}
+	}
+
+	if c.daysWarning <= 0 {
+		c.daysWarning = defaultDaysWarning
+	}
+	if c.daysCritical <= 0 {
+		c.daysCritical = defaultDaysCritical
+	}
+	if c.daysCritical > c.daysWarning {
+		return nil, fmt.Errorf("days_critical (%d) can't be greater than days_warning (%d)", c.daysCritical, c.daysWarning)
+	}
+
+	return c, nil
+}
+
+// parseTarget parses a target, that is either an HTTP(S) URL, or a
+// tcp://host:port or tls://host:port address
+func parseTarget(raw string) (*target, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %q: %w", raw, err)
+	}
+
+	t := &target{raw: raw}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid target %q: missing host", raw)
+		}
+		t.probeType = probeTypeHTTP
+		t.url = u
+	case probeTypeTCP, probeTypeTLS:
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			return nil, fmt.Errorf("invalid target %q: %w", raw, err)
+		}
+		t.probeType = u.Scheme
+		t.address = u.Host
+	default:
+		return nil, fmt.Errorf("invalid target %q: the scheme must be http, https, tcp or tls", raw)
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig([]byte(`
+targets:
+  - https://example.com/health
+  - tcp://db.internal:5432
+  - tls://[::1]:465
+timeout: 2.5
+method: head
+http_response_status_code: "200|204"
+tls_verify: false
+days_warning: 30
+`))
+	require.NoError(t, err)
+
+	require.Len(t, c.targets, 3)
+	assert.Equal(t, probeTypeHTTP, c.targets[0].probeType)
+	assert.Equal(t, probeTypeTCP, c.targets[1].probeType)
+	assert.Equal(t, "db.internal:5432", c.targets[1].address)
+	assert.Equal(t, probeTypeTLS, c.targets[2].probeType)
+	assert.Equal(t, "[::1]:465", c.targets[2].address)
+
+	assert.Equal(t, 2500*time.Millisecond, c.timeout)
+	assert.Equal(t, "HEAD", c.method)
+	assert.True(t, c.statusCodes.MatchString("204"))
+	assert.False(t, c.statusCodes.MatchString("2040"))
+	assert.True(t, c.tlsConfig.InsecureSkipVerify)
+	assert.True(t, c.allowRedirects)
+	assert.True(t, c.checkCertificate)
+	assert.Equal(t, 30, c.daysWarning)
+	assert.Equal(t, defaultDaysCritical, c.daysCritical)
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`timeout: 5`,
+		`targets: [example.com]`,
+		`targets: ["tcp://example.com"]`,
+		`targets: ["ftp://example.com:21"]`,
+		`targets: ["https://"]`,
+		`{"targets": ["https://example.com"], "http_response_status_code": "("}`,
+		`{"targets": ["https://example.com"], "content_match": "["}`,
+		`{"targets": ["https://example.com"], "days_warning": 5, "days_critical": 10}`,
+		`{"targets": ["https://example.com"], "tls_ca_cert": "/does/not/exist"}`,
+	} {
+		_, err := parseConfig([]byte(conf))
+		assert.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package probe implements a check probing HTTP(S) URLs, TCP ports and TLS
+// endpoints.
+//
+// The targets of an instance are probed concurrently. Each probe reports
+// whether the target could be reached, its response time broken down by
+// phase, and, when relevant, the status code and content of the HTTP
+// response and the expiration of the certificate of the target.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "probe"
+
+	metricPrefix = "probe."
+)
+
+// phaseMetrics maps the phases of a probe to the metrics reporting their
+// duration
+var phaseMetrics = map[string]string{
+	phaseDNS:     metricPrefix + "dns_time",
+	phaseConnect: metricPrefix + "connect_time",
+	phaseTLS:     metricPrefix + "tls_time",
+	phaseTTFB:    metricPrefix + "ttfb",
+}
+
+// Check probes HTTP(S), TCP and TLS endpoints
+type Check struct {
+	core.CheckBase
+	config *checkConfig
+	client *http.Client
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+		now:       time.Now,
+	}
+}
+
+// Configure parses the check configuration and initializes the check
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, data, initConfig)
+
+	if err := c.CommonConfigure(senderManager, integrationConfigDigest, initConfig, data, source); err != nil {
+		return err
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		return err
+	}
+	c.config = config
+	c.client = newHTTPClient(config)
+	return nil
+}
+
+// Run probes the targets concurrently and submits the results
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	results := make([]*result, len(c.config.targets))
+	var wg sync.WaitGroup
+	for i, t := range c.config.targets {
+		wg.Add(1)
+		go func(i int, t *target) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), c.config.timeout)
+			defer cancel()
+			if t.probeType == probeTypeHTTP {
+				results[i] = probeHTTP(ctx, c.config, c.client, t)
+			} else {
+				results[i] = probeTCP(ctx, c.config, t)
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		c.submit(sender, r)
+	}
+	return nil
+}
+
+func (c *Check) submit(sender sender.Sender, r *result) {
+	tags := []string{"target:" + r.target.raw, "probe_type:" + r.target.probeType}
+	if c.config.name != "" {
+		tags = append(tags, "instance:"+c.config.name)
+	}
+
+	if r.err != nil {
+		sender.Gauge(metricPrefix+"can_connect", 0, "", tags)
+		sender.ServiceCheck(metricPrefix+"can_connect", servicecheck.ServiceCheckCritical, "", tags, r.err.Error())
+		return
+	}
+	sender.Gauge(metricPrefix+"can_connect", 1, "", tags)
+	sender.ServiceCheck(metricPrefix+"can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+
+	sender.Gauge(metricPrefix+"response_time", r.duration.Seconds(), "", tags)
+	for phase, d := range r.phases {
+		sender.Gauge(phaseMetrics[phase], d.Seconds(), "", tags)
+	}
+
+	if r.target.probeType == probeTypeHTTP {
+		status, message := servicecheck.ServiceCheckOK, ""
+		if !c.config.statusCodes.MatchString(fmt.Sprint(r.statusCode)) {
+			status = servicecheck.ServiceCheckCritical
+			message = fmt.Sprintf("Unexpected status code %d", r.statusCode)
+		}
+		sender.ServiceCheck(metricPrefix+"http.status", status, "", append(tags, fmt.Sprintf("status_code:%d", r.statusCode)), message)
+
+		if c.config.contentMatch != nil {
+			status, message := servicecheck.ServiceCheckOK, ""
+			if r.contentMatched == c.config.reverseContentMatch {
+				status = servicecheck.ServiceCheckCritical
+				if c.config.reverseContentMatch {
+					message = fmt.Sprintf("Content matches %q", c.config.contentMatch)
+				} else {
+					message = fmt.Sprintf("Content doesn't match %q", c.config.contentMatch)
+				}
+			}
+			sender.ServiceCheck(metricPrefix+"http.content_match", status, "", tags, message)
+		}
+	}
+
+	if c.config.checkCertificate && r.certificate != nil {
+		c.submitCertificate(sender, r, tags)
+	}
+}
+
+// submitCertificate reports the number of days until the certificate of the
+// target expires
+func (c *Check) submitCertificate(sender sender.Sender, r *result, tags []string) {
+	daysLeft := r.certificate.NotAfter.Sub(c.now()).Hours() / 24
+	sender.Gauge(metricPrefix+"ssl.days_left", daysLeft, "", tags)
+
+	status, message := servicecheck.ServiceCheckOK, ""
+	switch {
+	case daysLeft < 0:
+		status = servicecheck.ServiceCheckCritical
+		message = fmt.Sprintf("The certificate expired on %s", r.certificate.NotAfter.Format(time.RFC3339))
+	case daysLeft < float64(c.config.daysCritical):
+		status = servicecheck.ServiceCheckCritical
+		message = fmt.Sprintf("The certificate expires in %.1f days", daysLeft)
+	case daysLeft < float64(c.config.daysWarning):
+		status = servicecheck.ServiceCheckWarning
+		message = fmt.Sprintf("The certificate expires in %.1f days", daysLeft)
+	}
+	sender.ServiceCheck(metricPrefix+"ssl.cert_expiration", status, "", tags, message)
+}
@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package probe
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func runCheck(t *testing.T, instance string, now time.Time) *mocksender.MockSender {
+	c := newCheck().(*Check)
+	if !now.IsZero() {
+		c.now = func() time.Time { return now }
+	}
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, []byte(instance), nil, "test"))
+	mockSender := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	require.NoError(t, c.Run())
+	return mockSender
+}
+
+func TestHTTPProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte(`{"status": "ok"}`))
+		case "/redirect":
+			http.Redirect(w, r, "/health", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	health := "target:" + server.URL + "/health"
+	down := "target:" + server.URL + "/down"
+	redirect := "target:" + server.URL + "/redirect"
+
+	mockSender := runCheck(t, `
+name: web
+targets:
+  - `+server.URL+`/health
+  - `+server.URL+`/down
+  - `+server.URL+`/redirect
+content_match: '"status": "ok"'
+allow_redirects: false
+skip_proxy: true
+`, time.Time{})
+
+	tags := []string{health, "probe_type:http", "instance:web"}
+	mockSender.AssertServiceCheck(t, "probe.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+	mockSender.AssertMetric(t, "Gauge", "probe.can_connect", 1, "", tags)
+	mockSender.AssertMetricInRange(t, "Gauge", "probe.response_time", 0, 10, "", tags)
+	mockSender.AssertMetricInRange(t, "Gauge", "probe.connect_time", 0, 10, "", tags)
+	mockSender.AssertMetricInRange(t, "Gauge", "probe.ttfb", 0, 10, "", tags)
+	mockSender.AssertServiceCheck(t, "probe.http.status", servicecheck.ServiceCheckOK, "", append(tags, "status_code:200"), "")
+	mockSender.AssertServiceCheck(t, "probe.http.content_match", servicecheck.ServiceCheckOK, "", tags, "")
+	mockSender.AssertNotCalled(t, "Gauge", "probe.dns_time", mock.Anything, "", mocksender.MatchTagsContains(tags))
+	mockSender.AssertNotCalled(t, "Gauge", "probe.ssl.days_left", mock.Anything, mock.Anything, mock.Anything)
+
+	mockSender.AssertServiceCheck(t, "probe.can_connect", servicecheck.ServiceCheckOK, "", []string{down}, "")
+	mockSender.AssertServiceCheck(t, "probe.http.status", servicecheck.ServiceCheckCritical, "", []string{down, "status_code:503"}, "Unexpected status code 503")
+	mockSender.AssertServiceCheck(t, "probe.http.content_match", servicecheck.ServiceCheckCritical, "", []string{down}, `Content doesn't match "\"status\": \"ok\""`)
+
+	mockSender.AssertServiceCheck(t, "probe.http.status", servicecheck.ServiceCheckOK, "", []string{redirect, "status_code:302"}, "")
+}
+
+func TestHTTPSProbeCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	notAfter := server.Certificate().NotAfter
+	tags := []string{"target:" + server.URL, "probe_type:http"}
+
+	// the certificate of the test server is self-signed
+	mockSender := runCheck(t, "targets: ["+server.URL+"]\nskip_proxy: true\n", time.Time{})
+	mockSender.AssertServiceCheck(t, "probe.can_connect", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+	mockSender.AssertMetric(t, "Gauge", "probe.can_connect", 0, "", tags)
+
+	mockSender = runCheck(t, "targets: ["+server.URL+"]\nskip_proxy: true\ntls_verify: false\n", notAfter.Add(-10*24*time.Hour))
+	mockSender.AssertMetric(t, "Gauge", "probe.ssl.days_left", 10, "", tags)
+	mockSender.AssertServiceCheck(t, "probe.ssl.cert_expiration", servicecheck.ServiceCheckWarning, "", tags, "The certificate expires in 10.0 days")
+	mockSender.AssertMetricInRange(t, "Gauge", "probe.tls_time", 0, 10, "", tags)
+}
+
+func TestTCPAndTLSProbes(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcpListener.Close()
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	tlsServer := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	tlsServer.TLS = &tls.Config{}
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	tlsAddress := strings.TrimPrefix(tlsServer.URL, "https://")
+	notAfter := tlsServer.Certificate().NotAfter
+
+	// a port nobody listens on
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	closedListener.Close()
+
+	mockSender := runCheck(t, `
+targets:
+  - tcp://`+tcpListener.Addr().String()+`
+  - tls://`+tlsAddress+`
+  - tcp://`+closedAddress+`
+tls_verify: false
+timeout: 2
+`, notAfter.Add(-2*24*time.Hour))
+
+	tcpTags := []string{"target:tcp://" + tcpListener.Addr().String(), "probe_type:tcp"}
+	mockSender.AssertServiceCheck(t, "probe.can_connect", servicecheck.ServiceCheckOK, "", tcpTags, "")
+	mockSender.AssertMetricInRange(t, "Gauge", "probe.connect_time", 0, 2, "", tcpTags)
+	mockSender.AssertNotCalled(t, "Gauge", "probe.tls_time", mock.Anything, "", mocksender.MatchTagsContains(tcpTags))
+
+	tlsTags := []string{"target:tls://" + tlsAddress, "probe_type:tls"}
+	mockSender.AssertServiceCheck(t, "probe.can_connect", servicecheck.ServiceCheckOK, "", tlsTags, "")
+	mockSender.AssertMetricInRange(t, "Gauge", "probe.tls_time", 0, 2, "", tlsTags)
+	mockSender.AssertMetric(t, "Gauge", "probe.ssl.days_left", 2, "", tlsTags)
+	mockSender.AssertServiceCheck(t, "probe.ssl.cert_expiration", servicecheck.ServiceCheckCritical, "", tlsTags, "The certificate expires in 2.0 days")
+
+	closedTags := []string{"target:tcp://" + closedAddress, "probe_type:tcp"}
+	mockSender.AssertMetric(t, "Gauge", "probe.can_connect", 0, "", closedTags)
+	mockSender.AssertNotCalled(t, "Gauge", "probe.response_time", mock.Anything, "", mocksender.MatchTagsContains(closedTags))
+}
@@ -0,0 +1,222 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+const (
+	phaseDNS     = "dns"
+	phaseConnect = "connect"
+	phaseTLS     = "tls"
+	phaseTTFB    = "ttfb"
+)
+
+// result is the outcome of probing a target
+type result struct {
+	target   *target
+	err      error
+	duration time.Duration
+	// phases holds the duration of the phases of the probe that happened
+	phases map[string]time.Duration
+
+	statusCode     int
+	contentMatched bool
+	certificate    *x509.Certificate
+}
+
+// phaseTimer records the duration of the phases of a probe. Only the first
+// occurrence of a phase is kept, later ones happening when following
+// redirects or racing connections.
+type phaseTimer struct {
+	m      sync.Mutex
+	starts map[string]time.Time
+	phases map[string]time.Duration
+}
+
+func newPhaseTimer() *phaseTimer {
+	return &phaseTimer{
+		starts: make(map[string]time.Time),
+		phases: make(map[string]time.Duration),
+	}
+}
+
+func (p *phaseTimer) start(phase string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if _, found := p.starts[phase]; !found {
+		p.starts[phase] = time.Now()
+	}
+}
+
+func (p *phaseTimer) done(phase string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	start, found := p.starts[phase]
+	if _, done := p.phases[phase]; found && !done {
+		p.phases[phase] = time.Since(start)
+	}
+}
+
+// durations returns the durations of the phases that completed
+func (p *phaseTimer) durations() map[string]time.Duration {
+	p.m.Lock()
+	defer p.m.Unlock()
+	durations := make(map[string]time.Duration, len(p.phases))
+	for phase, d := range p.phases {
+		durations[phase] = d
+	}
+	return durations
+}
+
+// newHTTPClient returns the client used to probe the HTTP targets of an
+// instance. Keep-alives are disabled so that every probe measures the
+// connection phases.
+func newHTTPClient(config *checkConfig) *http.Client {
+	transport := &http.Transport{
+		TLSClientConfig:   config.tlsConfig.Clone(),
+		DisableKeepAlives: true,
+	}
+	if config.proxy != nil {
+		transport.Proxy = http.ProxyURL(config.proxy)
+	} else if proxies := pkgconfig.Datadog.GetProxies(); proxies != nil && !config.skipProxy {
+		transport.Proxy = httputils.GetProxyTransportFunc(proxies, pkgconfig.Datadog)
+	}
+
+	client := &http.Client{Transport: transport}
+	if !config.allowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// probeHTTP sends a request to an HTTP target
+func probeHTTP(ctx context.Context, config *checkConfig, client *http.Client, t *target) *result {
+	r := &result{target: t}
+	timer := newPhaseTimer()
+	// the time to first byte only covers the server processing the request,
+	// the DNS, connect and TLS phases are reported on their own
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { timer.start(phaseDNS) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { timer.done(phaseDNS) },
+		ConnectStart:         func(string, string) { timer.start(phaseConnect) },
+		ConnectDone:          func(string, string, error) { timer.done(phaseConnect) },
+		TLSHandshakeStart:    func() { timer.start(phaseTLS) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { timer.done(phaseTLS) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { timer.start(phaseTTFB) },
+		GotFirstResponseByte: func() { timer.done(phaseTTFB) },
+	}
+
+	var body io.Reader
+	if config.data != "" {
+		body = strings.NewReader(config.data)
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), config.method, t.url.String(), body)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	for k, v := range config.headers {
+		if strings.EqualFold(k, "host") {
+			req.Host = v
+		} else {
+			req.Header.Set(k, v)
+		}
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	defer resp.Body.Close()
+
+	r.statusCode = resp.StatusCode
+	if config.contentMatch != nil {
+		content, err := io.ReadAll(io.LimitReader(resp.Body, defaultMaxContentSize))
+		if err != nil {
+			r.err = err
+			return r
+		}
+		r.contentMatched = config.contentMatch.Match(content)
+	}
+	r.duration = time.Since(start)
+	r.phases = timer.durations()
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		r.certificate = resp.TLS.PeerCertificates[0]
+	}
+	return r
+}
+
+// probeTCP connects to a TCP target, and performs a TLS handshake for TLS
+// targets
+func probeTCP(ctx context.Context, config *checkConfig, t *target) *result {
+	r := &result{target: t, phases: make(map[string]time.Duration)}
+	start := time.Now()
+
+	host, port, err := net.SplitHostPort(t.address)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	ip := host
+	if net.ParseIP(host) == nil {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			r.err = err
+			return r
+		}
+		ip = addrs[0]
+		r.phases[phaseDNS] = time.Since(start)
+	}
+
+	connectStart := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, port))
+	if err != nil {
+		r.err = err
+		return r
+	}
+	defer conn.Close()
+	r.phases[phaseConnect] = time.Since(connectStart)
+
+	if t.probeType == probeTypeTLS {
+		tlsConfig := config.tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = host
+		}
+		tlsStart := time.Now()
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			r.err = err
+			return r
+		}
+		r.phases[phaseTLS] = time.Since(tlsStart)
+		if certs := tlsConn.ConnectionState().PeerCertificates; len(certs) > 0 {
+			r.certificate = certs[0]
+		}
+	}
+
+	r.duration = time.Since(start)
+	return r
+}
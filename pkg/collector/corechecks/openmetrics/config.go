@@ -0,0 +1,377 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultBearerTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	typeGauge     = "gauge"
+	typeCounter   = "counter"
+	typeHistogram = "histogram"
+	typeSummary   = "summary"
+)
+
+// instanceConfig is the configuration of an instance. It accepts the options
+// of the openmetrics integration, the ones of the legacy (V1) instances, that
+// only set `prometheus_url`, being mapped to their V2 equivalent.
+type instanceConfig struct {
+	OpenMetricsEndpoint              string                 `yaml:"openmetrics_endpoint"`
+	Namespace                        string                 `yaml:"namespace"`
+	RawMetricPrefix                  string                 `yaml:"raw_metric_prefix"`
+	RawLineFilters                   []string               `yaml:"raw_line_filters"`
+	Metrics                          []interface{}          `yaml:"metrics"`
+	ExcludeMetrics                   []string               `yaml:"exclude_metrics"`
+	ExcludeMetricsByLabels           map[string]interface{} `yaml:"exclude_metrics_by_labels"`
+	RenameLabels                     map[string]string      `yaml:"rename_labels"`
+	IncludeLabels                    []string               `yaml:"include_labels"`
+	ExcludeLabels                    []string               `yaml:"exclude_labels"`
+	TypeOverrides                    map[string]string      `yaml:"type_overrides"`
+	CollectHistogramBuckets          *bool                  `yaml:"collect_histogram_buckets"`
+	HistogramBucketsAsDistributions  bool                   `yaml:"histogram_buckets_as_distributions"`
+	CollectCountersWithDistributions bool                   `yaml:"collect_counters_with_distributions"`
+	EnableHealthServiceCheck         *bool                  `yaml:"enable_health_service_check"`
+	TagByEndpoint                    *bool                  `yaml:"tag_by_endpoint"`
+
+	// legacy options, superseded by the ones above
+	PrometheusURL           string                 `yaml:"prometheus_url"`
+	PrometheusMetricsPrefix string                 `yaml:"prometheus_metrics_prefix"`
+	IgnoreMetrics           []string               `yaml:"ignore_metrics"`
+	IgnoreMetricsByLabels   map[string]interface{} `yaml:"ignore_metrics_by_labels"`
+	LabelsMapper            map[string]string      `yaml:"labels_mapper"`
+	SendHistogramsBuckets   *bool                  `yaml:"send_histograms_buckets"`
+	SendDistributionBuckets bool                   `yaml:"send_distribution_buckets"`
+	HealthServiceCheck      *bool                  `yaml:"health_service_check"`
+
+	// HTTP options
+	BearerTokenAuth interface{}       `yaml:"bearer_token_auth"`
+	BearerTokenPath string            `yaml:"bearer_token_path"`
+	TLSVerify       *bool             `yaml:"tls_verify"`
+	TLSCACert       string            `yaml:"tls_ca_cert"`
+	TLSCert         string            `yaml:"tls_cert"`
+	TLSPrivateKey   string            `yaml:"tls_private_key"`
+	Headers         map[string]string `yaml:"headers"`
+	ExtraHeaders    map[string]string `yaml:"extra_headers"`
+	Timeout         float64           `yaml:"timeout"`
+	SkipProxy       bool              `yaml:"skip_proxy"`
+}
+
+// metricConfig is how a metric matched by name is submitted
+type metricConfig struct {
+	name     string
+	typeName string
+}
+
+// checkConfig is the parsed and validated configuration of an instance
+type checkConfig struct {
+	endpoint        string
+	namespace       string
+	rawMetricPrefix string
+	rawLineFilters  []string
+
+	// metrics maps raw metric names to their configuration, patterns
+	// matches the metrics submitted under their raw name
+	metrics        map[string]metricConfig
+	patterns       []*regexp.Regexp
+	excludeMetrics []*regexp.Regexp
+	// excludeByLabels maps label names to the values excluding a sample, a
+	// nil slice excluding any value
+	excludeByLabels map[string][]string
+
+	renameLabels  map[string]string
+	includeLabels map[string]struct{}
+	excludeLabels map[string]struct{}
+	typeOverrides map[string]string
+
+	collectBuckets             bool
+	bucketsAsDistributions     bool
+	countersWithDistributions  bool
+	healthServiceCheck         bool
+	tagByEndpoint              bool
+	bearerTokenAuth            bool
+	bearerTokenTLSOnly         bool
+	bearerTokenPath            string
+	tlsVerify                  bool
+	tlsCACert, tlsCert, tlsKey string
+	headers                    map[string]string
+	timeout                    time.Duration
+	skipProxy                  bool
+}
+
+func parseConfig(data []byte) (*checkConfig, error) {
+	var instance instanceConfig
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	c := &checkConfig{
+		endpoint:                  instance.OpenMetricsEndpoint,
+		namespace:                 strings.TrimSuffix(instance.Namespace, "."),
+		rawMetricPrefix:           instance.RawMetricPrefix,
+		rawLineFilters:            instance.RawLineFilters,
+		metrics:                   make(map[string]metricConfig),
+		renameLabels:              instance.RenameLabels,
+		typeOverrides:             make(map[string]string),
+		collectBuckets:            boolOr(instance.CollectHistogramBuckets, true),
+		bucketsAsDistributions:    instance.HistogramBucketsAsDistributions,
+		countersWithDistributions: instance.CollectCountersWithDistributions,
+		healthServiceCheck:        boolOr(instance.EnableHealthServiceCheck, true),
+		tagByEndpoint:             boolOr(instance.TagByEndpoint, true),
+		bearerTokenPath:           instance.BearerTokenPath,
+		tlsVerify:                 boolOr(instance.TLSVerify, true),
+		tlsCACert:                 instance.TLSCACert,
+		tlsCert:                   instance.TLSCert,
+		tlsKey:                    instance.TLSPrivateKey,
+		headers:                   make(map[string]string),
+		timeout:                   defaultTimeout,
+		skipProxy:                 instance.SkipProxy,
+	}
+
+	// metric patterns are regular expressions, except in legacy instances
+	// where they are wildcards
+	compile := compileRegexp
+	excludeMetrics := instance.ExcludeMetrics
+	excludeByLabels := instance.ExcludeMetricsByLabels
+	if c.endpoint == "" {
+		c.endpoint = instance.PrometheusURL
+		compile = compileWildcard
+		if c.rawMetricPrefix == "" {
+			c.rawMetricPrefix = instance.PrometheusMetricsPrefix
+		}
+		if excludeMetrics == nil {
+			excludeMetrics = instance.IgnoreMetrics
+		}
+		if excludeByLabels == nil {
+			excludeByLabels = instance.IgnoreMetricsByLabels
+		}
+		if c.renameLabels == nil {
+			c.renameLabels = instance.LabelsMapper
+		}
+		if instance.CollectHistogramBuckets == nil {
+			c.collectBuckets = boolOr(instance.SendHistogramsBuckets, true)
+		}
+		if !c.bucketsAsDistributions {
+			c.bucketsAsDistributions = instance.SendDistributionBuckets
+		}
+		if instance.EnableHealthServiceCheck == nil {
+			c.healthServiceCheck = boolOr(instance.HealthServiceCheck, true)
+		}
+	}
+	if c.endpoint == "" {
+		return nil, errors.New("the openmetrics_endpoint must be set")
+	}
+	if len(instance.Metrics) == 0 {
+		return nil, errors.New("the metrics to collect must be set")
+	}
+
+	if err := c.parseMetrics(instance.Metrics, compile); err != nil {
+		return nil, err
+	}
+	for _, pattern := range excludeMetrics {
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_metrics pattern %q: %w", pattern, err)
+		}
+		c.excludeMetrics = append(c.excludeMetrics, re)
+	}
+	var err error
+	if c.excludeByLabels, err = parseExcludeByLabels(excludeByLabels); err != nil {
+		return nil, err
+	}
+
+	for name, typeName := range instance.TypeOverrides {
+		if err := validateTypeOverride(typeName); err != nil {
+			return nil, fmt.Errorf("invalid type override for %s: %w", name, err)
+		}
+		c.typeOverrides[name] = typeName
+	}
+
+	if len(instance.IncludeLabels) > 0 {
+		c.includeLabels = toSet(instance.IncludeLabels)
+	}
+	c.excludeLabels = toSet(instance.ExcludeLabels)
+
+	if err := c.parseBearerTokenAuth(instance.BearerTokenAuth); err != nil {
+		return nil, err
+	}
+	if c.bearerTokenPath == "" {
+		c.bearerTokenPath = defaultBearerTokenPath
+	}
+	if (c.tlsCert == "") != (c.tlsKey == "") {
+		return nil, errors.New("tls_cert and tls_private_key must be set together")
+	}
+
+	for k, v := range instance.Headers {
+		c.headers[k] = v
+	}
+	for k, v := range instance.ExtraHeaders {
+		c.headers[k] = v
+	}
+	if instance.Timeout > 0 {
+		c.timeout = time.Duration(instance.Timeout * float64(time.Second))
+	}
+
+	return c, nil
+}
+
+// parseMetrics parses the metrics to collect, that are either a pattern, or
+// a map of raw metric names to their new name or to a map with their new
+// name and type
+func (c *checkConfig) parseMetrics(metrics []interface{}, compile func(string) (*regexp.Regexp, error)) error {
+	for _, m := range metrics {
+		switch metric := m.(type) {
+		case string:
+			re, err := compile(metric)
+			if err != nil {
+				return fmt.Errorf("invalid metrics pattern %q: %w", metric, err)
+			}
+			c.patterns = append(c.patterns, re)
+		case map[interface{}]interface{}:
+			for k, v := range metric {
+				rawName, ok := k.(string)
+				if !ok {
+					return fmt.Errorf("invalid metric name %v", k)
+				}
+				conf, err := parseMetricConfig(rawName, v)
+				if err != nil {
+					return err
+				}
+				c.metrics[rawName] = conf
+			}
+		case map[string]interface{}:
+			for rawName, v := range metric {
+				conf, err := parseMetricConfig(rawName, v)
+				if err != nil {
+					return err
+				}
+				c.metrics[rawName] = conf
+			}
+		default:
+			return fmt.Errorf("invalid metrics entry %v", m)
+		}
+	}
+	return nil
+}
+
+func parseMetricConfig(rawName string, v interface{}) (metricConfig, error) {
+	switch value := v.(type) {
+	case string:
+		return metricConfig{name: value}, nil
+	case map[interface{}]interface{}:
+		conf := metricConfig{name: rawName}
+		for k, field := range value {
+			s, ok := field.(string)
+			if !ok {
+				return conf, fmt.Errorf("invalid %v of metric %s", k, rawName)
+			}
+			switch k {
+			case "name":
+				conf.name = s
+			case "type":
+				if err := validateTypeOverride(s); err != nil {
+					return conf, fmt.Errorf("invalid type of metric %s: %w", rawName, err)
+				}
+				conf.typeName = s
+			}
+		}
+		return conf, nil
+	}
+	return metricConfig{}, fmt.Errorf("invalid configuration of metric %s", rawName)
+}
+
+func parseExcludeByLabels(labels map[string]interface{}) (map[string][]string, error) {
+	excluded := make(map[string][]string, len(labels))
+	for label, v := range labels {
+		switch values := v.(type) {
+		case bool:
+			if values {
+				excluded[label] = nil
+			}
+		case []interface{}:
+			var excludedValues []string
+			for _, value := range values {
+				s, ok := value.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid value %v to exclude for label %s", value, label)
+				}
+				if s == "*" {
+					excludedValues = nil
+					break
+				}
+				excludedValues = append(excludedValues, s)
+			}
+			excluded[label] = excludedValues
+		default:
+			return nil, fmt.Errorf("invalid values to exclude for label %s", label)
+		}
+	}
+	return excluded, nil
+}
+
+// parseBearerTokenAuth parses bearer_token_auth, that is either a boolean
+// or "tls_only" to send the token only to HTTPS endpoints
+func (c *checkConfig) parseBearerTokenAuth(v interface{}) error {
+	switch auth := v.(type) {
+	case nil:
+	case bool:
+		c.bearerTokenAuth = auth
+	case string:
+		if auth != "tls_only" {
+			return fmt.Errorf("invalid bearer_token_auth %q", auth)
+		}
+		c.bearerTokenAuth = true
+		c.bearerTokenTLSOnly = true
+	default:
+		return fmt.Errorf("invalid bearer_token_auth %v", v)
+	}
+	return nil
+}
+
+func validateTypeOverride(typeName string) error {
+	switch typeName {
+	case typeGauge, typeCounter:
+		return nil
+	}
+	return fmt.Errorf("unsupported type %q, must be %s or %s", typeName, typeGauge, typeCounter)
+}
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// compileWildcard compiles a pattern where '*' matches any string
+func compileWildcard(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func boolOr(b *bool, defaultValue bool) bool {
+	if b == nil {
+		return defaultValue
+	}
+	return *b
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig([]byte(`
+openmetrics_endpoint: http://localhost:9090/metrics
+namespace: app.
+raw_metric_prefix: app_
+metrics:
+  - requests
+  - go_.*
+  - process_cpu_seconds: cpu
+  - queue_depth:
+      name: queue.depth
+      type: counter
+exclude_metrics:
+  - go_gc_.*
+exclude_metrics_by_labels:
+  status: ["500", "503"]
+  debug: true
+rename_labels:
+  pod: pod_name
+include_labels: [pod]
+type_overrides:
+  jobs: counter
+bearer_token_auth: tls_only
+timeout: 2.5
+headers:
+  X-Api-Key: key
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://localhost:9090/metrics", c.endpoint)
+	assert.Equal(t, "app", c.namespace)
+	assert.Equal(t, "app_", c.rawMetricPrefix)
+	assert.Equal(t, map[string]metricConfig{
+		"process_cpu_seconds": {name: "cpu"},
+		"queue_depth":         {name: "queue.depth", typeName: typeCounter},
+	}, c.metrics)
+	assert.Equal(t, map[string][]string{"status": {"500", "503"}, "debug": nil}, c.excludeByLabels)
+	assert.Equal(t, map[string]string{"jobs": typeCounter}, c.typeOverrides)
+	assert.True(t, c.bearerTokenAuth)
+	assert.True(t, c.bearerTokenTLSOnly)
+	assert.Equal(t, defaultBearerTokenPath, c.bearerTokenPath)
+	assert.Equal(t, 2500*time.Millisecond, c.timeout)
+	assert.Equal(t, map[string]string{"X-Api-Key": "key"}, c.headers)
+	assert.True(t, c.collectBuckets)
+	assert.True(t, c.healthServiceCheck)
+
+	for name, expected := range map[string]string{
+		"requests":            "requests",
+		"go_goroutines":       "go_goroutines",
+		"go_gc_duration":      "",
+		"process_cpu_seconds": "cpu",
+		"requests_total":      "",
+	} {
+		matched, _, ok := c.match(name)
+		assert.Equal(t, expected != "", ok, name)
+		assert.Equal(t, expected, matched, name)
+	}
+}
+
+func TestParseLegacyConfig(t *testing.T) {
+	// instances scheduled by the prometheus autodiscovery in version 1
+	c, err := parseConfig([]byte(`{"prometheus_url":"http://10.0.0.1:8080/metrics","namespace":"","metrics":["*"],"ignore_metrics":["go_*"],"labels_mapper":{"pod":"pod_name"},"send_histograms_buckets":false,"health_service_check":false}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://10.0.0.1:8080/metrics", c.endpoint)
+	assert.Equal(t, map[string]string{"pod": "pod_name"}, c.renameLabels)
+	assert.False(t, c.collectBuckets)
+	assert.False(t, c.healthServiceCheck)
+
+	_, _, ok := c.match("http_requests")
+	assert.True(t, ok)
+	_, _, ok = c.match("go_goroutines")
+	assert.False(t, ok)
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`metrics: [".*"]`,
+		`openmetrics_endpoint: http://localhost`,
+		`{"openmetrics_endpoint": "http://localhost", "metrics": ["("]}`,
+		`{"openmetrics_endpoint": "http://localhost", "metrics": [".*"], "type_overrides": {"a": "histogram"}}`,
+		`{"openmetrics_endpoint": "http://localhost", "metrics": [".*"], "bearer_token_auth": "always"}`,
+		`{"openmetrics_endpoint": "http://localhost", "metrics": [".*"], "tls_cert": "/etc/cert.pem"}`,
+		`{"openmetrics_endpoint": "http://localhost", "metrics": [".*"], "exclude_metrics_by_labels": {"a": "b"}}`,
+	} {
+		_, err := parseConfig([]byte(conf))
+		assert.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,265 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/prometheus"
+)
+
+const (
+	nameLabel     = model.MetricNameLabel
+	bucketLabel   = model.BucketLabel
+	quantileLabel = model.QuantileLabel
+)
+
+// bucket is a bucket of a histogram, counting the observations up to its
+// upper bound
+type bucket struct {
+	upperBound float64
+	count      float64
+}
+
+// histogramSeries holds the buckets of one series of a histogram
+type histogramSeries struct {
+	labels  model.Metric
+	buckets []bucket
+}
+
+// match returns the name to submit a metric under and its type override,
+// or false if the metric isn't collected
+func (c *checkConfig) match(name string) (string, string, bool) {
+	for _, re := range c.excludeMetrics {
+		if re.MatchString(name) {
+			return "", "", false
+		}
+	}
+	if conf, found := c.metrics[name]; found {
+		return conf.name, conf.typeName, true
+	}
+	for _, re := range c.patterns {
+		if re.MatchString(name) {
+			return name, "", true
+		}
+	}
+	return "", "", false
+}
+
+// excluded returns whether a sample is excluded by its labels
+func (c *checkConfig) excluded(labels model.Metric) bool {
+	for label, values := range c.excludeByLabels {
+		value, found := labels[model.LabelName(label)]
+		if !found {
+			continue
+		}
+		if values == nil {
+			return true
+		}
+		for _, v := range values {
+			if string(value) == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// metricName returns the name of a metric with the namespace
+func (c *Check) metricName(name string) string {
+	if c.config.namespace == "" {
+		return name
+	}
+	return c.config.namespace + "." + name
+}
+
+// tags returns the tags of a sample from its labels, except the ones listed
+// in skip
+func (c *Check) tags(labels model.Metric, baseTags []string, skip ...model.LabelName) []string {
+	tags := append([]string{}, baseTags...)
+	for name, value := range labels {
+		if name == nameLabel || containsLabel(skip, name) {
+			continue
+		}
+		label := string(name)
+		if c.config.includeLabels != nil {
+			if _, found := c.config.includeLabels[label]; !found {
+				continue
+			}
+		}
+		if _, found := c.config.excludeLabels[label]; found {
+			continue
+		}
+		if renamed, found := c.config.renameLabels[label]; found {
+			label = renamed
+		}
+		tags = append(tags, label+":"+string(value))
+	}
+	return tags
+}
+
+// submitFamily submits the samples of a metric family according to its type
+func (c *Check) submitFamily(sender sender.Sender, family *prometheus.MetricFamily, baseTags []string) {
+	typeName := strings.ToLower(family.Type)
+	rawName := family.Name
+	if typeName == typeCounter {
+		rawName = strings.TrimSuffix(rawName, "_total")
+	}
+	rawName = strings.TrimPrefix(rawName, c.config.rawMetricPrefix)
+
+	name, typeOverride, ok := c.config.match(rawName)
+	if !ok {
+		return
+	}
+	if typeOverride == "" {
+		typeOverride = c.config.typeOverrides[rawName]
+	}
+	if typeOverride != "" {
+		if typeName == typeHistogram || typeName == typeSummary {
+			log.Debugf("Ignoring the type override of %s, a %s can't be overridden", rawName, typeName)
+		} else {
+			typeName = typeOverride
+		}
+	}
+	name = c.metricName(name)
+
+	switch typeName {
+	case typeHistogram:
+		c.submitHistogram(sender, name, family.Samples, baseTags)
+	case typeSummary:
+		c.submitSummary(sender, name, family.Samples, baseTags)
+	case typeCounter:
+		for _, sample := range c.validSamples(family.Samples) {
+			sender.MonotonicCount(name+".count", float64(sample.Value), "", c.tags(sample.Metric, baseTags))
+		}
+	default:
+		// gauges and untyped metrics
+		for _, sample := range c.validSamples(family.Samples) {
+			sender.Gauge(name, float64(sample.Value), "", c.tags(sample.Metric, baseTags))
+		}
+	}
+}
+
+// validSamples returns the samples that aren't excluded and have a finite
+// value
+func (c *Check) validSamples(samples model.Vector) model.Vector {
+	valid := make(model.Vector, 0, len(samples))
+	for _, sample := range samples {
+		if c.config.excluded(sample.Metric) {
+			continue
+		}
+		if value := float64(sample.Value); math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		valid = append(valid, sample)
+	}
+	return valid
+}
+
+func (c *Check) submitSummary(sender sender.Sender, name string, samples model.Vector, baseTags []string) {
+	for _, sample := range c.validSamples(samples) {
+		sampleName := string(sample.Metric[nameLabel])
+		tags := c.tags(sample.Metric, baseTags)
+		switch {
+		case strings.HasSuffix(sampleName, "_sum"):
+			sender.MonotonicCount(name+".sum", float64(sample.Value), "", tags)
+		case strings.HasSuffix(sampleName, "_count"):
+			sender.MonotonicCount(name+".count", float64(sample.Value), "", tags)
+		default:
+			sender.Gauge(name+".quantile", float64(sample.Value), "", tags)
+		}
+	}
+}
+
+func (c *Check) submitHistogram(sender sender.Sender, name string, samples model.Vector, baseTags []string) {
+	series := make(map[model.Fingerprint]*histogramSeries)
+	for _, sample := range c.validSamples(samples) {
+		sampleName := string(sample.Metric[nameLabel])
+		switch {
+		case strings.HasSuffix(sampleName, "_sum"):
+			if !c.config.bucketsAsDistributions || c.config.countersWithDistributions {
+				sender.MonotonicCount(name+".sum", float64(sample.Value), "", c.tags(sample.Metric, baseTags))
+			}
+		case strings.HasSuffix(sampleName, "_count"):
+			if !c.config.bucketsAsDistributions || c.config.countersWithDistributions {
+				sender.MonotonicCount(name+".count", float64(sample.Value), "", c.tags(sample.Metric, baseTags))
+			}
+		case strings.HasSuffix(sampleName, "_bucket"):
+			if !c.config.collectBuckets && !c.config.bucketsAsDistributions {
+				continue
+			}
+			upperBound, err := strconv.ParseFloat(string(sample.Metric[bucketLabel]), 64)
+			if err != nil {
+				log.Debugf("Invalid upper bound %q for metric %s", sample.Metric[bucketLabel], name)
+				continue
+			}
+			if !c.config.bucketsAsDistributions {
+				tags := append(c.tags(sample.Metric, baseTags, bucketLabel), "upper_bound:"+formatBound(upperBound))
+				sender.MonotonicCount(name+".bucket", float64(sample.Value), "", tags)
+				continue
+			}
+
+			labels := sample.Metric.Clone()
+			delete(labels, bucketLabel)
+			delete(labels, nameLabel)
+			fingerprint := labels.Fingerprint()
+			if series[fingerprint] == nil {
+				series[fingerprint] = &histogramSeries{labels: labels}
+			}
+			series[fingerprint].buckets = append(series[fingerprint].buckets, bucket{upperBound: upperBound, count: float64(sample.Value)})
+		}
+	}
+
+	for _, s := range series {
+		c.submitDistributionBuckets(sender, name, s, baseTags)
+	}
+}
+
+// submitDistributionBuckets submits the buckets of a histogram series as
+// distribution buckets. The buckets of Prometheus histograms are cumulative,
+// so the count of each bucket is the difference with the previous one.
+func (c *Check) submitDistributionBuckets(sender sender.Sender, name string, s *histogramSeries, baseTags []string) {
+	sort.Slice(s.buckets, func(i, j int) bool {
+		return s.buckets[i].upperBound < s.buckets[j].upperBound
+	})
+
+	tags := c.tags(s.labels, baseTags)
+	var previousCount float64
+	for i, b := range s.buckets {
+		lowerBound := math.Min(0, b.upperBound)
+		if i > 0 {
+			lowerBound = s.buckets[i-1].upperBound
+		}
+		count := b.count - previousCount
+		previousCount = b.count
+
+		bucketTags := append(append([]string{}, tags...), "lower_bound:"+formatBound(lowerBound), "upper_bound:"+formatBound(b.upperBound))
+		sender.HistogramBucket(name, int64(count), lowerBound, b.upperBound, true, "", bucketTags, false)
+	}
+}
+
+func formatBound(bound float64) string {
+	if math.IsInf(bound, 1) {
+		return "inf"
+	}
+	return strconv.FormatFloat(bound, 'f', -1, 64)
+}
+
+func containsLabel(labels []model.LabelName, name model.LabelName) bool {
+	for _, l := range labels {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
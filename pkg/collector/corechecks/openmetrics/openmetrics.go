@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package openmetrics implements a core check scraping OpenMetrics and
+// Prometheus endpoints.
+//
+// It follows the options and the metric naming of the V2 of the openmetrics
+// integration, and runs instead of it when `loader: core` is set in its
+// init_config or instance.
+package openmetrics
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "openmetrics"
+
+	healthServiceCheck = "openmetrics.health"
+)
+
+// Check scrapes an OpenMetrics or Prometheus endpoint
+type Check struct {
+	core.CheckBase
+	config  *checkConfig
+	scraper *scraper
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+	}
+}
+
+// Configure parses the check configuration and initializes the check
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, data, initConfig)
+
+	if err := c.CommonConfigure(senderManager, integrationConfigDigest, initConfig, data, source); err != nil {
+		return err
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		return err
+	}
+	scraper, err := newScraper(config)
+	if err != nil {
+		return err
+	}
+
+	c.config = config
+	c.scraper = scraper
+	return nil
+}
+
+// Run scrapes the endpoint and submits its metrics
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	var tags []string
+	if c.config.tagByEndpoint {
+		tags = append(tags, "endpoint:"+c.config.endpoint)
+	}
+
+	families, err := c.scraper.scrape(context.Background())
+	if c.config.healthServiceCheck {
+		status, message := servicecheck.ServiceCheckOK, ""
+		if err != nil {
+			status, message = servicecheck.ServiceCheckCritical, err.Error()
+		}
+		sender.ServiceCheck(c.metricName(healthServiceCheck), status, "", tags, message)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		c.submitFamily(sender, family, tags)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package openmetrics
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+const testPayload = `# HELP app_http_requests_total The number of requests.
+# TYPE app_http_requests_total counter
+app_http_requests_total{code="200",pod="web-1"} 1027
+app_http_requests_total{code="500",pod="web-1"} 3
+# HELP app_queue_depth The depth of the queue.
+# TYPE app_queue_depth gauge
+app_queue_depth{queue="jobs"} 12
+app_queue_depth{queue="nan"} NaN
+# HELP app_jobs The number of processed jobs.
+# TYPE app_jobs untyped
+app_jobs 42
+# HELP app_latency_seconds The request latency.
+# TYPE app_latency_seconds histogram
+app_latency_seconds_bucket{pod="web-1",le="0.1"} 5
+app_latency_seconds_bucket{pod="web-1",le="0.5"} 8
+app_latency_seconds_bucket{pod="web-1",le="+Inf"} 10
+app_latency_seconds_sum{pod="web-1"} 2.5
+app_latency_seconds_count{pod="web-1"} 10
+# HELP app_gc_seconds The GC duration.
+# TYPE app_gc_seconds summary
+app_gc_seconds{quantile="0.5"} 0.01
+app_gc_seconds{quantile="0.99"} 0.2
+app_gc_seconds_sum 1.5
+app_gc_seconds_count 30
+# HELP go_goroutines Number of goroutines.
+# TYPE go_goroutines gauge
+go_goroutines 12
+`
+
+func newTestServer(t *testing.T, token string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(testPayload))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runCheck(t *testing.T, instance string) (*mocksender.MockSender, error) {
+	c := newCheck()
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, []byte(instance), nil, "test"))
+	mockSender := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	return mockSender, c.Run()
+}
+
+func TestRun(t *testing.T) {
+	server := newTestServer(t, "")
+	endpoint := "endpoint:" + server.URL
+
+	mockSender, err := runCheck(t, `
+openmetrics_endpoint: `+server.URL+`
+namespace: app
+raw_metric_prefix: app_
+metrics:
+  - http_requests
+  - queue_depth: queue.depth
+  - latency_seconds
+  - gc_seconds
+  - jobs
+exclude_metrics_by_labels:
+  code: ["500"]
+rename_labels:
+  pod: pod_name
+type_overrides:
+  jobs: counter
+`)
+	require.NoError(t, err)
+
+	mockSender.AssertServiceCheck(t, "app.openmetrics.health", servicecheck.ServiceCheckOK, "", []string{endpoint}, "")
+	mockSender.AssertMetric(t, "MonotonicCount", "app.http_requests.count", 1027, "", []string{endpoint, "code:200", "pod_name:web-1"})
+	mockSender.AssertNotCalled(t, "MonotonicCount", "app.http_requests.count", float64(3), "", mock.Anything)
+	mockSender.AssertMetric(t, "Gauge", "app.queue.depth", 12, "", []string{endpoint, "queue:jobs"})
+	mockSender.AssertNotCalled(t, "Gauge", "app.queue.depth", mock.Anything, "", mocksender.MatchTagsContains([]string{"queue:nan"}))
+	mockSender.AssertMetric(t, "MonotonicCount", "app.jobs.count", 42, "", []string{endpoint})
+
+	mockSender.AssertMetric(t, "MonotonicCount", "app.latency_seconds.bucket", 5, "", []string{endpoint, "pod_name:web-1", "upper_bound:0.1"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.latency_seconds.bucket", 10, "", []string{endpoint, "pod_name:web-1", "upper_bound:inf"})
+	mockSender.AssertMetricNotTaggedWith(t, "MonotonicCount", "app.latency_seconds.bucket", []string{"le:0.1"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.latency_seconds.sum", 2.5, "", []string{endpoint, "pod_name:web-1"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.latency_seconds.count", 10, "", []string{endpoint, "pod_name:web-1"})
+
+	mockSender.AssertMetric(t, "Gauge", "app.gc_seconds.quantile", 0.2, "", []string{endpoint, "quantile:0.99"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.gc_seconds.sum", 1.5, "", []string{endpoint})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.gc_seconds.count", 30, "", []string{endpoint})
+
+	mockSender.AssertNotCalled(t, "Gauge", "app.go_goroutines", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertNotCalled(t, "Gauge", "go_goroutines", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunHistogramDistributions(t *testing.T) {
+	server := newTestServer(t, "")
+
+	mockSender, err := runCheck(t, `
+openmetrics_endpoint: `+server.URL+`
+metrics: [app_latency_seconds]
+histogram_buckets_as_distributions: true
+tag_by_endpoint: false
+`)
+	require.NoError(t, err)
+
+	for _, b := range []struct {
+		count      int64
+		lowerBound float64
+		upperBound float64
+		tags       []string
+	}{
+		{5, 0, 0.1, []string{"pod:web-1", "lower_bound:0", "upper_bound:0.1"}},
+		{3, 0.1, 0.5, []string{"pod:web-1", "lower_bound:0.1", "upper_bound:0.5"}},
+		{2, 0.5, math.Inf(1), []string{"pod:web-1", "lower_bound:0.5", "upper_bound:inf"}},
+	} {
+		mockSender.AssertCalled(t, "HistogramBucket", "app_latency_seconds", b.count, b.lowerBound, b.upperBound, true, "", mocksender.MatchTagsContains(b.tags), false)
+	}
+	mockSender.AssertNotCalled(t, "MonotonicCount", "app_latency_seconds.sum", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertNotCalled(t, "MonotonicCount", "app_latency_seconds.bucket", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunBearerToken(t *testing.T) {
+	server := newTestServer(t, "secret-token")
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("secret-token\n"), 0600))
+
+	mockSender, err := runCheck(t, `
+openmetrics_endpoint: `+server.URL+`
+metrics: [go_goroutines]
+bearer_token_auth: true
+bearer_token_path: `+tokenPath+`
+`)
+	require.NoError(t, err)
+	mockSender.AssertMetric(t, "Gauge", "go_goroutines", 12, "", nil)
+
+	// the token is only sent to HTTPS endpoints
+	mockSender, err = runCheck(t, `
+openmetrics_endpoint: `+server.URL+`
+metrics: [go_goroutines]
+bearer_token_auth: tls_only
+bearer_token_path: `+tokenPath+`
+`)
+	assert.Error(t, err)
+	mockSender.AssertServiceCheck(t, "openmetrics.health", servicecheck.ServiceCheckCritical, "", []string{"endpoint:" + server.URL}, err.Error())
+}
+
+func TestRunTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testPayload))
+	}))
+	defer server.Close()
+
+	_, err := runCheck(t, "openmetrics_endpoint: "+server.URL+"\nmetrics: [go_goroutines]\n")
+	assert.Error(t, err)
+
+	mockSender, err := runCheck(t, "openmetrics_endpoint: "+server.URL+"\nmetrics: [go_goroutines]\ntls_verify: false\n")
+	require.NoError(t, err)
+	mockSender.AssertMetric(t, "Gauge", "go_goroutines", 12, "", nil)
+}
+
+func TestRunProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte(testPayload))
+	}))
+	defer proxy.Close()
+	config.Mock(t).SetWithoutSource("proxy.http", proxy.URL)
+
+	// the endpoint can only be reached through the proxy
+	mockSender, err := runCheck(t, "openmetrics_endpoint: http://metrics.invalid/metrics\nmetrics: [go_goroutines]\n")
+	require.NoError(t, err)
+	mockSender.AssertMetric(t, "Gauge", "go_goroutines", 12, "", []string{"endpoint:http://metrics.invalid/metrics"})
+	assert.Equal(t, []string{"http://metrics.invalid/metrics"}, proxied)
+
+	_, err = runCheck(t, "openmetrics_endpoint: http://metrics.invalid/metrics\nmetrics: [go_goroutines]\nskip_proxy: true\n")
+	assert.Error(t, err)
+	assert.Len(t, proxied, 1)
+}
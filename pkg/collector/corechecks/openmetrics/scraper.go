@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/prometheus"
+)
+
+const acceptHeader = "text/plain;version=0.0.4;q=0.9,*/*;q=0.1"
+
+// scraper fetches and parses the metrics exposed by an endpoint
+type scraper struct {
+	config *checkConfig
+	client *http.Client
+}
+
+func newScraper(config *checkConfig) (*scraper, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: !config.tlsVerify,
+	}
+	if config.tlsCACert != "" {
+		caCert, err := os.ReadFile(config.tlsCACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in %s", config.tlsCACert)
+		}
+	}
+	if config.tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(config.tlsCert, config.tlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	if proxies := pkgconfig.Datadog.GetProxies(); proxies != nil && !config.skipProxy {
+		transport.Proxy = httputils.GetProxyTransportFunc(proxies, pkgconfig.Datadog)
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return &scraper{
+		config: config,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   config.timeout,
+		},
+	}, nil
+}
+
+// scrape returns the metric families exposed by the endpoint
+func (s *scraper) scrape(ctx context.Context) ([]*prometheus.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", acceptHeader)
+	for k, v := range s.config.headers {
+		req.Header.Set(k, v)
+	}
+	if s.config.bearerTokenAuth && (!s.config.bearerTokenTLSOnly || req.URL.Scheme == "https") {
+		// the token is read on every scrape since it can be rotated
+		token, err := os.ReadFile(s.config.bearerTokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, s.config.endpoint)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return prometheus.ParseMetricsWithFilter(body, s.config.rawLineFilters)
+}
@@ -21,6 +21,7 @@ import (
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	"github.com/DataDog/datadog-agent/pkg/sbom"
 	"github.com/DataDog/datadog-agent/pkg/sbom/collectors"
+	"github.com/DataDog/datadog-agent/pkg/sbom/vulns"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
 )
@@ -155,13 +156,31 @@ func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigD
 		c.instance.ChunkSize,
 		time.Duration(c.instance.NewSBOMMaxLatencySeconds)*time.Second,
 		c.cfg.GetBool("sbom.host.enabled"),
-		time.Duration(c.instance.HostHeartbeatValiditySeconds)*time.Second); err != nil {
+		time.Duration(c.instance.HostHeartbeatValiditySeconds)*time.Second,
+		c.loadVulnerabilityDB()); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// loadVulnerabilityDB loads the local vulnerability database when the
+// matching of vulnerabilities is enabled. It returns nil otherwise, or if the
+// database could not be loaded.
+func (c *Check) loadVulnerabilityDB() vulns.Database {
+	if !c.cfg.GetBool("sbom.vulnerabilities.enabled") {
+		return nil
+	}
+	path := vulns.DBPathFromConfig(c.cfg)
+	db, err := vulns.Open(path)
+	if err != nil {
+		log.Errorf("Unable to load the vulnerability database %s, vulnerabilities won't be reported: %v", path, err)
+		return nil
+	}
+	log.Infof("Loaded vulnerability database %s", path)
+	return db
+}
+
 // Run starts the sbom check
 func (c *Check) Run() error {
 	log.Infof("Starting long-running check %q", c.ID())
@@ -9,10 +9,11 @@ package sbom
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"io/fs"
+	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -21,15 +22,18 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
 	"github.com/DataDog/datadog-agent/comp/forwarder/eventplatform"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
 
 	ddConfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/sbom"
 	"github.com/DataDog/datadog-agent/pkg/sbom/collectors/host"
 	sbomscanner "github.com/DataDog/datadog-agent/pkg/sbom/scanner"
+	"github.com/DataDog/datadog-agent/pkg/sbom/vulns"
 	queue "github.com/DataDog/datadog-agent/pkg/util/aggregatingqueue"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
+	cyclonedxgo "github.com/CycloneDX/cyclonedx-go"
 	model "github.com/DataDog/agent-payload/v5/sbom"
 
 	"google.golang.org/protobuf/proto"
@@ -41,6 +45,13 @@ var /* const */ (
 	sourceAgent = "agent"
 )
 
+const (
+	// vulnerabilitiesEventType is the event type of the vulnerabilities events
+	vulnerabilitiesEventType = "sbom_vulnerabilities"
+	// maxEventFindings is the maximum number of findings listed in a vulnerabilities event
+	maxEventFindings = 50
+)
+
 type processor struct {
 	queue                 chan *model.SBOMEntity
 	workloadmetaStore     workloadmeta.Component
@@ -52,9 +63,12 @@ type processor struct {
 	hostCache             string
 	hostLastFullSBOM      time.Time
 	hostHeartbeatValidity time.Duration
+	sender                sender.Sender
+	vulnDB                vulns.Database
+	vulnDigests           map[string]string // Map where keys are entity IDs and values are the digest of the last reported findings
 }
 
-func newProcessor(workloadmetaStore workloadmeta.Component, sender sender.Sender, maxNbItem int, maxRetentionTime time.Duration, hostSBOM bool, hostHeartbeatValidity time.Duration) (*processor, error) {
+func newProcessor(workloadmetaStore workloadmeta.Component, sender sender.Sender, maxNbItem int, maxRetentionTime time.Duration, hostSBOM bool, hostHeartbeatValidity time.Duration, vulnDB vulns.Database) (*processor, error) {
 	sbomScanner := sbomscanner.GetGlobalScanner()
 	if sbomScanner == nil {
 		return nil, errors.New("failed to get global SBOM scanner")
@@ -87,6 +101,9 @@ func newProcessor(workloadmetaStore workloadmeta.Component, sender sender.Sender
 		hostSBOM:              hostSBOM,
 		hostname:              hname,
 		hostHeartbeatValidity: hostHeartbeatValidity,
+		sender:                sender,
+		vulnDB:                vulnDB,
+		vulnDigests:           make(map[string]string),
 	}, nil
 }
 
@@ -130,6 +147,7 @@ func (p *processor) unregisterImage(img *workloadmeta.ContainerImageMetadata) {
 			delete(p.imageRepoDigests, repoDigest)
 		}
 	}
+	delete(p.vulnDigests, img.ID)
 }
 
 func (p *processor) registerContainer(ctr *workloadmeta.Container) {
@@ -210,6 +228,7 @@ func (p *processor) processHostScanResult(result sbom.ScanResult) {
 				sbom.Sbom = &model.SBOMEntity_Cyclonedx{
 					Cyclonedx: convertBOM(report),
 				}
+				p.reportVulnerabilities(p.hostname, p.hostname, nil, report)
 			}
 
 			sbom.Hash = result.Report.ID()
@@ -221,28 +240,6 @@ func (p *processor) processHostScanResult(result sbom.ScanResult) {
 	p.queue <- sbom
 }
 
-type relFS struct {
-	root string
-	fs   fs.FS
-}
-
-func newFS(root string) fs.FS {
-	fs := os.DirFS(root)
-	return &relFS{root: "/", fs: fs}
-}
-
-func (f *relFS) Open(name string) (fs.File, error) {
-	if filepath.IsAbs(name) {
-		var err error
-		name, err = filepath.Rel(f.root, name)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return f.fs.Open(name)
-}
-
 func (p *processor) triggerHostScan() {
 	if !p.hostSBOM {
 		return
@@ -253,7 +250,7 @@ func (p *processor) triggerHostScan() {
 	if hostRoot := os.Getenv("HOST_ROOT"); ddConfig.IsContainerized() && hostRoot != "" {
 		scanPath = hostRoot
 	}
-	scanRequest := host.NewScanRequest(scanPath, newFS("/"))
+	scanRequest := host.NewScanRequest(scanPath, host.NewFS("/"))
 
 	if err := p.sbomScanner.Scan(scanRequest); err != nil {
 		log.Errorf("Failed to trigger SBOM generation for host: %s", err)
@@ -276,6 +273,10 @@ func (p *processor) processImageSBOM(img *workloadmeta.ContainerImageMetadata) {
 		log.Errorf("Could not retrieve tags for container image %s: %v", img.ID, err)
 	}
 
+	if img.SBOM.Status == workloadmeta.Success {
+		p.reportVulnerabilities(img.ID, "", ddTags, img.SBOM.CycloneDXBOM)
+	}
+
 	// In containerd some images are created without a repo digest, and it's
 	// also possible to remove repo digests manually.
 	// This means that the set of repos that we need to handle is the union of
@@ -371,6 +372,75 @@ func (p *processor) processImageSBOM(img *workloadmeta.ContainerImageMetadata) {
 	}
 }
 
+// reportVulnerabilities matches the given SBOM against the local
+// vulnerability database, if any, and sends an event listing the findings.
+// Findings are only reported when they changed since the last report for the
+// same entity.
+func (p *processor) reportVulnerabilities(entityID string, host string, tags []string, bom *cyclonedxgo.BOM) {
+	if p.vulnDB == nil || bom == nil {
+		return
+	}
+
+	findings, err := vulns.MatchBOM(p.vulnDB, bom)
+	if err != nil {
+		// errors only concern some packages, report the other findings
+		log.Debugf("Error while matching vulnerabilities of %s: %v", entityID, err)
+	}
+
+	h := sha256.New()
+	for _, finding := range findings {
+		fmt.Fprintf(h, "%s|%s|%s\n", finding.VulnerabilityID, finding.PURL, finding.FixedVersion)
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+	if previous, found := p.vulnDigests[entityID]; found && previous == digest {
+		return
+	}
+	p.vulnDigests[entityID] = digest
+
+	if len(findings) == 0 {
+		return
+	}
+	p.sender.Event(vulnerabilitiesEvent(entityID, host, tags, findings))
+}
+
+func vulnerabilitiesEvent(entityID string, host string, tags []string, findings []vulns.Finding) event.Event {
+	alertType := event.EventAlertTypeWarning
+	if findings[0].Severity == vulns.SeverityCritical || findings[0].Severity == vulns.SeverityHigh {
+		alertType = event.EventAlertTypeError
+	}
+
+	var text strings.Builder
+	text.WriteString("%%% \n")
+	text.WriteString("| Vulnerability | Severity | Package | Version | Fixed version |\n")
+	text.WriteString("|---|---|---|---|---|\n")
+	for i, finding := range findings {
+		if i == maxEventFindings {
+			fmt.Fprintf(&text, "\n%d more vulnerabilities not listed\n", len(findings)-maxEventFindings)
+			break
+		}
+		fmt.Fprintf(&text, "| %s | %s | %s | %s | %s |\n", finding.VulnerabilityID, finding.Severity, finding.PackageName, finding.PackageVersion, finding.FixedVersion)
+	}
+	text.WriteString("\n %%%")
+
+	return event.Event{
+		Title:          fmt.Sprintf("%d vulnerabilities found in %s", len(findings), entityID),
+		Text:           text.String(),
+		Ts:             time.Now().Unix(),
+		Priority:       event.EventPriorityNormal,
+		Host:           host,
+		Tags:           tags,
+		AlertType:      alertType,
+		AggregationKey: entityID,
+		SourceTypeName: CheckName,
+		EventType:      vulnerabilitiesEventType,
+	}
+}
+
 func (p *processor) stop() {
 	close(p.queue)
+	if p.vulnDB != nil {
+		if err := p.vulnDB.Close(); err != nil {
+			log.Warnf("Unable to close vulnerability database: %v", err)
+		}
+	}
 }
@@ -10,6 +10,7 @@ package sbom
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -31,7 +32,9 @@ import (
 	"github.com/DataDog/datadog-agent/comp/forwarder/eventplatform"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
 	sbomscanner "github.com/DataDog/datadog-agent/pkg/sbom/scanner"
+	"github.com/DataDog/datadog-agent/pkg/sbom/vulns"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
@@ -624,7 +627,7 @@ func TestProcessEvents(t *testing.T) {
 
 			// Define a max size of 1 for the queue. With a size > 1, it's difficult to
 			// control the number of events sent on each call.
-			p, err := newProcessor(workloadmetaStore, sender, 1, 50*time.Millisecond, false, time.Second)
+			p, err := newProcessor(workloadmetaStore, sender, 1, 50*time.Millisecond, false, time.Second, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -665,3 +668,54 @@ func TestProcessEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestReportVulnerabilities(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "osv.json")
+	osvEntries := `[{
+		"id": "GHSA-xvch-5gv4-984h",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "minimist"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "1.0.0"}, {"fixed": "1.2.6"}]}]
+		}],
+		"database_specific": {"severity": "CRITICAL"}
+	}]`
+	assert.NoError(t, os.WriteFile(dbPath, []byte(osvEntries), 0600))
+	vulnDB, err := vulns.Open(dbPath)
+	assert.NoError(t, err)
+
+	sender := mocksender.NewMockSender("")
+	var events []event.Event
+	sender.On("Event", mock.Anything).Return().Run(func(args mock.Arguments) {
+		events = append(events, args.Get(0).(event.Event))
+	})
+
+	p := &processor{
+		sender:      sender,
+		vulnDB:      vulnDB,
+		vulnDigests: make(map[string]string),
+	}
+
+	bom := func(version string) *cyclonedx.BOM {
+		return &cyclonedx.BOM{
+			Components: &[]cyclonedx.Component{
+				{Name: "minimist", Version: version, PackageURL: "pkg:npm/minimist@" + version},
+			},
+		}
+	}
+
+	p.reportVulnerabilities("sha256:9634b84c45c6ad220c3d0d2305aaa5523e47d6d43649c9bbeda46ff010b4aeb8", "", []string{"image_name:app"}, bom("1.2.5"))
+	// unchanged findings are not reported again
+	p.reportVulnerabilities("sha256:9634b84c45c6ad220c3d0d2305aaa5523e47d6d43649c9bbeda46ff010b4aeb8", "", []string{"image_name:app"}, bom("1.2.5"))
+	// no finding, nothing to report
+	p.reportVulnerabilities("sha256:9634b84c45c6ad220c3d0d2305aaa5523e47d6d43649c9bbeda46ff010b4aeb8", "", []string{"image_name:app"}, bom("1.2.6"))
+	p.reportVulnerabilities("my-host", "my-host", nil, bom("1.0.0"))
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "1 vulnerabilities found in sha256:9634b84c45c6ad220c3d0d2305aaa5523e47d6d43649c9bbeda46ff010b4aeb8", events[0].Title)
+		assert.Equal(t, event.EventAlertTypeError, events[0].AlertType)
+		assert.Equal(t, []string{"image_name:app"}, events[0].Tags)
+		assert.Equal(t, vulnerabilitiesEventType, events[0].EventType)
+		assert.Contains(t, events[0].Text, "| GHSA-xvch-5gv4-984h | CRITICAL | minimist | 1.2.5 | 1.2.6 |")
+		assert.Equal(t, "my-host", events[1].Host)
+	}
+}
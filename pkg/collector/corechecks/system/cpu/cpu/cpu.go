@@ -48,6 +48,11 @@ func (c *Check) Run() error {
 		// read the context switches
 	}
 
+	err = c.collectPressure(sender)
+	if err != nil {
+		log.Debugf("cpu.Check could not read the CPU pressure: %s", err.Error())
+	}
+
 	cpuTimes, err := times(false)
 	if err != nil {
 		log.Errorf("cpu.Check: could not retrieve cpu stats: %s", err)
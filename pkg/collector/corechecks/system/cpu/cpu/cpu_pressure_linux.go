@@ -0,0 +1,22 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+//go:build linux
+
+package cpu
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func (c *Check) collectPressure(sender sender.Sender) error {
+	procfsPath := "/proc"
+	if config.Datadog.IsSet("procfs_path") {
+		procfsPath = config.Datadog.GetString("procfs_path")
+	}
+	// The full line of the CPU pressure is undefined at the system level
+	return pressure.Submit(sender, procfsPath, "cpu", false)
+}
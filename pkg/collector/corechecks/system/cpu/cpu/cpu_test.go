@@ -12,10 +12,10 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 
 	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/stretchr/testify/mock"
 )
 
 var (
@@ -76,7 +76,15 @@ func CPUInfo() ([]cpu.InfoStat, error) {
 	}, nil
 }
 
+func expectPressure(m *mocksender.MockSender) {
+	m.On(metrics.GaugeType.String(), "system.pressure.cpu.some.avg10", 1.52, "", []string(nil)).Return().Times(1)
+	m.On(metrics.GaugeType.String(), "system.pressure.cpu.some.avg60", 0.87, "", []string(nil)).Return().Times(1)
+	m.On(metrics.GaugeType.String(), "system.pressure.cpu.some.avg300", 0.31, "", []string(nil)).Return().Times(1)
+	m.On(metrics.MonotonicCountType.String(), "system.pressure.cpu.some.total", 123456789.0, "", []string(nil)).Return().Times(1)
+}
+
 func TestCPUCheckLinux(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", "../../testfiles/proc")
 	times = CPUTimes
 	cpuInfo = CPUInfo
 	cpuCheck := new(Check)
@@ -85,7 +93,8 @@ func TestCPUCheckLinux(t *testing.T) {
 
 	m.On(metrics.GaugeType.String(), "system.cpu.num_cores", 1.0, "", []string(nil)).Return().Times(1)
 	if runtime.GOOS == "linux" {
-		m.On(metrics.MonotonicCountType.String(), "system.cpu.context_switches", mock.AnythingOfType("float64"), "", []string(nil)).Return().Times(1)
+		m.On(metrics.MonotonicCountType.String(), "system.cpu.context_switches", 1990473.0, "", []string(nil)).Return().Times(1)
+		expectPressure(m)
 	}
 
 	m.On("Commit").Return().Times(1)
@@ -94,9 +103,11 @@ func TestCPUCheckLinux(t *testing.T) {
 	cpuCheck.Run()
 
 	m.AssertExpectations(t)
-	m.AssertNumberOfCalls(t, metrics.GaugeType.String(), 1)
 	if runtime.GOOS == "linux" {
-		m.AssertNumberOfCalls(t, metrics.MonotonicCountType.String(), 1)
+		m.AssertNumberOfCalls(t, metrics.GaugeType.String(), 4)
+		m.AssertNumberOfCalls(t, metrics.MonotonicCountType.String(), 2)
+	} else {
+		m.AssertNumberOfCalls(t, metrics.GaugeType.String(), 1)
 	}
 	m.AssertNumberOfCalls(t, "Commit", 1)
 
@@ -110,15 +121,18 @@ func TestCPUCheckLinux(t *testing.T) {
 	m.On(metrics.GaugeType.String(), "system.cpu.guest", 0.0, "", []string(nil)).Return().Times(1)
 	m.On(metrics.GaugeType.String(), "system.cpu.num_cores", 1.0, "", []string(nil)).Return().Times(1)
 	if runtime.GOOS == "linux" {
-		m.On(metrics.MonotonicCountType.String(), "system.cpu.context_switches", mock.AnythingOfType("float64"), "", []string(nil)).Return().Times(1)
+		m.On(metrics.MonotonicCountType.String(), "system.cpu.context_switches", 1990473.0, "", []string(nil)).Return().Times(1)
+		expectPressure(m)
 	}
 	m.On("Commit").Return().Times(1)
 	cpuCheck.Run()
 
 	m.AssertExpectations(t)
-	m.AssertNumberOfCalls(t, metrics.GaugeType.String(), 9)
 	if runtime.GOOS == "linux" {
-		m.AssertNumberOfCalls(t, metrics.MonotonicCountType.String(), 2)
+		m.AssertNumberOfCalls(t, metrics.GaugeType.String(), 15)
+		m.AssertNumberOfCalls(t, metrics.MonotonicCountType.String(), 4)
+	} else {
+		m.AssertNumberOfCalls(t, metrics.GaugeType.String(), 9)
 	}
 	m.AssertNumberOfCalls(t, "Commit", 2)
 }
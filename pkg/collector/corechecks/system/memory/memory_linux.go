@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package memory
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// vmstatMetrics maps the /proc/vmstat counters to the metrics reporting them
+var vmstatMetrics = map[string]string{
+	"pgfault":    "system.mem.page_faults",
+	"pgmajfault": "system.mem.major_page_faults",
+	"pswpin":     "system.swap.pages_in",
+	"pswpout":    "system.swap.pages_out",
+	"oom_kill":   "system.mem.oom_kills",
+}
+
+// collectKernelStats submits the memory and IO pressure stall information,
+// and the /proc/vmstat counters
+func (c *Check) collectKernelStats(sender sender.Sender) {
+	procfsPath := "/proc"
+	if config.Datadog.IsSet("procfs_path") {
+		procfsPath = config.Datadog.GetString("procfs_path")
+	}
+
+	for _, resource := range []string{"memory", "io"} {
+		if err := pressure.Submit(sender, procfsPath, resource, true); err != nil {
+			log.Debugf("memory.Check could not read the %s pressure: %s", resource, err)
+		}
+	}
+
+	counters, err := readVMStat(filepath.Join(procfsPath, "vmstat"))
+	if err != nil {
+		log.Debugf("memory.Check could not read vmstat: %s", err)
+		return
+	}
+	for counter, metric := range vmstatMetrics {
+		// oom_kill was only added in kernel 4.13
+		if value, found := counters[counter]; found {
+			sender.MonotonicCount(metric, float64(value), "", nil)
+		}
+	}
+}
+
+// readVMStat returns the counters of /proc/vmstat reported by the check
+func readVMStat(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	counters := make(map[string]uint64, len(vmstatMetrics))
+	scanner := bufio.NewScanner(file)
+	for i := 0; scanner.Scan(); i++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if _, found := vmstatMetrics[fields[0]]; !found {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s in '%s' at line %d", err, path, i)
+		}
+		counters[fields[0]] = value
+	}
+	return counters, scanner.Err()
+}
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestMemoryCheckKernelStats(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", "../testfiles/proc")
+	virtualMemory = VirtualMemory
+	swapMemory = SwapMemory
+	runtimeOS = "linux"
+	memCheck := new(Check)
+
+	mock := mocksender.NewMockSender(memCheck.ID())
+	mock.SetupAcceptAll()
+	memCheck.Configure(mock.GetSenderManager(), 0, nil, nil, "")
+	require.NoError(t, memCheck.Run())
+
+	mock.AssertMetric(t, "Gauge", "system.pressure.memory.some.avg10", 4.20, "", nil)
+	mock.AssertMetric(t, "Gauge", "system.pressure.memory.some.avg60", 2.10, "", nil)
+	mock.AssertMetric(t, "Gauge", "system.pressure.memory.some.avg300", 0.90, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.pressure.memory.some.total", 98765432, "", nil)
+	mock.AssertMetric(t, "Gauge", "system.pressure.memory.full.avg10", 1.10, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.pressure.memory.full.total", 45678901, "", nil)
+	mock.AssertMetric(t, "Gauge", "system.pressure.io.some.avg10", 12.50, "", nil)
+	mock.AssertMetric(t, "Gauge", "system.pressure.io.full.avg300", 1.50, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.pressure.io.full.total", 333333333, "", nil)
+
+	mock.AssertMetric(t, "MonotonicCount", "system.mem.page_faults", 87654321, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.mem.major_page_faults", 4321, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.swap.pages_in", 1024, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.swap.pages_out", 2048, "", nil)
+	mock.AssertMetric(t, "MonotonicCount", "system.mem.oom_kills", 3, "", nil)
+}
+
+func TestMemoryCheckKernelStatsMissing(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", t.TempDir())
+	virtualMemory = VirtualMemory
+	swapMemory = SwapMemory
+	runtimeOS = "linux"
+	memCheck := new(Check)
+
+	mock := mocksender.NewMockSender(memCheck.ID())
+	mock.SetupAcceptAll()
+	memCheck.Configure(mock.GetSenderManager(), 0, nil, nil, "")
+	require.NoError(t, memCheck.Run())
+
+	mock.AssertNumberOfCalls(t, "MonotonicCount", 0)
+	mock.AssertMetric(t, "Gauge", "system.mem.total", 12345667890.0/mbSize, "", nil)
+}
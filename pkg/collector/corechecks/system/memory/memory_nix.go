@@ -77,6 +77,10 @@ func (c *Check) Run() error {
 		return fmt.Errorf("failed to gather any memory information")
 	}
 
+	if runtimeOS == "linux" {
+		c.collectKernelStats(sender)
+	}
+
 	sender.Commit()
 	return nil
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 func VirtualMemory() (*mem.VirtualMemoryStat, error) {
@@ -57,6 +58,7 @@ func SwapMemory() (*mem.SwapMemoryStat, error) {
 }
 
 func TestMemoryCheckLinux(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", t.TempDir())
 	virtualMemory = VirtualMemory
 	swapMemory = SwapMemory
 	memCheck := new(Check)
@@ -164,6 +166,7 @@ func TestMemoryCheckDarwin(t *testing.T) {
 }
 
 func TestMemoryError(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", t.TempDir())
 	virtualMemory = func() (*mem.VirtualMemoryStat, error) { return nil, fmt.Errorf("some error") }
 	swapMemory = func() (*mem.SwapMemoryStat, error) { return nil, fmt.Errorf("some error") }
 	memCheck := new(Check)
@@ -182,6 +185,7 @@ func TestMemoryError(t *testing.T) {
 }
 
 func TestSwapMemoryError(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", t.TempDir())
 	virtualMemory = VirtualMemory
 	swapMemory = func() (*mem.SwapMemoryStat, error) { return nil, fmt.Errorf("some error") }
 	memCheck := new(Check)
@@ -217,6 +221,7 @@ func TestSwapMemoryError(t *testing.T) {
 }
 
 func TestVirtualMemoryError(t *testing.T) {
+	config.Mock(t).SetWithoutSource("procfs_path", t.TempDir())
 	virtualMemory = func() (*mem.VirtualMemoryStat, error) { return nil, fmt.Errorf("some error") }
 	swapMemory = SwapMemory
 	memCheck := new(Check)
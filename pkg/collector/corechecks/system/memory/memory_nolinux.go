@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !linux && !windows
+
+package memory
+
+import "github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+
+//nolint:revive // TODO(PLINT) Fix revive linter
+func (c *Check) collectKernelStats(sender sender.Sender) {
+	// On non-linux systems, do nothing
+}
@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package pressure reads the host-wide pressure stall information (PSI)
+// exposed by the kernel under /proc/pressure.
+package pressure
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+)
+
+// Stats holds one line of a PSI file. The averages are percentages of the
+// time during which tasks were stalled, and the total is the cumulated stall
+// time in microseconds.
+type Stats struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// Read parses /proc/pressure/<resource>. The full line is nil when the
+// kernel doesn't report it.
+func Read(procfsPath, resource string) (some *Stats, full *Stats, err error) {
+	path := filepath.Join(procfsPath, "pressure", resource)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		stats, err := parseLine(fields[1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s in '%s'", err, path)
+		}
+		switch fields[0] {
+		case "some":
+			some = stats
+		case "full":
+			full = stats
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if some == nil {
+		return nil, nil, fmt.Errorf("could not find the some line in '%s'", path)
+	}
+	return some, full, nil
+}
+
+// parseLine parses the "avg10=0.00 avg60=0.00 avg300=0.00 total=0" fields of
+// a PSI line
+func parseLine(fields []string) (*Stats, error) {
+	stats := &Stats{}
+	for _, field := range fields {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return nil, fmt.Errorf("unexpected field %q", field)
+		}
+		var err error
+		switch key {
+		case "avg10":
+			stats.Avg10, err = strconv.ParseFloat(value, 64)
+		case "avg60":
+			stats.Avg60, err = strconv.ParseFloat(value, 64)
+		case "avg300":
+			stats.Avg300, err = strconv.ParseFloat(value, 64)
+		case "total":
+			stats.Total, err = strconv.ParseUint(value, 10, 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// Submit reads the PSI of a resource and submits it as system.pressure.<resource>.*
+// metrics. The full line is skipped when withFull is false, which is the
+// case for the CPU since it is undefined at the system level.
+func Submit(sender sender.Sender, procfsPath, resource string, withFull bool) error {
+	some, full, err := Read(procfsPath, resource)
+	if err != nil {
+		return err
+	}
+	submitStats(sender, "system.pressure."+resource+".some", some)
+	if withFull && full != nil {
+		submitStats(sender, "system.pressure."+resource+".full", full)
+	}
+	return nil
+}
+
+func submitStats(sender sender.Sender, prefix string, stats *Stats) {
+	sender.Gauge(prefix+".avg10", stats.Avg10, "", nil)
+	sender.Gauge(prefix+".avg60", stats.Avg60, "", nil)
+	sender.Gauge(prefix+".avg300", stats.Avg300, "", nil)
+	sender.MonotonicCount(prefix+".total", float64(stats.Total), "", nil)
+}
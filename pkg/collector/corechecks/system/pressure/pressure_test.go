@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package pressure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	some, full, err := Read("../testfiles/proc", "io")
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{Avg10: 12.50, Avg60: 8.25, Avg300: 3.75, Total: 555555555}, some)
+	assert.Equal(t, &Stats{Avg10: 6.00, Avg60: 4.00, Avg300: 1.50, Total: 333333333}, full)
+}
+
+func TestReadWithoutFull(t *testing.T) {
+	// Kernels before 5.13 don't report the full line of the CPU pressure
+	procfsPath := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(procfsPath, "pressure"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(procfsPath, "pressure", "cpu"), []byte("some avg10=0.50 avg60=0.25 avg300=0.10 total=42\n"), 0644))
+
+	some, full, err := Read(procfsPath, "cpu")
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{Avg10: 0.50, Avg60: 0.25, Avg300: 0.10, Total: 42}, some)
+	assert.Nil(t, full)
+}
+
+func TestReadErrors(t *testing.T) {
+	_, _, err := Read(t.TempDir(), "cpu")
+	assert.Error(t, err)
+
+	procfsPath := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(procfsPath, "pressure"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(procfsPath, "pressure", "memory"), []byte("some avg10=abc avg60=0.25 avg300=0.10 total=42\n"), 0644))
+	_, _, err = Read(procfsPath, "memory")
+	assert.Error(t, err)
+}
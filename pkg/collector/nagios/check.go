@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package nagios
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/secrets/utils"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	defaultTimeout = 10 // seconds, like NRPE
+	metricPrefix   = "nagios"
+)
+
+// exitCodeStatus maps the exit codes of Nagios plugins to service check
+// statuses. Any other exit code is reported as unknown.
+var exitCodeStatus = map[int]servicecheck.ServiceCheckStatus{
+	0: servicecheck.ServiceCheckOK,
+	1: servicecheck.ServiceCheckWarning,
+	2: servicecheck.ServiceCheckCritical,
+	3: servicecheck.ServiceCheckUnknown,
+}
+
+type checkConfig struct {
+	Command            string   `yaml:"command"`
+	Args               []string `yaml:"args"`
+	Timeout            int      `yaml:"timeout"`
+	ServiceCheckName   string   `yaml:"service_check_name"`
+	MetricPrefix       string   `yaml:"metric_prefix"`
+	AllowGroupExecPerm bool     `yaml:"allow_group_exec_perm"`
+}
+
+// Check runs a Nagios plugin
+type Check struct {
+	core.CheckBase
+	config checkConfig
+}
+
+func newCheck(name string) *Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(name),
+	}
+}
+
+func (c *checkConfig) parse(data []byte) error {
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return err
+	}
+
+	if c.Command == "" {
+		return errors.New("the command of the plugin must be set")
+	}
+	if !filepath.IsAbs(c.Command) {
+		return fmt.Errorf("the command of the plugin must be an absolute path: %s", c.Command)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	pluginName := sanitizeMetricName(strings.TrimSuffix(filepath.Base(c.Command), filepath.Ext(c.Command)))
+	if c.ServiceCheckName == "" {
+		c.ServiceCheckName = metricPrefix + "." + pluginName
+	}
+	if c.MetricPrefix == "" {
+		c.MetricPrefix = metricPrefix + "." + pluginName
+	}
+	return nil
+}
+
+// Configure parses the check configuration
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, data, initConfig)
+
+	if err := c.CommonConfigure(senderManager, integrationConfigDigest, initConfig, data, source); err != nil {
+		return err
+	}
+
+	return c.config.parse(data)
+}
+
+// Run runs the plugin and reports its status and performance data
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	status, output, runErr := c.runPlugin()
+	message, perfData := parseOutput(output)
+	if runErr != nil {
+		message = runErr.Error()
+	}
+	sender.ServiceCheck(c.config.ServiceCheckName, status, "", nil, message)
+
+	data, err := parsePerfData(perfData)
+	if err != nil {
+		log.Debugf("%s: %s", c, err)
+		_ = c.Warnf("Some performance data of %s couldn't be parsed: %s", c.config.Command, err)
+	}
+	for _, datum := range data {
+		c.submit(sender, datum)
+	}
+
+	return runErr
+}
+
+// runPlugin runs the plugin and returns the status matching its exit code
+// with its output. A plugin that times out is critical, one that can't be run
+// is unknown and the error is returned.
+func (c *Check) runPlugin() (servicecheck.ServiceCheckStatus, string, error) {
+	if err := utils.CheckRights(c.config.Command, c.config.AllowGroupExecPerm); err != nil {
+		return servicecheck.ServiceCheckUnknown, "", err
+	}
+
+	timeout := time.Duration(c.config.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.config.Command, c.config.Args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// don't wait for the children of the plugin still holding its output
+	// once it's killed
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if stderr.Len() > 0 {
+		log.Debugf("%s: plugin stderr: %s", c, stderr.String())
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return servicecheck.ServiceCheckCritical, fmt.Sprintf("CRITICAL - plugin timed out after %s", timeout), nil
+	}
+
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return servicecheck.ServiceCheckUnknown, "", fmt.Errorf("unable to run plugin: %w", err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	status, found := exitCodeStatus[exitCode]
+	if !found {
+		status = servicecheck.ServiceCheckUnknown
+	}
+	return status, stdout.String(), nil
+}
+
+// submit sends a performance data item as a metric, along with its
+// thresholds
+func (c *Check) submit(sender sender.Sender, datum perfDatum) {
+	label := sanitizeMetricName(datum.label)
+	if label == "" {
+		return
+	}
+	name := c.config.MetricPrefix + "." + label
+
+	if datum.counter {
+		sender.MonotonicCount(name, datum.value, "", nil)
+	} else {
+		sender.Gauge(name, datum.value, "", nil)
+	}
+	for threshold, value := range datum.thresholds {
+		sender.Gauge(name+"."+threshold, value, "", nil)
+	}
+}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test && !windows
+
+package nagios
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func writePlugin(t *testing.T, script string, perm os.FileMode) string {
+	path := filepath.Join(t.TempDir(), "check_test-plugin.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), perm))
+	return path
+}
+
+func configureCheck(t *testing.T, instance string) (*Check, *mocksender.MockSender) {
+	c := newCheck("nagios_test")
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, []byte(instance), nil, "test"))
+	mockSender := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	return c, mockSender
+}
+
+func TestCheckExitCodes(t *testing.T) {
+	for exitCode, status := range map[int]servicecheck.ServiceCheckStatus{
+		0: servicecheck.ServiceCheckOK,
+		1: servicecheck.ServiceCheckWarning,
+		2: servicecheck.ServiceCheckCritical,
+		3: servicecheck.ServiceCheckUnknown,
+		4: servicecheck.ServiceCheckUnknown,
+	} {
+		plugin := writePlugin(t, "echo \"STATUS $1 | time=5ms;10;20\"\nexit $1\n", 0700)
+		c, mockSender := configureCheck(t, "command: "+plugin+"\nargs: [\""+strconv.Itoa(exitCode)+"\"]\n")
+
+		require.NoError(t, c.Run())
+
+		mockSender.AssertServiceCheck(t, "nagios.check_test_plugin", status, "", nil, "STATUS "+strconv.Itoa(exitCode))
+		mockSender.AssertMetric(t, "Gauge", "nagios.check_test_plugin.time", 0.005, "", nil)
+		mockSender.AssertMetric(t, "Gauge", "nagios.check_test_plugin.time.warning", 0.01, "", nil)
+		mockSender.AssertMetric(t, "Gauge", "nagios.check_test_plugin.time.critical", 0.02, "", nil)
+		mockSender.AssertNumberOfCalls(t, "Commit", 1)
+	}
+}
+
+func TestCheckCustomNames(t *testing.T) {
+	plugin := writePlugin(t, "echo 'OK | users=3 in=10c'\n", 0700)
+	c, mockSender := configureCheck(t, "command: "+plugin+"\nservice_check_name: host.users\nmetric_prefix: host\n")
+
+	require.NoError(t, c.Run())
+
+	mockSender.AssertServiceCheck(t, "host.users", servicecheck.ServiceCheckOK, "", nil, "OK")
+	mockSender.AssertMetric(t, "Gauge", "host.users", 3, "", nil)
+	mockSender.AssertMetric(t, "MonotonicCount", "host.in", 10, "", nil)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	plugin := writePlugin(t, "sleep 10\n", 0700)
+	c, mockSender := configureCheck(t, "command: "+plugin+"\ntimeout: 1\n")
+
+	require.NoError(t, c.Run())
+
+	mockSender.AssertServiceCheck(t, "nagios.check_test_plugin", servicecheck.ServiceCheckCritical, "", nil, "CRITICAL - plugin timed out after 1s")
+}
+
+func TestCheckPermissions(t *testing.T) {
+	plugin := writePlugin(t, "echo OK\n", 0755)
+	c, mockSender := configureCheck(t, "command: "+plugin+"\n")
+
+	assert.Error(t, c.Run())
+
+	mockSender.AssertCalled(t, "ServiceCheck", "nagios.check_test_plugin", servicecheck.ServiceCheckUnknown, "", []string(nil), mock.Anything)
+	mockSender.AssertNotCalled(t, "Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckConfigure(t *testing.T) {
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+
+	assert.Error(t, newCheck("nagios_test").Configure(senderManager, integration.FakeConfigHash, []byte("args: [-w]"), nil, "test"))
+	assert.Error(t, newCheck("nagios_test").Configure(senderManager, integration.FakeConfigHash, []byte("command: check_load"), nil, "test"))
+}
+
+func TestLoaderSelection(t *testing.T) {
+	plugin := writePlugin(t, "echo OK\n", 0700)
+	loader, err := NewCheckLoader()
+	require.NoError(t, err)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+
+	instance := integration.Data("command: " + plugin)
+	_, err = loader.Load(senderManager, integration.Config{Name: "load"}, instance)
+	assert.Error(t, err)
+
+	c, err := loader.Load(senderManager, integration.Config{Name: "load", InitConfig: integration.Data("loader: nagios")}, instance)
+	require.NoError(t, err)
+	assert.Equal(t, "load", c.String())
+
+	_, err = loader.Load(senderManager, integration.Config{Name: "load"}, integration.Data("loader: nagios\ncommand: "+plugin))
+	assert.NoError(t, err)
+}
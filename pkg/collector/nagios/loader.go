@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package nagios implements a check loader running Nagios plugins.
+//
+// A check is run by this loader when its init_config or its instance sets
+// `loader: nagios`:
+//
+//	init_config:
+//	  loader: nagios
+//	instances:
+//	  - command: /usr/lib/nagios/plugins/check_load
+//	    args: ["-w", "5,4,3", "-c", "10,8,6"]
+//
+// The exit code of the plugin is reported as a service check and its
+// performance data as metrics.
+package nagios
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/loaders"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// LoaderName is the name of the Nagios plugin loader, to be set as the
+// `loader` of the checks it should run
+const LoaderName = "nagios"
+
+// CheckLoader is a loader for checks running Nagios plugins
+type CheckLoader struct{}
+
+// NewCheckLoader creates a loader for Nagios plugins
+func NewCheckLoader() (*CheckLoader, error) {
+	return &CheckLoader{}, nil
+}
+
+// Name returns the Nagios loader name
+func (l *CheckLoader) Name() string {
+	return LoaderName
+}
+
+// Load returns a check running the Nagios plugin configured in the instance
+func (l *CheckLoader) Load(senderManager sender.SenderManager, config integration.Config, instance integration.Data) (check.Check, error) {
+	if !selectsLoader(config.InitConfig) && !selectsLoader(instance) {
+		return nil, fmt.Errorf("check %s doesn't use the %s loader", config.Name, LoaderName)
+	}
+
+	c := newCheck(config.Name)
+	if err := c.Configure(senderManager, config.FastDigest(), instance, config.InitConfig, config.Source); err != nil {
+		if errors.Is(err, check.ErrSkipCheckInstance) {
+			return c, err
+		}
+		log.Errorf("nagios.loader: could not configure check %s: %s", c, err)
+		return c, fmt.Errorf("Could not configure check %s: %s", c, err)
+	}
+
+	return c, nil
+}
+
+func (l *CheckLoader) String() string {
+	return "Nagios Plugin Loader"
+}
+
+// selectsLoader returns whether the given configuration explicitly selects
+// this loader. Nagios plugins are never loaded implicitly, since any check
+// failing to load with the other loaders would end up here.
+func selectsLoader(data integration.Data) bool {
+	var conf struct {
+		LoaderName string `yaml:"loader"`
+	}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return false
+	}
+	return conf.LoaderName == LoaderName
+}
+
+func init() {
+	factory := func(sender.SenderManager) (check.Loader, error) {
+		return NewCheckLoader()
+	}
+
+	loaders.RegisterLoader(40, factory)
+}
@@ -0,0 +1,205 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package nagios
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// perfDatum is a single item of the performance data of a plugin, following
+// the format 'label'=value[UOM];[warn];[crit];[min];[max]
+type perfDatum struct {
+	label string
+	// value is normalized to seconds, bytes or percents when the plugin
+	// reports a unit of measure
+	value   float64
+	counter bool
+	// thresholds holds the warn, crit, min and max values that are plain
+	// numbers, normalized like the value
+	thresholds map[string]float64
+}
+
+var (
+	// unitsOfMeasure are the factors normalizing the Nagios units of
+	// measure to seconds, bytes and percents
+	unitsOfMeasure = map[string]float64{
+		"s":  1,
+		"ms": 1e-3,
+		"us": 1e-6,
+		"%":  1,
+		"B":  1,
+		"KB": 1 << 10,
+		"MB": 1 << 20,
+		"GB": 1 << 30,
+		"TB": 1 << 40,
+	}
+
+	thresholdNames = []string{"warning", "critical", "min", "max"}
+
+	valuePattern = regexp.MustCompile(`^([-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?)(.*)$`)
+)
+
+// parseOutput splits the output of a plugin into its message and its
+// performance data. The first line holds the status text and optionally
+// performance data after a '|'. The following lines are long text, until a
+// line containing a '|' after which everything is performance data.
+func parseOutput(output string) (string, string) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	text, perfData, _ := strings.Cut(lines[0], "|")
+	message := []string{strings.TrimSpace(text)}
+	perfDataParts := []string{perfData}
+
+	for i := 1; i < len(lines); i++ {
+		text, perfData, found := strings.Cut(lines[i], "|")
+		if text = strings.TrimRight(text, "\r "); text != "" {
+			message = append(message, text)
+		}
+		if found {
+			perfDataParts = append(perfDataParts, perfData)
+			perfDataParts = append(perfDataParts, lines[i+1:]...)
+			break
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(message, "\n")), strings.Join(perfDataParts, " ")
+}
+
+// parsePerfData parses performance data. Malformed items are skipped and
+// reported in the returned error, the other items are still returned.
+func parsePerfData(s string) ([]perfDatum, error) {
+	var data []perfDatum
+	var errs []error
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var label strings.Builder
+		if runes[i] == '\'' {
+			// quoted labels can contain spaces and '=', a quote is escaped by
+			// doubling it
+			i++
+			for i < len(runes) {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						label.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				label.WriteRune(runes[i])
+				i++
+			}
+		} else {
+			for i < len(runes) && runes[i] != '=' && !unicode.IsSpace(runes[i]) {
+				label.WriteRune(runes[i])
+				i++
+			}
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		item := string(runes[start:i])
+		if !strings.HasPrefix(item, "=") {
+			errs = append(errs, fmt.Errorf("invalid performance data %q: missing '='", label.String()+item))
+			continue
+		}
+
+		datum, skip, err := parsePerfDatum(label.String(), item[1:])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !skip {
+			data = append(data, datum)
+		}
+	}
+
+	return data, errors.Join(errs...)
+}
+
+// parsePerfDatum parses the value[UOM];[warn];[crit];[min];[max] part of a
+// performance data item. skip is true when the value is undetermined.
+func parsePerfDatum(label string, s string) (perfDatum, bool, error) {
+	datum := perfDatum{label: label}
+	if label == "" {
+		return datum, false, fmt.Errorf("invalid performance data %q: empty label", "="+s)
+	}
+
+	fields := strings.Split(s, ";")
+	if fields[0] == "U" {
+		return datum, true, nil
+	}
+
+	match := valuePattern.FindStringSubmatch(fields[0])
+	if match == nil {
+		return datum, false, fmt.Errorf("invalid performance data %q: invalid value %q", label, fields[0])
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return datum, false, fmt.Errorf("invalid performance data %q: invalid value %q", label, fields[0])
+	}
+
+	factor := 1.0
+	switch uom := match[2]; {
+	case uom == "":
+	case uom == "c":
+		datum.counter = true
+	default:
+		var found bool
+		if factor, found = unitsOfMeasure[uom]; !found {
+			return datum, false, fmt.Errorf("invalid performance data %q: unknown unit of measure %q", label, uom)
+		}
+	}
+	datum.value = value * factor
+
+	for idx, field := range fields[1:] {
+		if idx >= len(thresholdNames) {
+			break
+		}
+		// thresholds can be ranges, only plain numbers are reported
+		threshold, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			continue
+		}
+		if datum.thresholds == nil {
+			datum.thresholds = make(map[string]float64)
+		}
+		datum.thresholds[thresholdNames[idx]] = threshold * factor
+	}
+
+	return datum, false, nil
+}
+
+// sanitizeMetricName turns a plugin or label name into a valid metric name
+// component
+func sanitizeMetricName(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.') {
+			b.WriteRune(unicode.ToLower(r))
+			underscore = false
+		} else if !underscore {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	return strings.Trim(b.String(), "_.")
+}
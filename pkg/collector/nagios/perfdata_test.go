@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package nagios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutput(t *testing.T) {
+	for _, tc := range []struct {
+		output   string
+		message  string
+		perfData string
+	}{
+		{
+			output:  "OK - load average: 0.10\n",
+			message: "OK - load average: 0.10",
+		},
+		{
+			output:   "OK - load average: 0.10 | load1=0.10;5;10;0\n",
+			message:  "OK - load average: 0.10",
+			perfData: " load1=0.10;5;10;0",
+		},
+		{
+			output:   "DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968\n/ 15272 MB (77%);\n/boot 68 MB (69%);\n/home 69357 MB (27%);\n/var/log 819 MB (84%); | /boot=68MB;88;93;0;98\n/home=69357MB;253404;253409;0;253414\n",
+			message:  "DISK OK - free space: / 3326 MB (56%);\n/ 15272 MB (77%);\n/boot 68 MB (69%);\n/home 69357 MB (27%);\n/var/log 819 MB (84%);",
+			perfData: " /=2643MB;5948;5958;0;5968  /boot=68MB;88;93;0;98 /home=69357MB;253404;253409;0;253414",
+		},
+		{
+			output: "",
+		},
+	} {
+		message, perfData := parseOutput(tc.output)
+		assert.Equal(t, tc.message, message, tc.output)
+		assert.Equal(t, tc.perfData, perfData, tc.output)
+	}
+}
+
+func TestParsePerfData(t *testing.T) {
+	data, err := parsePerfData(`time=0.0021s;1;2;0 'free space'=2GB;;;0;4 rta=150.5ms;;@100:200 pl=0% in=1234c out=U size=1e3B users=3;5:;10:`)
+	require.NoError(t, err)
+	assert.Equal(t, []perfDatum{
+		{label: "time", value: 0.0021, thresholds: map[string]float64{"warning": 1, "critical": 2, "min": 0}},
+		{label: "free space", value: 2 << 30, thresholds: map[string]float64{"min": 0, "max": 4 << 30}},
+		{label: "rta", value: 0.1505},
+		{label: "pl", value: 0},
+		{label: "in", value: 1234, counter: true},
+		{label: "size", value: 1000},
+		{label: "users", value: 3},
+	}, data)
+}
+
+func TestParsePerfDataQuotedLabel(t *testing.T) {
+	data, err := parsePerfData(`'it''s = here'=1`)
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, "it's = here", data[0].label)
+	assert.Equal(t, float64(1), data[0].value)
+}
+
+func TestParsePerfDataErrors(t *testing.T) {
+	data, err := parsePerfData(`ok=1 novalue =2 bad=abc unit=10XB other=2`)
+	assert.Error(t, err)
+	assert.Equal(t, []perfDatum{{label: "ok", value: 1}, {label: "other", value: 2}}, data)
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	assert.Equal(t, "free_space", sanitizeMetricName("free space"))
+	assert.Equal(t, "var_log", sanitizeMetricName("/var/log"))
+	assert.Equal(t, "check_http", sanitizeMetricName("check-http"))
+	assert.Equal(t, "load1", sanitizeMetricName("Load1"))
+	assert.Equal(t, "", sanitizeMetricName("/"))
+}
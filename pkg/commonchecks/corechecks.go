@@ -28,9 +28,11 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/embed/process"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/ntp"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
 	ciscosdwan "github.com/DataDog/datadog-agent/pkg/collector/corechecks/network-devices/cisco-sdwan"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/networkpath"
 	nvidia "github.com/DataDog/datadog-agent/pkg/collector/corechecks/nvidia/jetson"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/openmetrics"
 	oracle "github.com/DataDog/datadog-agent/pkg/collector/corechecks/oracle"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/orchestrator/ecs"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/orchestrator/pod"
@@ -48,6 +50,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/winproc"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/systemd"
 	telemetryCheck "github.com/DataDog/datadog-agent/pkg/collector/corechecks/telemetry"
+	_ "github.com/DataDog/datadog-agent/pkg/collector/nagios" // registers the Nagios plugin loader
 )
 
 // RegisterChecks registers all core checks
@@ -58,8 +61,10 @@ func RegisterChecks(store workloadmeta.Component, cfg config.Component) {
 	corecheckLoader.RegisterCheck(uptime.CheckName, uptime.Factory())
 	corecheckLoader.RegisterCheck(telemetryCheck.CheckName, telemetryCheck.Factory())
 	corecheckLoader.RegisterCheck(ntp.CheckName, ntp.Factory())
+	corecheckLoader.RegisterCheck(probe.CheckName, probe.Factory())
 	corecheckLoader.RegisterCheck(snmp.CheckName, snmp.Factory())
 	corecheckLoader.RegisterCheck(networkpath.CheckName, networkpath.Factory())
+	corecheckLoader.RegisterCheck(openmetrics.CheckName, openmetrics.Factory())
 	corecheckLoader.RegisterCheck(io.CheckName, io.Factory())
 	corecheckLoader.RegisterCheck(filehandles.CheckName, filehandles.Factory())
 	corecheckLoader.RegisterCheck(containerimage.CheckName, containerimage.Factory(store))
@@ -246,6 +246,9 @@ type (
 		Package       *InputSpecPackage       `yaml:"package,omitempty" json:"package,omitempty"`
 		XCCDF         *InputSpecXCCDF         `yaml:"xccdf,omitempty" json:"xccdf,omitempty"`
 		Constants     *InputSpecConstants     `yaml:"constants,omitempty" json:"constants,omitempty"`
+		Systemd       *InputSpecSystemd       `yaml:"systemd,omitempty" json:"systemd,omitempty"`
+		Sysctl        *InputSpecSysctl        `yaml:"sysctl,omitempty" json:"sysctl,omitempty"`
+		KernelModule  *InputSpecKernelModule  `yaml:"kernelModule,omitempty" json:"kernelModule,omitempty"`
 
 		TagName string `yaml:"tag,omitempty" json:"tag,omitempty"`
 		Type    string `yaml:"type,omitempty" json:"type,omitempty"`
@@ -310,6 +313,24 @@ type (
 
 	// InputSpecConstants can be used to pass constants data to the evaluator.
 	InputSpecConstants map[string]interface{}
+
+	// InputSpecSystemd describes the spec to resolve the state of a systemd
+	// unit.
+	InputSpecSystemd struct {
+		Unit string `yaml:"unit" json:"unit"`
+	}
+
+	// InputSpecSysctl describes the spec to resolve the runtime and
+	// configured values of a kernel parameter.
+	InputSpecSysctl struct {
+		Name string `yaml:"name" json:"name"`
+	}
+
+	// InputSpecKernelModule describes the spec to resolve the state of a
+	// kernel module.
+	InputSpecKernelModule struct {
+		Name string `yaml:"name" json:"name"`
+	}
 )
 
 // ResolvingContext is part of the resolved inputs data that should be passed
@@ -14,6 +14,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/DataDog/datadog-agent/pkg/compliance/utils"
@@ -33,6 +34,12 @@ const (
 
 	mongoDBResourceType = "db_mongodb"
 	mongoDBConfigPath   = "/etc/mongod.conf"
+
+	mysqlResourceType = "db_mysql"
+
+	redisResourceType = "db_redis"
+
+	redactedValue = "<redacted>"
 )
 
 func relPath(hostroot, configPath string) string {
@@ -69,6 +76,10 @@ func GetProcResourceType(proc *process.Process) (string, bool) {
 		return postgresqlResourceType, true
 	case "mongod":
 		return mongoDBResourceType, true
+	case "mysqld", "mariadbd":
+		return mysqlResourceType, true
+	case "redis-server":
+		return redisResourceType, true
 	case "java":
 		cmdline, _ := proc.CmdlineSlice()
 		if len(cmdline) > 0 && cmdline[len(cmdline)-1] == "org.apache.cassandra.service.CassandraDaemon" {
@@ -93,6 +104,10 @@ func LoadConfiguration(ctx context.Context, rootPath string, proc *process.Proce
 		conf, ok = LoadMongoDBConfig(ctx, rootPath, proc)
 	case cassandraResourceType:
 		conf, ok = LoadCassandraConfig(ctx, rootPath, proc)
+	case mysqlResourceType:
+		conf, ok = LoadMySQLConfig(ctx, rootPath, proc)
+	case redisResourceType:
+		conf, ok = LoadRedisConfig(ctx, rootPath, proc)
 	default:
 		ok = false
 	}
@@ -129,6 +144,10 @@ func LoadDBResourceFromPID(ctx context.Context, pid int32) (*DBResource, bool) {
 		conf, ok = LoadMongoDBConfig(ctx, hostroot, proc)
 	case cassandraResourceType:
 		conf, ok = LoadCassandraConfig(ctx, hostroot, proc)
+	case mysqlResourceType:
+		conf, ok = LoadMySQLConfig(ctx, hostroot, proc)
+	case redisResourceType:
+		conf, ok = LoadRedisConfig(ctx, hostroot, proc)
 	default:
 		ok = false
 	}
@@ -483,3 +502,387 @@ func isIdentifierChar(c uint8) bool {
 		c == '_' ||
 		c == '.'
 }
+
+// LoadMySQLConfig loads and extracts the MySQL or MariaDB configuration data
+// found on the system.
+func LoadMySQLConfig(ctx context.Context, hostroot string, proc *process.Process) (*DBConfig, bool) {
+	var result DBConfig
+	result.ProcessUser, _ = proc.UsernameWithContext(ctx)
+	result.ProcessName, _ = proc.NameWithContext(ctx)
+
+	var hintPath, extraPath string
+	cmdline, _ := proc.CmdlineSlice()
+	for _, arg := range cmdline {
+		if strings.HasPrefix(arg, "--defaults-file=") {
+			hintPath = filepath.Clean(strings.TrimPrefix(arg, "--defaults-file="))
+		} else if strings.HasPrefix(arg, "--defaults-extra-file=") {
+			extraPath = filepath.Clean(strings.TrimPrefix(arg, "--defaults-extra-file="))
+		}
+	}
+
+	var homeDir string
+	if env, err := proc.EnvironWithContext(ctx); err == nil {
+		for _, v := range env {
+			if strings.HasPrefix(v, "HOME=") {
+				homeDir = strings.TrimPrefix(v, "HOME=")
+			}
+		}
+	}
+
+	optionFiles := locateMySQLOptionFiles(hostroot, hintPath, extraPath, homeDir)
+	if len(optionFiles) == 0 {
+		// mysqld can run with its compiled-in defaults only.
+		result.ConfigFileUser = "<none>"
+		result.ConfigFileGroup = "<none>"
+		result.ConfigData = map[string]interface{}{}
+		return &result, true
+	}
+	configPath := optionFiles[0]
+	fi, err := os.Stat(filepath.Join(hostroot, configPath))
+	if err != nil || fi.IsDir() {
+		return nil, false
+	}
+	result.ConfigFileUser = utils.GetFileUser(fi)
+	result.ConfigFileGroup = utils.GetFileGroup(fi)
+	result.ConfigFileMode = uint32(fi.Mode())
+	result.ConfigFilePath = configPath
+
+	configData := make(map[string]map[string]string)
+	if !parseMySQLConfig(hostroot, configPath, configData, 0) {
+		return nil, false
+	}
+	for _, path := range optionFiles[1:] {
+		parseMySQLConfig(hostroot, path, configData, 0)
+	}
+	result.ConfigData = configData
+	return &result, true
+}
+
+// locateMySQLOptionFiles returns the existing option files read by mysqld, in
+// the order they are read, options of a file overriding the ones of the files
+// read before it: the global option files, then the file given by
+// --defaults-extra-file, then the user option file. When --defaults-file is
+// given, only this file is read.
+func locateMySQLOptionFiles(hostroot, defaultsFile, extraFile, homeDir string) []string {
+	var mysqlConfigPaths []string
+	if defaultsFile != "" {
+		mysqlConfigPaths = []string{defaultsFile}
+	} else {
+		mysqlConfigPaths = []string{
+			"/etc/my.cnf",
+			"/etc/mysql/my.cnf",
+			"/usr/etc/my.cnf",
+			"/etc/mysql/mariadb.cnf",
+		}
+		if extraFile != "" {
+			mysqlConfigPaths = append(mysqlConfigPaths, extraFile)
+		}
+		if homeDir != "" {
+			mysqlConfigPaths = append(mysqlConfigPaths, filepath.Join(homeDir, ".my.cnf"))
+		}
+	}
+
+	var files []string
+	for _, path := range mysqlConfigPaths {
+		if fi, err := os.Stat(filepath.Join(hostroot, path)); err == nil && !fi.IsDir() {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// parseMySQLConfig tries to load and parse the given option file path and
+// merges its options into the given configuration, indexed by option groups.
+// Option names are normalized to use underscores, just like mysqld does, and
+// boolean values are normalized to the on / off nomenclature. Options given
+// without value, like skip-name-resolve, are set to on.
+//
+// references:
+//   - https://dev.mysql.com/doc/refman/8.0/en/option-files.html
+//   - https://mariadb.com/kb/en/configuring-mariadb-with-option-files/
+func parseMySQLConfig(hostroot, configPath string, config map[string]map[string]string, includeDepth int) bool {
+	// Same protection against circular includes as for PostgreSQL.
+	if includeDepth > 10 {
+		return false
+	}
+	if configPath == "" {
+		return false
+	}
+
+	b, err := readFileLimit(filepath.Join(hostroot, configPath))
+	if err != nil {
+		return false
+	}
+
+	var group string
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Split(bufio.ScanLines)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '!' {
+			directive, path, _ := strings.Cut(line, " ")
+			path = strings.TrimSpace(path)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(configPath), path)
+			}
+			switch directive {
+			case "!include":
+				parseMySQLConfig(hostroot, path, config, includeDepth+1)
+			case "!includedir":
+				matches, _ := filepath.Glob(filepath.Join(hostroot, path, "*.cnf"))
+				slices.Sort(matches)
+				for _, match := range matches {
+					parseMySQLConfig(hostroot, relPath(hostroot, match), config, includeDepth+1)
+				}
+			}
+			continue
+		}
+		if line[0] == '[' {
+			if end := strings.IndexByte(line, ']'); end > 0 {
+				group = strings.ToLower(strings.TrimSpace(line[1:end]))
+			}
+			continue
+		}
+		if group == "" {
+			continue
+		}
+
+		key, val, hasValue := strings.Cut(line, "=")
+		key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+		val = unquoteMySQLValue(strings.TrimSpace(val))
+		if !hasValue {
+			val = "on"
+		} else if v := strings.ToLower(val); v == "on" || v == "true" {
+			val = "on"
+		} else if v == "off" || v == "false" {
+			val = "off"
+		}
+		if key == "password" || strings.HasSuffix(key, "_password") {
+			val = redactedValue
+		}
+		if _, ok := config[group]; !ok {
+			config[group] = make(map[string]string)
+		}
+		config[group][key] = val
+	}
+
+	return true
+}
+
+func unquoteMySQLValue(val string) string {
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') {
+		if end := strings.IndexByte(val[1:], val[0]); end >= 0 {
+			return val[1 : end+1]
+		}
+	}
+	// trailing comments are only allowed for unquoted values
+	if i := strings.Index(val, " #"); i >= 0 {
+		val = strings.TrimSpace(val[:i])
+	} else if i := strings.Index(val, "\t#"); i >= 0 {
+		val = strings.TrimSpace(val[:i])
+	}
+	return val
+}
+
+// LoadRedisConfig loads and extracts the Redis configuration data found on
+// the system.
+func LoadRedisConfig(ctx context.Context, hostroot string, proc *process.Process) (*DBConfig, bool) {
+	var result DBConfig
+	result.ProcessUser, _ = proc.UsernameWithContext(ctx)
+	result.ProcessName, _ = proc.NameWithContext(ctx)
+
+	// The configuration file is given as argument of redis-server, when
+	// specified. Note that redis-server may rewrite its process title, in
+	// which case we fallback on the default locations.
+	var hintPath string
+	cmdline, _ := proc.CmdlineSlice()
+	for _, arg := range cmdline {
+		if !strings.HasPrefix(arg, "-") && strings.HasSuffix(arg, ".conf") {
+			hintPath = filepath.Clean(arg)
+			break
+		}
+	}
+
+	configPath, ok := locateRedisConfigFile(hostroot, hintPath)
+	if !ok {
+		// redis-server can run without a configuration file.
+		result.ConfigFileUser = "<none>"
+		result.ConfigFileGroup = "<none>"
+		result.ConfigData = map[string]interface{}{}
+		return &result, true
+	}
+	fi, err := os.Stat(filepath.Join(hostroot, configPath))
+	if err != nil || fi.IsDir() {
+		return nil, false
+	}
+	result.ConfigFileUser = utils.GetFileUser(fi)
+	result.ConfigFileGroup = utils.GetFileGroup(fi)
+	result.ConfigFileMode = uint32(fi.Mode())
+	result.ConfigFilePath = configPath
+
+	configData := make(map[string]interface{})
+	if !parseRedisConfig(hostroot, configPath, configData, 0) {
+		return nil, false
+	}
+	result.ConfigData = configData
+	return &result, true
+}
+
+func locateRedisConfigFile(hostroot, hintPath string) (string, bool) {
+	var redisConfigPaths = []string{
+		"/etc/redis/redis.conf",
+		"/etc/redis.conf",
+		"/usr/local/etc/redis/redis.conf",
+		"/usr/local/etc/redis.conf",
+	}
+	if hintPath != "" {
+		redisConfigPaths = []string{hintPath}
+	}
+	for _, path := range redisConfigPaths {
+		if fi, err := os.Stat(filepath.Join(hostroot, path)); err == nil && !fi.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// redisMultiValuedDirectives are the directives that can be specified
+// multiple times and are all taken into account.
+var redisMultiValuedDirectives = []string{
+	"save",
+	"loadmodule",
+	"user",
+}
+
+// redisSensitiveDirectives are the directives which values are redacted.
+var redisSensitiveDirectives = []string{
+	"requirepass",
+	"masterauth",
+	"tls-key-file-pass",
+	"tls-client-key-file-pass",
+}
+
+// parseRedisConfig tries to load and parse the given configuration file path
+// and merges its directives into the given configuration. Directives are
+// lowercased, and their arguments joined with a space. The rename-command
+// directives are exported as a map of the renamed commands to their new
+// names, an empty name meaning the command is disabled.
+//
+// reference: https://redis.io/docs/management/config-file/
+func parseRedisConfig(hostroot, configPath string, config map[string]interface{}, includeDepth int) bool {
+	// Same protection against circular includes as for PostgreSQL.
+	if includeDepth > 10 {
+		return false
+	}
+	if configPath == "" {
+		return false
+	}
+
+	b, err := readFileLimit(filepath.Join(hostroot, configPath))
+	if err != nil {
+		return false
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Split(bufio.ScanLines)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		args := splitRedisConfigLine(line)
+		if len(args) == 0 {
+			continue
+		}
+		directive, args := strings.ToLower(args[0]), args[1:]
+		switch {
+		case directive == "include":
+			for _, path := range args {
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(filepath.Dir(configPath), path)
+				}
+				matches, _ := filepath.Glob(filepath.Join(hostroot, path))
+				for _, match := range matches {
+					parseRedisConfig(hostroot, relPath(hostroot, match), config, includeDepth+1)
+				}
+			}
+		case directive == "rename-command":
+			if len(args) != 2 {
+				continue
+			}
+			renamed, ok := config[directive].(map[string]string)
+			if !ok {
+				renamed = make(map[string]string)
+				config[directive] = renamed
+			}
+			renamed[strings.ToUpper(args[0])] = args[1]
+		case slices.Contains(redisSensitiveDirectives, directive):
+			config[directive] = redactedValue
+		case directive == "user":
+			values, _ := config[directive].([]string)
+			config[directive] = append(values, strings.Join(redactRedisACLRule(args), " "))
+		case slices.Contains(redisMultiValuedDirectives, directive):
+			values, _ := config[directive].([]string)
+			config[directive] = append(values, strings.Join(args, " "))
+		default:
+			config[directive] = strings.Join(args, " ")
+		}
+	}
+
+	return true
+}
+
+// redactRedisACLRule removes the passwords and password hashes from an ACL
+// rule.
+func redactRedisACLRule(args []string) []string {
+	redacted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg, ">") || strings.HasPrefix(arg, "<") || strings.HasPrefix(arg, "#") || strings.HasPrefix(arg, "!") {
+			arg = arg[:1] + redactedValue
+		}
+		redacted = append(redacted, arg)
+	}
+	return redacted
+}
+
+// splitRedisConfigLine splits a configuration line into its arguments,
+// handling double and single quoted strings.
+func splitRedisConfigLine(line string) []string {
+	var args []string
+	var current strings.Builder
+	var quote byte
+	inArg := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' && i+1 < len(line) {
+				i++
+				current.WriteByte(line[i])
+			} else if c == quote {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -309,6 +309,181 @@ func TestMongoDBConfParsing(t *testing.T) {
 	assert.Equal(t, "/var/log/mongodb/mongod.log", *configData.SystemLog.Path)
 }
 
+func TestMySQLConfParsing(t *testing.T) {
+	hostroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(hostroot, "/etc/mysql/conf.d"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	const config = `
+# MySQL configuration
+[client]
+password = "secret"
+
+[mysqld]
+user = mysql
+local-infile = 0
+skip-name-resolve
+bind-address = 127.0.0.1 # only local
+log_error = /var/log/mysql/error.log
+
+!includedir /etc/mysql/conf.d/
+`
+	const configOverride = `
+[mysqld]
+bind_address = 0.0.0.0
+secure-file-priv = "/var/lib/mysql-files"
+have_ssl = ON
+`
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/mysql/my.cnf"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/mysql/conf.d/override.cnf"), []byte(configOverride), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/mysql/conf.d/ignored.conf"), []byte("[mysqld]\nuser = root\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	proc, stop := launchFakeProcess(context.Background(), t, "mysqld")
+	defer stop()
+	c, ok := LoadMySQLConfig(context.Background(), hostroot, proc)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0600), c.ConfigFileMode)
+	assert.Equal(t, "/etc/mysql/my.cnf", c.ConfigFilePath)
+	assert.NotEmpty(t, c.ConfigFileUser)
+	configData := c.ConfigData.(map[string]map[string]string)
+	assert.Equal(t, redactedValue, configData["client"]["password"])
+	assert.Equal(t, "mysql", configData["mysqld"]["user"])
+	assert.Equal(t, "0", configData["mysqld"]["local_infile"])
+	assert.Equal(t, "on", configData["mysqld"]["skip_name_resolve"])
+	assert.Equal(t, "0.0.0.0", configData["mysqld"]["bind_address"])
+	assert.Equal(t, "/var/lib/mysql-files", configData["mysqld"]["secure_file_priv"])
+	assert.Equal(t, "on", configData["mysqld"]["have_ssl"])
+	assert.Equal(t, "/var/log/mysql/error.log", configData["mysqld"]["log_error"])
+}
+
+func TestMySQLConfDefaultsFile(t *testing.T) {
+	hostroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(hostroot, "/opt/mysql"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/opt/mysql/custom.cnf"), []byte("[mysqld]\nport=3307\n!include extra.cnf\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/opt/mysql/extra.cnf"), []byte("[mariadb]\nsql_mode='STRICT_ALL_TABLES'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proc, stop := launchFakeProcess(context.Background(), t, "mariadbd", "--defaults-file=/opt/mysql/custom.cnf")
+	defer stop()
+	c, ok := LoadMySQLConfig(context.Background(), hostroot, proc)
+	assert.True(t, ok)
+	assert.Equal(t, "/opt/mysql/custom.cnf", c.ConfigFilePath)
+	configData := c.ConfigData.(map[string]map[string]string)
+	assert.Equal(t, "3307", configData["mysqld"]["port"])
+	assert.Equal(t, "STRICT_ALL_TABLES", configData["mariadb"]["sql_mode"])
+
+	// no configuration file at all
+	c, ok = LoadMySQLConfig(context.Background(), t.TempDir(), proc)
+	assert.True(t, ok)
+	assert.Equal(t, "<none>", c.ConfigFileUser)
+	assert.Empty(t, c.ConfigFilePath)
+}
+
+func TestMySQLConfExtraFile(t *testing.T) {
+	hostroot := t.TempDir()
+	for _, dir := range []string{"/etc/mysql", "/opt/mysql", "/home/mysql"} {
+		if err := os.MkdirAll(filepath.Join(hostroot, dir), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/my.cnf"), []byte("[mysqld]\nport=3306\nlocal_infile=1\nuser=mysql\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/mysql/my.cnf"), []byte("[mysqld]\nlocal_infile=0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/opt/mysql/extra.cnf"), []byte("[mysqld]\nport=3307\nlocal_infile=1\nuser=root\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/home/mysql/.my.cnf"), []byte("[mysqld]\nuser=mysql\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", "/home/mysql")
+	proc, stop := launchFakeProcess(context.Background(), t, "mysqld", "--defaults-extra-file=/opt/mysql/extra.cnf")
+	defer stop()
+	c, ok := LoadMySQLConfig(context.Background(), hostroot, proc)
+	assert.True(t, ok)
+	assert.Equal(t, "/etc/my.cnf", c.ConfigFilePath)
+	configData := c.ConfigData.(map[string]map[string]string)
+	// the extra file overrides the global files, and the user file overrides the extra file
+	assert.Equal(t, "3307", configData["mysqld"]["port"])
+	assert.Equal(t, "1", configData["mysqld"]["local_infile"])
+	assert.Equal(t, "mysql", configData["mysqld"]["user"])
+}
+
+func TestRedisConfParsing(t *testing.T) {
+	hostroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(hostroot, "/etc/redis/conf.d"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	const config = `
+# Redis configuration
+bind 127.0.0.1 -::1
+protected-mode yes
+port 6379
+requirepass "s3cr3t"
+save 900 1
+save 300 10
+rename-command CONFIG ""
+rename-command flushall "FLUSHALL_b840fc02"
+user default on >password ~* +@all
+include /etc/redis/conf.d/*.conf
+include conf.d/relative.inc
+`
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/redis/redis.conf"), []byte(config), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/redis/conf.d/local.conf"), []byte("Protected-Mode no\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hostroot, "/etc/redis/conf.d/relative.inc"), []byte("maxclients 100\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	proc, stop := launchFakeProcess(context.Background(), t, "redis-server", "/etc/redis/redis.conf", "--port", "6380")
+	defer stop()
+	c, ok := LoadRedisConfig(context.Background(), hostroot, proc)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0640), c.ConfigFileMode)
+	assert.Equal(t, "/etc/redis/redis.conf", c.ConfigFilePath)
+	assert.NotEmpty(t, c.ConfigFileUser)
+	configData := c.ConfigData.(map[string]interface{})
+	assert.Equal(t, "127.0.0.1 -::1", configData["bind"])
+	assert.Equal(t, "no", configData["protected-mode"])
+	assert.Equal(t, "6379", configData["port"])
+	assert.Equal(t, "100", configData["maxclients"])
+	assert.Equal(t, redactedValue, configData["requirepass"])
+	assert.Equal(t, []string{"900 1", "300 10"}, configData["save"])
+	assert.Equal(t, map[string]string{"CONFIG": "", "FLUSHALL": "FLUSHALL_b840fc02"}, configData["rename-command"])
+	assert.Equal(t, []string{"default on >" + redactedValue + " ~* +@all"}, configData["user"])
+}
+
+func TestDBConfProcessTypes(t *testing.T) {
+	for procname, expected := range map[string]string{
+		"mysqld":       mysqlResourceType,
+		"mariadbd":     mysqlResourceType,
+		"redis-server": redisResourceType,
+	} {
+		proc, stop := launchFakeProcess(context.Background(), t, procname)
+		resourceType, ok := GetProcResourceType(proc)
+		stop()
+		assert.True(t, ok)
+		assert.Equal(t, expected, resourceType)
+	}
+}
+
 const pgConfigCommon = `
 # -----------------------------
 # PostgreSQL configuration file
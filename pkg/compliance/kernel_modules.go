@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// modprobeConfDirs lists the modprobe.d directories.
+var modprobeConfDirs = []string{
+	"/etc/modprobe.d",
+	"/run/modprobe.d",
+	"/usr/local/lib/modprobe.d",
+	"/usr/lib/modprobe.d",
+	"/lib/modprobe.d",
+}
+
+// modprobeDisablingCommands are the install commands commonly used to prevent
+// a module from being loaded.
+var modprobeDisablingCommands = []string{
+	"/bin/true",
+	"/bin/false",
+	"/usr/bin/true",
+	"/usr/bin/false",
+}
+
+type kernelModuleInfo struct {
+	Name           string `json:"name"`
+	Loaded         bool   `json:"loaded"`
+	Blacklisted    bool   `json:"blacklisted"`
+	InstallCommand string `json:"installCommand"`
+	Disabled       bool   `json:"disabled"`
+}
+
+// normalizeKernelModuleName returns the name of a module as listed in
+// /proc/modules, dashes and underscores being interchangeable.
+func normalizeKernelModuleName(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+}
+
+func (r *defaultResolver) isKernelModuleLoaded(name string) bool {
+	f, err := os.Open(r.pathNormalizeToHostRoot("/proc/modules"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && normalizeKernelModuleName(fields[0]) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseModprobeConfFile(path, name string, info *kernelModuleInfo) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if normalizeKernelModuleName(fields[1]) != name {
+			continue
+		}
+		switch fields[0] {
+		case "blacklist":
+			info.Blacklisted = true
+		case "install":
+			info.InstallCommand = strings.Join(fields[2:], " ")
+		}
+	}
+}
+
+// getKernelModuleInfo resolves whether a kernel module is loaded and whether
+// its loading is prevented by the modprobe configuration on the host
+// filesystem.
+func (r *defaultResolver) getKernelModuleInfo(name string) *kernelModuleInfo {
+	name = normalizeKernelModuleName(name)
+	info := &kernelModuleInfo{
+		Name:   name,
+		Loaded: r.isKernelModuleLoaded(name),
+	}
+
+	for _, dir := range modprobeConfDirs {
+		matches, _ := filepath.Glob(r.pathNormalizeToHostRoot(filepath.Join(dir, "*.conf")))
+		for _, match := range matches {
+			parseModprobeConfFile(match, name, info)
+		}
+	}
+
+	for _, cmd := range modprobeDisablingCommands {
+		if info.InstallCommand == cmd {
+			info.Disabled = true
+		}
+	}
+
+	return info
+}
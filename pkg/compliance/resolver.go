@@ -237,6 +237,15 @@ func (r *defaultResolver) ResolveInputs(ctx context.Context, rule *Rule) (Resolv
 		case spec.Package != nil:
 			resultType = "package"
 			result, err = r.resolvePackage(ctx, *spec.Package)
+		case spec.Systemd != nil:
+			resultType = "systemd"
+			result, err = r.resolveSystemd(ctx, *spec.Systemd)
+		case spec.Sysctl != nil:
+			resultType = "sysctl"
+			result, err = r.resolveSysctl(ctx, *spec.Sysctl)
+		case spec.KernelModule != nil:
+			resultType = "kernelModule"
+			result, err = r.resolveKernelModule(ctx, *spec.KernelModule)
 		case spec.Constants != nil:
 			resultType = "constants"
 			result = *spec.Constants
@@ -834,6 +843,27 @@ func (r *defaultResolver) resolvePackage(ctx context.Context, spec InputSpecPack
 	return nil, nil
 }
 
+func (r *defaultResolver) resolveSystemd(_ context.Context, spec InputSpecSystemd) (interface{}, error) {
+	if spec.Unit == "" {
+		return nil, fmt.Errorf("systemd input requires a unit name")
+	}
+	return r.getSystemdUnitInfo(spec.Unit), nil
+}
+
+func (r *defaultResolver) resolveSysctl(_ context.Context, spec InputSpecSysctl) (interface{}, error) {
+	if spec.Name == "" {
+		return nil, fmt.Errorf("sysctl input requires a parameter name")
+	}
+	return r.getSysctlInfo(spec.Name), nil
+}
+
+func (r *defaultResolver) resolveKernelModule(_ context.Context, spec InputSpecKernelModule) (interface{}, error) {
+	if spec.Name == "" {
+		return nil, fmt.Errorf("kernelModule input requires a module name")
+	}
+	return r.getKernelModuleInfo(spec.Name), nil
+}
+
 func parseCmdlineFlags(cmdline []string) map[string]string {
 	flagsMap := make(map[string]string, 0)
 	pendingFlagValue := false
@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sysctlConfDirs lists the sysctl.d directories in order of precedence.
+var sysctlConfDirs = []string{
+	"/etc/sysctl.d",
+	"/run/sysctl.d",
+	"/usr/local/lib/sysctl.d",
+	"/usr/lib/sysctl.d",
+	"/lib/sysctl.d",
+}
+
+const sysctlConf = "/etc/sysctl.conf"
+
+type sysctlInfo struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Exists     bool   `json:"exists"`
+	Configured string `json:"configured"`
+	ConfigFile string `json:"configFile"`
+}
+
+// normalizeSysctlName returns the dotted form of a kernel parameter name.
+func normalizeSysctlName(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "/", ".")
+}
+
+// normalizeSysctlValue collapses whitespaces so that runtime values with
+// multiple fields can be compared to configured ones.
+func normalizeSysctlValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// listSysctlConfFiles returns the sysctl configuration files in the order
+// they are applied: files of sysctl.d directories sorted by name, a file
+// shadowing the ones with the same name in directories of lower precedence,
+// then /etc/sysctl.conf.
+func (r *defaultResolver) listSysctlConfFiles() []string {
+	files := make(map[string]string)
+	for i := len(sysctlConfDirs) - 1; i >= 0; i-- {
+		matches, _ := filepath.Glob(r.pathNormalizeToHostRoot(filepath.Join(sysctlConfDirs[i], "*.conf")))
+		for _, match := range matches {
+			name := filepath.Base(match)
+			files[name] = filepath.Join(sysctlConfDirs[i], name)
+		}
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	paths := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		paths = append(paths, files[name])
+	}
+	return append(paths, sysctlConf)
+}
+
+func parseSysctlConfFile(path, name string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	var value string
+	var found bool
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		// a leading dash means errors setting the parameter are ignored
+		key = strings.TrimPrefix(strings.TrimSpace(key), "-")
+		if normalizeSysctlName(key) == name {
+			value, found = normalizeSysctlValue(val), true
+		}
+	}
+	return value, found
+}
+
+// getSysctlInfo resolves the runtime value of a kernel parameter and the
+// value persisted in the sysctl configuration on the host filesystem.
+func (r *defaultResolver) getSysctlInfo(name string) *sysctlInfo {
+	name = normalizeSysctlName(name)
+	info := &sysctlInfo{
+		Name: name,
+	}
+
+	procPath := r.pathNormalizeToHostRoot(filepath.Join("/proc/sys", strings.ReplaceAll(name, ".", "/")))
+	if data, err := os.ReadFile(procPath); err == nil {
+		info.Value = normalizeSysctlValue(string(data))
+		info.Exists = true
+	}
+
+	for _, path := range r.listSysctlConfFiles() {
+		if value, ok := parseSysctlConfFile(r.pathNormalizeToHostRoot(path), name); ok {
+			info.Configured = value
+			info.ConfigFile = path
+		}
+	}
+
+	return info
+}
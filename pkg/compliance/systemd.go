@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// systemdUnitDirs lists the systemd unit directories in order of precedence.
+var systemdUnitDirs = []string{
+	"/etc/systemd/system",
+	"/run/systemd/system",
+	"/usr/local/lib/systemd/system",
+	"/usr/lib/systemd/system",
+	"/lib/systemd/system",
+}
+
+// systemdRuntimeUnitsDir holds an invocation symlink for each unit that is
+// currently active.
+const systemdRuntimeUnitsDir = "/run/systemd/units"
+
+type systemdUnitInfo struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Exists  bool   `json:"exists"`
+	Enabled bool   `json:"enabled"`
+	Masked  bool   `json:"masked"`
+	Active  bool   `json:"active"`
+}
+
+// getSystemdUnitInfo resolves the state of the given unit by inspecting the
+// systemd directories on the host filesystem. This avoids
+// relying on D-Bus, which is not available when running from a container.
+func (r *defaultResolver) getSystemdUnitInfo(unit string) *systemdUnitInfo {
+	info := &systemdUnitInfo{
+		Name: unit,
+	}
+
+	for _, dir := range systemdUnitDirs {
+		path := filepath.Join(dir, unit)
+		fi, err := os.Lstat(r.pathNormalizeToHostRoot(path))
+		if err != nil {
+			continue
+		}
+		if info.Path == "" {
+			info.Path = path
+			info.Exists = true
+			// a unit is masked when it is linked to /dev/null or is empty
+			if fi.Mode()&os.ModeSymlink != 0 {
+				target, _ := os.Readlink(r.pathNormalizeToHostRoot(path))
+				info.Masked = target == os.DevNull
+			} else if fi.Size() == 0 {
+				info.Masked = true
+			}
+		}
+	}
+
+	for _, dir := range systemdUnitDirs {
+		for _, pattern := range []string{"*.wants", "*.requires"} {
+			links, _ := filepath.Glob(r.pathNormalizeToHostRoot(filepath.Join(dir, pattern, unit)))
+			if len(links) > 0 {
+				info.Enabled = !info.Masked
+			}
+		}
+	}
+
+	if _, err := os.Lstat(r.pathNormalizeToHostRoot(filepath.Join(systemdRuntimeUnitsDir, "invocation:"+unit))); err == nil {
+		info.Active = true
+		info.Exists = true
+	}
+
+	return info
+}
@@ -29,6 +29,7 @@ type suite struct {
 	t        *testing.T
 	hostname string
 	rootDir  string
+	hostRoot string
 
 	dockerClient docker.CommonAPIClient
 	auditClient  compliance.LinuxAuditClient
@@ -66,6 +67,11 @@ func (s *suite) WithHostname(hostname string) *suite {
 	return s
 }
 
+func (s *suite) WithHostRoot(hostRoot string) *suite {
+	s.hostRoot = hostRoot
+	return s
+}
+
 func (s *suite) WithDockerClient(cl docker.CommonAPIClient) *suite {
 	s.dockerClient = cl
 	return s
@@ -104,6 +110,7 @@ func (s *suite) Run() {
 		s.t.Run(c.name, func(t *testing.T) {
 			options := compliance.ResolverOptions{
 				Hostname: s.hostname,
+				HostRoot: s.hostRoot,
 			}
 			if s.auditClient != nil {
 				options.LinuxAuditProvider = func(ctx context.Context) (compliance.LinuxAuditClient, error) { return s.auditClient, nil }
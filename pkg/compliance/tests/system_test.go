@@ -0,0 +1,232 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRootFile(t *testing.T, root, name, data string) {
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func symlinkRootFile(t *testing.T, root, target, name string) {
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSystemdInput(t *testing.T) {
+	hostRoot := t.TempDir()
+	writeRootFile(t, hostRoot, "/lib/systemd/system/auditd.service", "[Unit]\n")
+	symlinkRootFile(t, hostRoot, "/lib/systemd/system/auditd.service", "/etc/systemd/system/multi-user.target.wants/auditd.service")
+	symlinkRootFile(t, hostRoot, "/lib/systemd/system/auditd.service", "/run/systemd/units/invocation:auditd.service")
+	writeRootFile(t, hostRoot, "/lib/systemd/system/rsync.service", "[Unit]\n")
+	symlinkRootFile(t, hostRoot, os.DevNull, "/etc/systemd/system/rsync.service")
+
+	b := newTestBench(t).WithHostRoot(hostRoot)
+	defer b.Run()
+
+	b.AddRule("SystemdEnabled").
+		WithInput(`
+- systemd:
+		unit: auditd.service
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	input.systemd.exists
+	input.systemd.enabled
+	input.systemd.active
+	not input.systemd.masked
+	f := dd.passed_finding("systemd_unit", input.systemd.name, {"path": input.systemd.path})
+}
+`).
+		AssertPassedEvent(func(t *testing.T, evt *compliance.CheckEvent) {
+			assert.Equal(t, "auditd.service", evt.ResourceID)
+			assert.Equal(t, "/lib/systemd/system/auditd.service", evt.Data["path"])
+		})
+
+	b.AddRule("SystemdMasked").
+		WithInput(`
+- systemd:
+		unit: rsync.service
+	tag: rsync
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	input.rsync.masked
+	not input.rsync.enabled
+	not input.rsync.active
+	f := dd.passed_finding("systemd_unit", input.rsync.name, {})
+}
+`).
+		AssertPassedEvent(nil)
+
+	b.AddRule("SystemdMissing").
+		WithInput(`
+- systemd:
+		unit: telnet.socket
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	not input.systemd.exists
+	f := dd.passed_finding("systemd_unit", input.systemd.name, {})
+}
+`).
+		AssertPassedEvent(nil)
+}
+
+func TestSysctlInput(t *testing.T) {
+	hostRoot := t.TempDir()
+	writeRootFile(t, hostRoot, "/proc/sys/net/ipv4/ip_forward", "1\n")
+	writeRootFile(t, hostRoot, "/proc/sys/net/ipv4/tcp_rmem", "4096\t131072\t6291456\n")
+	writeRootFile(t, hostRoot, "/usr/lib/sysctl.d/50-default.conf", "net.ipv4.ip_forward = 1\nkernel.randomize_va_space = 1\n")
+	writeRootFile(t, hostRoot, "/etc/sysctl.d/50-default.conf", "# override\n-net/ipv4/ip_forward=0\n")
+	writeRootFile(t, hostRoot, "/etc/sysctl.conf", "kernel.randomize_va_space = 2\n")
+
+	b := newTestBench(t).WithHostRoot(hostRoot)
+	defer b.Run()
+
+	b.AddRule("SysctlConfigured").
+		WithInput(`
+- sysctl:
+		name: net.ipv4.ip_forward
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	input.sysctl.value != input.sysctl.configured
+	f := dd.failing_finding("sysctl", input.sysctl.name, {
+		"value": input.sysctl.value,
+		"configured": input.sysctl.configured,
+		"configFile": input.sysctl.configFile,
+	})
+}
+`).
+		AssertFailedEvent(func(t *testing.T, evt *compliance.CheckEvent) {
+			assert.Equal(t, "net.ipv4.ip_forward", evt.ResourceID)
+			assert.Equal(t, "1", evt.Data["value"])
+			assert.Equal(t, "0", evt.Data["configured"])
+			assert.Equal(t, "/etc/sysctl.d/50-default.conf", evt.Data["configFile"])
+		})
+
+	b.AddRule("SysctlConf").
+		WithInput(`
+- sysctl:
+		name: kernel/randomize_va_space
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	not input.sysctl.exists
+	input.sysctl.configured == "2"
+	f := dd.passed_finding("sysctl", input.sysctl.name, {"configFile": input.sysctl.configFile})
+}
+`).
+		AssertPassedEvent(func(t *testing.T, evt *compliance.CheckEvent) {
+			assert.Equal(t, "kernel.randomize_va_space", evt.ResourceID)
+			assert.Equal(t, "/etc/sysctl.conf", evt.Data["configFile"])
+		})
+
+	b.AddRule("SysctlMultipleFields").
+		WithInput(`
+- sysctl:
+		name: net.ipv4.tcp_rmem
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	input.sysctl.value == "4096 131072 6291456"
+	f := dd.passed_finding("sysctl", input.sysctl.name, {})
+}
+`).
+		AssertPassedEvent(nil)
+}
+
+func TestKernelModuleInput(t *testing.T) {
+	hostRoot := t.TempDir()
+	writeRootFile(t, hostRoot, "/proc/modules", "nf_tables 282624 0 - Live 0x0000000000000000\nusb_storage 81920 0 - Live 0x0000000000000000\n")
+	writeRootFile(t, hostRoot, "/etc/modprobe.d/cis.conf", "install cramfs /bin/true\nblacklist cramfs\n# blacklist usb-storage\n")
+	writeRootFile(t, hostRoot, "/lib/modprobe.d/dist.conf", "blacklist usb-storage\n")
+
+	b := newTestBench(t).WithHostRoot(hostRoot)
+	defer b.Run()
+
+	b.AddRule("KernelModuleDisabled").
+		WithInput(`
+- kernelModule:
+		name: cramfs
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	not input.kernelModule.loaded
+	input.kernelModule.disabled
+	input.kernelModule.blacklisted
+	f := dd.passed_finding("kernel_module", input.kernelModule.name, {"install": input.kernelModule.installCommand})
+}
+`).
+		AssertPassedEvent(func(t *testing.T, evt *compliance.CheckEvent) {
+			assert.Equal(t, "cramfs", evt.ResourceID)
+			assert.Equal(t, "/bin/true", evt.Data["install"])
+		})
+
+	b.AddRule("KernelModuleLoaded").
+		WithInput(`
+- kernelModule:
+		name: usb-storage
+	tag: usb
+`).
+		WithRego(`
+package datadog
+import data.datadog as dd
+
+findings[f] {
+	input.usb.loaded
+	input.usb.blacklisted
+	not input.usb.disabled
+	f := dd.failing_finding("kernel_module", input.usb.name, {})
+}
+`).
+		AssertFailedEvent(func(t *testing.T, evt *compliance.CheckEvent) {
+			assert.Equal(t, "usb_storage", evt.ResourceID)
+		})
+}
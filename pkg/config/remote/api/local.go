@@ -0,0 +1,261 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	localConfigRepo   = "config"
+	localDirectorRepo = "director"
+	localTargetsDir   = "targets"
+
+	// maxLocalFileSize caps the size of the files read from a local repository
+	maxLocalFileSize = 10 * 1024 * 1024
+)
+
+var errLocalFileNotFound = errors.New("file not found")
+
+// LocalRepository fetches configurations from a signed repository stored in
+// a local directory or served by an on-prem HTTP mirror, for agents that
+// can't reach the Remote Configuration backend. The repository is laid out
+// as follows:
+//
+//	config/<version>.root.json     config repository roots
+//	config/timestamp.json
+//	config/snapshot.json
+//	config/targets.json
+//	config/<role>.json             delegated targets of the config repository
+//	director/<version>.root.json   director repository roots
+//	director/timestamp.json
+//	director/snapshot.json
+//	director/targets.json
+//	targets/<path>                 target files listed in director/targets.json
+//
+// The metadata is verified by the uptane client exactly as if it came from
+// the backend, so the roots of both repositories must be provided.
+type LocalRepository struct {
+	read func(ctx context.Context, path string) ([]byte, error)
+}
+
+// NewLocalRepository returns a LocalRepository reading the repository at
+// location, either a directory or the http(s) URL of a mirror
+func NewLocalRepository(cfg model.Reader, location string) (*LocalRepository, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		dir := strings.TrimPrefix(location, "file://")
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("invalid local remote configuration repository: %w", err)
+		}
+		return &LocalRepository{read: func(_ context.Context, p string) ([]byte, error) {
+			return readLocalFile(filepath.Join(dir, filepath.FromSlash(p)))
+		}}, nil
+	}
+
+	baseURL, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if baseURL.Scheme != "https" && !cfg.GetBool("remote_configuration.no_tls") {
+		return nil, fmt.Errorf("remote Configuration repository URL %s is invalid as TLS is required by default. While it is not advised, the `remote_configuration.no_tls` config option can be set to `true` to disable this protection", baseURL)
+	}
+	transport := httputils.CreateHTTPTransport(cfg)
+	if transport.TLSClientConfig.InsecureSkipVerify && !cfg.GetBool("remote_configuration.no_tls_validation") {
+		return nil, fmt.Errorf("remote Configuration does not allow skipping TLS validation by default (currently skipped because `skip_ssl_validation` is set to true). While it is not advised, the `remote_configuration.no_tls_validation` config option can be set to `true` to disable this protection")
+	}
+	client := &http.Client{Transport: transport}
+	base := strings.TrimSuffix(baseURL.String(), "/")
+	return &LocalRepository{read: func(ctx context.Context, p string) ([]byte, error) {
+		return readMirrorFile(ctx, client, base+"/"+p)
+	}}, nil
+}
+
+// Fetch returns the metadata of both repositories and the target files of the
+// director repository. Only the roots newer than the ones of the request are
+// returned, the other metadata is always returned.
+func (r *LocalRepository) Fetch(ctx context.Context, request *pbgo.LatestConfigsRequest) (*pbgo.LatestConfigsResponse, error) {
+	configRoots, err := r.fetchRoots(ctx, localConfigRepo, request.CurrentConfigRootVersion)
+	if err != nil {
+		return nil, err
+	}
+	directorRoots, err := r.fetchRoots(ctx, localDirectorRepo, request.CurrentDirectorRootVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var metas [6]*pbgo.TopMeta
+	for i, name := range []string{
+		localConfigRepo + "/timestamp.json",
+		localConfigRepo + "/snapshot.json",
+		localConfigRepo + "/targets.json",
+		localDirectorRepo + "/timestamp.json",
+		localDirectorRepo + "/snapshot.json",
+		localDirectorRepo + "/targets.json",
+	} {
+		if metas[i], err = r.fetchMeta(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	var configTargets localTargets
+	if err := json.Unmarshal(metas[2].Raw, &configTargets); err != nil {
+		return nil, fmt.Errorf("could not parse %s/targets.json: %w", localConfigRepo, err)
+	}
+	var delegatedTargets []*pbgo.DelegatedMeta
+	if configTargets.Signed.Delegations != nil {
+		for _, role := range configTargets.Signed.Delegations.Roles {
+			if role.Name == "" || strings.ContainsAny(role.Name, `/\`) || strings.HasPrefix(role.Name, ".") {
+				return nil, fmt.Errorf("invalid delegated role name %s", role.Name)
+			}
+			meta, err := r.fetchMeta(ctx, localConfigRepo+"/"+role.Name+".json")
+			if err != nil {
+				return nil, err
+			}
+			delegatedTargets = append(delegatedTargets, &pbgo.DelegatedMeta{Role: role.Name, Version: meta.Version, Raw: meta.Raw})
+		}
+	}
+
+	var directorTargets localTargets
+	if err := json.Unmarshal(metas[5].Raw, &directorTargets); err != nil {
+		return nil, fmt.Errorf("could not parse %s/targets.json: %w", localDirectorRepo, err)
+	}
+	targetFiles := make([]*pbgo.File, 0, len(directorTargets.Signed.Targets))
+	for targetPath := range directorTargets.Signed.Targets {
+		// the metadata isn't verified yet, so the paths must not escape the
+		// repository
+		cleanPath := path.Clean(targetPath)
+		if path.IsAbs(cleanPath) || strings.HasPrefix(cleanPath, "..") {
+			return nil, fmt.Errorf("invalid target path %s", targetPath)
+		}
+		raw, err := r.read(ctx, localTargetsDir+"/"+cleanPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read target %s: %w", targetPath, err)
+		}
+		targetFiles = append(targetFiles, &pbgo.File{Path: targetPath, Raw: raw})
+	}
+
+	return &pbgo.LatestConfigsResponse{
+		ConfigMetas: &pbgo.ConfigMetas{
+			Roots:            configRoots,
+			Timestamp:        metas[0],
+			Snapshot:         metas[1],
+			TopTargets:       metas[2],
+			DelegatedTargets: delegatedTargets,
+		},
+		DirectorMetas: &pbgo.DirectorMetas{
+			Roots:     directorRoots,
+			Timestamp: metas[3],
+			Snapshot:  metas[4],
+			Targets:   metas[5],
+		},
+		TargetFiles: targetFiles,
+	}, nil
+}
+
+// FetchOrgData returns no org UUID: the snapshots of a local repository
+// should not set one
+func (r *LocalRepository) FetchOrgData(context.Context) (*pbgo.OrgDataResponse, error) {
+	return &pbgo.OrgDataResponse{}, nil
+}
+
+// FetchOrgStatus always reports Remote Configuration as enabled and
+// authorized since the repository is managed by the operator
+func (r *LocalRepository) FetchOrgStatus(context.Context) (*pbgo.OrgStatusResponse, error) {
+	return &pbgo.OrgStatusResponse{Enabled: true, Authorized: true}, nil
+}
+
+// fetchRoots returns the roots of a repository newer than currentVersion
+func (r *LocalRepository) fetchRoots(ctx context.Context, repo string, currentVersion uint64) ([]*pbgo.TopMeta, error) {
+	var roots []*pbgo.TopMeta
+	for version := currentVersion + 1; ; version++ {
+		raw, err := r.read(ctx, fmt.Sprintf("%s/%d.root.json", repo, version))
+		if errors.Is(err, errLocalFileNotFound) {
+			return roots, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, &pbgo.TopMeta{Version: version, Raw: raw})
+	}
+}
+
+// fetchMeta returns a metadata file along with its version
+func (r *LocalRepository) fetchMeta(ctx context.Context, name string) (*pbgo.TopMeta, error) {
+	raw, err := r.read(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", name, err)
+	}
+	var meta struct {
+		Signed struct {
+			Version uint64 `json:"version"`
+		} `json:"signed"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", name, err)
+	}
+	return &pbgo.TopMeta{Version: meta.Signed.Version, Raw: raw}, nil
+}
+
+// localTargets holds the fields of a targets metadata file needed to know
+// which other files to fetch
+type localTargets struct {
+	Signed struct {
+		Targets     map[string]json.RawMessage `json:"targets"`
+		Delegations *struct {
+			Roles []struct {
+				Name string `json:"name"`
+			} `json:"roles"`
+		} `json:"delegations"`
+	} `json:"signed"`
+}
+
+func readLocalFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, errLocalFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, maxLocalFileSize))
+}
+
+func readMirrorFile(ctx context.Context, client *http.Client, fileURL string) ([]byte, error) {
+	log.Debugf("fetching %s", fileURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errLocalFileNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-200 response code: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxLocalFileSize))
+}
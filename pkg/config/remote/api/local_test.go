@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+)
+
+func newTestLocalConfig() model.Config {
+	cfg := model.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	cfg.SetWithoutSource("remote_configuration.no_tls", true)
+	return cfg
+}
+
+// writeTestRepository writes unsigned metadata to dir, the signatures being
+// checked by the uptane client and not by the repository
+func writeTestRepository(t *testing.T, dir string, targetPath string) {
+	files := map[string]string{
+		"config/1.root.json":      `{"signed":{"version":1}}`,
+		"config/2.root.json":      `{"signed":{"version":2}}`,
+		"config/timestamp.json":   `{"signed":{"version":10}}`,
+		"config/snapshot.json":    `{"signed":{"version":11}}`,
+		"config/targets.json":     `{"signed":{"version":12,"delegations":{"roles":[{"name":"apm"}]}}}`,
+		"config/apm.json":         `{"signed":{"version":13}}`,
+		"director/1.root.json":    `{"signed":{"version":1}}`,
+		"director/timestamp.json": `{"signed":{"version":20}}`,
+		"director/snapshot.json":  `{"signed":{"version":21}}`,
+		"director/targets.json":   fmt.Sprintf(`{"signed":{"version":22,"targets":{%q:{}}}}`, targetPath),
+		"targets/" + targetPath:   `{"rate":0.5}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestLocalRepositoryFetch(t *testing.T) {
+	dir := t.TempDir()
+	writeTestRepository(t, dir, "datadog/2/APM_SAMPLING/id/config")
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+
+	for name, location := range map[string]string{"directory": dir, "mirror": server.URL} {
+		t.Run(name, func(t *testing.T) {
+			repo, err := NewLocalRepository(newTestLocalConfig(), location)
+			require.NoError(t, err)
+
+			resp, err := repo.Fetch(context.Background(), &pbgo.LatestConfigsRequest{CurrentConfigRootVersion: 1})
+			require.NoError(t, err)
+
+			// only the roots newer than the current ones are returned
+			require.Len(t, resp.ConfigMetas.Roots, 1)
+			assert.EqualValues(t, 2, resp.ConfigMetas.Roots[0].Version)
+			require.Len(t, resp.DirectorMetas.Roots, 1)
+			assert.EqualValues(t, 1, resp.DirectorMetas.Roots[0].Version)
+
+			assert.EqualValues(t, 10, resp.ConfigMetas.Timestamp.Version)
+			assert.EqualValues(t, 11, resp.ConfigMetas.Snapshot.Version)
+			assert.EqualValues(t, 12, resp.ConfigMetas.TopTargets.Version)
+			require.Len(t, resp.ConfigMetas.DelegatedTargets, 1)
+			assert.Equal(t, "apm", resp.ConfigMetas.DelegatedTargets[0].Role)
+			assert.EqualValues(t, 13, resp.ConfigMetas.DelegatedTargets[0].Version)
+			assert.EqualValues(t, 20, resp.DirectorMetas.Timestamp.Version)
+			assert.EqualValues(t, 21, resp.DirectorMetas.Snapshot.Version)
+			assert.EqualValues(t, 22, resp.DirectorMetas.Targets.Version)
+
+			require.Len(t, resp.TargetFiles, 1)
+			assert.Equal(t, "datadog/2/APM_SAMPLING/id/config", resp.TargetFiles[0].Path)
+			assert.Equal(t, `{"rate":0.5}`, string(resp.TargetFiles[0].Raw))
+
+			status, err := repo.FetchOrgStatus(context.Background())
+			require.NoError(t, err)
+			assert.True(t, status.Enabled && status.Authorized)
+		})
+	}
+}
+
+func TestLocalRepositoryInvalidTargetPath(t *testing.T) {
+	dir := t.TempDir()
+	writeTestRepository(t, dir, "../../etc/passwd")
+
+	repo, err := NewLocalRepository(newTestLocalConfig(), dir)
+	require.NoError(t, err)
+	_, err = repo.Fetch(context.Background(), &pbgo.LatestConfigsRequest{})
+	assert.EqualError(t, err, "invalid target path ../../etc/passwd")
+}
+
+func TestNewLocalRepository(t *testing.T) {
+	_, err := NewLocalRepository(newTestLocalConfig(), "/does/not/exist")
+	assert.ErrorContains(t, err, "invalid local remote configuration repository")
+
+	cfg := model.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	_, err = NewLocalRepository(cfg, "http://mirror.local/rc")
+	assert.ErrorContains(t, err, "TLS is required by default")
+}
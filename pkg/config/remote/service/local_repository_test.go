@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/go-tuf/data"
+	"github.com/DataDog/go-tuf/pkg/keys"
+	"github.com/DataDog/go-tuf/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+)
+
+// writeLocalRepository writes a signed repository serving a single target
+// file to dir, and returns the roots of its config and director repositories
+func writeLocalRepository(t *testing.T, dir string, targetPath string, target []byte) (string, string) {
+	hash := sha256.Sum256(target)
+	targets := data.TargetFiles{targetPath: data.TargetFileMeta{FileMeta: data.FileMeta{
+		Length: int64(len(target)),
+		Hashes: data.Hashes{"sha256": hash[:]},
+	}}}
+
+	marshal := func(meta interface{}, key keys.Signer) []byte {
+		signed, err := sign.Marshal(meta, key)
+		require.NoError(t, err)
+		raw, err := json.Marshal(signed)
+		require.NoError(t, err)
+		return raw
+	}
+	write := func(name string, content []byte) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, content, 0644))
+	}
+
+	var roots []string
+	for _, repo := range []string{"config", "director"} {
+		roleKeys := map[string]keys.Signer{}
+		root := data.NewRoot()
+		root.Version = 1
+		root.Expires = time.Now().Add(time.Hour)
+		for _, role := range []string{"root", "timestamp", "snapshot", "targets"} {
+			key, err := keys.GenerateEd25519Key()
+			require.NoError(t, err)
+			roleKeys[role] = key
+			root.AddKey(key.PublicData())
+			root.Roles[role] = &data.Role{KeyIDs: key.PublicData().IDs(), Threshold: 1}
+		}
+		rawRoot := marshal(root, roleKeys["root"])
+		roots = append(roots, string(rawRoot))
+
+		targetsMeta := data.NewTargets()
+		targetsMeta.Version = 3
+		targetsMeta.Expires = time.Now().Add(time.Hour)
+		targetsMeta.Targets = targets
+		rawTargets := marshal(targetsMeta, roleKeys["targets"])
+
+		snapshot := data.NewSnapshot()
+		snapshot.Version = 2
+		snapshot.Expires = time.Now().Add(time.Hour)
+		snapshot.Meta["targets.json"] = data.SnapshotFileMeta{Version: targetsMeta.Version}
+		rawSnapshot := marshal(snapshot, roleKeys["snapshot"])
+
+		snapshotHash := sha256.Sum256(rawSnapshot)
+		timestamp := data.NewTimestamp()
+		timestamp.Version = 2
+		timestamp.Expires = time.Now().Add(time.Hour)
+		timestamp.Meta["snapshot.json"] = data.TimestampFileMeta{Version: snapshot.Version, Length: int64(len(rawSnapshot)), Hashes: data.Hashes{"sha256": snapshotHash[:]}}
+
+		write(repo+"/1.root.json", rawRoot)
+		write(repo+"/targets.json", rawTargets)
+		write(repo+"/snapshot.json", rawSnapshot)
+		write(repo+"/timestamp.json", marshal(timestamp, roleKeys["timestamp"]))
+	}
+	write("targets/"+targetPath, target)
+	return roots[0], roots[1]
+}
+
+func TestServiceWithLocalRepository(t *testing.T) {
+	cfg := model.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	cfg.SetWithoutSource("run_path", t.TempDir())
+
+	repository := t.TempDir()
+	targetPath := "datadog/2/APM_SAMPLING/id/config"
+	configRoot, directorRoot := writeLocalRepository(t, repository, targetPath, []byte(`{"rate":0.5}`))
+
+	options := []Option{
+		WithAPIKey("abc"),
+		WithLocalRepository(repository),
+		WithConfigRootOverride("datadoghq.com", configRoot),
+		WithDirectorRootOverride("datadoghq.com", directorRoot),
+	}
+	service, err := NewService(cfg, "Remote Config", "", "localhost", nil, newMockRcTelemetryReporter(), agentVersion, options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { service.Stop() })
+
+	require.NoError(t, service.refresh())
+
+	targets, err := service.uptane.Targets()
+	require.NoError(t, err)
+	assert.Contains(t, targets, targetPath)
+	content, err := service.uptane.TargetFile(targetPath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"rate":0.5}`, string(content))
+
+	// a target that doesn't match the signed metadata is rejected
+	require.NoError(t, os.WriteFile(filepath.Join(repository, "targets", filepath.FromSlash(targetPath)), []byte(`{"rate":1.0}`), 0644))
+	assert.Error(t, service.refresh())
+}
+
+func TestServiceWithLocalRepositoryWithoutRoots(t *testing.T) {
+	cfg := model.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	cfg.SetWithoutSource("run_path", t.TempDir())
+
+	options := []Option{
+		WithAPIKey("abc"),
+		WithLocalRepository(t.TempDir()),
+	}
+	_, err := NewService(cfg, "Remote Config", "", "localhost", nil, newMockRcTelemetryReporter(), agentVersion, options...)
+	assert.ErrorContains(t, err, "the config and director roots of the local repository")
+}
@@ -162,6 +162,7 @@ type options struct {
 	databaseFileName               string
 	configRootOverride             string
 	directorRootOverride           string
+	localRepository                string
 	clientCacheBypassLimit         int
 	refresh                        time.Duration
 	refreshIntervalOverrideAllowed bool
@@ -176,6 +177,7 @@ var defaultOptions = options{
 	databaseFileName:               "remote-config.db",
 	configRootOverride:             "",
 	directorRootOverride:           "",
+	localRepository:                "",
 	clientCacheBypassLimit:         defaultCacheBypassLimit,
 	refresh:                        defaultRefreshInterval,
 	refreshIntervalOverrideAllowed: true,
@@ -212,6 +214,13 @@ func WithDirectorRootOverride(site string, override string) func(s *options) {
 	}
 }
 
+// WithLocalRepository makes the service read the configurations from a signed
+// repository stored in a local directory or served by an on-prem HTTP mirror
+// instead of the Remote Configuration backend
+func WithLocalRepository(location string) func(s *options) {
+	return func(s *options) { s.localRepository = location }
+}
+
 // WithRefreshInterval validates and sets the service refresh interval
 func WithRefreshInterval(interval time.Duration, cfgPath string) func(s *options) {
 	if interval < minimalRefreshInterval {
@@ -314,13 +323,25 @@ func NewService(cfg model.Reader, rcType, baseRawURL, hostname string, tags []st
 		return nil, err
 	}
 
-	baseURL, err := url.Parse(baseRawURL)
-	if err != nil {
-		return nil, err
-	}
-	http, err := api.NewHTTPClient(authKeys.apiAuth(), cfg, baseURL)
-	if err != nil {
-		return nil, err
+	var http api.API
+	if options.localRepository != "" {
+		// the embedded roots can't verify a repository signed by the operator
+		if options.configRootOverride == "" || options.directorRootOverride == "" {
+			return nil, fmt.Errorf("the config and director roots of the local repository %s must be provided", options.localRepository)
+		}
+		http, err = api.NewLocalRepository(cfg, options.localRepository)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		baseURL, err := url.Parse(baseRawURL)
+		if err != nil {
+			return nil, err
+		}
+		http, err = api.NewHTTPClient(authKeys.apiAuth(), cfg, baseURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	dbPath := path.Join(cfg.GetString("run_path"), options.databaseFileName)
@@ -283,6 +283,9 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("remote_configuration.no_tls_validation", false)
 	config.BindEnvAndSetDefault("remote_configuration.config_root", "")
 	config.BindEnvAndSetDefault("remote_configuration.director_root", "")
+	// Directory or URL of an on-prem mirror of a signed repository to read configurations from instead of the
+	// Datadog backend, for air-gapped environments. The config and director roots must be set.
+	config.BindEnvAndSetDefault("remote_configuration.local_repository", "")
 	config.BindEnv("remote_configuration.refresh_interval")
 	config.BindEnvAndSetDefault("remote_configuration.max_backoff_interval", 5*time.Minute)
 	config.BindEnvAndSetDefault("remote_configuration.clients.ttl_seconds", 30*time.Second)
@@ -371,6 +374,22 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("secret_backend_remove_trailing_line_break", false)
 	config.BindEnvAndSetDefault("secret_refresh_interval", 0)
 	config.SetDefault("secret_audit_file_max_size", 0)
+	config.BindEnvAndSetDefault("secret_backend_builtin_enabled", false)
+	config.BindEnvAndSetDefault("secret_backend_vault.address", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.namespace", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.auth_method", "token")
+	config.BindEnvAndSetDefault("secret_backend_vault.token", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.token_file", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.approle.mount_path", "approle")
+	config.BindEnvAndSetDefault("secret_backend_vault.approle.role_id", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.approle.secret_id", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.approle.secret_id_file", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.kubernetes.mount_path", "kubernetes")
+	config.BindEnvAndSetDefault("secret_backend_vault.kubernetes.role", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.kubernetes.token_file", "/var/run/secrets/kubernetes.io/serviceaccount/token")
+	config.BindEnvAndSetDefault("secret_backend_vault.kv_version", 0)
+	config.BindEnvAndSetDefault("secret_backend_vault.tls_ca_cert", "")
+	config.BindEnvAndSetDefault("secret_backend_vault.tls_skip_verify", false)
 
 	// Use to output logs in JSON format
 	config.BindEnvAndSetDefault("log_format_json", false)
@@ -610,6 +629,8 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("autoconfig_from_environment", true)
 	config.BindEnvAndSetDefault("autoconfig_exclude_features", []string{})
 	config.BindEnvAndSetDefault("autoconfig_include_features", []string{})
+	config.BindEnvAndSetDefault("process_listener.discovery_interval", 30*time.Second)
+	config.SetKnown("process_listener.rules")
 
 	// Docker
 	config.BindEnvAndSetDefault("docker_query_timeout", int64(5))
@@ -672,6 +693,7 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("prometheus_scrape.service_endpoints", false) // Enables Service Endpoints checks in the prometheus config provider
 	config.BindEnv("prometheus_scrape.checks")                                // Defines any extra prometheus/openmetrics check configurations to be handled by the prometheus config provider
 	config.BindEnvAndSetDefault("prometheus_scrape.version", 1)               // Version of the openmetrics check to be scheduled by the Prometheus auto-discovery
+	config.BindEnvAndSetDefault("prometheus_scrape.use_core_check", false)    // Schedules the Go openmetrics core check instead of the Python integration
 
 	// Network Devices Monitoring
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.metadata.")
@@ -1067,6 +1089,10 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("external_metrics_provider.local_copy_refresh_rate", 30)        // value in seconds
 	config.BindEnvAndSetDefault("external_metrics_provider.chunk_size", 35)                     // Maximum number of queries to batch when querying Datadog.
 	config.BindEnvAndSetDefault("external_metrics_provider.split_batches_with_backoff", false)  // Splits batches and runs queries with errors individually with an exponential backoff
+	config.BindEnvAndSetDefault("external_metrics_provider.local.enabled", false)               // Resolves the DatadogMetric queries on the selected metrics from the points forwarded by the node agents
+	config.BindEnvAndSetDefault("external_metrics_provider.local.metric_names", []string{})     // Metrics the node agents forward to the Cluster Agent
+	config.BindEnvAndSetDefault("external_metrics_provider.local.retention", 10*60)             // value in seconds. How long the Cluster Agent keeps the forwarded points
+	config.BindEnvAndSetDefault("external_metrics_provider.local.max_series", 10000)            // Maximum number of series the Cluster Agent keeps, new series are dropped once it's reached
 	pkgconfigmodel.AddOverrideFunc(sanitizeExternalMetricsProviderChunkSize)
 	// Cluster check Autodiscovery
 	config.BindEnvAndSetDefault("cluster_checks.support_hybrid_ignore_ad_tags", false) // TODO(CINT)(Agent 7.53+) Remove this flag when hybrid ignore_ad_tags is fully deprecated
@@ -1117,6 +1143,7 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnv("admission_controller.pod_owners_cache_validity")                              // Alias for admission_controller.inject_tags.pod_owners_cache_validity. Was added without the "inject_tags" prefix by mistake but needs to be kept for backwards compatibility
 	config.BindEnvAndSetDefault("admission_controller.namespace_selector_fallback", false)
 	config.BindEnvAndSetDefault("admission_controller.failure_policy", "Ignore")
+	config.BindEnvAndSetDefault("admission_controller.validation_failure_policy", "Ignore")
 	config.BindEnvAndSetDefault("admission_controller.reinvocation_policy", "IfNeeded")
 	config.BindEnvAndSetDefault("admission_controller.add_aks_selectors", false) // adds in the webhook some selectors that are required in AKS
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.enabled", true)
@@ -1155,6 +1182,12 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.image_name", "agent")
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.image_tag", "latest")
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.cluster_agent.enabled", "true")
+	config.BindEnvAndSetDefault("admission_controller.validate_tags.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.validate_tags.endpoint", "/validatetags")
+	config.BindEnvAndSetDefault("admission_controller.validate_tags.mode", "warn") // possible values: warn / deny / dryrun
+	// List of validation modes and namespace selectors
+	config.SetKnown("admission_controller.validate_tags.policies")
+	config.BindEnvAndSetDefault("admission_controller.validate_tags.required_tags", []string{"env", "service", "version"})
 
 	// Telemetry
 	// Enable telemetry metrics on the internals of the Agent.
@@ -1240,6 +1273,10 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("sbom.host.enabled", false)
 	config.BindEnvAndSetDefault("sbom.host.analyzers", []string{"os"})
 
+	// SBOM vulnerabilities configuration
+	config.BindEnvAndSetDefault("sbom.vulnerabilities.enabled", false)
+	config.BindEnvAndSetDefault("sbom.vulnerabilities.db_path", "") // defaults to db/trivy.db in sbom.cache_directory
+
 	// Orchestrator Explorer - process agent
 	// DEPRECATED in favor of `orchestrator_explorer.orchestrator_dd_url` setting. If both are set `orchestrator_explorer.orchestrator_dd_url` will take precedence.
 	config.BindEnv("process_config.orchestrator_dd_url", "DD_PROCESS_CONFIG_ORCHESTRATOR_DD_URL", "DD_PROCESS_AGENT_ORCHESTRATOR_DD_URL")
@@ -1384,6 +1421,13 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("updater.remote_updates", false)
 	config.BindEnv("updater.registry")
 	config.BindEnvAndSetDefault("updater.registry_auth", "")
+	// Experiments are watched during the soak window and stopped when unhealthy, a zero window disables the watch
+	// the soak window starts over when the installer daemon restarts during an experiment
+	config.BindEnvAndSetDefault("updater.experiment_health.soak_window", 0*time.Second)
+	config.BindEnvAndSetDefault("updater.experiment_health.check_interval", 30*time.Second)
+	config.BindEnvAndSetDefault("updater.experiment_health.failure_threshold", 3)
+	config.BindEnvAndSetDefault("updater.experiment_health.max_restarts", 3)
+	config.BindEnvAndSetDefault("updater.experiment_health.diagnose_commands", []string{})
 }
 
 // LoadProxyFromEnv overrides the proxy settings with environment variables
@@ -1508,7 +1552,7 @@ func Merge(configPaths []string, config pkgconfigmodel.Config) error {
 	return nil
 }
 
-func findUnknownKeys(config pkgconfigmodel.Config) []string {
+func findUnknownKeys(config pkgconfigmodel.Reader) []string {
 	var unknownKeys []string
 	knownKeys := config.GetKnownKeysLowercased()
 	loadedKeys := config.AllKeysLowercased()
@@ -1729,8 +1773,8 @@ func LoadCustom(config pkgconfigmodel.Config, origin string, secretResolver opti
 		return &warnings, err
 	}
 
-	for _, key := range findUnknownKeys(config) {
-		log.Warnf("Unknown key in config file: %v", key)
+	for _, issue := range ValidateConfig(config) {
+		log.Warnf("Invalid setting in config file %s: %s", config.ConfigFileUsed(), issue)
 	}
 
 	for _, v := range findUnknownEnvVars(config, os.Environ(), additionalKnownEnvVars) {
@@ -1904,9 +1948,27 @@ func ResolveSecrets(config pkgconfigmodel.Config, secretResolver secrets.Compone
 		RemoveLinebreak:  config.GetBool("secret_backend_remove_trailing_line_break"),
 		RunPath:          config.GetString("run_path"),
 		AuditFileMaxSize: config.GetInt("secret_audit_file_max_size"),
+		BuiltinBackends:  config.GetBool("secret_backend_builtin_enabled"),
+		Vault: secrets.VaultParams{
+			Address:             config.GetString("secret_backend_vault.address"),
+			Namespace:           config.GetString("secret_backend_vault.namespace"),
+			AuthMethod:          config.GetString("secret_backend_vault.auth_method"),
+			Token:               config.GetString("secret_backend_vault.token"),
+			TokenFile:           config.GetString("secret_backend_vault.token_file"),
+			AppRoleMountPath:    config.GetString("secret_backend_vault.approle.mount_path"),
+			RoleID:              config.GetString("secret_backend_vault.approle.role_id"),
+			SecretID:            config.GetString("secret_backend_vault.approle.secret_id"),
+			SecretIDFile:        config.GetString("secret_backend_vault.approle.secret_id_file"),
+			KubernetesMountPath: config.GetString("secret_backend_vault.kubernetes.mount_path"),
+			KubernetesRole:      config.GetString("secret_backend_vault.kubernetes.role"),
+			KubernetesTokenFile: config.GetString("secret_backend_vault.kubernetes.token_file"),
+			KVVersion:           config.GetInt("secret_backend_vault.kv_version"),
+			TLSCACert:           config.GetString("secret_backend_vault.tls_ca_cert"),
+			TLSSkipVerify:       config.GetBool("secret_backend_vault.tls_skip_verify"),
+		},
 	})
 
-	if config.GetString("secret_backend_command") != "" {
+	if config.GetString("secret_backend_command") != "" || config.GetBool("secret_backend_builtin_enabled") {
 		// Viper doesn't expose the final location of the file it
 		// loads. Since we are searching for 'datadog.yaml' in multiple
 		// locations we let viper determine the one to use before
@@ -39,6 +39,7 @@ require (
 	github.com/DataDog/datadog-agent/pkg/util/optional v0.54.0-rc.2
 	github.com/DataDog/datadog-agent/pkg/util/system v0.54.0-rc.2
 	github.com/DataDog/datadog-agent/pkg/util/winutil v0.54.0-rc.2
+	github.com/spf13/cast v1.5.1
 	github.com/stretchr/testify v1.9.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -83,7 +84,6 @@ require (
 	github.com/shirou/gopsutil/v3 v3.23.12 // indirect
 	github.com/shoenig/go-m1cpu v0.1.6 // indirect
 	github.com/spf13/afero v1.1.2 // indirect
-	github.com/spf13/cast v1.5.1 // indirect
 	github.com/spf13/cobra v1.7.0 // indirect
 	github.com/spf13/jwalterweatherman v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
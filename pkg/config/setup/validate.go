@@ -0,0 +1,243 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package setup
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+)
+
+// ValidationIssueKind is the kind of a problem found in a configuration file
+type ValidationIssueKind string
+
+const (
+	// ValidationUnknownKey is reported for keys the agent doesn't know about,
+	// usually typos
+	ValidationUnknownKey ValidationIssueKind = "unknown key"
+	// ValidationTypeMismatch is reported for values that can't be converted
+	// to the type of the setting
+	ValidationTypeMismatch ValidationIssueKind = "type mismatch"
+	// ValidationDeprecatedKey is reported for keys that still work but have
+	// been replaced by another one
+	ValidationDeprecatedKey ValidationIssueKind = "deprecated key"
+)
+
+// ValidationIssue is a problem found in a configuration file
+type ValidationIssue struct {
+	Kind    ValidationIssueKind
+	Key     string
+	Message string
+}
+
+// String returns a human readable description of the issue
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s '%s': %s", i.Kind, i.Key, i.Message)
+}
+
+// deprecatedKeys maps the deprecated keys of the datadog.yaml file to the key
+// replacing them
+var deprecatedKeys = map[string]string{
+	"ipc_address":                                      "cmd_host",
+	"log_enabled":                                      "logs_enabled",
+	"tracemalloc_whitelist":                            "tracemalloc_include",
+	"tracemalloc_blacklist":                            "tracemalloc_exclude",
+	"forwarder_retry_queue_max_size":                   "forwarder_retry_queue_payloads_max_size",
+	"logs_config.use_http":                             "logs_config.force_use_http",
+	"logs_config.use_tcp":                              "logs_config.force_use_tcp",
+	"process_config.orchestrator_dd_url":               "orchestrator_explorer.orchestrator_dd_url",
+	"process_config.orchestrator_additional_endpoints": "orchestrator_explorer.orchestrator_additional_endpoints",
+	"compliance_config.xccdf.enabled":                  "compliance_config.host_benchmarks.enabled",
+}
+
+// ValidateConfig checks the settings read from the configuration file of the
+// given config against the keys it knows about. It reports unknown keys, with
+// the closest known key as a suggestion, values whose type doesn't match the
+// type of the default value of their key, and deprecated keys. The issues are
+// sorted by key.
+func ValidateConfig(config pkgconfigmodel.Reader) []ValidationIssue {
+	knownKeys := config.GetKnownKeysLowercased()
+	var issues []ValidationIssue
+
+	unknownKeys := findUnknownKeys(config)
+	var candidates []string
+	if len(unknownKeys) > 0 {
+		candidates = knownKeysCandidates(knownKeys)
+	}
+	for _, key := range unknownKeys {
+		message := "this setting isn't used by the agent"
+		if suggestion := SuggestKey(key, candidates); suggestion != "" {
+			message += fmt.Sprintf(", did you mean '%s'?", suggestion)
+		}
+		issues = append(issues, ValidationIssue{Kind: ValidationUnknownKey, Key: key, Message: message})
+	}
+
+	// sections are the keys with known sub-keys, their settings are checked
+	// individually
+	sections := map[string]bool{}
+	for key := range knownKeys {
+		if strings.HasSuffix(key, ".*") {
+			continue
+		}
+		parts := strings.Split(key, ".")
+		for i := 1; i < len(parts); i++ {
+			sections[strings.Join(parts[:i], ".")] = true
+		}
+	}
+
+	var visit func(prefix string, settings map[string]interface{})
+	visit = func(prefix string, settings map[string]interface{}) {
+		for name, value := range settings {
+			key := prefix + strings.ToLower(name)
+			if nested, ok := value.(map[string]interface{}); ok && sections[key] {
+				visit(key+".", nested)
+				continue
+			}
+			if _, known := knownKeys[key]; !known {
+				continue
+			}
+			if expected, ok := checkValueType(defaultValue(config, key), value); !ok {
+				issues = append(issues, ValidationIssue{
+					Kind:    ValidationTypeMismatch,
+					Key:     key,
+					Message: fmt.Sprintf("expected %s, got %v", expected, value),
+				})
+			}
+		}
+	}
+	visit("", config.AllSourceSettingsWithoutDefault(pkgconfigmodel.SourceFile))
+
+	for key, replacement := range deprecatedKeys {
+		if config.IsSetForSource(key, pkgconfigmodel.SourceFile) {
+			issues = append(issues, ValidationIssue{
+				Kind:    ValidationDeprecatedKey,
+				Key:     key,
+				Message: fmt.Sprintf("use '%s' instead", replacement),
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Key < issues[j].Key
+	})
+	return issues
+}
+
+// knownKeysCandidates returns the known keys that can be suggested in place
+// of an unknown key, replacing the deprecated keys with their replacement
+func knownKeysCandidates(knownKeys map[string]interface{}) []string {
+	candidates := make([]string, 0, len(knownKeys))
+	for key := range knownKeys {
+		if strings.HasSuffix(key, ".*") {
+			continue
+		}
+		if replacement, deprecated := deprecatedKeys[key]; deprecated {
+			key = replacement
+		}
+		candidates = append(candidates, key)
+	}
+	return candidates
+}
+
+// defaultValue returns the default value of a key, or nil if it has none
+func defaultValue(config pkgconfigmodel.Reader, key string) interface{} {
+	for _, v := range config.GetAllSources(key) {
+		if v.Source == pkgconfigmodel.SourceDefault {
+			return v.Value
+		}
+	}
+	return nil
+}
+
+// checkValueType returns whether a value can be used for a setting whose
+// default value is defaultValue and, when it can't, a description of the
+// expected type. Values are converted the same way the config getters do, so
+// that for instance "true" is a valid boolean.
+func checkValueType(defaultValue, value interface{}) (string, bool) {
+	if defaultValue == nil || value == nil {
+		return "", true
+	}
+
+	var err error
+	switch defaultValue.(type) {
+	case bool:
+		_, err = cast.ToBoolE(value)
+		return "a boolean", err == nil
+	case time.Duration:
+		_, err = cast.ToDurationE(value)
+		return "a duration", err == nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		_, err = cast.ToInt64E(value)
+		return "an integer", err == nil
+	case float32, float64:
+		_, err = cast.ToFloat64E(value)
+		return "a number", err == nil
+	}
+
+	valueKind := reflect.TypeOf(value).Kind()
+	switch reflect.TypeOf(defaultValue).Kind() {
+	case reflect.String:
+		return "a string", valueKind != reflect.Map && valueKind != reflect.Slice
+	case reflect.Slice:
+		// strings are split on whitespaces when read as lists
+		return "a list", valueKind == reflect.Slice || valueKind == reflect.String
+	case reflect.Map:
+		return "a mapping", valueKind == reflect.Map
+	}
+	return "", true
+}
+
+// SuggestKey returns the candidate closest to an unknown key, or an empty
+// string if none is close enough to likely be what was meant
+func SuggestKey(key string, candidates []string) string {
+	// allow roughly one typo every four characters
+	maxDistance := len(key) / 4
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	suggestion := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := levenshteinDistance(key, candidate)
+		if distance < bestDistance || (distance == bestDistance && candidate < suggestion) {
+			suggestion = candidate
+			bestDistance = distance
+		}
+	}
+	return suggestion
+}
+
+// levenshteinDistance returns the minimal number of single character edits
+// required to change a into b
+func levenshteinDistance(a, b string) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		previous := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			previous = row[j]
+			row[j] = min(row[j]+1, row[j-1]+1, substitution)
+		}
+	}
+	return row[len(b)]
+}
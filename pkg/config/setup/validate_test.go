@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	conf := ConfFromYAML(`
+api_key: abcdef
+log_enable: true
+log_enabled: true
+cmd_port: not_a_port
+apm_config:
+  enabled: sometimes
+  enabeld: true
+logs_config:
+  use_http: true
+custom_section.custom_key: value
+`)
+
+	assert.Equal(t, []ValidationIssue{
+		{Kind: ValidationUnknownKey, Key: "apm_config.enabeld", Message: "this setting isn't used by the agent, did you mean 'apm_config.enabled'?"},
+		{Kind: ValidationTypeMismatch, Key: "apm_config.enabled", Message: "expected a boolean, got sometimes"},
+		{Kind: ValidationTypeMismatch, Key: "cmd_port", Message: "expected an integer, got not_a_port"},
+		{Kind: ValidationUnknownKey, Key: "custom_section.custom_key", Message: "this setting isn't used by the agent"},
+		{Kind: ValidationUnknownKey, Key: "log_enable", Message: "this setting isn't used by the agent, did you mean 'logs_enabled'?"},
+		{Kind: ValidationDeprecatedKey, Key: "log_enabled", Message: "use 'logs_enabled' instead"},
+		{Kind: ValidationDeprecatedKey, Key: "logs_config.use_http", Message: "use 'logs_config.force_use_http' instead"},
+	}, ValidateConfig(conf))
+}
+
+func TestValidateConfigValid(t *testing.T) {
+	conf := ConfFromYAML(`
+api_key: abcdef
+logs_enabled: "true"
+cmd_port: "5001"
+tags: team:agent
+forwarder_timeout: 30
+additional_endpoints:
+  "https://app.datadoghq.com":
+  - apikey2
+`)
+
+	assert.Empty(t, ValidateConfig(conf))
+}
+
+func TestSuggestKey(t *testing.T) {
+	candidates := []string{"logs_enabled", "log_level", "apm_config.enabled"}
+
+	assert.Equal(t, "logs_enabled", SuggestKey("log_enable", candidates))
+	assert.Equal(t, "log_level", SuggestKey("log_levl", candidates))
+	assert.Equal(t, "apm_config.enabled", SuggestKey("apm_confg.enabled", candidates))
+	assert.Equal(t, "", SuggestKey("site", candidates))
+}
@@ -94,6 +94,27 @@ func GetClusterChecks(w io.Writer, checkName string) error {
 	}
 	table.Flush()
 
+	// Print placement of the checks having placement constraints
+	var placements []types.PlacementResponse
+	for _, p := range cr.Placements {
+		if checkName == "" || p.CheckName == checkName {
+			placements = append(placements, p)
+		}
+	}
+	if len(placements) > 0 {
+		fmt.Fprintf(w, "\n=== %s ===\n", color.BlueString("Placement"))
+		table = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(table, "\nCheck\tNode\tReason")
+		for _, p := range placements {
+			node := p.Node
+			if node == "" {
+				node = "unassigned"
+			}
+			fmt.Fprintf(table, "%s:%s\t%s\t%s\n", p.CheckName, p.Digest, node, p.Reason)
+		}
+		table.Flush()
+	}
+
 	// Print per-node configurations
 	for _, node := range cr.Nodes {
 		if len(node.Configs) == 0 {
@@ -46,6 +46,7 @@ type Daemon interface {
 
 	GetPackage(pkg string, version string) (Package, error)
 	GetState() (map[string]repository.State, error)
+	GetExperimentsHealth() map[string]ExperimentHealth
 }
 
 type daemonImpl struct {
@@ -58,6 +59,10 @@ type daemonImpl struct {
 	catalog       catalog
 	requests      chan remoteAPIRequest
 	requestsWG    sync.WaitGroup
+
+	experimentHealth  experimentHealthConfig
+	experimentWatches map[string]*experimentWatch
+	experimentsHealth map[string]ExperimentHealth
 }
 
 func newInstaller(config config.Reader, installerBin string) installer.Installer {
@@ -84,17 +89,21 @@ func NewDaemon(rcFetcher client.ConfigFetcher, config config.Reader) (Daemon, er
 	}
 	installer := newInstaller(config, installerBin)
 	remoteUpdates := config.GetBool("updater.remote_updates")
-	return newDaemon(rc, installer, remoteUpdates), nil
+	d := newDaemon(rc, installer, remoteUpdates)
+	d.experimentHealth = newExperimentHealthConfig(config)
+	return d, nil
 }
 
 func newDaemon(rc *remoteConfig, installer installer.Installer, remoteUpdates bool) *daemonImpl {
 	i := &daemonImpl{
-		remoteUpdates: remoteUpdates,
-		rc:            rc,
-		installer:     installer,
-		requests:      make(chan remoteAPIRequest, 32),
-		catalog:       catalog{},
-		stopChan:      make(chan struct{}),
+		remoteUpdates:     remoteUpdates,
+		rc:                rc,
+		installer:         installer,
+		requests:          make(chan remoteAPIRequest, 32),
+		catalog:           catalog{},
+		stopChan:          make(chan struct{}),
+		experimentWatches: map[string]*experimentWatch{},
+		experimentsHealth: map[string]ExperimentHealth{},
 	}
 	i.refreshState(context.Background())
 	return i
@@ -120,10 +129,12 @@ func (d *daemonImpl) GetPackage(pkg string, version string) (Package, error) {
 	return catalogPackage, nil
 }
 
-// Start starts remote config and the garbage collector.
+// Start starts remote config, the garbage collector and the health watch of
+// the running experiments.
 func (d *daemonImpl) Start(_ context.Context) error {
 	d.m.Lock()
 	defer d.m.Unlock()
+	d.watchExperiments()
 	go func() {
 		for {
 			select {
@@ -158,6 +169,9 @@ func (d *daemonImpl) Stop(_ context.Context) error {
 	defer d.m.Unlock()
 	d.rc.Close()
 	close(d.stopChan)
+	for pkg := range d.experimentWatches {
+		d.unwatchExperiment(pkg)
+	}
 	d.requestsWG.Wait()
 	return nil
 }
@@ -202,6 +216,7 @@ func (d *daemonImpl) startExperiment(ctx context.Context, url string) (err error
 	if err != nil {
 		return fmt.Errorf("could not install experiment: %w", err)
 	}
+	d.watchExperiments()
 	log.Infof("Daemon: Successfully started experiment for package from %s", url)
 	return nil
 }
@@ -224,6 +239,7 @@ func (d *daemonImpl) promoteExperiment(ctx context.Context, pkg string) (err err
 	if err != nil {
 		return fmt.Errorf("could not promote experiment: %w", err)
 	}
+	d.unwatchExperiment(pkg)
 	log.Infof("Daemon: Successfully promoted experiment for package %s", pkg)
 	return nil
 }
@@ -242,6 +258,7 @@ func (d *daemonImpl) stopExperiment(ctx context.Context, pkg string) (err error)
 	defer d.refreshState(ctx)
 
 	log.Infof("Daemon: Stopping experiment for package %s", pkg)
+	d.unwatchExperiment(pkg)
 	err = d.installer.RemoveExperiment(ctx, pkg)
 	if err != nil {
 		return fmt.Errorf("could not stop experiment: %w", err)
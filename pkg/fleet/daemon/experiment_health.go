@@ -0,0 +1,346 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	agentPackage        = "datadog-agent"
+	agentExperimentUnit = "datadog-agent-exp.service"
+
+	healthProbeTimeout = 5 * time.Second
+
+	defaultExperimentCheckInterval = 30 * time.Second
+)
+
+// ExperimentHealthState is the state of the health watch of an experiment.
+type ExperimentHealthState string
+
+const (
+	// ExperimentHealthWatching is the state of an experiment in its soak window.
+	ExperimentHealthWatching ExperimentHealthState = "watching"
+	// ExperimentHealthHealthy is the state of an experiment that stayed healthy during its soak window.
+	ExperimentHealthHealthy ExperimentHealthState = "healthy"
+	// ExperimentHealthRolledBack is the state of an experiment stopped because it was unhealthy.
+	ExperimentHealthRolledBack ExperimentHealthState = "rolled_back"
+	// ExperimentHealthRollbackFailed is the state of an unhealthy experiment that could not be stopped.
+	ExperimentHealthRollbackFailed ExperimentHealthState = "rollback_failed"
+)
+
+// ExperimentHealth is the health of the last experiment of a package.
+type ExperimentHealth struct {
+	Version        string                `json:"version"`
+	State          ExperimentHealthState `json:"state"`
+	StartedAt      time.Time             `json:"started_at"`
+	SoakWindowEnd  time.Time             `json:"soak_window_end"`
+	RollbackReason string                `json:"rollback_reason,omitempty"`
+}
+
+// experimentHealthConfig configures the health watch of the experiments.
+// Experiments are watched only when the soak window is set. The soak window
+// isn't persisted, it starts over when the daemon restarts during an
+// experiment.
+type experimentHealthConfig struct {
+	soakWindow       time.Duration
+	checkInterval    time.Duration
+	failureThreshold int
+	maxRestarts      int
+	healthPort       int
+	diagnoseCommands []string
+}
+
+func newExperimentHealthConfig(config config.Reader) experimentHealthConfig {
+	c := experimentHealthConfig{
+		soakWindow:       config.GetDuration("updater.experiment_health.soak_window"),
+		checkInterval:    config.GetDuration("updater.experiment_health.check_interval"),
+		failureThreshold: config.GetInt("updater.experiment_health.failure_threshold"),
+		maxRestarts:      config.GetInt("updater.experiment_health.max_restarts"),
+		healthPort:       config.GetInt("health_port"),
+		diagnoseCommands: config.GetStringSlice("updater.experiment_health.diagnose_commands"),
+	}
+	if c.checkInterval <= 0 {
+		log.Warnf("Daemon: invalid updater.experiment_health.check_interval %s, using %s", c.checkInterval, defaultExperimentCheckInterval)
+		c.checkInterval = defaultExperimentCheckInterval
+	}
+	return c
+}
+
+// experimentHealthCheck is a health signal of an experiment, check returns an
+// error when the experiment is unhealthy.
+type experimentHealthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// experimentWatch is the health watch of a running experiment
+type experimentWatch struct {
+	pkg     string
+	version string
+	cancel  context.CancelFunc
+}
+
+// healthChecks returns the health signals of the experiment of a package: the
+// health probe and the restarts of the agent for the agent package, and the
+// custom diagnose commands for every package.
+func (c experimentHealthConfig) healthChecks(ctx context.Context, pkg string) []experimentHealthCheck {
+	var checks []experimentHealthCheck
+	if pkg == agentPackage {
+		if c.healthPort > 0 {
+			checks = append(checks, healthProbeCheck(fmt.Sprintf("http://localhost:%d/live", c.healthPort)))
+		}
+		if check, err := unitRestartsCheck(ctx, agentExperimentUnit, c.maxRestarts); err != nil {
+			log.Warnf("Daemon: restarts of %s won't be watched: %v", agentExperimentUnit, err)
+		} else {
+			checks = append(checks, check)
+		}
+	}
+	for _, command := range c.diagnoseCommands {
+		checks = append(checks, diagnoseCommandCheck(command))
+	}
+	return checks
+}
+
+// healthProbeCheck fails when the health probe of the agent doesn't report it
+// as live
+func healthProbeCheck(url string) experimentHealthCheck {
+	client := &http.Client{Timeout: healthProbeTimeout}
+	return experimentHealthCheck{
+		name: "health probe",
+		check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("%s returned %s", url, resp.Status)
+			}
+			return nil
+		},
+	}
+}
+
+// unitRestartsCheck fails when a systemd unit restarted more than maxRestarts
+// times since the check was created
+func unitRestartsCheck(ctx context.Context, unit string, maxRestarts int) (experimentHealthCheck, error) {
+	baseline, err := unitRestarts(ctx, unit)
+	if err != nil {
+		return experimentHealthCheck{}, err
+	}
+	return experimentHealthCheck{
+		name: "process restarts",
+		check: func(ctx context.Context) error {
+			restarts, err := unitRestarts(ctx, unit)
+			if err != nil {
+				return err
+			}
+			if restarts-baseline > maxRestarts {
+				return fmt.Errorf("%s restarted %d times", unit, restarts-baseline)
+			}
+			return nil
+		},
+	}, nil
+}
+
+func unitRestarts(ctx context.Context, unit string) (int, error) {
+	output, err := exec.CommandContext(ctx, "systemctl", "show", "--property=NRestarts", "--value", unit).Output()
+	if err != nil {
+		return 0, fmt.Errorf("could not get the restarts of %s: %w", unit, err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
+// diagnoseCommandCheck fails when a custom diagnose command exits with an error
+func diagnoseCommandCheck(command string) experimentHealthCheck {
+	return experimentHealthCheck{
+		name: fmt.Sprintf("diagnose command '%s'", command),
+		check: func(ctx context.Context) error {
+			output, err := exec.CommandContext(ctx, "sh", "-c", command).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+			}
+			return nil
+		},
+	}
+}
+
+// GetExperimentsHealth returns the health of the last experiment of each package.
+func (d *daemonImpl) GetExperimentsHealth() map[string]ExperimentHealth {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	health := make(map[string]ExperimentHealth, len(d.experimentsHealth))
+	for pkg, h := range d.experimentsHealth {
+		health[pkg] = h
+	}
+	return health
+}
+
+// watchExperiments starts watching the running experiments that aren't
+// watched yet. It must be called with the daemon lock held.
+func (d *daemonImpl) watchExperiments() {
+	if d.experimentHealth.soakWindow <= 0 {
+		return
+	}
+	states, err := d.installer.States()
+	if err != nil {
+		log.Errorf("Daemon: could not get installer state to watch experiments: %v", err)
+		return
+	}
+	for pkg, state := range states {
+		if state.Experiment == "" {
+			continue
+		}
+		if w, ok := d.experimentWatches[pkg]; ok && w.version == state.Experiment {
+			continue
+		}
+		d.unwatchExperiment(pkg)
+		d.watchExperiment(pkg, state.Experiment)
+	}
+}
+
+func (d *daemonImpl) watchExperiment(pkg string, version string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &experimentWatch{pkg: pkg, version: version, cancel: cancel}
+	now := time.Now()
+	d.experimentWatches[pkg] = w
+	d.experimentsHealth[pkg] = ExperimentHealth{
+		Version:       version,
+		State:         ExperimentHealthWatching,
+		StartedAt:     now,
+		SoakWindowEnd: now.Add(d.experimentHealth.soakWindow),
+	}
+	log.Infof("Daemon: Watching the health of experiment %s for package %s during %s", version, pkg, d.experimentHealth.soakWindow)
+	go d.runExperimentWatch(ctx, w)
+}
+
+// unwatchExperiment stops watching the experiment of a package, forgetting its
+// health unless the watch is over. It must be called with the daemon lock held.
+func (d *daemonImpl) unwatchExperiment(pkg string) {
+	w, ok := d.experimentWatches[pkg]
+	if !ok {
+		return
+	}
+	w.cancel()
+	delete(d.experimentWatches, pkg)
+	if d.experimentsHealth[pkg].State == ExperimentHealthWatching {
+		delete(d.experimentsHealth, pkg)
+	}
+}
+
+func (d *daemonImpl) runExperimentWatch(ctx context.Context, w *experimentWatch) {
+	span, _ := tracer.StartSpanFromContext(context.Background(), "watch_experiment")
+	span.SetTag("package", w.pkg)
+	span.SetTag("experiment_version", w.version)
+	outcome := "stopped"
+	defer func() {
+		span.SetTag("outcome", outcome)
+		span.Finish()
+	}()
+
+	checks := d.experimentHealth.healthChecks(ctx, w.pkg)
+	failures := make([]int, len(checks))
+	deadline := time.NewTimer(d.experimentHealth.soakWindow)
+	defer deadline.Stop()
+	ticker := time.NewTicker(d.experimentHealth.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			if d.endExperimentWatch(w, ExperimentHealthHealthy, "") {
+				outcome = string(ExperimentHealthHealthy)
+				log.Infof("Daemon: Experiment %s for package %s stayed healthy during its soak window", w.version, w.pkg)
+			}
+			return
+		case <-ticker.C:
+			for i, c := range checks {
+				err := c.check(ctx)
+				if ctx.Err() != nil {
+					return
+				}
+				if err == nil {
+					failures[i] = 0
+					continue
+				}
+				failures[i]++
+				log.Warnf("Daemon: Health check %s of experiment %s for package %s failed (%d/%d): %v", c.name, w.version, w.pkg, failures[i], d.experimentHealth.failureThreshold, err)
+				if failures[i] < d.experimentHealth.failureThreshold {
+					continue
+				}
+				reason := fmt.Sprintf("%s failed: %v", c.name, err)
+				span.SetTag("rollback_reason", reason)
+				outcome = string(d.rollbackExperiment(w, reason))
+				return
+			}
+		}
+	}
+}
+
+// endExperimentWatch records the final state of a watch, it returns false if
+// the watch was cancelled meanwhile
+func (d *daemonImpl) endExperimentWatch(w *experimentWatch, state ExperimentHealthState, reason string) bool {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.setExperimentWatchResult(w, state, reason)
+}
+
+func (d *daemonImpl) setExperimentWatchResult(w *experimentWatch, state ExperimentHealthState, reason string) bool {
+	if d.experimentWatches[w.pkg] != w {
+		return false
+	}
+	delete(d.experimentWatches, w.pkg)
+	health := d.experimentsHealth[w.pkg]
+	health.State = state
+	health.RollbackReason = reason
+	d.experimentsHealth[w.pkg] = health
+	return true
+}
+
+// rollbackExperiment stops an unhealthy experiment, restoring the stable version
+// of the package
+func (d *daemonImpl) rollbackExperiment(w *experimentWatch, reason string) ExperimentHealthState {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if !d.setExperimentWatchResult(w, ExperimentHealthRolledBack, reason) {
+		return "stopped"
+	}
+
+	log.Errorf("Daemon: Experiment %s for package %s is unhealthy, rolling back: %s", w.version, w.pkg, reason)
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "rollback_experiment")
+	span.SetTag("package", w.pkg)
+	span.SetTag("experiment_version", w.version)
+	span.SetTag("rollback_reason", reason)
+	err := d.stopExperiment(ctx, w.pkg)
+	span.Finish(tracer.WithError(err))
+	if err != nil {
+		log.Errorf("Daemon: could not roll back experiment %s for package %s: %v", w.version, w.pkg, err)
+		health := d.experimentsHealth[w.pkg]
+		health.State = ExperimentHealthRollbackFailed
+		health.RollbackReason = fmt.Sprintf("%s (rollback failed: %v)", reason, err)
+		d.experimentsHealth[w.pkg] = health
+		return ExperimentHealthRollbackFailed
+	}
+	return ExperimentHealthRolledBack
+}
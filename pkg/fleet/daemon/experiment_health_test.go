@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// for now the installer is not supported on windows
+//go:build !windows
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/fleet/installer/repository"
+)
+
+const testExperimentURL = "oci://example.com/test-package@sha256:5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"
+
+func newTestWatchedInstaller(soakWindow time.Duration, diagnoseCommand string) *testInstaller {
+	pm := &testPackageManager{}
+	pm.On("States").Return(map[string]repository.State{
+		"test-package": {Stable: "1.0.0", Experiment: "2.0.0"},
+	}, nil)
+	rcc := newTestRemoteConfigClient()
+	d := newDaemon(&remoteConfig{client: rcc}, pm, true)
+	d.experimentHealth = experimentHealthConfig{
+		soakWindow:       soakWindow,
+		checkInterval:    10 * time.Millisecond,
+		failureThreshold: 2,
+		diagnoseCommands: []string{diagnoseCommand},
+	}
+	return &testInstaller{daemonImpl: d, rcc: rcc, pm: pm}
+}
+
+func waitExperimentHealthState(t *testing.T, i *testInstaller, state ExperimentHealthState) ExperimentHealth {
+	var health ExperimentHealth
+	require.Eventually(t, func() bool {
+		health = i.GetExperimentsHealth()["test-package"]
+		return health.State == state
+	}, 5*time.Second, 10*time.Millisecond)
+	return health
+}
+
+func TestExperimentHealthRollback(t *testing.T) {
+	i := newTestWatchedInstaller(time.Hour, "echo broken; exit 1")
+	i.Start(context.Background())
+	defer i.Stop()
+
+	i.pm.On("InstallExperiment", mock.Anything, testExperimentURL).Return(nil).Once()
+	i.pm.On("RemoveExperiment", mock.Anything, "test-package").Return(nil).Once()
+	require.NoError(t, i.StartExperiment(context.Background(), testExperimentURL))
+
+	health := waitExperimentHealthState(t, i, ExperimentHealthRolledBack)
+	assert.Equal(t, "2.0.0", health.Version)
+	assert.Equal(t, "diagnose command 'echo broken; exit 1' failed: exit status 1: broken", health.RollbackReason)
+	i.pm.AssertExpectations(t)
+}
+
+func TestExperimentHealthRollbackFailed(t *testing.T) {
+	i := newTestWatchedInstaller(time.Hour, "exit 1")
+	i.Start(context.Background())
+	defer i.Stop()
+
+	i.pm.On("InstallExperiment", mock.Anything, testExperimentURL).Return(nil).Once()
+	i.pm.On("RemoveExperiment", mock.Anything, "test-package").Return(errors.New("disk full")).Once()
+	require.NoError(t, i.StartExperiment(context.Background(), testExperimentURL))
+
+	health := waitExperimentHealthState(t, i, ExperimentHealthRollbackFailed)
+	assert.Equal(t, "diagnose command 'exit 1' failed: exit status 1:  (rollback failed: could not stop experiment: disk full)", health.RollbackReason)
+	i.pm.AssertExpectations(t)
+}
+
+func TestExperimentHealthHealthy(t *testing.T) {
+	i := newTestWatchedInstaller(50*time.Millisecond, "true")
+	i.Start(context.Background())
+	defer i.Stop()
+
+	i.pm.On("InstallExperiment", mock.Anything, testExperimentURL).Return(nil).Once()
+	require.NoError(t, i.StartExperiment(context.Background(), testExperimentURL))
+
+	health := waitExperimentHealthState(t, i, ExperimentHealthHealthy)
+	assert.Empty(t, health.RollbackReason)
+	assert.Equal(t, health.StartedAt.Add(50*time.Millisecond), health.SoakWindowEnd)
+	i.pm.AssertNotCalled(t, "RemoveExperiment", mock.Anything, mock.Anything)
+}
+
+func TestExperimentHealthStoppedExperiment(t *testing.T) {
+	i := newTestWatchedInstaller(time.Hour, "true")
+	// the experiment running when the daemon starts is watched
+	i.Start(context.Background())
+	defer i.Stop()
+	assert.Equal(t, ExperimentHealthWatching, i.GetExperimentsHealth()["test-package"].State)
+
+	i.pm.On("RemoveExperiment", mock.Anything, "test-package").Return(nil).Once()
+	require.NoError(t, i.StopExperiment(context.Background(), "test-package"))
+	assert.Empty(t, i.GetExperimentsHealth())
+	assert.Empty(t, i.experimentWatches)
+}
+
+func TestExperimentHealthDisabled(t *testing.T) {
+	i := newTestWatchedInstaller(0, "exit 1")
+	i.Start(context.Background())
+	defer i.Stop()
+
+	i.pm.On("InstallExperiment", mock.Anything, testExperimentURL).Return(nil).Once()
+	require.NoError(t, i.StartExperiment(context.Background(), testExperimentURL))
+	assert.Empty(t, i.GetExperimentsHealth())
+}
+
+func TestExperimentHealthConfigInvalidCheckInterval(t *testing.T) {
+	cfg := pkgconfig.Mock(t)
+	cfg.SetWithoutSource("updater.experiment_health.check_interval", 0)
+
+	c := newExperimentHealthConfig(cfg)
+	assert.Equal(t, defaultExperimentCheckInterval, c.checkInterval)
+}
+
+func TestHealthProbeCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/live", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := healthProbeCheck(server.URL + "/live")
+	assert.NoError(t, check.check(context.Background()))
+
+	status = http.StatusInternalServerError
+	assert.EqualError(t, check.check(context.Background()), server.URL+"/live returned 500 Internal Server Error")
+}
@@ -29,8 +29,9 @@ const (
 // StatusResponse is the response to the status endpoint.
 type StatusResponse struct {
 	APIResponse
-	Version  string                      `json:"version"`
-	Packages map[string]repository.State `json:"packages"`
+	Version           string                      `json:"version"`
+	Packages          map[string]repository.State `json:"packages"`
+	ExperimentsHealth map[string]ExperimentHealth `json:"experiments_health"`
 }
 
 // APIResponse is the response to an API request.
@@ -117,8 +118,9 @@ func (l *localAPIImpl) status(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 	response = StatusResponse{
-		Version:  version.AgentVersion,
-		Packages: pacakges,
+		Version:           version.AgentVersion,
+		Packages:          pacakges,
+		ExperimentsHealth: l.daemon.GetExperimentsHealth(),
 	}
 }
 
@@ -13,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/fleet/installer/repository"
 	"github.com/DataDog/datadog-agent/pkg/version"
@@ -65,6 +66,11 @@ func (m *testDaemon) GetState() (map[string]repository.State, error) {
 	return args.Get(0).(map[string]repository.State), args.Error(1)
 }
 
+func (m *testDaemon) GetExperimentsHealth() map[string]ExperimentHealth {
+	args := m.Called()
+	return args.Get(0).(map[string]ExperimentHealth)
+}
+
 type testLocalAPI struct {
 	i *testDaemon
 	s *localAPIImpl
@@ -102,7 +108,17 @@ func TestAPIStatus(t *testing.T) {
 			Experiment: "2.0.0",
 		},
 	}
+	experimentsHealth := map[string]ExperimentHealth{
+		"pkg1": {
+			Version:        "2.0.0",
+			State:          ExperimentHealthRolledBack,
+			StartedAt:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			SoakWindowEnd:  time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+			RollbackReason: "health probe failed",
+		},
+	}
 	api.i.On("GetState").Return(installerState, nil)
+	api.i.On("GetExperimentsHealth").Return(experimentsHealth)
 
 	resp, err := api.c.Status()
 
@@ -110,6 +126,7 @@ func TestAPIStatus(t *testing.T) {
 	assert.Nil(t, resp.Error)
 	assert.Equal(t, version.AgentVersion, resp.Version)
 	assert.Equal(t, installerState, resp.Packages)
+	assert.Equal(t, experimentsHealth, resp.ExperimentsHealth)
 }
 
 func TestAPIInstall(t *testing.T) {
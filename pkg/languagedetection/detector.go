@@ -9,9 +9,12 @@ package languagedetection
 import (
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/patrickmn/go-cache"
+
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/languagedetection/internal/detectors"
 	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
@@ -84,6 +87,16 @@ func languageNameFromCommand(command string) languagemodels.LanguageName {
 
 const subsystem = "language_detection"
 
+const versionCacheTTL = 10 * time.Minute
+
+// versionCache holds the runtime versions detected by the system probe, keyed by pid and executable, so that the
+// system probe is queried once per process
+var versionCache = cache.New(versionCacheTTL, versionCacheTTL)
+
+func versionCacheKey(pid int32, exePath string) string {
+	return strconv.FormatInt(int64(pid), 10) + ":" + exePath
+}
+
 var (
 	detectLanguageRuntimeMs = telemetry.NewHistogram(subsystem, "detect_language_ms", nil,
 		"The amount of time it took for the call to DetectLanguage to complete.", nil)
@@ -100,6 +113,9 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 
 	langs := make([]*languagemodels.Language, len(procs))
 	unknownPids := make([]int32, 0, len(procs))
+	// unversionedPids are the pids of processes whose language is known, but not its runtime version
+	unversionedPids := make([]int32, 0, len(procs))
+	versionKeys := make(map[int32]string, len(procs))
 	langsToModify := make(map[int32]*languagemodels.Language, len(procs))
 	for i, proc := range procs {
 		// Language-specific detectors should precede matches on the command/exe
@@ -120,8 +136,8 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 			continue
 		}
 
-		exe := getExe(proc.GetCmdline())
-		languageName := languageNameFromCommand(exe)
+		exePath := getExePath(proc.GetCmdline())
+		languageName := languageNameFromCommand(normalizeExe(exePath))
 		if languageName == languagemodels.Unknown {
 			languageName = languageNameFromCommand(proc.GetCommand())
 		}
@@ -130,7 +146,22 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 		if lang.Name == languagemodels.Unknown {
 			unknownPids = append(unknownPids, proc.GetPid())
 			langsToModify[proc.GetPid()] = lang
+			continue
+		}
+
+		lang.Version = detectors.VersionFromPath(lang.Name, exePath)
+		if lang.Version != "" || !detectors.SupportsVersionDetection(lang.Name) {
+			continue
 		}
+
+		key := versionCacheKey(proc.GetPid(), exePath)
+		if version, found := versionCache.Get(key); found {
+			lang.Version = version.(string)
+			continue
+		}
+		unversionedPids = append(unversionedPids, proc.GetPid())
+		langsToModify[proc.GetPid()] = lang
+		versionKeys[proc.GetPid()] = key
 	}
 
 	if privilegedLanguageDetectionEnabled(sysprobeConfig) {
@@ -148,19 +179,37 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 			return langs
 		}
 
-		privilegedLangs, err := util.DetectLanguage(unknownPids)
+		// unknown pids come first so that the languages of unversioned pids never shift the detected languages
+		privilegedLangs, err := util.DetectLanguage(append(unknownPids, unversionedPids...))
 		if err != nil {
 			log.Warn("[language detection] Failed to request language:", err)
 			return langs
 		}
 
-		for i, pid := range unknownPids {
-			*langsToModify[pid] = privilegedLangs[i]
+		applyPrivilegedLanguages(langsToModify, unknownPids, unversionedPids, privilegedLangs)
+		// versions the system probe can't detect are cached as well, so that it isn't queried again
+		for _, pid := range unversionedPids {
+			versionCache.SetDefault(versionKeys[pid], langsToModify[pid].Version)
 		}
 	}
 	return langs
 }
 
+// applyPrivilegedLanguages updates the languages of unknown pids, and the versions of unversioned pids, with the
+// languages detected by the system probe for the unknown pids followed by the unversioned pids.
+func applyPrivilegedLanguages(langsToModify map[int32]*languagemodels.Language, unknownPids, unversionedPids []int32, privilegedLangs []languagemodels.Language) {
+	for i, pid := range unknownPids {
+		*langsToModify[pid] = privilegedLangs[i]
+	}
+	for i, pid := range unversionedPids {
+		lang, privilegedLang := langsToModify[pid], privilegedLangs[len(unknownPids)+i]
+		// a version detected for another language, e.g. when the binary was replaced, is ignored
+		if privilegedLang.Name == lang.Name {
+			lang.Version = privilegedLang.Version
+		}
+	}
+}
+
 func privilegedLanguageDetectionEnabled(sysProbeConfig config.Reader) bool {
 	if sysProbeConfig == nil {
 		return false
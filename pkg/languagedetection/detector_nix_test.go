@@ -21,12 +21,42 @@ func TestDetectLanguage(t *testing.T) {
 		cmdline  []string
 		comm     string
 		expected languagemodels.LanguageName
+		version  string
 	}{
 		{
 			name:     "python2",
 			cmdline:  []string{"/opt/Python/2.7.11/bin/python2.7", "/opt/foo/bar/baz", "--config=asdf"},
 			comm:     "baz",
 			expected: languagemodels.Python,
+			version:  "2.7.11",
+		},
+		{
+			name:     "versioned python executable",
+			cmdline:  []string{"/usr/bin/python3.11", "-m", "http.server"},
+			comm:     "python3.11",
+			expected: languagemodels.Python,
+			version:  "3.11",
+		},
+		{
+			name:     "java from jvm directory",
+			cmdline:  []string{"/usr/lib/jvm/java-17-openjdk-amd64/bin/java", "-jar", "app.jar"},
+			comm:     "java",
+			expected: languagemodels.Java,
+			version:  "17",
+		},
+		{
+			name:     "node installed by nvm",
+			cmdline:  []string{"/root/.nvm/versions/node/v18.17.0/bin/node", "index.js"},
+			comm:     "node",
+			expected: languagemodels.Node,
+			version:  "18.17.0",
+		},
+		{
+			name:     "ruby installed by rbenv",
+			cmdline:  []string{"/root/.rbenv/versions/3.2.2/bin/ruby", "prog.rb"},
+			comm:     "ruby",
+			expected: languagemodels.Ruby,
+			version:  "3.2.2",
 		},
 		{
 			name:     "Java",
@@ -115,7 +145,7 @@ func TestDetectLanguage(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			process := []languagemodels.Process{makeProcess(tc.cmdline, tc.comm)}
-			expected := []*languagemodels.Language{{Name: tc.expected}}
+			expected := []*languagemodels.Language{{Name: tc.expected, Version: tc.version}}
 			assert.Equal(t, expected, DetectLanguage(process, nil))
 		})
 	}
@@ -303,3 +333,18 @@ func BenchmarkDetectLanguage(b *testing.B) {
 		DetectLanguage(procs, nil)
 	}
 }
+
+func TestDetectLanguageCachedVersion(t *testing.T) {
+	python := makeProcess([]string{"/usr/bin/python", "-m", "http.server"}, "python")
+	versionCache.SetDefault(versionCacheKey(python.Pid, "/usr/bin/python"), "3.11.4")
+	t.Cleanup(versionCache.Flush)
+
+	// the version cached for another executable of the same pid is ignored
+	other := makeProcess([]string{"/usr/local/bin/python", "-m", "http.server"}, "python")
+	other.Pid = python.Pid
+
+	assert.Equal(t, []*languagemodels.Language{
+		{Name: languagemodels.Python, Version: "3.11.4"},
+		{Name: languagemodels.Python},
+	}, DetectLanguage([]languagemodels.Process{python, other}, nil))
+}
@@ -7,7 +7,11 @@ package languagedetection
 
 import (
 	"math/rand"
+	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
 	"github.com/DataDog/datadog-agent/pkg/process/procutil"
 )
 
@@ -18,3 +22,26 @@ func makeProcess(cmdline []string, comm string) *procutil.Process {
 		Comm:    comm,
 	}
 }
+
+func TestApplyPrivilegedLanguages(t *testing.T) {
+	langsToModify := map[int32]*languagemodels.Language{
+		1: {Name: languagemodels.Unknown},
+		2: {Name: languagemodels.Unknown},
+		3: {Name: languagemodels.Python},
+		4: {Name: languagemodels.Node},
+	}
+
+	applyPrivilegedLanguages(langsToModify, []int32{1, 2}, []int32{3, 4}, []languagemodels.Language{
+		{Name: languagemodels.Go, Version: "go1.21.3"},
+		{},
+		{Name: languagemodels.Python, Version: "3.11.4"},
+		{Name: languagemodels.Java, Version: "21.0.1"},
+	})
+
+	assert.Equal(t, map[int32]*languagemodels.Language{
+		1: {Name: languagemodels.Go, Version: "go1.21.3"},
+		2: {Name: languagemodels.Unknown},
+		3: {Name: languagemodels.Python, Version: "3.11.4"},
+		4: {Name: languagemodels.Node},
+	}, langsToModify)
+}
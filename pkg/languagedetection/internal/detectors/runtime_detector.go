@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+// interpreterPrefixes maps the prefix of an interpreter binary name to its language
+var interpreterPrefixes = []struct {
+	prefix   string
+	language languagemodels.LanguageName
+}{
+	{prefix: "python", language: languagemodels.Python},
+	{prefix: "ruby", language: languagemodels.Ruby},
+	{prefix: "node", language: languagemodels.Node},
+	{prefix: "java", language: languagemodels.Java},
+}
+
+// RuntimeDetector is a languagemodels.Detector that detects the runtime version of interpreted languages
+// by resolving the binary of the process within its own root filesystem.
+type RuntimeDetector struct {
+	hostProc string
+}
+
+// NewRuntimeDetector returns a new RuntimeDetector
+func NewRuntimeDetector() RuntimeDetector {
+	return RuntimeDetector{hostProc: kernel.ProcFSRoot()}
+}
+
+// DetectLanguage detects if a process runs a python, ruby, node or java interpreter, and its version.
+// The version is read from the `release` file of the JVM home for java, and from the path of the resolved
+// interpreter binary otherwise, falling back to the shared libraries it links for python and ruby.
+// If the process is not an interpreter, languagemodels.Unknown is returned.
+func (d RuntimeDetector) DetectLanguage(process languagemodels.Process) (languagemodels.Language, error) {
+	procPath := path.Join(d.hostProc, strconv.FormatInt(int64(process.GetPid()), 10))
+	exePath, err := os.Readlink(path.Join(procPath, "exe"))
+	if err != nil {
+		return languagemodels.Language{}, fmt.Errorf("readlink: %v", err)
+	}
+
+	language := interpreterLanguage(path.Base(exePath))
+	if language == languagemodels.Unknown {
+		return languagemodels.Language{Name: languagemodels.Unknown}, nil
+	}
+
+	version := ""
+	if language == languagemodels.Java {
+		version = readJavaVersion(path.Join(procPath, "root"), exePath)
+	}
+	if version == "" {
+		version = VersionFromPath(language, exePath)
+	}
+	if version == "" {
+		version = readLinkedVersion(path.Join(procPath, "root"), exePath, language)
+	}
+
+	return languagemodels.Language{
+		Name:    language,
+		Version: version,
+	}, nil
+}
+
+func interpreterLanguage(binary string) languagemodels.LanguageName {
+	for _, interpreter := range interpreterPrefixes {
+		if binary == interpreter.prefix {
+			return interpreter.language
+		}
+		// only versioned binaries like python3.11 or ruby3.1 are accepted to avoid matching javac or nodemon
+		if rest, found := strings.CutPrefix(binary, interpreter.prefix); found && rest[0] >= '0' && rest[0] <= '9' {
+			return interpreter.language
+		}
+	}
+	return languagemodels.Unknown
+}
+
+// readJavaVersion reads the version of the JVM running the java binary at exePath. The `release` file is located
+// in the JVM home, or in the parent of the JRE home for java 8 and earlier.
+func readJavaVersion(root string, exePath string) string {
+	javaHome := path.Dir(path.Dir(exePath))
+	for _, dir := range []string{javaHome, path.Dir(javaHome)} {
+		f, err := os.Open(path.Join(root, dir, "release"))
+		if err != nil {
+			continue
+		}
+		version := parseJavaReleaseFile(f)
+		f.Close()
+		if version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// readLinkedVersion reads the runtime version from the name of the shared libraries linked by the interpreter
+// at exePath, like libpython3.11.so.1.0 or libruby.so.3.2.
+func readLinkedVersion(root string, exePath string, language languagemodels.LanguageName) string {
+	f, err := elf.Open(path.Join(root, exePath))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	libraries, err := f.ImportedLibraries()
+	if err != nil {
+		return ""
+	}
+	return VersionFromLibraries(language, libraries)
+}
@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/proto/pbgo/languagedetection"
+)
+
+// makeFakeProc creates a fake procfs entry for pid 1 whose binary is exePath within the process root
+func makeFakeProc(t *testing.T, exePath string, files map[string]string) string {
+	hostProc := t.TempDir()
+	procPath := filepath.Join(hostProc, "1")
+	require.NoError(t, os.MkdirAll(procPath, 0o755))
+	require.NoError(t, os.Symlink(exePath, filepath.Join(procPath, "exe")))
+	for name, content := range files {
+		path := filepath.Join(procPath, "root", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return hostProc
+}
+
+func TestRuntimeDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		exePath  string
+		files    map[string]string
+		expected languagemodels.Language
+	}{
+		{
+			name:    "java from release file",
+			exePath: "/opt/java/openjdk/bin/java",
+			files: map[string]string{
+				"/opt/java/openjdk/release": "IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\"21.0.1\"\n",
+			},
+			expected: languagemodels.Language{Name: languagemodels.Java, Version: "21.0.1"},
+		},
+		{
+			name:    "java 8 jre from release file",
+			exePath: "/usr/lib/jvm/openjdk8/jre/bin/java",
+			files: map[string]string{
+				"/usr/lib/jvm/openjdk8/release": "JAVA_VERSION=\"1.8.0_382\"\n",
+			},
+			expected: languagemodels.Language{Name: languagemodels.Java, Version: "1.8.0_382"},
+		},
+		{
+			name:     "java without release file",
+			exePath:  "/usr/lib/jvm/java-17-openjdk-amd64/bin/java",
+			expected: languagemodels.Language{Name: languagemodels.Java, Version: "17"},
+		},
+		{
+			name:     "resolved python interpreter",
+			exePath:  "/usr/bin/python3.11",
+			expected: languagemodels.Language{Name: languagemodels.Python, Version: "3.11"},
+		},
+		{
+			name:     "node without version",
+			exePath:  "/usr/local/bin/node",
+			expected: languagemodels.Language{Name: languagemodels.Node},
+		},
+		{
+			name:     "javac is not an interpreter",
+			exePath:  "/usr/lib/jvm/java-17-openjdk-amd64/bin/javac",
+			expected: languagemodels.Language{Name: languagemodels.Unknown},
+		},
+		{
+			name:     "nodemon is not an interpreter",
+			exePath:  "/usr/local/bin/nodemon",
+			expected: languagemodels.Language{Name: languagemodels.Unknown},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := RuntimeDetector{hostProc: makeFakeProc(t, tc.exePath, tc.files)}
+			lang, err := d.DetectLanguage(&languagedetection.Process{Pid: 1})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lang)
+		})
+	}
+}
+
+func TestRuntimeDetectorMissingProcess(t *testing.T) {
+	d := RuntimeDetector{hostProc: t.TempDir()}
+	_, err := d.DetectLanguage(&languagedetection.Process{Pid: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package detectors
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+)
+
+// versionPatterns maps languages to the patterns matching the runtime version in the path of an interpreter.
+// Patterns are ordered from the most to the least precise, the first match wins.
+var versionPatterns = map[languagemodels.LanguageName][]*regexp.Regexp{
+	languagemodels.Python: {
+		// pyenv, asdf, or custom installs: /root/.pyenv/versions/3.11.4/bin/python, /opt/Python/2.7.11/bin/python2.7
+		regexp.MustCompile(`(?:^|/)(?:[Pp]ython[-/@]?|versions/)(\d+\.\d+(?:\.\d+)?)(?:/|$)`),
+		// versioned executables: /usr/bin/python3.11
+		regexp.MustCompile(`(?:^|/)python(\d+\.\d+)[a-z]?$`),
+	},
+	languagemodels.Ruby: {
+		// rbenv, rvm, or ruby-install: /opt/rubies/ruby-3.2.2/bin/ruby, /root/.rbenv/versions/3.2.2/bin/ruby
+		regexp.MustCompile(`(?:^|/)(?:ruby[-/@]?|versions/)(\d+\.\d+(?:\.\d+)?)(?:/|$)`),
+		// versioned executables: /usr/bin/ruby3.1
+		regexp.MustCompile(`(?:^|/)ruby(\d+\.\d+)$`),
+	},
+	languagemodels.Node: {
+		// nvm or release archives: /root/.nvm/versions/node/v18.17.0/bin/node, /opt/node-v20.9.0-linux-x64/bin/node
+		regexp.MustCompile(`(?:^|/)node[-/]v(\d+\.\d+\.\d+)(?:[-/]|$)`),
+	},
+	languagemodels.Java: {
+		// JVM installs: /usr/lib/jvm/java-17-openjdk-amd64/bin/java, /usr/lib/jvm/jdk-17.0.8+7/bin/java
+		regexp.MustCompile(`(?:^|/)(?:java|jdk|jre)-?(\d+(?:\.\d+)*)(?:[-+_/]|$)`),
+	},
+}
+
+// libraryPatterns maps languages to the patterns matching the runtime version in the name of the shared libraries
+// linked by an interpreter, for the interpreters installed without a version in their path.
+var libraryPatterns = map[languagemodels.LanguageName]*regexp.Regexp{
+	// libpython3.11.so.1.0
+	languagemodels.Python: regexp.MustCompile(`^libpython(\d+\.\d+)[a-z]?\.so`),
+	// libruby.so.3.2, libruby-3.2.so.3.2
+	languagemodels.Ruby: regexp.MustCompile(`^libruby(?:-\d+\.\d+)?\.so\.(\d+\.\d+(?:\.\d+)?)`),
+}
+
+// SupportsVersionDetection returns whether the runtime version of a language can be detected
+func SupportsVersionDetection(language languagemodels.LanguageName) bool {
+	_, found := versionPatterns[language]
+	return found
+}
+
+// VersionFromPath returns the runtime version of a language found in the path of its interpreter.
+// If no version is found, an empty string is returned.
+func VersionFromPath(language languagemodels.LanguageName, path string) string {
+	for _, pattern := range versionPatterns[language] {
+		if matches := pattern.FindStringSubmatch(path); len(matches) == 2 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// VersionFromLibraries returns the runtime version of a language found in the shared libraries linked by its
+// interpreter. If no version is found, an empty string is returned.
+func VersionFromLibraries(language languagemodels.LanguageName, libraries []string) string {
+	pattern, found := libraryPatterns[language]
+	if !found {
+		return ""
+	}
+	for _, library := range libraries {
+		if matches := pattern.FindStringSubmatch(library); len(matches) == 2 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// parseJavaReleaseFile returns the JAVA_VERSION property of the `release` file shipped in a JVM home
+func parseJavaReleaseFile(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if found && strings.TrimSpace(key) == "JAVA_VERSION" {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
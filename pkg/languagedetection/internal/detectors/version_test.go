@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package detectors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+)
+
+func TestVersionFromPath(t *testing.T) {
+	for _, tc := range []struct {
+		language languagemodels.LanguageName
+		path     string
+		expected string
+	}{
+		{language: languagemodels.Python, path: "/usr/bin/python3.11", expected: "3.11"},
+		{language: languagemodels.Python, path: "/root/.pyenv/versions/3.11.4/bin/python", expected: "3.11.4"},
+		{language: languagemodels.Python, path: "/opt/homebrew/opt/python@3.12/bin/python3", expected: "3.12"},
+		{language: languagemodels.Python, path: "/usr/bin/python3", expected: ""},
+		{language: languagemodels.Ruby, path: "/usr/bin/ruby3.1", expected: "3.1"},
+		{language: languagemodels.Ruby, path: "/opt/rubies/ruby-3.2.2/bin/ruby", expected: "3.2.2"},
+		{language: languagemodels.Ruby, path: "/usr/local/bin/ruby", expected: ""},
+		{language: languagemodels.Node, path: "/root/.nvm/versions/node/v18.17.0/bin/node", expected: "18.17.0"},
+		{language: languagemodels.Node, path: "/opt/node-v20.9.0-linux-x64/bin/node", expected: "20.9.0"},
+		{language: languagemodels.Node, path: "/usr/local/bin/node", expected: ""},
+		{language: languagemodels.Java, path: "/usr/lib/jvm/java-17-openjdk-amd64/bin/java", expected: "17"},
+		{language: languagemodels.Java, path: "/usr/lib/jvm/jdk-17.0.8+7/bin/java", expected: "17.0.8"},
+		{language: languagemodels.Java, path: "/usr/lib/jvm/java-1.8.0-openjdk/jre/bin/java", expected: "1.8.0"},
+		{language: languagemodels.Java, path: "/opt/java/openjdk/bin/java", expected: ""},
+		{language: languagemodels.Dotnet, path: "/usr/share/dotnet/dotnet", expected: ""},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, VersionFromPath(tc.language, tc.path))
+		})
+	}
+}
+
+func TestVersionFromLibraries(t *testing.T) {
+	for _, tc := range []struct {
+		language  languagemodels.LanguageName
+		libraries []string
+		expected  string
+	}{
+		{language: languagemodels.Python, libraries: []string{"libpython3.11.so.1.0", "libc.so.6"}, expected: "3.11"},
+		{language: languagemodels.Python, libraries: []string{"libc.so.6", "libpython3.7m.so.1.0"}, expected: "3.7"},
+		{language: languagemodels.Python, libraries: []string{"libc.so.6"}, expected: ""},
+		{language: languagemodels.Ruby, libraries: []string{"libruby.so.3.2", "libz.so.1"}, expected: "3.2"},
+		{language: languagemodels.Ruby, libraries: []string{"libruby-3.1.so.3.1.2"}, expected: "3.1.2"},
+		{language: languagemodels.Node, libraries: []string{"libnode.so.108"}, expected: ""},
+	} {
+		t.Run(strings.Join(tc.libraries, ","), func(t *testing.T) {
+			assert.Equal(t, tc.expected, VersionFromLibraries(tc.language, tc.libraries))
+		})
+	}
+}
+
+func TestSupportsVersionDetection(t *testing.T) {
+	assert.True(t, SupportsVersionDetection(languagemodels.Python))
+	assert.True(t, SupportsVersionDetection(languagemodels.Java))
+	assert.False(t, SupportsVersionDetection(languagemodels.Dotnet))
+	assert.False(t, SupportsVersionDetection(languagemodels.Unknown))
+}
+
+func TestParseJavaReleaseFile(t *testing.T) {
+	release := `IMPLEMENTOR="Eclipse Adoptium"
+JAVA_RUNTIME_VERSION="17.0.8+7"
+JAVA_VERSION="17.0.8"
+JAVA_VERSION_DATE="2023-07-18"
+`
+	assert.Equal(t, "17.0.8", parseJavaReleaseFile(strings.NewReader(release)))
+	assert.Equal(t, "", parseJavaReleaseFile(strings.NewReader(`IMPLEMENTOR="Eclipse Adoptium"`)))
+}
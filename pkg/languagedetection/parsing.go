@@ -31,6 +31,11 @@ func parseExeStartWithSymbol(exe string) string {
 }
 
 func getExe(cmd []string) string {
+	return normalizeExe(getExePath(cmd))
+}
+
+// getExePath returns the path of the executable of a command line, as it was invoked
+func getExePath(cmd []string) string {
 	if len(cmd) == 0 {
 		return ""
 	}
@@ -44,8 +49,11 @@ func getExe(cmd []string) string {
 	}
 
 	// trim any quotes from the executable
-	exe = strings.Trim(exe, "\"")
+	return strings.Trim(exe, "\"")
+}
 
+// normalizeExe returns the lowercased name of an executable from its path
+func normalizeExe(exe string) string {
 	// Extract executable from commandline args
 	exe = removeFilePath(exe)
 	if !isRuneAlphanumeric(exe, 0) {
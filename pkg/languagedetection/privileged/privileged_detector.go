@@ -30,6 +30,7 @@ import (
 
 var detectorsWithPrivilege = []languagemodels.Detector{
 	detectors.NewGoDetector(),
+	detectors.NewRuntimeDetector(),
 }
 
 var (
@@ -92,6 +93,10 @@ func (l *LanguageDetector) DetectWithPrivileges(procs []languagemodels.Process)
 				continue
 			}
 			languages[i] = lang
+			// the first detector identifying the language wins
+			if lang.Name != languagemodels.Unknown {
+				break
+			}
 		}
 		l.binaryIDCache.Add(bin, lang)
 	}
@@ -8,6 +8,7 @@ package util
 
 import (
 	"regexp"
+	"strings"
 )
 
 const (
@@ -19,11 +20,19 @@ const (
 // AnnotationRegex defines the regex pattern of language detection annotations
 var AnnotationRegex = regexp.MustCompile(`internal\.dd\.datadoghq\.com\/(init\.)?(.+?)\.detected_langs`)
 
+// VersionsAnnotationRegex defines the regex pattern of language versions annotations
+var VersionsAnnotationRegex = regexp.MustCompile(`internal\.dd\.datadoghq\.com\/(init\.)?(.+?)\.detected_lang_versions`)
+
 // GetLanguageAnnotationKey returns the language annotation key for the specified container
 func GetLanguageAnnotationKey(containerName string) string {
 	return AnnotationPrefix + containerName + ".detected_langs"
 }
 
+// GetLanguageVersionsAnnotationKey returns the language versions annotation key for the specified container
+func GetLanguageVersionsAnnotationKey(containerName string) string {
+	return AnnotationPrefix + containerName + ".detected_lang_versions"
+}
+
 // ExtractContainerFromAnnotationKey extracts container name from annotation key and indicates if it is an init container
 // if the annotation key is not a language annotation it returns an empty container name
 func ExtractContainerFromAnnotationKey(annotationKey string) (string, bool) {
@@ -38,3 +47,27 @@ func ExtractContainerFromAnnotationKey(annotationKey string) (string, bool) {
 
 	return containerName, isInitContainer
 }
+
+// ExtractContainerFromVersionsAnnotationKey extracts container name from a language versions annotation key and
+// indicates if it is an init container
+// if the annotation key is not a language versions annotation it returns an empty container name
+func ExtractContainerFromVersionsAnnotationKey(annotationKey string) (string, bool) {
+	matches := VersionsAnnotationRegex.FindStringSubmatch(annotationKey)
+	if len(matches) != 3 {
+		return "", false
+	}
+
+	return matches[2], matches[1] != ""
+}
+
+// ParseLanguageVersions parses the value of a language versions annotation, like `java:17.0.8,python:3.11.4`
+func ParseLanguageVersions(value string) map[Language]string {
+	versions := make(map[Language]string)
+	for _, entry := range strings.Split(value, ",") {
+		lang, version, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if found && lang != "" && version != "" {
+			versions[Language(lang)] = version
+		}
+	}
+	return versions
+}
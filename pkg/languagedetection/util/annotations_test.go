@@ -49,6 +49,12 @@ func TestExtractContainerFromAnnotationKey(t *testing.T) {
 			containerName:   "initializer",
 			isInitContainer: false,
 		},
+		{
+			name:            "Language versions annotation",
+			annotationKey:   "internal.dd.datadoghq.com/some-container-name.detected_lang_versions",
+			containerName:   "",
+			isInitContainer: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,3 +65,44 @@ func TestExtractContainerFromAnnotationKey(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractContainerFromVersionsAnnotationKey(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotationKey   string
+		containerName   string
+		isInitContainer bool
+	}{
+		{
+			name:            "Language annotation",
+			annotationKey:   "internal.dd.datadoghq.com/some-container-name.detected_langs",
+			containerName:   "",
+			isInitContainer: false,
+		},
+		{
+			name:            "Standard language versions annotation",
+			annotationKey:   GetLanguageVersionsAnnotationKey("some-container-name"),
+			containerName:   "some-container-name",
+			isInitContainer: false,
+		},
+		{
+			name:            "Language versions annotation for init container",
+			annotationKey:   "internal.dd.datadoghq.com/init.some-container-name.detected_lang_versions",
+			containerName:   "some-container-name",
+			isInitContainer: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualContainerName, actualIsInit := ExtractContainerFromVersionsAnnotationKey(tt.annotationKey)
+			assert.Equal(t, tt.containerName, actualContainerName)
+			assert.Equal(t, tt.isInitContainer, actualIsInit)
+		})
+	}
+}
+
+func TestParseLanguageVersions(t *testing.T) {
+	assert.Equal(t, map[Language]string{"java": "17.0.8", "python": "3.11.4"}, ParseLanguageVersions("java:17.0.8, python:3.11.4"))
+	assert.Equal(t, map[Language]string{"go": "go1.21.3"}, ParseLanguageVersions("go:go1.21.3,ruby,:1.0,node:"))
+}
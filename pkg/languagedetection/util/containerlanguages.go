@@ -85,8 +85,12 @@ func (c ContainersLanguages) ToAnnotations() map[string]string {
 		annotationKey := GetLanguageAnnotationKey(containerName)
 
 		languagesNames := make([]string, 0, len(langSet))
-		for lang := range langSet {
+		languagesVersions := make([]string, 0, len(langSet))
+		for lang, details := range langSet {
 			languagesNames = append(languagesNames, string(lang))
+			if details.Version != "" {
+				languagesVersions = append(languagesVersions, string(lang)+":"+details.Version)
+			}
 		}
 
 		sort.Strings(languagesNames)
@@ -95,11 +99,33 @@ func (c ContainersLanguages) ToAnnotations() map[string]string {
 		if annotationValue != "" {
 			annotations[annotationKey] = annotationValue
 		}
+
+		sort.Strings(languagesVersions)
+		if len(languagesVersions) > 0 {
+			annotations[GetLanguageVersionsAnnotationKey(containerName)] = strings.Join(languagesVersions, ",")
+		}
 	}
 
 	return annotations
 }
 
+// Merge merges another containers languages object to the current object, keeping the most recent versions
+// Returns true if new languages or versions were added, and false otherwise
+func (c ContainersLanguages) Merge(other ContainersLanguages) bool {
+	modified := false
+	for container, languageSet := range other {
+		if _, found := c[container]; !found {
+			c[container] = make(LanguageSet)
+		}
+		for language, details := range languageSet {
+			if c[container].AddWithVersion(language, details.Version) {
+				modified = true
+			}
+		}
+	}
+	return modified
+}
+
 ////////////////////////////////
 //                            //
 // Timed Containers Languages //
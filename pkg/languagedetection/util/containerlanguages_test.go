@@ -41,6 +41,19 @@ func TestToAnnotations(t *testing.T) {
 				"internal.dd.datadoghq.com/init.cont-2.detected_langs": "java,python",
 			},
 		},
+		{
+			name: "Containers languages with versions",
+			self: ContainersLanguages{
+				*NewContainer("cont-1"):     {"python": {Version: "3.11.4"}, "java": {Version: "17.0.8"}},
+				*NewInitContainer("cont-2"): {"java": {}, "python": {Version: "3.9.18"}},
+			},
+			expectedAnnotations: map[string]string{
+				"internal.dd.datadoghq.com/cont-1.detected_langs":              "java,python",
+				"internal.dd.datadoghq.com/cont-1.detected_lang_versions":      "java:17.0.8,python:3.11.4",
+				"internal.dd.datadoghq.com/init.cont-2.detected_langs":         "java,python",
+				"internal.dd.datadoghq.com/init.cont-2.detected_lang_versions": "python:3.9.18",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -53,6 +66,33 @@ func TestToAnnotations(t *testing.T) {
 
 }
 
+func TestContainersLanguagesMerge(t *testing.T) {
+	containersLanguages := ContainersLanguages{
+		*NewContainer("cont-1"): {"java": {}, "python": {Version: "3.11.4"}},
+	}
+
+	modified := containersLanguages.Merge(ContainersLanguages{
+		*NewContainer("cont-1"): {"java": {Version: "17.0.8"}, "python": {}},
+	})
+	assert.True(t, modified)
+
+	modified = containersLanguages.Merge(ContainersLanguages{
+		*NewContainer("cont-1"): {"java": {Version: "17.0.8"}},
+	})
+	assert.False(t, modified)
+
+	modified = containersLanguages.Merge(ContainersLanguages{
+		*NewInitContainer("cont-2"): {"go": {Version: "go1.21.3"}},
+	})
+	assert.True(t, modified)
+
+	expected := ContainersLanguages{
+		*NewContainer("cont-1"):     {"java": {Version: "17.0.8"}, "python": {Version: "3.11.4"}},
+		*NewInitContainer("cont-2"): {"go": {Version: "go1.21.3"}},
+	}
+	assert.Equal(t, expected, containersLanguages)
+}
+
 //////////////////////////////////////////
 //                                      //
 //    TimedContainersLanguages Tests    //
@@ -6,8 +6,12 @@
 package util
 
 import (
+	"cmp"
 	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/process"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,23 +24,59 @@ type Language string
 //                            //
 ////////////////////////////////
 
+// LanguageDetails holds the metadata detected along with a language
+type LanguageDetails struct {
+	// Version is the runtime version of the language, empty if unknown
+	Version string
+}
+
 // LanguageSet represents a set of languages
-type LanguageSet map[Language]struct{}
+type LanguageSet map[Language]LanguageDetails
 
 // Add adds a new language to the language set
 // returns false if the language is already included in the set, and true otherwise
 func (s LanguageSet) Add(language Language) bool {
-	_, found := s[language]
-	s[language] = struct{}{}
-	return !found
+	return s.AddWithVersion(language, "")
+}
+
+// AddWithVersion adds a language along with its runtime version to the language set
+// only the highest version is kept, so that containers running different versions don't flip the version of the set
+// returns true if the language is new or if its version changed, and false otherwise
+func (s LanguageSet) AddWithVersion(language Language, version string) bool {
+	details, found := s[language]
+	if found && compareVersions(version, details.Version) <= 0 {
+		return false
+	}
+	s[language] = LanguageDetails{Version: version}
+	return true
+}
+
+var versionNumberPattern = regexp.MustCompile(`\d+`)
+
+// compareVersions compares the numbers of two runtime versions, like 3.11.4 or go1.21.3, in order
+// an empty version is lower than any other
+func compareVersions(a, b string) int {
+	aNumbers, bNumbers := versionNumberPattern.FindAllString(a, -1), versionNumberPattern.FindAllString(b, -1)
+	for i := 0; i < len(aNumbers) && i < len(bNumbers); i++ {
+		aNumber, _ := strconv.Atoi(aNumbers[i])
+		bNumber, _ := strconv.Atoi(bNumbers[i])
+		if c := cmp.Compare(aNumber, bNumber); c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(len(aNumbers), len(bNumbers)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
 }
 
 // ToProto returns a proto message Language
 func (s LanguageSet) ToProto() []*pbgo.Language {
 	res := make([]*pbgo.Language, 0, len(s))
-	for lang := range s {
+	for lang, details := range s {
 		res = append(res, &pbgo.Language{
-			Name: string(lang),
+			Name:    string(lang),
+			Version: details.Version,
 		})
 	}
 	return res
@@ -33,6 +33,43 @@ func TestAddToLanguageSet(t *testing.T) {
 	assert.Truef(t, reflect.DeepEqual(s, expectedAfterAdd), "Expected %v, found %v", expectedAfterAdd, s)
 }
 
+func TestAddWithVersionToLanguageSet(t *testing.T) {
+	s := LanguageSet{"java": {}}
+
+	assert.True(t, s.AddWithVersion("java", "17.0.8"))
+	assert.Equal(t, LanguageSet{"java": {Version: "17.0.8"}}, s)
+
+	assert.False(t, s.AddWithVersion("java", "17.0.8"))
+	assert.False(t, s.AddWithVersion("java", ""), "an empty version should not override a known one")
+	assert.False(t, s.Add("java"))
+	assert.Equal(t, LanguageSet{"java": {Version: "17.0.8"}}, s)
+
+	assert.True(t, s.AddWithVersion("java", "21.0.1"))
+	assert.False(t, s.AddWithVersion("java", "17.0.8"), "a lower version should not override a higher one")
+	assert.True(t, s.AddWithVersion("python", ""))
+	assert.Equal(t, LanguageSet{"java": {Version: "21.0.1"}, "python": {}}, s)
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "3.11.4", b: "3.11.4", expected: 0},
+		{a: "3.9", b: "3.11", expected: -1},
+		{a: "3.11.4", b: "3.11", expected: 1},
+		{a: "go1.21.3", b: "go1.9.7", expected: 1},
+		{a: "17.0.8+7", b: "17.0.8+10", expected: -1},
+		{a: "", b: "2.7", expected: -1},
+		{a: "", b: "", expected: 0},
+	} {
+		t.Run(fmt.Sprintf("%s vs %s", tc.a, tc.b), func(t *testing.T) {
+			assert.Equal(t, tc.expected, compareVersions(tc.a, tc.b))
+			assert.Equal(t, -tc.expected, compareVersions(tc.b, tc.a))
+		})
+	}
+}
+
 ////////////////////////////////
 //                            //
 //    TimedLanguageSet Set    //
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package dotnetparser wraps functions to guess service name for .NET applications
+package dotnetparser
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var projectExtensions = []string{".csproj", ".fsproj", ".vbproj"}
+
+// project is used to unmarshal only the required fields of a MSBuild project file
+type project struct {
+	PropertyGroups []struct {
+		AssemblyName string `xml:"AssemblyName"`
+	} `xml:"PropertyGroup"`
+}
+
+// FindProjectFile returns the project file to run from the value of the --project
+// option of `dotnet run`, or from the working directory when the option is empty.
+// Like the dotnet cli, a directory is searched for a single project file.
+func FindProjectFile(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	if !info.IsDir() {
+		return path, isProjectFile(path)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", false
+	}
+	var found string
+	for _, entry := range entries {
+		if entry.IsDir() || !isProjectFile(entry.Name()) {
+			continue
+		}
+		if found != "" {
+			// the dotnet cli fails when a directory contains multiple projects
+			return "", false
+		}
+		found = filepath.Join(path, entry.Name())
+	}
+	return found, found != ""
+}
+
+// GetAssemblyName returns the name of the assembly built from a project file, which is
+// either declared by the AssemblyName property or the name of the project file.
+func GetAssemblyName(projectFile string) string {
+	name := strings.TrimSuffix(filepath.Base(projectFile), filepath.Ext(projectFile))
+
+	reader, err := os.Open(projectFile)
+	if err != nil {
+		log.Tracef("Error opening project file at %s: %v", projectFile, err)
+		return name
+	}
+	defer reader.Close()
+
+	var p project
+	if err := xml.NewDecoder(reader).Decode(&p); err != nil {
+		log.Tracef("Error parsing project file at %s: %v", projectFile, err)
+		return name
+	}
+	for _, group := range p.PropertyGroups {
+		if assemblyName := strings.TrimSpace(group.AssemblyName); assemblyName != "" {
+			return assemblyName
+		}
+	}
+	return name
+}
+
+func isProjectFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, projectExt := range projectExtensions {
+		if ext == projectExt {
+			return true
+		}
+	}
+	return false
+}
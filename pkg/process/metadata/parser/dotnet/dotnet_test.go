@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dotnetparser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindProjectFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "project file",
+			path:     "testdata/named/Api.csproj",
+			expected: "testdata/named/Api.csproj",
+		},
+		{
+			name:     "directory with a single project file",
+			path:     "testdata/unnamed",
+			expected: "testdata/unnamed/Worker.fsproj",
+		},
+		{
+			name:     "directory with multiple project files",
+			path:     "testdata/multiple",
+			expected: "",
+		},
+		{
+			name:     "not a project file",
+			path:     "dotnet.go",
+			expected: "",
+		},
+		{
+			name:     "missing path",
+			path:     "testdata/missing",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := FindProjectFile(filepath.FromSlash(tt.path))
+			assert.Equal(t, len(tt.expected) > 0, ok)
+			if ok {
+				assert.Equal(t, filepath.FromSlash(tt.expected), value)
+			}
+		})
+	}
+}
+
+func TestGetAssemblyName(t *testing.T) {
+	assert.Equal(t, "Contoso.Orders.Api", GetAssemblyName(filepath.FromSlash("testdata/named/Api.csproj")))
+	assert.Equal(t, "Worker", GetAssemblyName(filepath.FromSlash("testdata/unnamed/Worker.fsproj")))
+	assert.Equal(t, "Missing", GetAssemblyName(filepath.FromSlash("testdata/Missing.csproj")))
+}
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package phpparser wraps functions to guess service name for php applications
+package phpparser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// composerJSON is used to unmarshal only the required fields of a composer.json file
+type composerJSON struct {
+	Name string `json:"name"`
+}
+
+// FindNameFromNearestComposerJSON finds the composer.json walking up from dir.
+// If a composer.json is found, returns the package part of its name field
+// (`vendor/package`) if declared.
+func FindNameFromNearestComposerJSON(dir string) (string, bool) {
+	current := filepath.Clean(dir)
+	for {
+		value, ok := maybeExtractServiceName(filepath.Join(current, "composer.json"))
+		if ok {
+			return value, len(value) > 0
+		}
+		up := filepath.Dir(current)
+		if up == current {
+			return "", false
+		}
+		current = up
+	}
+}
+
+// maybeExtractServiceName returns true if a composer.json has been found and eventually the
+// package part of its name field inside.
+func maybeExtractServiceName(filename string) (string, bool) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Tracef("Error opening composer.json file at %s: %v", filename, err)
+		}
+		return "", false
+	}
+	defer reader.Close()
+
+	var composer composerJSON
+	if err := json.NewDecoder(reader).Decode(&composer); err != nil {
+		log.Tracef("Error parsing composer.json file at %s: %v", filename, err)
+		return "", true
+	}
+	_, name, found := strings.Cut(composer.Name, "/")
+	if !found {
+		name = composer.Name
+	}
+	return name, true
+}
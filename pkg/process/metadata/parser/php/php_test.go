@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package phpparser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindNameFromNearestComposerJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		{
+			name:     "should return false when nothing found",
+			dir:      "./",
+			expected: "",
+		},
+		{
+			name:     "should return false when name is empty",
+			dir:      "./testdata/noname",
+			expected: "",
+		},
+		{
+			name:     "should return true when name is found",
+			dir:      "./testdata/laravel",
+			expected: "billing-portal",
+		},
+		{
+			name:     "should return true when name is found in a parent directory",
+			dir:      "./testdata/laravel/public",
+			expected: "billing-portal",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			abs, err := filepath.Abs(filepath.Clean(tt.dir))
+			assert.NoError(t, err)
+			value, ok := FindNameFromNearestComposerJSON(abs)
+			assert.Equal(t, len(tt.expected) > 0, ok)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package pythonparser wraps functions to guess service name for python applications
+package pythonparser
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// appServer describes how to find the application in the command line of a
+// python application server
+type appServer struct {
+	// nameFlags are the flags setting the name of the application
+	nameFlags []string
+	// appFlags are the flags setting the module path of the application. When
+	// empty, the application is the first positional argument.
+	appFlags []string
+	// booleanFlags are the flags not followed by a value
+	booleanFlags []string
+}
+
+var appServers = map[string]appServer{
+	"gunicorn": {
+		nameFlags: []string{"-n", "--name"},
+		booleanFlags: []string{
+			"-D", "--daemon", "-R", "--enable-stdio-inheritance", "--reload", "--preload", "--capture-output",
+			"--check-config", "--print-config", "--reuse-port", "--no-sendfile", "--spew", "--strip-header-spaces",
+		},
+	},
+	"uvicorn": {
+		booleanFlags: []string{
+			"--reload", "--factory", "--use-colors", "--no-use-colors", "--access-log", "--no-access-log",
+			"--proxy-headers", "--no-proxy-headers", "--server-header", "--no-server-header", "--date-header",
+			"--no-date-header",
+		},
+	},
+	"celery": {
+		appFlags: []string{"-A", "--app"},
+		booleanFlags: []string{
+			"-C", "--no-color", "-q", "--quiet", "--skip-checks", "-B", "--beat", "-E", "--task-events",
+			"--without-gossip", "--without-mingle", "--without-heartbeat", "-D", "--detach",
+		},
+	},
+}
+
+// modulePathRegex matches a python module path with an optional variable, like
+// `myproject.wsgi:application`
+var modulePathRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*(:[A-Za-z_][A-Za-z0-9_.()]*)?$`)
+
+// IsAppServer returns true if the name is the name of a python application server
+// whose command line references the application module, like gunicorn, uvicorn or celery
+func IsAppServer(name string) bool {
+	_, ok := appServers[name]
+	return ok
+}
+
+// FindNameFromAppServerArgs finds the name of the application served by a python
+// application server from the arguments following the server name. The name is
+// either set explicitly by a flag, or derived from the WSGI/ASGI/celery module
+// path of the application.
+func FindNameFromAppServerArgs(server string, args []string) (string, bool) {
+	as, ok := appServers[server]
+	if !ok {
+		return "", false
+	}
+
+	// process title set by gunicorn, like `gunicorn: master [myproject.wsgi:application]`
+	for _, a := range args {
+		if strings.HasPrefix(a, "[") && strings.HasSuffix(a, "]") {
+			return nameFromModulePath(a[1 : len(a)-1])
+		}
+	}
+
+	var app string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			if len(as.appFlags) == 0 && app == "" && modulePathRegex.MatchString(a) {
+				app = a
+			}
+			continue
+		}
+
+		flag, value, hasValue := strings.Cut(a, "=")
+		if !hasValue && !slices.Contains(as.booleanFlags, flag) && i+1 < len(args) {
+			i++
+			value, hasValue = args[i], true
+		}
+		if !hasValue {
+			continue
+		}
+		if slices.Contains(as.nameFlags, flag) && value != "" {
+			return value, true
+		}
+		if slices.Contains(as.appFlags, flag) && app == "" {
+			app = value
+		}
+	}
+
+	return nameFromModulePath(app)
+}
+
+// nameFromModulePath returns the top level package of a module path, skipping
+// the `src` directory of the src layout
+func nameFromModulePath(path string) (string, bool) {
+	if !modulePathRegex.MatchString(path) {
+		return "", false
+	}
+	module, _, _ := strings.Cut(path, ":")
+	parts := strings.Split(module, ".")
+	if len(parts) > 1 && parts[0] == "src" {
+		parts = parts[1:]
+	}
+	return parts[0], true
+}
@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package pythonparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindNameFromAppServerArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "gunicorn django project",
+			server:   "gunicorn",
+			args:     []string{"--workers", "3", "--bind", "0.0.0.0:8000", "myproject.wsgi:application"},
+			expected: "myproject",
+		},
+		{
+			name:     "gunicorn with boolean flag before the app",
+			server:   "gunicorn",
+			args:     []string{"--reload", "app:app"},
+			expected: "app",
+		},
+		{
+			name:     "gunicorn with worker class",
+			server:   "gunicorn",
+			args:     []string{"-k", "uvicorn.workers.UvicornWorker", "src.api.main:create_app()"},
+			expected: "api",
+		},
+		{
+			name:     "gunicorn with name flag",
+			server:   "gunicorn",
+			args:     []string{"--name=billing", "myproject.wsgi"},
+			expected: "billing",
+		},
+		{
+			name:     "gunicorn process title",
+			server:   "gunicorn",
+			args:     []string{"master", "[myproject.wsgi:application]"},
+			expected: "myproject",
+		},
+		{
+			name:     "uvicorn",
+			server:   "uvicorn",
+			args:     []string{"main:app", "--host", "0.0.0.0", "--port", "80"},
+			expected: "main",
+		},
+		{
+			name:     "celery with app flag",
+			server:   "celery",
+			args:     []string{"-A", "proj", "worker", "-l", "INFO"},
+			expected: "proj",
+		},
+		{
+			name:     "celery with app flag after the command",
+			server:   "celery",
+			args:     []string{"worker", "--app=tasks.celery:app", "--concurrency", "4"},
+			expected: "tasks",
+		},
+		{
+			name:     "celery without app",
+			server:   "celery",
+			args:     []string{"worker"},
+			expected: "",
+		},
+		{
+			name:     "no module path",
+			server:   "gunicorn",
+			args:     []string{"-b", "0.0.0.0:8000"},
+			expected: "",
+		},
+		{
+			name:     "unknown server",
+			server:   "flask",
+			args:     []string{"app:app"},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := FindNameFromAppServerArgs(tt.server, tt.args)
+			assert.Equal(t, len(tt.expected) > 0, ok)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
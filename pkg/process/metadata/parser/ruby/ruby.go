@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package rubyparser wraps functions to guess service name for ruby applications
+package rubyparser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	moduleRegex           = regexp.MustCompile(`^\s*module\s+([A-Z][A-Za-z0-9_]*)`)
+	railsApplicationRegex = regexp.MustCompile(`^\s*class\s+Application\s*<\s*(::)?Rails::Application\b`)
+)
+
+// FindRailsAppName finds the config/application.rb of a Rails application walking up
+// from dir. If found, returns the name of the application, which is the module
+// declaring the Rails::Application class, in snake case.
+func FindRailsAppName(dir string) (string, bool) {
+	current := filepath.Clean(dir)
+	for {
+		value, ok := maybeExtractRailsAppName(filepath.Join(current, "config", "application.rb"))
+		if ok {
+			return value, len(value) > 0
+		}
+		up := filepath.Dir(current)
+		if up == current {
+			return "", false
+		}
+		current = up
+	}
+}
+
+// maybeExtractRailsAppName returns true if an application.rb has been found and eventually
+// the name of the application declared inside.
+func maybeExtractRailsAppName(filename string) (string, bool) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Tracef("Error opening application.rb file at %s: %v", filename, err)
+		}
+		return "", false
+	}
+	defer file.Close()
+
+	var module string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := moduleRegex.FindStringSubmatch(line); matches != nil && module == "" {
+			module = matches[1]
+		} else if railsApplicationRegex.MatchString(line) {
+			return underscore(module), true
+		}
+	}
+	return "", true
+}
+
+// underscore converts a ruby constant name to snake case, like ActiveSupport's underscore
+func underscore(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
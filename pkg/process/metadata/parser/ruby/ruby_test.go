@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rubyparser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRailsAppName(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		{
+			name:     "should return false when nothing found",
+			dir:      "./",
+			expected: "",
+		},
+		{
+			name:     "should return false when the application is not a rails application",
+			dir:      "./testdata/norails",
+			expected: "",
+		},
+		{
+			name:     "should return true when the application is found",
+			dir:      "./testdata/rails",
+			expected: "http_store_front",
+		},
+		{
+			name:     "should return true when the application is found in a parent directory",
+			dir:      "./testdata/rails/app/jobs",
+			expected: "http_store_front",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			abs, err := filepath.Abs(filepath.Clean(tt.dir))
+			assert.NoError(t, err)
+			value, ok := FindRailsAppName(abs)
+			assert.Equal(t, len(tt.expected) > 0, ok)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestUnderscore(t *testing.T) {
+	assert.Equal(t, "my_app", underscore("MyApp"))
+	assert.Equal(t, "http_store_front", underscore("HTTPStoreFront"))
+	assert.Equal(t, "app2_go", underscore("App2Go"))
+	assert.Equal(t, "blog", underscore("Blog"))
+}
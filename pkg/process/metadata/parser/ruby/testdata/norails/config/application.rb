@@ -0,0 +1,4 @@
+module NotRails
+  class Application
+  end
+end
@@ -17,8 +17,12 @@ import (
 	"github.com/cihub/seelog"
 
 	"github.com/DataDog/datadog-agent/pkg/process/metadata"
+	dotnetparser "github.com/DataDog/datadog-agent/pkg/process/metadata/parser/dotnet"
 	javaparser "github.com/DataDog/datadog-agent/pkg/process/metadata/parser/java"
 	nodejsparser "github.com/DataDog/datadog-agent/pkg/process/metadata/parser/nodejs"
+	phpparser "github.com/DataDog/datadog-agent/pkg/process/metadata/parser/php"
+	pythonparser "github.com/DataDog/datadog-agent/pkg/process/metadata/parser/python"
+	rubyparser "github.com/DataDog/datadog-agent/pkg/process/metadata/parser/ruby"
 	"github.com/DataDog/datadog-agent/pkg/process/procutil"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -33,10 +37,16 @@ const (
 	javaSnapshotSuffix  = "-SNAPSHOT"
 	javaApachePrefix    = "org.apache."
 	dllSuffix           = ".dll"
+	// phpFPMDefaultPool is the pool configured by the stock php-fpm installs
+	phpFPMDefaultPool = "www"
 )
 
 var (
 	javaAllowedFlags = []string{javaJarFlag, javaModuleFlag, javaModuleFlagShort}
+	// rubyAppServers are the ruby scripts usually running a Rails application
+	rubyAppServers = []string{"rails", "puma", "sidekiq", "unicorn", "rackup", "bundle"}
+	// phpFlagsWithValue are the php cli flags followed by a value
+	phpFlagsWithValue = []string{"-c", "-d", "-r", "-z", "-S", "-B", "-R", "-F", "-E"}
 )
 
 // List of binaries that usually have additional process context of what's running
@@ -45,8 +55,18 @@ var binsWithContext = map[string]serviceExtractorFn{
 	"python2.7":  parseCommandContextPython,
 	"python3":    parseCommandContextPython,
 	"python3.7":  parseCommandContextPython,
-	"ruby2.3":    parseCommandContext,
-	"ruby":       parseCommandContext,
+	"gunicorn":   parseCommandContextPythonAppServer("gunicorn"),
+	"uvicorn":    parseCommandContextPythonAppServer("uvicorn"),
+	"celery":     parseCommandContextPythonAppServer("celery"),
+	"ruby2.3":    parseCommandContextRuby,
+	"ruby":       parseCommandContextRuby,
+	"puma":       parseCommandContextRubyAppServer("puma"),
+	"sidekiq":    parseCommandContextRubyAppServer("sidekiq"),
+	"rails":      parseCommandContextRubyAppServer("rails"),
+	"unicorn":    parseCommandContextRubyAppServer("unicorn"),
+	"bundle":     parseCommandContextRubyAppServer("bundle"),
+	"php":        parseCommandContextPHP,
+	"php-fpm":    parseCommandContextPHPFPM,
 	"java":       parseCommandContextJava,
 	"java.exe":   parseCommandContextJava,
 	"sudo":       parseCommandContext,
@@ -313,15 +333,22 @@ func parseCommandContextPython(se *ServiceExtractor, _ *procutil.Process, args [
 		moduleFlag    bool
 	)
 
-	for _, a := range args {
+	for i, a := range args {
 		hasFlagPrefix, isEnvVariable := strings.HasPrefix(a, "-"), strings.ContainsRune(a, '=')
 
 		shouldSkipArg := prevArgIsFlag || hasFlagPrefix || isEnvVariable
 
 		if !shouldSkipArg || moduleFlag {
 			if c := trimColonRight(removeFilePath(a)); isRuneLetterAt(c, 0) {
-				if se.useImprovedAlgorithm && !moduleFlag {
-					return strings.TrimSuffix(c, filepath.Ext(c))
+				if !se.useImprovedAlgorithm {
+					return c
+				}
+				if !moduleFlag {
+					c = strings.TrimSuffix(c, filepath.Ext(c))
+				}
+				// the application run by a server like gunicorn is a better context than the server itself
+				if value, ok := pythonparser.FindNameFromAppServerArgs(c, args[i+1:]); ok {
+					return value
 				}
 				return c
 			}
@@ -337,6 +364,108 @@ func parseCommandContextPython(se *ServiceExtractor, _ *procutil.Process, args [
 	return ""
 }
 
+// parseCommandContextPythonAppServer returns the function extracting metadata from the command line of a
+// python application server, like `gunicorn myproject.wsgi:application`
+func parseCommandContextPythonAppServer(server string) serviceExtractorFn {
+	return func(se *ServiceExtractor, _ *procutil.Process, args []string) string {
+		if !se.useImprovedAlgorithm {
+			return server
+		}
+		if value, ok := pythonparser.FindNameFromAppServerArgs(server, args); ok {
+			return value
+		}
+		return server
+	}
+}
+
+// parseCommandContextRuby extracts metadata from a ruby command line. When the script is a server or
+// a worker of a Rails application, like `ruby bin/rails server`, the name of the application is used.
+func parseCommandContextRuby(se *ServiceExtractor, process *procutil.Process, args []string) string {
+	c := parseCommandContext(se, process, args)
+	if se.useImprovedAlgorithm && slices.Contains(rubyAppServers, c) {
+		if value, ok := findRailsAppName(process); ok {
+			return value
+		}
+	}
+	return c
+}
+
+// parseCommandContextRubyAppServer returns the function extracting metadata from the command line of a
+// ruby application server, like puma or sidekiq
+func parseCommandContextRubyAppServer(server string) serviceExtractorFn {
+	return func(se *ServiceExtractor, process *procutil.Process, args []string) string {
+		if !se.useImprovedAlgorithm {
+			return server
+		}
+		if value, ok := findRailsAppName(process); ok {
+			return value
+		}
+		// process title set by puma, like `puma 6.4.0 (tcp://0.0.0.0:3000) [myapp]`
+		if n := len(args); n > 0 {
+			if last := args[n-1]; len(last) > 2 && strings.HasPrefix(last, "[") && strings.HasSuffix(last, "]") {
+				return last[1 : len(last)-1]
+			}
+		}
+		return server
+	}
+}
+
+// findRailsAppName looks for a Rails application in the working directory of the process
+func findRailsAppName(process *procutil.Process) (string, bool) {
+	if !filepath.IsAbs(process.Cwd) {
+		return "", false
+	}
+	return rubyparser.FindRailsAppName(process.Cwd)
+}
+
+// parseCommandContextPHP extracts metadata from a php command line, like `php artisan queue:work`,
+// using the name of the composer package of the script, or of the document root of the built-in server
+func parseCommandContextPHP(se *ServiceExtractor, process *procutil.Process, args []string) string {
+	if !se.useImprovedAlgorithm {
+		return "php"
+	}
+
+	dir := process.Cwd
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if (a == "-f" || a == "-t") && i+1 < len(args) {
+			i++
+			if a == "-f" {
+				dir = filepath.Dir(abs(args[i], process.Cwd))
+				break
+			}
+			dir = abs(args[i], process.Cwd)
+			continue
+		}
+		if strings.HasPrefix(a, "-") {
+			if slices.Contains(phpFlagsWithValue, a) {
+				i++
+			}
+			continue
+		}
+		if a != "" {
+			dir = filepath.Dir(abs(a, process.Cwd))
+		}
+		break
+	}
+
+	if filepath.IsAbs(dir) {
+		if value, ok := phpparser.FindNameFromNearestComposerJSON(dir); ok {
+			return value
+		}
+	}
+	return "php"
+}
+
+// parseCommandContextPHPFPM extracts the pool name from the process title of a php-fpm worker, like
+// `php-fpm: pool orders`. The default `www` pool doesn't tell anything about the application.
+func parseCommandContextPHPFPM(se *ServiceExtractor, _ *procutil.Process, args []string) string {
+	if se.useImprovedAlgorithm && len(args) >= 2 && args[0] == "pool" && args[1] != "" && args[1] != phpFPMDefaultPool {
+		return args[1]
+	}
+	return "php-fpm"
+}
+
 func parseCommandContextJava(se *ServiceExtractor, process *procutil.Process, args []string) string {
 	prevArgIsFlag := false
 
@@ -457,23 +586,82 @@ func abs(path string, cwd string) string {
 }
 
 // parseCommandContextDotnet extracts metadata from a dotnet launcher command line
-func parseCommandContextDotnet(se *ServiceExtractor, _ *procutil.Process, args []string) string {
+func parseCommandContextDotnet(se *ServiceExtractor, process *procutil.Process, args []string) string {
 	if !se.useImprovedAlgorithm {
 		return "dotnet"
 	}
-	for _, a := range args {
+	for i, a := range args {
 		if strings.HasPrefix(a, "-") {
 			continue
 		}
 		// when running assembly's dll, the cli must be executed without command
 		// https://learn.microsoft.com/en-us/dotnet/core/tools/dotnet-run#description
-		if strings.HasSuffix(strings.ToLower(a), dllSuffix) {
-			_, file := filepath.Split(a)
-			return file[:len(file)-len(dllSuffix)]
+		if name, ok := dotnetAssemblyName(a); ok {
+			return name
 		}
 		// dotnet cli syntax is something like `dotnet <cmd> <args> <dll> <prog args>`
-		// if the first non arg (`-v, --something, ...) is not a dll file, exit early since nothing is matching a dll execute case
+		// only the commands running an application are inspected further
+		switch a {
+		case "exec":
+			if name, ok := parseDotnetExecArgs(args[i+1:]); ok {
+				return name
+			}
+		case "run":
+			if name, ok := parseDotnetRunArgs(process, args[i+1:]); ok {
+				return name
+			}
+		}
 		break
 	}
 	return "dotnet"
 }
+
+// dotnetAssemblyName returns the assembly name of a dll file
+func dotnetAssemblyName(arg string) (string, bool) {
+	if !strings.HasSuffix(strings.ToLower(arg), dllSuffix) {
+		return "", false
+	}
+	_, file := filepath.Split(arg)
+	return file[:len(file)-len(dllSuffix)], true
+}
+
+// parseDotnetExecArgs extracts the assembly name from the args of `dotnet exec [options] <dll>`,
+// where all the options are followed by a value
+func parseDotnetExecArgs(args []string) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "-") {
+			if !strings.ContainsRune(args[i], '=') {
+				i++
+			}
+			continue
+		}
+		return dotnetAssemblyName(args[i])
+	}
+	return "", false
+}
+
+// parseDotnetRunArgs extracts the assembly name from the project run by `dotnet run [--project <path>]`
+func parseDotnetRunArgs(process *procutil.Process, args []string) (string, bool) {
+	path := process.Cwd
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		if flag, value, found := strings.Cut(a, "="); found && (flag == "--project" || flag == "-p") {
+			path = abs(value, process.Cwd)
+			break
+		}
+		if (a == "--project" || a == "-p") && i+1 < len(args) && args[i+1] != "" {
+			path = abs(args[i+1], process.Cwd)
+			break
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return "", false
+	}
+	projectFile, ok := dotnetparser.FindProjectFile(path)
+	if !ok {
+		return "", false
+	}
+	return dotnetparser.GetAssemblyName(projectFile), true
+}
@@ -6,6 +6,7 @@
 package parser
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -19,6 +20,7 @@ func TestExtractServiceMetadata(t *testing.T) {
 	tests := []struct {
 		name                 string
 		cmdline              []string
+		cwd                  string
 		useImprovedAlgorithm bool
 		expectedServiceTag   string
 	}{
@@ -345,6 +347,164 @@ func TestExtractServiceMetadata(t *testing.T) {
 			},
 			expectedServiceTag: "process_context:dotnet",
 		},
+		{
+			name: "gunicorn with wsgi module",
+			cmdline: []string{
+				"/usr/local/bin/gunicorn", "--workers", "4", "--bind", "0.0.0.0:8000", "myproject.wsgi:application",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:myproject",
+		},
+		{
+			name: "gunicorn with name",
+			cmdline: []string{
+				"/usr/local/bin/gunicorn", "-n", "orders-api", "myproject.wsgi:application",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:orders-api",
+		},
+		{
+			name: "gunicorn with improved algorithm disabled",
+			cmdline: []string{
+				"/usr/local/bin/gunicorn", "myproject.wsgi:application",
+			},
+			expectedServiceTag: "process_context:gunicorn",
+		},
+		{
+			name: "python running gunicorn module",
+			cmdline: []string{
+				"/usr/bin/python3", "-m", "gunicorn", "-w", "2", "myproject.wsgi",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:myproject",
+		},
+		{
+			name: "uvicorn with app",
+			cmdline: []string{
+				"/usr/local/bin/uvicorn", "--host", "0.0.0.0", "app.main:app",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:app",
+		},
+		{
+			name: "celery worker",
+			cmdline: []string{
+				"/usr/local/bin/celery", "-A", "tasks", "worker", "--loglevel=INFO",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:tasks",
+		},
+		{
+			name: "rails server",
+			cmdline: []string{
+				"/usr/bin/ruby", "bin/rails", "server",
+			},
+			cwd:                  "ruby/testdata/rails",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:http_store_front",
+		},
+		{
+			name: "puma in rails app",
+			cmdline: []string{
+				"puma", "6.4.0", "(tcp://0.0.0.0:3000)", "[store]",
+			},
+			cwd:                  "ruby/testdata/rails",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:http_store_front",
+		},
+		{
+			name: "puma process title",
+			cmdline: []string{
+				"puma", "6.4.0", "(tcp://0.0.0.0:3000)", "[store]",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:store",
+		},
+		{
+			name: "ruby script outside rails app",
+			cmdline: []string{
+				"/usr/bin/ruby", "bin/rails", "server",
+			},
+			cwd:                  "ruby/testdata/norails",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:rails",
+		},
+		{
+			name: "php artisan",
+			cmdline: []string{
+				"/usr/bin/php", "-d", "memory_limit=512M", "artisan", "queue:work",
+			},
+			cwd:                  "php/testdata/laravel",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:billing-portal",
+		},
+		{
+			name: "php built-in server with docroot",
+			cmdline: []string{
+				"/usr/bin/php", "-S", "0.0.0.0:8080", "-t", "php/testdata/laravel/public",
+			},
+			cwd:                  ".",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:billing-portal",
+		},
+		{
+			name: "php with improved algorithm disabled",
+			cmdline: []string{
+				"/usr/bin/php", "artisan", "queue:work",
+			},
+			cwd:                "php/testdata/laravel",
+			expectedServiceTag: "process_context:php",
+		},
+		{
+			name: "php-fpm pool worker",
+			cmdline: []string{
+				"php-fpm: pool www-orders",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:www-orders",
+		},
+		{
+			name: "php-fpm default pool worker",
+			cmdline: []string{
+				"php-fpm: pool www",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:php-fpm",
+		},
+		{
+			name: "php-fpm master",
+			cmdline: []string{
+				"php-fpm: master process (/etc/php/8.1/fpm/php-fpm.conf)",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:php-fpm",
+		},
+		{
+			name: "dotnet exec with options",
+			cmdline: []string{
+				"/usr/bin/dotnet", "exec", "--runtimeconfig", "./app.runtimeconfig.json", "./MyService.dll",
+			},
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:MyService",
+		},
+		{
+			name: "dotnet run with project",
+			cmdline: []string{
+				"/usr/bin/dotnet", "run", "--project", "dotnet/testdata/named/Api.csproj",
+			},
+			cwd:                  ".",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:Contoso.Orders.Api",
+		},
+		{
+			name: "dotnet run in project directory",
+			cmdline: []string{
+				"/usr/bin/dotnet", "run",
+			},
+			cwd:                  "dotnet/testdata/unnamed",
+			useImprovedAlgorithm: true,
+			expectedServiceTag:   "process_context:Worker",
+		},
 		{
 			name: "envs but no command",
 			cmdline: []string{
@@ -360,6 +520,11 @@ func TestExtractServiceMetadata(t *testing.T) {
 				Pid:     1,
 				Cmdline: tt.cmdline,
 			}
+			if tt.cwd != "" {
+				cwd, err := filepath.Abs(tt.cwd)
+				require.NoError(t, err)
+				proc.Cwd = cwd
+			}
 			procsByPid := map[int32]*procutil.Process{proc.Pid: &proc}
 			serviceExtractorEnabled := true
 			useWindowsServiceName := true
@@ -223,7 +223,10 @@ func getGRPCStreamPort(cfg config.Reader) int {
 func processEntityToEventSet(proc *ProcessEntity) *pbgo.ProcessEventSet {
 	var language *pbgo.Language
 	if proc.Language != nil {
-		language = &pbgo.Language{Name: string(proc.Language.Name)}
+		language = &pbgo.Language{
+			Name:    string(proc.Language.Name),
+			Version: proc.Language.Version,
+		}
 	}
 
 	return &pbgo.ProcessEventSet{
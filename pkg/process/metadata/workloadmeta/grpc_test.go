@@ -260,14 +260,15 @@ func TestProcessEntityToEventSet(t *testing.T) {
 				NsPid:        1,
 				CreationTime: 5311456,
 				Language: &languagemodels.Language{
-					Name: languagemodels.Python,
+					Name:    languagemodels.Python,
+					Version: "3.11.4",
 				},
 			},
 			event: &pbgo.ProcessEventSet{
 				Pid:          40,
 				Nspid:        1,
 				CreationTime: 5311456,
-				Language:     &pbgo.Language{Name: "python"},
+				Language:     &pbgo.Language{Name: "python", Version: "3.11.4"},
 			},
 		},
 		{
@@ -344,6 +345,7 @@ func assertSetEvent(t *testing.T, expected, actual *pbgo.ProcessEventSet) {
 	assert.Equal(t, expected.CreationTime, actual.CreationTime)
 	if expected.Language != nil {
 		assert.Equal(t, expected.Language.Name, actual.Language.Name)
+		assert.Equal(t, expected.Language.Version, actual.Language.Version)
 	}
 }
 
@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build trivy
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta"
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+	"github.com/DataDog/datadog-agent/pkg/util/trivy"
+)
+
+// channelSize defines the result channel size
+// Archives are scanned on demand, one at a time.
+const channelSize = 1
+
+// scanRequest defines a scan request. This struct should be
+// hashable to be pushed in the work queue for processing.
+type scanRequest struct {
+	Path string
+}
+
+// NewScanRequest creates a new scan request for the image archive at the
+// given path
+func NewScanRequest(path string) sbom.ScanRequest {
+	return scanRequest{Path: path}
+}
+
+// Collector returns the collector name
+func (r scanRequest) Collector() string {
+	return collectors.ImageArchiveCollector
+}
+
+// Type returns the scan request type
+func (r scanRequest) Type(sbom.ScanOptions) string {
+	return sbom.ScanFilesystemType
+}
+
+// ID returns the scan request ID
+func (r scanRequest) ID() string {
+	return r.Path
+}
+
+// Collector defines a container image archive collector
+type Collector struct {
+	trivyCollector *trivy.Collector
+	resChan        chan sbom.ScanResult
+	opts           sbom.ScanOptions
+
+	closed bool
+}
+
+// CleanCache cleans the cache
+func (c *Collector) CleanCache() error {
+	return nil
+}
+
+// Init initializes the image archive collector
+func (c *Collector) Init(cfg config.Component, wmeta optional.Option[workloadmeta.Component]) error {
+	trivyCollector, err := trivy.GetGlobalCollector(cfg, wmeta)
+	if err != nil {
+		return err
+	}
+	c.trivyCollector = trivyCollector
+	// Archives are scanned with the same options as the container images,
+	// but their layers are not cached as they are not tracked by workloadmeta.
+	c.opts = sbom.ScanOptionsFromConfig(cfg, true)
+	c.opts.NoCache = true
+	return nil
+}
+
+// Scan performs a scan
+func (c *Collector) Scan(ctx context.Context, request sbom.ScanRequest) sbom.ScanResult {
+	archiveScanRequest, ok := request.(scanRequest)
+	if !ok {
+		return sbom.ScanResult{Error: fmt.Errorf("invalid request type '%s' for collector '%s'", reflect.TypeOf(request), collectors.ImageArchiveCollector)}
+	}
+	log.Infof("image archive scan request [%v]", archiveScanRequest.ID())
+
+	report, err := c.trivyCollector.ScanImageArchive(ctx, archiveScanRequest.Path, c.opts)
+	return sbom.ScanResult{
+		Error:  err,
+		Report: report,
+	}
+}
+
+// Type returns the image archive scan type
+func (c *Collector) Type() collectors.ScanType {
+	return collectors.ImageArchiveScanType
+}
+
+// Channel returns the channel to send scan results
+func (c *Collector) Channel() chan sbom.ScanResult {
+	return c.resChan
+}
+
+// Options returns the collector options
+func (c *Collector) Options() sbom.ScanOptions {
+	return c.opts
+}
+
+// Shutdown shuts down the collector
+func (c *Collector) Shutdown() {
+	if c.resChan != nil && !c.closed {
+		close(c.resChan)
+	}
+	c.closed = true
+}
+
+func init() {
+	collectors.RegisterCollector(collectors.ImageArchiveCollector, &Collector{
+		resChan: make(chan sbom.ScanResult, channelSize),
+	})
+}
@@ -23,12 +23,16 @@ const (
 	ContainerImageScanType ScanType = "container-image"
 	// HostScanType defines the host scan type
 	HostScanType ScanType = "host"
+	// ImageArchiveScanType defines the container image archive scan type
+	ImageArchiveScanType ScanType = "image-archive"
 	// ContainerdCollector is the name of the containerd collector
 	ContainerdCollector = "containerd"
 	// DockerCollector is the name of the docker collector
 	DockerCollector = "docker"
 	// HostCollector is the name of the host collector
 	HostCollector = "host"
+	// ImageArchiveCollector is the name of the container image archive collector
+	ImageArchiveCollector = "image-archive"
 )
 
 // Collector interface
@@ -71,6 +75,11 @@ func GetContainerdScanner() Collector {
 	return Collectors[ContainerdCollector]
 }
 
+// GetImageArchiveScanner returns the container image archive scanner
+func GetImageArchiveScanner() Collector {
+	return Collectors[ImageArchiveCollector]
+}
+
 // GetHostScanner returns the host scanner
 func GetHostScanner() Collector {
 	return Collectors[HostCollector]
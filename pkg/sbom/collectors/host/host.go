@@ -11,6 +11,8 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"os"
+	"path/filepath"
 	"reflect"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
@@ -55,6 +57,30 @@ func (r scanRequest) ID() string {
 	return r.Path
 }
 
+type relFS struct {
+	root string
+	fs   fs.FS
+}
+
+// NewFS returns a filesystem rooted at the given path, that also accepts
+// absolute paths as expected by the host scans.
+func NewFS(root string) fs.FS {
+	fs := os.DirFS(root)
+	return &relFS{root: "/", fs: fs}
+}
+
+func (f *relFS) Open(name string) (fs.File, error) {
+	if filepath.IsAbs(name) {
+		var err error
+		name, err = filepath.Rel(f.root, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f.fs.Open(name)
+}
+
 // Collector defines a host collector
 type Collector struct {
 	trivyCollector *trivy.Collector
@@ -129,6 +129,25 @@ func (s *Scanner) Scan(request sbom.ScanRequest) error {
 	return nil
 }
 
+// PerformScan synchronously performs a scan request with the collector it
+// targets, bypassing the scan queue. The scan result is returned instead of
+// being sent to the collector channel. It is meant for on-demand scans, like
+// the ones triggered from the command line.
+func (s *Scanner) PerformScan(ctx context.Context, request sbom.ScanRequest) sbom.ScanResult {
+	collector, ok := s.collectors[request.Collector()]
+	if !ok {
+		return sbom.ScanResult{Error: fmt.Errorf("invalid collector '%s'", request.Collector())}
+	}
+
+	if result := s.checkDiskSpace(nil, collector); result != nil {
+		return *result
+	}
+
+	scanContext, cancel := context.WithTimeout(ctx, timeout(collector))
+	defer cancel()
+	return *s.performScan(scanContext, request, collector)
+}
+
 func (s *Scanner) enoughDiskSpace(opts sbom.ScanOptions) error {
 	if !opts.CheckDiskUsage {
 		return nil
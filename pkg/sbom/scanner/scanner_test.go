@@ -282,3 +282,29 @@ func TestRetryChannelFull(t *testing.T) {
 	cancel()
 	shutdown.WaitUntil(time.After(5 * time.Second))
 }
+
+func TestPerformScan(t *testing.T) {
+	// Create a mock collector
+	collName := "mock"
+	mockCollector := collectors.NewMockCollector()
+	expectedResult := sbom.ScanResult{Report: mockReport{id: "/"}}
+	mockCollector.On("Options").Return(sbom.ScanOptions{})
+	mockCollector.On("Scan", mock.Anything, mock.Anything).Return(expectedResult).Once()
+
+	// Set up the configuration
+	cfg := config.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	cfg.Set("sbom.scan_queue.base_backoff", "200ms", model.SourceAgentRuntime)
+	cfg.Set("sbom.scan_queue.max_backoff", "600ms", model.SourceAgentRuntime)
+
+	// The scan is performed without starting the scanner
+	scanner := NewScanner(cfg, map[string]collectors.Collector{collName: mockCollector}, optional.NewNoneOption[workloadmeta.Component]())
+
+	result := scanner.PerformScan(context.Background(), &scanRequest{collectorName: collName, id: "/", scanRequestType: sbom.ScanFilesystemType})
+	assert.NoError(t, result.Error)
+	assert.Equal(t, expectedResult.Report, result.Report)
+	assert.False(t, result.CreatedAt.IsZero())
+	mockCollector.AssertExpectations(t)
+
+	result = scanner.PerformScan(context.Background(), &scanRequest{collectorName: "unknown", id: "/"})
+	assert.Error(t, result.Error)
+}
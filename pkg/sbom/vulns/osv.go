@@ -0,0 +1,311 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package vulns
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// osvEcosystems maps the package URL types of language packages to the OSV
+// ecosystems.
+var osvEcosystems = map[string]string{
+	"npm":      "npm",
+	"pypi":     "PyPI",
+	"golang":   "Go",
+	"cargo":    "crates.io",
+	"maven":    "Maven",
+	"gem":      "RubyGems",
+	"composer": "Packagist",
+	"nuget":    "NuGet",
+}
+
+// osvEntry is a vulnerability entry in the OSV format.
+//
+// reference: https://ossf.github.io/osv-schema/
+type osvEntry struct {
+	ID               string        `json:"id"`
+	Summary          string        `json:"summary"`
+	Affected         []osvAffected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity interface{} `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []osvRange `json:"ranges"`
+	Versions []string   `json:"versions"`
+}
+
+type osvRange struct {
+	Type   string          `json:"type"`
+	Events []osvRangeEvent `json:"events"`
+}
+
+type osvRangeEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type osvIndexedEntry struct {
+	entry    *osvEntry
+	affected *osvAffected
+}
+
+// osvDB is an in-memory database of OSV entries, indexed by ecosystem and
+// package name.
+type osvDB struct {
+	entries map[string]map[string][]osvIndexedEntry
+}
+
+func openOSVDB(path string) (*osvDB, error) {
+	db := &osvDB{entries: make(map[string]map[string][]osvIndexedEntry)}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		if err := db.loadFile(path); err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".json") {
+			return nil
+		}
+		return db.loadFile(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// loadFile loads a file holding either a single OSV entry or a list of
+// entries.
+func (db *osvDB) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read OSV file: %w", err)
+	}
+
+	var entries []*osvEntry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &entries)
+	} else {
+		var entry osvEntry
+		err = json.Unmarshal(data, &entry)
+		entries = append(entries, &entry)
+	}
+	if err != nil {
+		return fmt.Errorf("could not parse OSV file %s: %w", path, err)
+	}
+
+	for _, entry := range entries {
+		for i := range entry.Affected {
+			affected := &entry.Affected[i]
+			ecosystem := normalizeOSVEcosystem(affected.Package.Ecosystem)
+			name := affected.Package.Name
+			if ecosystem == "PyPI" {
+				name = normalizePythonName(name)
+			}
+			if db.entries[ecosystem] == nil {
+				db.entries[ecosystem] = make(map[string][]osvIndexedEntry)
+			}
+			db.entries[ecosystem][name] = append(db.entries[ecosystem][name], osvIndexedEntry{entry: entry, affected: affected})
+		}
+	}
+	return nil
+}
+
+// Close implements Database#Close
+func (db *osvDB) Close() error {
+	return nil
+}
+
+// Match implements Database#Match
+func (db *osvDB) Match(pkg Package) ([]Finding, error) {
+	ecosystem, ok := osvEcosystem(pkg)
+	if !ok {
+		return nil, nil
+	}
+
+	name, version := pkg.Name, pkg.Version
+	switch pkg.Ecosystem {
+	case "pypi":
+		name = normalizePythonName(name)
+	case "deb":
+		// Debian advisories are given for source packages
+		if pkg.SrcName != "" {
+			name = pkg.SrcName
+		}
+		if pkg.SrcVersion != "" {
+			version = pkg.SrcVersion
+		}
+	case "apk":
+		if pkg.SrcName != "" {
+			name = pkg.SrcName
+		}
+	}
+
+	cmp := comparerFor(pkg.Ecosystem)
+	var findings []Finding
+	for _, indexed := range db.entries[ecosystem][name] {
+		vulnerable, fixed := indexed.affected.isVulnerable(cmp, version)
+		if !vulnerable {
+			continue
+		}
+		findings = append(findings, Finding{
+			VulnerabilityID: indexed.entry.ID,
+			PackageName:     pkg.Name,
+			PackageVersion:  pkg.Version,
+			PURL:            pkg.PURL,
+			FixedVersion:    fixed,
+			Severity:        indexed.entry.severity(),
+			Title:           indexed.entry.Summary,
+		})
+	}
+	return findings, nil
+}
+
+// isVulnerable returns whether the given version is affected, along with the
+// version fixing the vulnerability if any.
+func (a *osvAffected) isVulnerable(cmp comparer, version string) (bool, string) {
+	for _, v := range a.Versions {
+		if v == version {
+			return true, ""
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "ECOSYSTEM" && r.Type != "SEMVER" {
+			continue
+		}
+		if affected, fixed := r.contains(cmp, version); affected {
+			return true, fixed
+		}
+	}
+	return false, ""
+}
+
+// contains evaluates the events of the range, sorted by version, as
+// specified by the OSV schema.
+func (r *osvRange) contains(cmp comparer, version string) (bool, string) {
+	events := make([]osvRangeEvent, len(r.Events))
+	copy(events, r.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].version(), events[j].version()
+		if a == "0" || b == "0" {
+			return a == "0" && b != "0"
+		}
+		res, _ := cmp(a, b)
+		return res < 0
+	})
+
+	affected, fixed := false, ""
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" {
+				affected = true
+			} else if res, err := cmp(version, event.Introduced); err == nil && res >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if res, err := cmp(version, event.Fixed); err == nil && res >= 0 {
+				affected = false
+			} else if affected && fixed == "" {
+				fixed = event.Fixed
+			}
+		case event.LastAffected != "":
+			if res, err := cmp(version, event.LastAffected); err == nil && res > 0 {
+				affected = false
+			}
+		case event.Limit != "":
+			if res, err := cmp(version, event.Limit); err == nil && res >= 0 {
+				affected = false
+			}
+		}
+	}
+	if !affected {
+		fixed = ""
+	}
+	return affected, fixed
+}
+
+func (e *osvRangeEvent) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
+}
+
+func (e *osvEntry) severity() string {
+	if s, ok := e.DatabaseSpecific.Severity.(string); ok {
+		return normalizeSeverity(s)
+	}
+	return SeverityUnknown
+}
+
+// osvEcosystem returns the OSV ecosystem of the given package.
+func osvEcosystem(pkg Package) (string, bool) {
+	if ecosystem, ok := osvEcosystems[pkg.Ecosystem]; ok {
+		return ecosystem, true
+	}
+	if pkg.DistroVersion == "" {
+		return "", false
+	}
+	switch pkg.Distro {
+	case "debian":
+		return "Debian:" + majorVersion(pkg.DistroVersion), true
+	case "ubuntu":
+		return "Ubuntu:" + majorMinorVersion(pkg.DistroVersion), true
+	case "alpine":
+		return "Alpine:v" + majorMinorVersion(pkg.DistroVersion), true
+	case "rocky":
+		return "Rocky Linux:" + majorVersion(pkg.DistroVersion), true
+	case "alma":
+		return "AlmaLinux:" + majorVersion(pkg.DistroVersion), true
+	}
+	return "", false
+}
+
+// normalizeOSVEcosystem removes the qualifiers that some ecosystems append
+// to the release, like in "Ubuntu:22.04:LTS".
+func normalizeOSVEcosystem(ecosystem string) string {
+	if strings.HasPrefix(ecosystem, "Ubuntu:") {
+		parts := strings.Split(ecosystem, ":")
+		for _, part := range parts[1:] {
+			if len(part) > 0 && part[0] >= '0' && part[0] <= '9' {
+				return "Ubuntu:" + part
+			}
+		}
+	}
+	return ecosystem
+}
@@ -0,0 +1,240 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package vulns
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// trivyVulnerabilityBucket is the bucket holding the details of the
+// vulnerabilities in the Trivy database.
+const trivyVulnerabilityBucket = "vulnerability"
+
+// trivyLanguageSources maps the package URL types to the prefix of the data
+// sources buckets of the Trivy database.
+var trivyLanguageSources = map[string]string{
+	"npm":      "npm::",
+	"pypi":     "pip::",
+	"golang":   "go::",
+	"cargo":    "cargo::",
+	"maven":    "maven::",
+	"gem":      "rubygems::",
+	"composer": "composer::",
+	"nuget":    "nuget::",
+}
+
+// trivyAdvisory is an advisory stored in a data source bucket of the Trivy
+// database.
+type trivyAdvisory struct {
+	FixedVersion       string   `json:",omitempty"`
+	VulnerableVersions []string `json:",omitempty"`
+	PatchedVersions    []string `json:",omitempty"`
+	UnaffectedVersions []string `json:",omitempty"`
+}
+
+// trivyVulnerability holds the details of a vulnerability in the Trivy
+// database.
+type trivyVulnerability struct {
+	Title          string         `json:",omitempty"`
+	Severity       string         `json:",omitempty"`
+	VendorSeverity map[string]int `json:",omitempty"`
+}
+
+// trivySeverities are the severities of the Trivy database, indexed by their
+// numeric value.
+var trivySeverities = []string{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+type trivyDB struct {
+	db *bolt.DB
+}
+
+func openTrivyDB(path string) (*trivyDB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open trivy vulnerability database: %w", err)
+	}
+	return &trivyDB{db: db}, nil
+}
+
+// Close implements Database#Close
+func (t *trivyDB) Close() error {
+	return t.db.Close()
+}
+
+// Match implements Database#Match
+func (t *trivyDB) Match(pkg Package) ([]Finding, error) {
+	name, version := pkg.Name, pkg.Version
+	var sources []string
+	if prefix, ok := trivyLanguageSources[pkg.Ecosystem]; ok {
+		if pkg.Ecosystem == "pypi" {
+			name = normalizePythonName(name)
+		}
+		sources = t.sourcesWithPrefix(prefix)
+	} else {
+		source, ok := trivyOSSource(pkg.Distro, pkg.DistroVersion)
+		if !ok {
+			return nil, nil
+		}
+		sources = []string{source}
+		// Advisories of Debian based distributions and Alpine are given for
+		// source packages.
+		if pkg.Ecosystem == "deb" || pkg.Ecosystem == "apk" {
+			if pkg.SrcName != "" {
+				name = pkg.SrcName
+			}
+			if pkg.Ecosystem == "deb" && pkg.SrcVersion != "" {
+				version = pkg.SrcVersion
+			}
+		}
+	}
+
+	cmp := comparerFor(pkg.Ecosystem)
+	var findings []Finding
+	err := t.db.View(func(tx *bolt.Tx) error {
+		vulnBucket := tx.Bucket([]byte(trivyVulnerabilityBucket))
+		for _, source := range sources {
+			sourceBucket := tx.Bucket([]byte(source))
+			if sourceBucket == nil {
+				continue
+			}
+			pkgBucket := sourceBucket.Bucket([]byte(name))
+			if pkgBucket == nil {
+				continue
+			}
+			err := pkgBucket.ForEach(func(k, v []byte) error {
+				var advisory trivyAdvisory
+				if err := json.Unmarshal(v, &advisory); err != nil {
+					return fmt.Errorf("invalid advisory %s for package %s: %w", k, name, err)
+				}
+				vulnerable, err := advisory.isVulnerable(cmp, version)
+				if err != nil || !vulnerable {
+					// unparsable versions are ignored rather than failing the whole match
+					return nil
+				}
+
+				finding := Finding{
+					VulnerabilityID: string(k),
+					PackageName:     pkg.Name,
+					PackageVersion:  pkg.Version,
+					PURL:            pkg.PURL,
+					FixedVersion:    advisory.fixedVersion(),
+					Severity:        SeverityUnknown,
+				}
+				if vulnBucket != nil {
+					if details := vulnBucket.Get(k); details != nil {
+						var vuln trivyVulnerability
+						if err := json.Unmarshal(details, &vuln); err == nil {
+							finding.Title = vuln.Title
+							finding.Severity = vuln.severity()
+						}
+					}
+				}
+				findings = append(findings, finding)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return findings, err
+}
+
+func (t *trivyDB) sourcesWithPrefix(prefix string) []string {
+	var sources []string
+	_ = t.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if strings.HasPrefix(string(name), prefix) {
+				sources = append(sources, string(name))
+			}
+			return nil
+		})
+	})
+	return sources
+}
+
+// trivyOSSource returns the name of the data source bucket holding the
+// advisories of the given distribution.
+func trivyOSSource(distro, distroVersion string) (string, bool) {
+	if distroVersion == "" {
+		return "", false
+	}
+	switch distro {
+	case "debian", "rocky", "alma":
+		return distro + " " + majorVersion(distroVersion), true
+	case "ubuntu", "alpine":
+		return distro + " " + majorMinorVersion(distroVersion), true
+	case "amazon":
+		return "amazon linux " + majorVersion(distroVersion), true
+	case "oracle":
+		return "Oracle Linux " + majorVersion(distroVersion), true
+	}
+	return "", false
+}
+
+func (a *trivyAdvisory) isVulnerable(cmp comparer, version string) (bool, error) {
+	if len(a.VulnerableVersions) > 0 {
+		return satisfiesConstraints(cmp, version, a.VulnerableVersions)
+	}
+	secure := make([]string, 0, len(a.PatchedVersions)+len(a.UnaffectedVersions))
+	secure = append(secure, a.PatchedVersions...)
+	secure = append(secure, a.UnaffectedVersions...)
+	if len(secure) > 0 {
+		ok, err := satisfiesConstraints(cmp, version, secure)
+		return !ok, err
+	}
+	if a.FixedVersion == "" {
+		// no fix available yet
+		return true, nil
+	}
+	res, err := cmp(version, a.FixedVersion)
+	return res < 0, err
+}
+
+func (a *trivyAdvisory) fixedVersion() string {
+	if a.FixedVersion != "" {
+		return a.FixedVersion
+	}
+	return strings.Join(a.PatchedVersions, ", ")
+}
+
+func (v *trivyVulnerability) severity() string {
+	if v.Severity != "" {
+		return normalizeSeverity(v.Severity)
+	}
+	highest := 0
+	for _, s := range v.VendorSeverity {
+		if s > highest && s < len(trivySeverities) {
+			highest = s
+		}
+	}
+	return trivySeverities[highest]
+}
+
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+func majorMinorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// normalizePythonName normalizes a python package name as specified by
+// PEP 503.
+func normalizePythonName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+}
@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package vulns
+
+import (
+	"fmt"
+	"strings"
+
+	pep440 "github.com/aquasecurity/go-pep440-version"
+	goversion "github.com/hashicorp/go-version"
+	apkversion "github.com/knqyf263/go-apk-version"
+	debversion "github.com/knqyf263/go-deb-version"
+	rpmversion "github.com/knqyf263/go-rpm-version"
+)
+
+// comparer compares two versions of a package and returns -1, 0 or 1
+// whether the first version is lower, equal or greater than the second one.
+type comparer func(v1, v2 string) (int, error)
+
+// comparerFor returns the version comparer to use for the given package
+// ecosystem, identified by its package URL type.
+func comparerFor(ecosystem string) comparer {
+	switch ecosystem {
+	case "deb":
+		return compareDeb
+	case "rpm":
+		return compareRpm
+	case "apk":
+		return compareApk
+	case "pypi":
+		return comparePep440
+	default:
+		return compareSemver
+	}
+}
+
+func compareDeb(v1, v2 string) (int, error) {
+	a, err := debversion.NewVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+	b, err := debversion.NewVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+	return a.Compare(b), nil
+}
+
+func compareRpm(v1, v2 string) (int, error) {
+	return rpmversion.NewVersion(v1).Compare(rpmversion.NewVersion(v2)), nil
+}
+
+func compareApk(v1, v2 string) (int, error) {
+	a, err := apkversion.NewVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+	b, err := apkversion.NewVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+	return a.Compare(b), nil
+}
+
+func comparePep440(v1, v2 string) (int, error) {
+	a, err := pep440.Parse(v1)
+	if err != nil {
+		return 0, err
+	}
+	b, err := pep440.Parse(v2)
+	if err != nil {
+		return 0, err
+	}
+	return a.Compare(b), nil
+}
+
+func compareSemver(v1, v2 string) (int, error) {
+	a, err := goversion.NewVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+	b, err := goversion.NewVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+	return a.Compare(b), nil
+}
+
+// satisfiesConstraints returns whether the given version matches one of the
+// given constraints. Constraints are expressed as in the advisories of the
+// Trivy database: each constraint is a list of comparisons separated by
+// commas or spaces that must all match, and alternatives can be expressed
+// using "||". A version without operator is an exact match.
+//
+// Example: ">= 1.0.0, < 1.2.3 || = 2.0.0"
+func satisfiesConstraints(cmp comparer, version string, constraints []string) (bool, error) {
+	for _, constraint := range constraints {
+		for _, alternative := range strings.Split(constraint, "||") {
+			ok, err := satisfiesAll(cmp, version, alternative)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func satisfiesAll(cmp comparer, version string, constraint string) (bool, error) {
+	fields := strings.Fields(strings.ReplaceAll(constraint, ",", " "))
+	if len(fields) == 0 {
+		return false, nil
+	}
+	for i := 0; i < len(fields); i++ {
+		op, target := splitOperator(fields[i])
+		// the operator can be separated from the version: "< 1.2.3"
+		if target == "" && i+1 < len(fields) {
+			i++
+			target = fields[i]
+		}
+		if target == "" {
+			return false, fmt.Errorf("invalid constraint %q", constraint)
+		}
+		res, err := cmp(version, target)
+		if err != nil {
+			return false, err
+		}
+		var ok bool
+		switch op {
+		case "<":
+			ok = res < 0
+		case "<=":
+			ok = res <= 0
+		case ">":
+			ok = res > 0
+		case ">=":
+			ok = res >= 0
+		case "!=":
+			ok = res != 0
+		default:
+			ok = res == 0
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func splitOperator(s string) (string, string) {
+	for _, op := range []string{"<=", ">=", "!=", "==", "<", ">", "="} {
+		if strings.HasPrefix(s, op) {
+			return op, s[len(op):]
+		}
+	}
+	return "=", s
+}
@@ -0,0 +1,260 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package vulns matches the packages of an SBOM against a locally provided
+// vulnerability database, for environments where the SBOMs cannot be
+// processed by the backend.
+package vulns
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+
+	cyclonedxgo "github.com/CycloneDX/cyclonedx-go"
+	packageurl "github.com/package-url/packageurl-go"
+)
+
+const (
+	// SeverityCritical is the severity of critical vulnerabilities
+	SeverityCritical = "CRITICAL"
+	// SeverityHigh is the severity of high vulnerabilities
+	SeverityHigh = "HIGH"
+	// SeverityMedium is the severity of medium vulnerabilities
+	SeverityMedium = "MEDIUM"
+	// SeverityLow is the severity of low vulnerabilities
+	SeverityLow = "LOW"
+	// SeverityUnknown is the severity of vulnerabilities without severity
+	SeverityUnknown = "UNKNOWN"
+)
+
+var severityRanks = map[string]int{
+	SeverityCritical: 4,
+	SeverityHigh:     3,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+	SeverityUnknown:  0,
+}
+
+// trivy properties exported in the CycloneDX components
+const (
+	trivyPropSrcName    = "aquasecurity:trivy:SrcName"
+	trivyPropSrcVersion = "aquasecurity:trivy:SrcVersion"
+	trivyPropSrcRelease = "aquasecurity:trivy:SrcRelease"
+	trivyPropSrcEpoch   = "aquasecurity:trivy:SrcEpoch"
+)
+
+// Package describes a package of an SBOM to be matched against a
+// vulnerability database.
+type Package struct {
+	// Ecosystem is the package URL type of the package: deb, rpm, apk, npm, pypi...
+	Ecosystem string
+	// Distro is the name of the distribution of OS packages: debian, alpine...
+	Distro string
+	// DistroVersion is the version of the distribution of OS packages
+	DistroVersion string
+	// Name is the name of the package, including its namespace if any
+	Name string
+	// Version is the installed version of the package, including its epoch if any
+	Version string
+	// SrcName is the name of the source package of OS packages
+	SrcName string
+	// SrcVersion is the version of the source package of OS packages
+	SrcVersion string
+	// PURL is the package URL of the package
+	PURL string
+}
+
+// Finding describes a vulnerability affecting a package.
+type Finding struct {
+	VulnerabilityID string `json:"vulnerability_id"`
+	PackageName     string `json:"package_name"`
+	PackageVersion  string `json:"package_version"`
+	PURL            string `json:"purl,omitempty"`
+	FixedVersion    string `json:"fixed_version,omitempty"`
+	Severity        string `json:"severity"`
+	Title           string `json:"title,omitempty"`
+}
+
+// Database is a vulnerability database.
+type Database interface {
+	// Match returns the vulnerabilities affecting the given package.
+	Match(pkg Package) ([]Finding, error)
+	// Close releases the resources held by the database.
+	Close() error
+}
+
+// DefaultDBPath returns the default path of the vulnerability database,
+// following the layout of the trivy cache directory.
+func DefaultDBPath(cacheDir string) string {
+	return filepath.Join(cacheDir, "db", "trivy.db")
+}
+
+// Open opens the vulnerability database at the given path. The format of the
+// database is detected from the path: a JSON file or a directory of JSON
+// files is read as OSV entries, any other file is read as a Trivy database.
+func Open(path string) (Database, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open vulnerability database: %w", err)
+	}
+	if fi.IsDir() || strings.HasSuffix(path, ".json") {
+		db, err := openOSVDB(path)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	db, err := openTrivyDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// DBPathFromConfig returns the path of the vulnerability database set in the
+// configuration, defaulting to the database stored in the SBOM cache
+// directory.
+func DBPathFromConfig(cfg config.Component) string {
+	if path := cfg.GetString("sbom.vulnerabilities.db_path"); path != "" {
+		return path
+	}
+	return DefaultDBPath(cfg.GetString("sbom.cache_directory"))
+}
+
+// MatchBOM matches all the packages of the given CycloneDX BOM against the
+// vulnerability database. Findings are sorted by decreasing severity.
+func MatchBOM(db Database, bom *cyclonedxgo.BOM) ([]Finding, error) {
+	if bom == nil || bom.Components == nil {
+		return nil, nil
+	}
+
+	var findings []Finding
+	var errs []error
+	seen := make(map[string]struct{})
+	walkComponents(*bom.Components, func(c *cyclonedxgo.Component) {
+		pkg, ok := packageFromComponent(c)
+		if !ok {
+			return
+		}
+		if _, found := seen[pkg.PURL]; found {
+			return
+		}
+		seen[pkg.PURL] = struct{}{}
+
+		pkgFindings, err := db.Match(pkg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not match package %s: %w", pkg.PURL, err))
+			return
+		}
+		findings = append(findings, pkgFindings...)
+	})
+
+	SortFindings(findings)
+	return findings, errors.Join(errs...)
+}
+
+// SortFindings sorts findings by decreasing severity, then by vulnerability
+// and package.
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if ra, rb := severityRanks[a.Severity], severityRanks[b.Severity]; ra != rb {
+			return ra > rb
+		}
+		if a.VulnerabilityID != b.VulnerabilityID {
+			return a.VulnerabilityID < b.VulnerabilityID
+		}
+		return a.PURL < b.PURL
+	})
+}
+
+func walkComponents(components []cyclonedxgo.Component, f func(*cyclonedxgo.Component)) {
+	for i := range components {
+		f(&components[i])
+		if components[i].Components != nil {
+			walkComponents(*components[i].Components, f)
+		}
+	}
+}
+
+func packageFromComponent(c *cyclonedxgo.Component) (Package, bool) {
+	if c.PackageURL == "" {
+		return Package{}, false
+	}
+	purl, err := packageurl.FromString(c.PackageURL)
+	if err != nil || purl.Version == "" {
+		return Package{}, false
+	}
+
+	pkg := Package{
+		Ecosystem: purl.Type,
+		Name:      purl.Name,
+		Version:   purl.Version,
+		PURL:      c.PackageURL,
+	}
+	qualifiers := purl.Qualifiers.Map()
+
+	switch purl.Type {
+	case "deb", "rpm", "apk":
+		pkg.Distro = strings.ToLower(purl.Namespace)
+		// the distro qualifier is either "<distro>-<version>" or just "<version>"
+		pkg.DistroVersion = strings.TrimPrefix(qualifiers["distro"], pkg.Distro+"-")
+		if epoch := qualifiers["epoch"]; epoch != "" && epoch != "0" {
+			pkg.Version = epoch + ":" + pkg.Version
+		}
+	case "maven":
+		pkg.Name = purl.Namespace + ":" + purl.Name
+	default:
+		if purl.Namespace != "" {
+			pkg.Name = purl.Namespace + "/" + purl.Name
+		}
+	}
+
+	if c.Properties != nil {
+		var srcVersion, srcRelease, srcEpoch string
+		for _, prop := range *c.Properties {
+			switch prop.Name {
+			case trivyPropSrcName:
+				pkg.SrcName = prop.Value
+			case trivyPropSrcVersion:
+				srcVersion = prop.Value
+			case trivyPropSrcRelease:
+				srcRelease = prop.Value
+			case trivyPropSrcEpoch:
+				srcEpoch = prop.Value
+			}
+		}
+		if srcVersion != "" {
+			pkg.SrcVersion = srcVersion
+			if srcRelease != "" {
+				pkg.SrcVersion += "-" + srcRelease
+			}
+			if srcEpoch != "" && srcEpoch != "0" {
+				pkg.SrcVersion = srcEpoch + ":" + pkg.SrcVersion
+			}
+		}
+	}
+
+	return pkg, true
+}
+
+// normalizeSeverity returns one of the known severities from the given
+// severity string.
+func normalizeSeverity(severity string) string {
+	severity = strings.ToUpper(severity)
+	if severity == "MODERATE" {
+		return SeverityMedium
+	}
+	if _, ok := severityRanks[severity]; ok {
+		return severity
+	}
+	return SeverityUnknown
+}
@@ -0,0 +1,237 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package vulns
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cyclonedxgo "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func testBOM() *cyclonedxgo.BOM {
+	return &cyclonedxgo.BOM{
+		Components: &[]cyclonedxgo.Component{
+			{
+				Name: "debian",
+				Type: cyclonedxgo.ComponentTypeOS,
+			},
+			{
+				Name:       "libssl3",
+				Version:    "3.0.9-1",
+				PackageURL: "pkg:deb/debian/libssl3@3.0.9-1?arch=amd64&distro=debian-12.1",
+				Properties: &[]cyclonedxgo.Property{
+					{Name: trivyPropSrcName, Value: "openssl"},
+					{Name: trivyPropSrcVersion, Value: "3.0.9"},
+					{Name: trivyPropSrcRelease, Value: "1"},
+				},
+			},
+			{
+				Name:       "minimist",
+				Version:    "1.2.5",
+				PackageURL: "pkg:npm/minimist@1.2.5",
+				Components: &[]cyclonedxgo.Component{
+					{
+						Name:       "requests",
+						Version:    "2.28.1",
+						PackageURL: "pkg:pypi/requests@2.28.1",
+					},
+				},
+			},
+			{
+				Name:       "lib",
+				Version:    "v1.3.0",
+				PackageURL: "pkg:golang/github.com/example/lib@v1.3.0",
+			},
+			{
+				Name:       "musl",
+				Version:    "1.2.4-r1",
+				PackageURL: "pkg:apk/alpine/musl@1.2.4-r1?arch=x86_64&distro=3.18.4",
+			},
+		},
+	}
+}
+
+func TestOSVDatabase(t *testing.T) {
+	db, err := Open(filepath.Join("testdata", "osv.json"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	findings, err := MatchBOM(db, testBOM())
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{
+		{
+			VulnerabilityID: "GHSA-xvch-5gv4-984h",
+			PackageName:     "minimist",
+			PackageVersion:  "1.2.5",
+			PURL:            "pkg:npm/minimist@1.2.5",
+			FixedVersion:    "1.2.6",
+			Severity:        SeverityCritical,
+			Title:           "Prototype Pollution in minimist",
+		},
+		{
+			VulnerabilityID: "PYSEC-2023-74",
+			PackageName:     "requests",
+			PackageVersion:  "2.28.1",
+			PURL:            "pkg:pypi/requests@2.28.1",
+			FixedVersion:    "2.31.0",
+			Severity:        SeverityMedium,
+			Title:           "Unintended leak of Proxy-Authorization header in requests",
+		},
+		{
+			VulnerabilityID: "DSA-5532-1",
+			PackageName:     "libssl3",
+			PackageVersion:  "3.0.9-1",
+			PURL:            "pkg:deb/debian/libssl3@3.0.9-1?arch=amd64&distro=debian-12.1",
+			FixedVersion:    "3.0.11-1~deb12u2",
+			Severity:        SeverityUnknown,
+			Title:           "openssl - security update",
+		},
+		{
+			VulnerabilityID: "GHSA-last-affected",
+			PackageName:     "github.com/example/lib",
+			PackageVersion:  "v1.3.0",
+			PURL:            "pkg:golang/github.com/example/lib@v1.3.0",
+			Severity:        SeverityUnknown,
+		},
+	}, findings)
+}
+
+func TestOSVRanges(t *testing.T) {
+	db, err := Open(filepath.Join("testdata", "osv.json"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	for version, expected := range map[string]bool{
+		"0.1.0": false,
+		"0.9.0": true, // explicitly listed
+		"1.1.0": true,
+		"1.2.9": true,
+		"1.3.0": true,
+		"1.3.1": false,
+	} {
+		findings, err := db.Match(Package{Ecosystem: "golang", Name: "github.com/example/lib", Version: version})
+		require.NoError(t, err)
+		assert.Equal(t, expected, len(findings) == 1, "version %s", version)
+	}
+}
+
+func writeTrivyDB(t *testing.T, advisories map[string]map[string]map[string]interface{}, vulnerabilities map[string]interface{}) string {
+	path := filepath.Join(t.TempDir(), "db", "trivy.db")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	db, err := bolt.Open(path, 0600, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for source, pkgs := range advisories {
+			sourceBucket, err := tx.CreateBucketIfNotExists([]byte(source))
+			require.NoError(t, err)
+			for pkg, vulns := range pkgs {
+				pkgBucket, err := sourceBucket.CreateBucketIfNotExists([]byte(pkg))
+				require.NoError(t, err)
+				for id, advisory := range vulns {
+					data, _ := json.Marshal(advisory)
+					require.NoError(t, pkgBucket.Put([]byte(id), data))
+				}
+			}
+		}
+		vulnBucket, err := tx.CreateBucketIfNotExists([]byte(trivyVulnerabilityBucket))
+		require.NoError(t, err)
+		for id, vuln := range vulnerabilities {
+			data, _ := json.Marshal(vuln)
+			require.NoError(t, vulnBucket.Put([]byte(id), data))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return path
+}
+
+func TestTrivyDatabase(t *testing.T) {
+	path := writeTrivyDB(t,
+		map[string]map[string]map[string]interface{}{
+			"debian 12": {
+				"openssl": {
+					"CVE-2023-5363": map[string]string{"FixedVersion": "3.0.11-1~deb12u2"},
+					"CVE-2023-0001": map[string]string{"FixedVersion": "3.0.8-1"},
+					"CVE-2023-0002": map[string]string{},
+				},
+			},
+			"alpine 3.18": {
+				"musl": {
+					"CVE-2023-0003": map[string]string{"FixedVersion": "1.2.4-r2"},
+				},
+			},
+			"npm::GitHub Security Advisory npm": {
+				"minimist": {
+					"CVE-2021-44906": map[string][]string{
+						"VulnerableVersions": {"< 0.2.4", ">= 1.0.0, < 1.2.6"},
+						"PatchedVersions":    {"0.2.4", "1.2.6"},
+					},
+				},
+			},
+			"pip::GitHub Security Advisory pip": {
+				"requests": {
+					"CVE-2023-32681": map[string][]string{
+						"PatchedVersions": {">=2.31.0", "<2.3.0"},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"CVE-2023-5363":  map[string]interface{}{"Title": "openssl: incorrect cipher key", "Severity": "HIGH"},
+			"CVE-2021-44906": map[string]interface{}{"Title": "minimist: prototype pollution", "VendorSeverity": map[string]int{"ghsa": 4, "nvd": 3}},
+			"CVE-2023-0003":  map[string]interface{}{"Severity": "LOW"},
+		},
+	)
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	findings, err := MatchBOM(db, testBOM())
+	require.NoError(t, err)
+
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.VulnerabilityID)
+	}
+	assert.Equal(t, []string{"CVE-2021-44906", "CVE-2023-5363", "CVE-2023-0003", "CVE-2023-0002", "CVE-2023-32681"}, ids)
+
+	assert.Equal(t, SeverityCritical, findings[0].Severity)
+	assert.Equal(t, "1.2.5", findings[0].PackageVersion)
+	assert.Equal(t, "0.2.4, 1.2.6", findings[0].FixedVersion)
+	assert.Equal(t, "openssl: incorrect cipher key", findings[1].Title)
+	assert.Equal(t, "libssl3", findings[1].PackageName)
+	assert.Equal(t, "3.0.11-1~deb12u2", findings[1].FixedVersion)
+	assert.Equal(t, "", findings[3].FixedVersion)
+}
+
+func TestSatisfiesConstraints(t *testing.T) {
+	for _, tc := range []struct {
+		version     string
+		constraints []string
+		expected    bool
+	}{
+		{"1.2.5", []string{">= 1.0.0, < 1.2.6"}, true},
+		{"1.2.6", []string{">= 1.0.0, < 1.2.6"}, false},
+		{"0.1.0", []string{"< 0.2.4", ">= 1.0.0, < 1.2.6"}, true},
+		{"2.0.0", []string{"<1.0.0 || >=2.0.0 <2.1.0"}, true},
+		{"2.1.0", []string{"<1.0.0 || >=2.0.0 <2.1.0"}, false},
+		{"1.0.0", []string{"1.0.0"}, true},
+		{"1.0.1", []string{"=1.0.0"}, false},
+	} {
+		ok, err := satisfiesConstraints(compareSemver, tc.version, tc.constraints)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, ok, "%s %v", tc.version, tc.constraints)
+	}
+}
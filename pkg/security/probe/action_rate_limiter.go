@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package probe holds probe related files
+package probe
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const (
+	defaultActionRateLimit       = 10
+	defaultActionRateLimitPeriod = time.Minute
+)
+
+type actionRateLimiterKey struct {
+	ruleID rules.RuleID
+	action rules.ActionName
+}
+
+// ActionRateLimiter rate limits the rule actions per rule and per action
+type ActionRateLimiter struct {
+	sync.Mutex
+
+	limiters map[actionRateLimiterKey]*rate.Limiter
+}
+
+// NewActionRateLimiter returns a new ActionRateLimiter
+func NewActionRateLimiter() *ActionRateLimiter {
+	return &ActionRateLimiter{
+		limiters: make(map[actionRateLimiterKey]*rate.Limiter),
+	}
+}
+
+// Allow returns whether the action of the given rule can be executed
+func (l *ActionRateLimiter) Allow(ruleID rules.RuleID, action rules.ActionName, def *rules.RateLimitDefinition) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	key := actionRateLimiterKey{ruleID: ruleID, action: action}
+
+	limiter, exists := l.limiters[key]
+	if !exists {
+		limit, period := defaultActionRateLimit, defaultActionRateLimitPeriod
+		if def != nil {
+			if def.Limit > 0 {
+				limit = def.Limit
+			}
+			if def.Period > 0 {
+				period = def.Period
+			}
+		}
+
+		limiter = rate.NewLimiter(rate.Every(period/time.Duration(limit)), limit)
+		l.limiters[key] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+// Reset resets the rate limiters, used when a new ruleset is applied
+func (l *ActionRateLimiter) Reset() {
+	l.Lock()
+	defer l.Unlock()
+
+	l.limiters = make(map[actionRateLimiterKey]*rate.Limiter)
+}
@@ -76,3 +76,180 @@ func (k *KillActionReport) ToJSON() ([]byte, bool, error) {
 
 	return data, resolved, nil
 }
+
+const (
+	// ActionStatusPerformed the action was performed
+	ActionStatusPerformed = "performed"
+	// ActionStatusRateLimited the action was rate limited
+	ActionStatusRateLimited = "rate_limited"
+	// ActionStatusError the action failed
+	ActionStatusError = "error"
+)
+
+// HashActionReport defines a hash action report
+type HashActionReport struct {
+	sync.RWMutex
+
+	Path       string
+	Size       int64
+	SHA256     string
+	Status     string
+	Error      string
+	DetectedAt time.Time
+	HashedAt   time.Time
+
+	// internal
+	resolved bool
+}
+
+// JHashActionReport used to serialize date
+// easyjson:json
+type JHashActionReport struct {
+	Type       string              `json:"type"`
+	Path       string              `json:"path"`
+	Size       int64               `json:"size,omitempty"`
+	SHA256     string              `json:"sha256,omitempty"`
+	Status     string              `json:"status"`
+	Error      string              `json:"error,omitempty"`
+	DetectedAt utils.EasyjsonTime  `json:"detected_at"`
+	HashedAt   *utils.EasyjsonTime `json:"hashed_at,omitempty"`
+}
+
+// ToJSON marshal the action
+func (h *HashActionReport) ToJSON() ([]byte, bool, error) {
+	h.RLock()
+	defer h.RUnlock()
+
+	jh := JHashActionReport{
+		Type:       rules.HashAction,
+		Path:       h.Path,
+		Size:       h.Size,
+		SHA256:     h.SHA256,
+		Status:     h.Status,
+		Error:      h.Error,
+		DetectedAt: utils.NewEasyjsonTime(h.DetectedAt),
+		HashedAt:   utils.NewEasyjsonTimeIfNotZero(h.HashedAt),
+	}
+
+	data, err := utils.MarshalEasyJSON(jh)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, h.resolved, nil
+}
+
+// CoreDumpActionReport defines a coredump action report
+type CoreDumpActionReport struct {
+	sync.RWMutex
+
+	Pid        uint32
+	Cmdline    []string
+	Env        []string
+	OpenFiles  []string
+	MemoryMaps []string
+	Truncated  bool
+	Status     string
+	Error      string
+	DetectedAt time.Time
+	DumpedAt   time.Time
+
+	// internal
+	resolved bool
+}
+
+// JCoreDumpActionReport used to serialize date
+// easyjson:json
+type JCoreDumpActionReport struct {
+	Type       string              `json:"type"`
+	Pid        uint32              `json:"pid"`
+	Cmdline    []string            `json:"cmdline,omitempty"`
+	Env        []string            `json:"env,omitempty"`
+	OpenFiles  []string            `json:"open_files,omitempty"`
+	MemoryMaps []string            `json:"memory_maps,omitempty"`
+	Truncated  bool                `json:"truncated,omitempty"`
+	Status     string              `json:"status"`
+	Error      string              `json:"error,omitempty"`
+	DetectedAt utils.EasyjsonTime  `json:"detected_at"`
+	DumpedAt   *utils.EasyjsonTime `json:"dumped_at,omitempty"`
+}
+
+// ToJSON marshal the action
+func (c *CoreDumpActionReport) ToJSON() ([]byte, bool, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	jc := JCoreDumpActionReport{
+		Type:       rules.CoreDumpAction,
+		Pid:        c.Pid,
+		Cmdline:    c.Cmdline,
+		Env:        c.Env,
+		OpenFiles:  c.OpenFiles,
+		MemoryMaps: c.MemoryMaps,
+		Truncated:  c.Truncated,
+		Status:     c.Status,
+		Error:      c.Error,
+		DetectedAt: utils.NewEasyjsonTime(c.DetectedAt),
+		DumpedAt:   utils.NewEasyjsonTimeIfNotZero(c.DumpedAt),
+	}
+
+	data, err := utils.MarshalEasyJSON(jc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, c.resolved, nil
+}
+
+// NetworkIsolationActionReport defines a network isolation action report
+type NetworkIsolationActionReport struct {
+	sync.RWMutex
+
+	ContainerID string
+	NetNS       uint32
+	CGroupPath  string
+	Status      string
+	Error       string
+	DetectedAt  time.Time
+	IsolatedAt  time.Time
+
+	// internal
+	resolved bool
+}
+
+// JNetworkIsolationActionReport used to serialize date
+// easyjson:json
+type JNetworkIsolationActionReport struct {
+	Type        string              `json:"type"`
+	ContainerID string              `json:"container_id,omitempty"`
+	NetNS       uint32              `json:"netns,omitempty"`
+	CGroupPath  string              `json:"cgroup_path,omitempty"`
+	Status      string              `json:"status"`
+	Error       string              `json:"error,omitempty"`
+	DetectedAt  utils.EasyjsonTime  `json:"detected_at"`
+	IsolatedAt  *utils.EasyjsonTime `json:"isolated_at,omitempty"`
+}
+
+// ToJSON marshal the action
+func (n *NetworkIsolationActionReport) ToJSON() ([]byte, bool, error) {
+	n.RLock()
+	defer n.RUnlock()
+
+	jn := JNetworkIsolationActionReport{
+		Type:        rules.NetworkIsolationAction,
+		ContainerID: n.ContainerID,
+		NetNS:       n.NetNS,
+		CGroupPath:  n.CGroupPath,
+		Status:      n.Status,
+		Error:       n.Error,
+		DetectedAt:  utils.NewEasyjsonTime(n.DetectedAt),
+		IsolatedAt:  utils.NewEasyjsonTimeIfNotZero(n.IsolatedAt),
+	}
+
+	data, err := utils.MarshalEasyJSON(jn)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, n.resolved, nil
+}
@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package probe holds probe related files
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+// CoreDumper defines a process snapshot structure
+type CoreDumper struct {
+	rateLimiter *ActionRateLimiter
+	scrubber    *procutil.DataScrubber
+}
+
+// NewCoreDumper returns a new CoreDumper
+func NewCoreDumper(rateLimiter *ActionRateLimiter, scrubber *procutil.DataScrubber) *CoreDumper {
+	return &CoreDumper{
+		rateLimiter: rateLimiter,
+		scrubber:    scrubber,
+	}
+}
+
+func procPidPath(pid uint32, path ...string) string {
+	return kernel.HostProc(append([]string{strconv.FormatUint(uint64(pid), 10)}, path...)...)
+}
+
+// readNullSeparated reads a null separated procfs file, returns at most maxEntries entries
+func readNullSeparated(path string, maxEntries int) ([]string, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entries []string
+	for _, entry := range bytes.Split(bytes.TrimRight(data, "\x00"), []byte{0}) {
+		if len(entries) >= maxEntries {
+			return entries, true, nil
+		}
+		entries = append(entries, string(entry))
+	}
+
+	return entries, false, nil
+}
+
+// readLines reads a procfs file line by line, returns at most maxEntries lines
+func readLines(path string, maxEntries int) ([]string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(lines) >= maxEntries {
+			return lines, true, nil
+		}
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, false, scanner.Err()
+}
+
+// readOpenFiles returns the targets of the file descriptors of a process, returns at most maxEntries files
+func readOpenFiles(pid uint32, maxEntries int) ([]string, bool, error) {
+	fdDir := procPidPath(pid, "fd")
+
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if len(files) >= maxEntries {
+			return files, true, nil
+		}
+
+		target, err := os.Readlink(fdDir + "/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		files = append(files, entry.Name()+" -> "+target)
+	}
+
+	return files, false, nil
+}
+
+type processSnapshot struct {
+	cmdline    []string
+	env        []string
+	openFiles  []string
+	memoryMaps []string
+	truncated  bool
+}
+
+func (c *CoreDumper) dump(pid uint32, def *rules.CoreDumpDefinition) (*processSnapshot, error) {
+	var snapshot processSnapshot
+
+	cmdline, truncated, err := readNullSeparated(procPidPath(pid, "cmdline"), def.MaxEntries)
+	if err != nil {
+		return nil, err
+	}
+	if c.scrubber != nil {
+		cmdline, _ = c.scrubber.ScrubCommand(cmdline)
+	}
+	snapshot.cmdline = cmdline
+	snapshot.truncated = truncated
+
+	if def.Env {
+		envs, truncated, err := readNullSeparated(procPidPath(pid, "environ"), def.MaxEntries)
+		if err != nil {
+			return nil, err
+		}
+		if c.scrubber != nil {
+			envs, _ = c.scrubber.ScrubCommand(envs)
+		}
+		snapshot.env = envs
+		snapshot.truncated = snapshot.truncated || truncated
+	}
+
+	if def.OpenFiles {
+		files, truncated, err := readOpenFiles(pid, def.MaxEntries)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.openFiles = files
+		snapshot.truncated = snapshot.truncated || truncated
+	}
+
+	if def.MemoryMaps {
+		maps, truncated, err := readLines(procPidPath(pid, "maps"), def.MaxEntries)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.memoryMaps = maps
+		snapshot.truncated = snapshot.truncated || truncated
+	}
+
+	return &snapshot, nil
+}
+
+// DumpAndReport takes a bounded snapshot of the process of the event and report
+func (c *CoreDumper) DumpAndReport(rule *rules.Rule, def *rules.CoreDumpDefinition, ev *model.Event) {
+	report := &CoreDumpActionReport{
+		Pid:        ev.ProcessContext.Pid,
+		DetectedAt: ev.ResolveEventTime(),
+	}
+	ev.ActionReports = append(ev.ActionReports, report)
+
+	if !c.rateLimiter.Allow(rule.ID, rules.CoreDumpAction, def.RateLimit) {
+		report.Status = ActionStatusRateLimited
+		report.resolved = true
+		return
+	}
+
+	pid := ev.ProcessContext.Pid
+
+	go func() {
+		snapshot, err := c.dump(pid, def)
+
+		report.Lock()
+		defer report.Unlock()
+
+		if err != nil {
+			seclog.Debugf("failed to dump process %d: %s", pid, err)
+
+			report.Status = ActionStatusError
+			report.Error = err.Error()
+		} else {
+			report.Status = ActionStatusPerformed
+			report.Cmdline = snapshot.cmdline
+			report.Env = snapshot.env
+			report.OpenFiles = snapshot.openFiles
+			report.MemoryMaps = snapshot.memoryMaps
+			report.Truncated = snapshot.truncated
+			report.DumpedAt = time.Now()
+		}
+		report.resolved = true
+	}()
+}
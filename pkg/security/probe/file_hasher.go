@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package probe holds probe related files
+package probe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+)
+
+// FileHasher defines a file hasher structure
+type FileHasher struct {
+	rateLimiter *ActionRateLimiter
+}
+
+// NewFileHasher returns a new FileHasher
+func NewFileHasher(rateLimiter *ActionRateLimiter) *FileHasher {
+	return &FileHasher{
+		rateLimiter: rateLimiter,
+	}
+}
+
+// resolveFilePath returns the path of the file targeted by the hash action. When no field is specified, the file of
+// the event is used, or the file of the process if the event doesn't have one.
+func resolveFilePath(field string, ev *model.Event) (string, error) {
+	if field == "" {
+		field = ev.GetEventType().String() + ".file.path"
+		if _, err := ev.GetFieldValue(field); err != nil {
+			field = "process.file.path"
+		}
+	}
+
+	value, err := ev.GetFieldValue(field)
+	if err != nil {
+		return "", err
+	}
+
+	path, ok := value.(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("no file path for field '%s'", field)
+	}
+
+	return path, nil
+}
+
+func hashFile(pid uint32, path string, maxFileSize int64) (int64, string, error) {
+	// use the root of the process so that the file is resolved in its mount namespace
+	f, err := os.Open(utils.ProcRootFilePath(pid, path))
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return 0, "", errors.New("not a regular file")
+	}
+
+	if fi.Size() > maxFileSize {
+		return fi.Size(), "", fmt.Errorf("file size exceeds the limit of %d bytes", maxFileSize)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, io.LimitReader(f, maxFileSize)); err != nil {
+		return fi.Size(), "", err
+	}
+
+	return fi.Size(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashAndReport hash the file of the event and report
+func (h *FileHasher) HashAndReport(rule *rules.Rule, def *rules.HashDefinition, ev *model.Event) {
+	report := &HashActionReport{
+		DetectedAt: ev.ResolveEventTime(),
+	}
+	ev.ActionReports = append(ev.ActionReports, report)
+
+	path, err := resolveFilePath(def.Field, ev)
+	if err != nil {
+		report.Status = ActionStatusError
+		report.Error = err.Error()
+		report.resolved = true
+		return
+	}
+	report.Path = path
+
+	if !h.rateLimiter.Allow(rule.ID, rules.HashAction, def.RateLimit) {
+		report.Status = ActionStatusRateLimited
+		report.resolved = true
+		return
+	}
+
+	pid := ev.ProcessContext.Pid
+
+	go func() {
+		size, sum, err := hashFile(pid, path, def.MaxFileSize)
+
+		report.Lock()
+		defer report.Unlock()
+
+		report.Size = size
+		if err != nil {
+			seclog.Debugf("failed to hash file %s: %s", path, err)
+
+			report.Status = ActionStatusError
+			report.Error = err.Error()
+		} else {
+			report.Status = ActionStatusPerformed
+			report.SHA256 = sum
+			report.HashedAt = time.Now()
+		}
+		report.resolved = true
+	}()
+}
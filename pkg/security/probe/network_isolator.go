@@ -0,0 +1,252 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package probe holds probe related files
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+// NetworkIsolator defines a container network isolation structure
+type NetworkIsolator struct {
+	sync.Mutex
+	rateLimiter *ActionRateLimiter
+	// isolations are the isolated cgroups, indexed by path
+	isolations map[string]*cgroupIsolation
+}
+
+// cgroupIsolation holds the deny programs attached to an isolated cgroup
+type cgroupIsolation struct {
+	links []link.Link
+	timer *time.Timer
+}
+
+// detach stops the expiry timer and detaches the deny programs
+func (c *cgroupIsolation) detach() error {
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+
+	var errs error
+	for _, l := range c.links {
+		errs = errors.Join(errs, l.Close())
+	}
+	return errs
+}
+
+// NewNetworkIsolator returns a new NetworkIsolator
+func NewNetworkIsolator(rateLimiter *ActionRateLimiter) *NetworkIsolator {
+	return &NetworkIsolator{
+		rateLimiter: rateLimiter,
+		isolations:  make(map[string]*cgroupIsolation),
+	}
+}
+
+// cgroup2Path returns the path of the cgroup v2 of the given process
+func cgroup2Path(pid uint32) (string, error) {
+	data, err := os.ReadFile(kernel.HostProc(strconv.FormatUint(uint64(pid), 10), "cgroup"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read the cgroup of the process: %w", err)
+	}
+
+	var relativePath string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// the unified hierarchy is reported as "0::<path>"
+		if path, found := bytes.CutPrefix(scanner.Bytes(), []byte("0::")); found {
+			relativePath = string(path)
+			break
+		}
+	}
+	// never isolate the root cgroup
+	if relativePath == "" || relativePath == "/" {
+		return "", errors.New("process isn't in a cgroup v2")
+	}
+
+	root, err := cgroup2Root()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, relativePath), nil
+}
+
+// cgroup2Root returns the mount point of the cgroup v2 hierarchy
+func cgroup2Root() (string, error) {
+	// the unified hierarchy is mounted on "unified" on hybrid systems
+	for _, mountPoint := range []string{"fs/cgroup", "fs/cgroup/unified"} {
+		root := filepath.Join(kernel.SysFSRoot(), mountPoint)
+
+		var statfs unix.Statfs_t
+		if err := unix.Statfs(root, &statfs); err != nil || statfs.Type != unix.CGROUP2_SUPER_MAGIC {
+			continue
+		}
+		return root, nil
+	}
+
+	return "", errors.New("cgroup v2 hierarchy not found")
+}
+
+// attachDenyPrograms attaches a program dropping all the packets to the given cgroup, for both ingress
+// and egress. The host interfaces, which may be shared with other containers, are left untouched.
+func attachDenyPrograms(cgroupPath string) ([]link.Link, error) {
+	prog, err := ebpf.NewProgramWithOptions(&ebpf.ProgramSpec{
+		Type:    ebpf.CGroupSKB,
+		License: "GPL",
+		Instructions: asm.Instructions{
+			// deny
+			asm.Mov.Imm(asm.R0, 0),
+			asm.Return(),
+		},
+	}, ebpf.ProgramOptions{
+		LogDisabled: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the deny program: %w", err)
+	}
+	// the links hold their own reference on the program
+	defer prog.Close()
+
+	var links []link.Link
+	for _, attachType := range []ebpf.AttachType{ebpf.AttachCGroupInetEgress, ebpf.AttachCGroupInetIngress} {
+		// with multiple programs attached, a packet is dropped as soon as one of them denies it
+		l, err := link.AttachCgroup(link.CgroupOptions{
+			Path:    cgroupPath,
+			Attach:  attachType,
+			Program: prog,
+		})
+		if err != nil {
+			for _, l := range links {
+				_ = l.Close()
+			}
+			return nil, fmt.Errorf("failed to attach the deny program to %s: %w", cgroupPath, err)
+		}
+		links = append(links, l)
+	}
+
+	return links, nil
+}
+
+// isolate isolates the given cgroup from the network. The isolation is lifted after the given duration,
+// if any, or when the isolations are released.
+func (n *NetworkIsolator) isolate(cgroupPath string, duration time.Duration) error {
+	n.Lock()
+	defer n.Unlock()
+
+	if _, isolated := n.isolations[cgroupPath]; isolated {
+		return nil
+	}
+
+	links, err := attachDenyPrograms(cgroupPath)
+	if err != nil {
+		return err
+	}
+
+	isolation := &cgroupIsolation{links: links}
+	if duration > 0 {
+		isolation.timer = time.AfterFunc(duration, func() {
+			n.release(cgroupPath, isolation)
+		})
+	}
+	n.isolations[cgroupPath] = isolation
+
+	return nil
+}
+
+// release lifts the isolation of the given cgroup, unless it was already replaced
+func (n *NetworkIsolator) release(cgroupPath string, isolation *cgroupIsolation) {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.isolations[cgroupPath] != isolation {
+		return
+	}
+	delete(n.isolations, cgroupPath)
+
+	if err := isolation.detach(); err != nil {
+		seclog.Warnf("failed to release the isolation of %s: %s", cgroupPath, err)
+	}
+}
+
+// ReleaseAll lifts all the isolations, when the rules are reloaded or the probe is closed
+func (n *NetworkIsolator) ReleaseAll() {
+	n.Lock()
+	defer n.Unlock()
+
+	for cgroupPath, isolation := range n.isolations {
+		if err := isolation.detach(); err != nil {
+			seclog.Warnf("failed to release the isolation of %s: %s", cgroupPath, err)
+		}
+	}
+	clear(n.isolations)
+}
+
+// IsolateAndReport isolates the container of the event from the network and report
+func (n *NetworkIsolator) IsolateAndReport(rule *rules.Rule, def *rules.NetworkIsolationDefinition, ev *model.Event) {
+	report := &NetworkIsolationActionReport{
+		NetNS:      ev.ProcessContext.NetNS,
+		DetectedAt: ev.ResolveEventTime(),
+	}
+	ev.ActionReports = append(ev.ActionReports, report)
+
+	entry, exists := ev.ResolveProcessCacheEntry()
+	if !exists || entry.ContainerID == "" {
+		report.Status = ActionStatusError
+		report.Error = "process isn't running in a container"
+		report.resolved = true
+		return
+	}
+	report.ContainerID = entry.ContainerID
+
+	if !n.rateLimiter.Allow(rule.ID, rules.NetworkIsolationAction, def.RateLimit) {
+		report.Status = ActionStatusRateLimited
+		report.resolved = true
+		return
+	}
+
+	pid := ev.ProcessContext.Pid
+
+	go func() {
+		cgroupPath, err := cgroup2Path(pid)
+		if err == nil {
+			err = n.isolate(cgroupPath, def.Duration)
+		}
+
+		report.Lock()
+		defer report.Unlock()
+
+		report.CGroupPath = cgroupPath
+		if err != nil {
+			seclog.Warnf("failed to isolate container %s: %s", report.ContainerID, err)
+
+			report.Status = ActionStatusError
+			report.Error = err.Error()
+		} else {
+			report.Status = ActionStatusPerformed
+			report.IsolatedAt = time.Now()
+		}
+		report.resolved = true
+	}()
+}
@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package probe holds probe related files
+package probe
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/features"
+	"github.com/cilium/ebpf/link"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCGroup(t *testing.T) string {
+	if os.Geteuid() != 0 {
+		t.Skip("isolating a cgroup requires root")
+	}
+	if err := features.HaveProgramType(ebpf.CGroupSKB); err != nil {
+		t.Skipf("cgroup skb programs aren't supported: %s", err)
+	}
+
+	root, err := cgroup2Root()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	cgroupPath, err := os.MkdirTemp(root, "network-isolator-")
+	if err != nil {
+		t.Skipf("failed to create a cgroup: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Remove(cgroupPath)
+	})
+
+	return cgroupPath
+}
+
+func attachedPrograms(t *testing.T, cgroupPath string) int {
+	cgroup, err := os.Open(cgroupPath)
+	require.NoError(t, err)
+	defer cgroup.Close()
+
+	var count int
+	for _, attachType := range []ebpf.AttachType{ebpf.AttachCGroupInetEgress, ebpf.AttachCGroupInetIngress} {
+		result, err := link.QueryPrograms(link.QueryOptions{
+			Target: int(cgroup.Fd()),
+			Attach: attachType,
+		})
+		require.NoError(t, err)
+		count += len(result.Programs)
+	}
+	return count
+}
+
+func TestNetworkIsolatorRelease(t *testing.T) {
+	cgroupPath := newTestCGroup(t)
+
+	n := NewNetworkIsolator(NewActionRateLimiter())
+	defer n.ReleaseAll()
+
+	require.NoError(t, n.isolate(cgroupPath, 0))
+	assert.Equal(t, 2, attachedPrograms(t, cgroupPath))
+
+	// isolating again doesn't attach more programs
+	require.NoError(t, n.isolate(cgroupPath, 0))
+	assert.Equal(t, 2, attachedPrograms(t, cgroupPath))
+
+	n.ReleaseAll()
+	assert.Equal(t, 0, attachedPrograms(t, cgroupPath))
+	assert.Empty(t, n.isolations)
+}
+
+func TestNetworkIsolatorExpiry(t *testing.T) {
+	cgroupPath := newTestCGroup(t)
+
+	n := NewNetworkIsolator(NewActionRateLimiter())
+	defer n.ReleaseAll()
+
+	require.NoError(t, n.isolate(cgroupPath, 100*time.Millisecond))
+	assert.Equal(t, 2, attachedPrograms(t, cgroupPath))
+
+	assert.Eventually(t, func() bool {
+		return attachedPrograms(t, cgroupPath) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	n.Lock()
+	defer n.Unlock()
+	assert.Empty(t, n.isolations)
+}
@@ -130,6 +130,12 @@ type EBPFProbe struct {
 	supportsBPFSendSignal bool
 	processKiller         *ProcessKiller
 
+	// incident response actions
+	actionRateLimiter *ActionRateLimiter
+	fileHasher        *FileHasher
+	coreDumper        *CoreDumper
+	networkIsolator   *NetworkIsolator
+
 	isRuntimeDiscarded bool
 	constantOffsets    map[string]uint64
 	runtimeCompiled    bool
@@ -1289,6 +1295,8 @@ func (p *EBPFProbe) Close() error {
 	// we wait until both the reorderer and the monitor are stopped
 	p.wg.Wait()
 
+	p.networkIsolator.ReleaseAll()
+
 	ebpfcheck.RemoveNameMappings(p.Manager)
 	ebpftelemetry.UnregisterTelemetry(p.Manager)
 	// Stopping the manager will stop the perf map reader and unload eBPF programs
@@ -1397,6 +1405,11 @@ func (p *EBPFProbe) applyDefaultFilterPolicies() {
 
 // ApplyRuleSet apply the required update to handle the new ruleset
 func (p *EBPFProbe) ApplyRuleSet(rs *rules.RuleSet) (*kfilters.ApplyRuleSetReport, error) {
+	// rate limits may have changed with the new ruleset
+	p.actionRateLimiter.Reset()
+	// the rules that isolated the containers may be gone
+	p.networkIsolator.ReleaseAll()
+
 	if p.opts.SyscallsMonitorEnabled {
 		if err := p.monitors.syscallsMonitor.Disable(); err != nil {
 			return nil, err
@@ -1471,6 +1484,8 @@ func NewEBPFProbe(probe *Probe, config *config.Config, opts Opts, wmeta optional
 
 	ctx, cancelFnc := context.WithCancel(context.Background())
 
+	actionRateLimiter := NewActionRateLimiter()
+
 	p := &EBPFProbe{
 		probe:                probe,
 		config:               config,
@@ -1485,6 +1500,10 @@ func NewEBPFProbe(probe *Probe, config *config.Config, opts Opts, wmeta optional
 		ctx:                  ctx,
 		cancelFnc:            cancelFnc,
 		processKiller:        NewProcessKiller(),
+		actionRateLimiter:    actionRateLimiter,
+		fileHasher:           NewFileHasher(actionRateLimiter),
+		coreDumper:           NewCoreDumper(actionRateLimiter, probe.scrubber),
+		networkIsolator:      NewNetworkIsolator(actionRateLimiter),
 	}
 
 	if err := p.detectKernelVersion(); err != nil {
@@ -1966,6 +1985,15 @@ func (p *EBPFProbe) HandleActions(ctx *eval.Context, rule *rules.Rule) {
 				}
 				return p.processKiller.KillFromUserspace(pid, sig, ev)
 			})
+
+		case action.Hash != nil:
+			p.fileHasher.HashAndReport(rule, action.Hash, ev)
+
+		case action.CoreDump != nil:
+			p.coreDumper.DumpAndReport(rule, action.CoreDump, ev)
+
+		case action.NetworkIsolation != nil:
+			p.networkIsolator.IsolateAndReport(rule, action.NetworkIsolation, ev)
 		}
 	}
 }
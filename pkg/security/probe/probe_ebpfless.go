@@ -78,6 +78,12 @@ type EBPFLessProbe struct {
 	buf           []byte
 	clients       map[net.Conn]*client
 	processKiller *ProcessKiller
+
+	// incident response actions
+	actionRateLimiter *ActionRateLimiter
+	fileHasher        *FileHasher
+	coreDumper        *CoreDumper
+	networkIsolator   *NetworkIsolator
 }
 
 func (p *EBPFLessProbe) handleClientMsg(cl *client, msg *ebpfless.Message) {
@@ -371,6 +377,8 @@ func (p *EBPFLessProbe) Close() error {
 		delete(p.clients, conn)
 	}
 
+	p.networkIsolator.ReleaseAll()
+
 	return nil
 }
 
@@ -540,6 +548,11 @@ func (p *EBPFLessProbe) FlushDiscarders() error {
 
 // ApplyRuleSet applies the new ruleset
 func (p *EBPFLessProbe) ApplyRuleSet(_ *rules.RuleSet) (*kfilters.ApplyRuleSetReport, error) {
+	// rate limits may have changed with the new ruleset
+	p.actionRateLimiter.Reset()
+	// the rules that isolated the containers may be gone
+	p.networkIsolator.ReleaseAll()
+
 	return &kfilters.ApplyRuleSetReport{}, nil
 }
 
@@ -557,6 +570,15 @@ func (p *EBPFLessProbe) HandleActions(ctx *eval.Context, rule *rules.Rule) {
 			p.processKiller.KillAndReport(action.Kill.Scope, action.Kill.Signal, ev, func(pid uint32, sig uint32) error {
 				return p.processKiller.KillFromUserspace(pid, sig, ev)
 			})
+
+		case action.Hash != nil:
+			p.fileHasher.HashAndReport(rule, action.Hash, ev)
+
+		case action.CoreDump != nil:
+			p.coreDumper.DumpAndReport(rule, action.CoreDump, ev)
+
+		case action.NetworkIsolation != nil:
+			p.networkIsolator.IsolateAndReport(rule, action.NetworkIsolation, ev)
 		}
 	}
 }
@@ -598,6 +620,8 @@ func NewEBPFLessProbe(probe *Probe, config *config.Config, opts Opts) (*EBPFLess
 	ctx, cancelFnc := context.WithCancel(context.Background())
 
 	var grpcOpts []grpc.ServerOption
+	actionRateLimiter := NewActionRateLimiter()
+
 	p := &EBPFLessProbe{
 		probe:             probe,
 		config:            config,
@@ -609,6 +633,10 @@ func NewEBPFLessProbe(probe *Probe, config *config.Config, opts Opts) (*EBPFLess
 		buf:               make([]byte, 4096),
 		clients:           make(map[net.Conn]*client),
 		processKiller:     NewProcessKiller(),
+		actionRateLimiter: actionRateLimiter,
+		fileHasher:        NewFileHasher(actionRateLimiter),
+		coreDumper:        NewCoreDumper(actionRateLimiter, probe.scrubber),
+		networkIsolator:   NewNetworkIsolator(actionRateLimiter),
 		containerContexts: make(map[string]*ebpfless.ContainerContext),
 	}
 
@@ -185,9 +185,12 @@ type PolicyState struct {
 // RuleAction is used to report policy was loaded
 // easyjson:json
 type RuleAction struct {
-	Filter *string         `json:"filter,omitempty"`
-	Set    *RuleSetAction  `json:"set,omitempty"`
-	Kill   *RuleKillAction `json:"kill,omitempty"`
+	Filter           *string                     `json:"filter,omitempty"`
+	Set              *RuleSetAction              `json:"set,omitempty"`
+	Kill             *RuleKillAction             `json:"kill,omitempty"`
+	Hash             *RuleHashAction             `json:"hash,omitempty"`
+	CoreDump         *RuleCoreDumpAction         `json:"coredump,omitempty"`
+	NetworkIsolation *RuleNetworkIsolationAction `json:"network_isolation,omitempty"`
 }
 
 // RuleSetAction is used to report 'set' action
@@ -207,6 +210,26 @@ type RuleKillAction struct {
 	Scope  string `json:"scope,omitempty"`
 }
 
+// RuleHashAction is used to report the 'hash' action
+// easyjson:json
+type RuleHashAction struct {
+	Field       string `json:"field,omitempty"`
+	MaxFileSize int64  `json:"max_file_size,omitempty"`
+}
+
+// RuleCoreDumpAction is used to report the 'coredump' action
+// easyjson:json
+type RuleCoreDumpAction struct {
+	Env        bool `json:"env,omitempty"`
+	OpenFiles  bool `json:"open_files,omitempty"`
+	MemoryMaps bool `json:"memory_maps,omitempty"`
+	MaxEntries int  `json:"max_entries,omitempty"`
+}
+
+// RuleNetworkIsolationAction is used to report the 'network_isolation' action
+// easyjson:json
+type RuleNetworkIsolationAction struct{}
+
 // RulesetLoadedEvent is used to report that a new ruleset was loaded
 // easyjson:json
 type RulesetLoadedEvent struct {
@@ -267,6 +290,20 @@ func RuleStateFromDefinition(def *rules.RuleDefinition, status string, message s
 				Append: action.Set.Append,
 				Scope:  string(action.Set.Scope),
 			}
+		case action.Hash != nil:
+			ruleAction.Hash = &RuleHashAction{
+				Field:       action.Hash.Field,
+				MaxFileSize: action.Hash.MaxFileSize,
+			}
+		case action.CoreDump != nil:
+			ruleAction.CoreDump = &RuleCoreDumpAction{
+				Env:        action.CoreDump.Env,
+				OpenFiles:  action.CoreDump.OpenFiles,
+				MemoryMaps: action.CoreDump.MemoryMaps,
+				MaxEntries: action.CoreDump.MaxEntries,
+			}
+		case action.NetworkIsolation != nil:
+			ruleAction.NetworkIsolation = &RuleNetworkIsolationAction{}
 		}
 		ruleState.Actions = append(ruleState.Actions, ruleAction)
 	}
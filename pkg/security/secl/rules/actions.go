@@ -9,6 +9,8 @@ package rules
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/ast"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
@@ -21,13 +23,31 @@ type ActionName = string
 const (
 	// KillAction name a the kill action
 	KillAction ActionName = "kill"
+	// HashAction name of the hash action
+	HashAction ActionName = "hash"
+	// CoreDumpAction name of the coredump action
+	CoreDumpAction ActionName = "coredump"
+	// NetworkIsolationAction name of the network isolation action
+	NetworkIsolationAction ActionName = "network_isolation"
+)
+
+const (
+	// DefaultHashMaxFileSize default maximum size of the files hashed by the hash action
+	DefaultHashMaxFileSize = 32 * 1024 * 1024
+	// DefaultCoreDumpMaxEntries default number of entries reported per section of a coredump
+	DefaultCoreDumpMaxEntries = 128
+	// MaxCoreDumpMaxEntries maximum number of entries reported per section of a coredump
+	MaxCoreDumpMaxEntries = 1024
 )
 
 // ActionDefinition describes a rule action section
 type ActionDefinition struct {
-	Filter *string         `yaml:"filter"`
-	Set    *SetDefinition  `yaml:"set"`
-	Kill   *KillDefinition `yaml:"kill"`
+	Filter           *string                     `yaml:"filter"`
+	Set              *SetDefinition              `yaml:"set"`
+	Kill             *KillDefinition             `yaml:"kill"`
+	Hash             *HashDefinition             `yaml:"hash"`
+	CoreDump         *CoreDumpDefinition         `yaml:"coredump"`
+	NetworkIsolation *NetworkIsolationDefinition `yaml:"network_isolation"`
 
 	// internal
 	InternalCallback *InternalCallbackDefinition
@@ -36,15 +56,16 @@ type ActionDefinition struct {
 
 // Check returns an error if the action in invalid
 func (a *ActionDefinition) Check(opts PolicyLoaderOpts) error {
-	if a.Set == nil && a.InternalCallback == nil && a.Kill == nil {
-		return errors.New("either 'set' or 'kill' section of an action must be specified")
+	if a.Set == nil && a.InternalCallback == nil && a.Kill == nil && a.Hash == nil && a.CoreDump == nil && a.NetworkIsolation == nil {
+		return errors.New("either 'set', 'kill', 'hash', 'coredump' or 'network_isolation' section of an action must be specified")
 	}
 
-	if a.Set != nil {
-		if a.Kill != nil {
-			return errors.New("only of 'set' or 'kill' section of an action can be specified")
-		}
+	if a.sectionCount() > 1 {
+		return errors.New("only one of 'set', 'kill', 'hash', 'coredump' or 'network_isolation' section of an action can be specified")
+	}
 
+	switch {
+	case a.Set != nil:
 		if a.Set.Name == "" {
 			return errors.New("action name is empty")
 		}
@@ -52,7 +73,7 @@ func (a *ActionDefinition) Check(opts PolicyLoaderOpts) error {
 		if (a.Set.Value == nil && a.Set.Field == "") || (a.Set.Value != nil && a.Set.Field != "") {
 			return errors.New("either 'value' or 'field' must be specified")
 		}
-	} else if a.Kill != nil {
+	case a.Kill != nil:
 		if opts.DisableEnforcement {
 			a.Kill = nil
 			return errors.New("'kill' action is disabled globally")
@@ -65,11 +86,73 @@ func (a *ActionDefinition) Check(opts PolicyLoaderOpts) error {
 		if _, found := model.SignalConstants[a.Kill.Signal]; !found {
 			return fmt.Errorf("unsupported signal '%s'", a.Kill.Signal)
 		}
+	case a.Hash != nil:
+		if a.Hash.Field != "" && !strings.HasSuffix(a.Hash.Field, ".file.path") {
+			return fmt.Errorf("'%s' is not a valid file path field for the 'hash' action", a.Hash.Field)
+		}
+
+		if a.Hash.MaxFileSize < 0 {
+			return errors.New("'max_file_size' of the 'hash' action can't be negative")
+		}
+
+		if a.Hash.MaxFileSize == 0 {
+			a.Hash.MaxFileSize = DefaultHashMaxFileSize
+		}
+	case a.CoreDump != nil:
+		if a.CoreDump.MaxEntries < 0 || a.CoreDump.MaxEntries > MaxCoreDumpMaxEntries {
+			return fmt.Errorf("'max_entries' of the 'coredump' action has to be between 0 and %d", MaxCoreDumpMaxEntries)
+		}
+
+		if a.CoreDump.MaxEntries == 0 {
+			a.CoreDump.MaxEntries = DefaultCoreDumpMaxEntries
+		}
+	case a.NetworkIsolation != nil:
+		if opts.DisableEnforcement {
+			a.NetworkIsolation = nil
+			return errors.New("'network_isolation' action is disabled globally")
+		}
+
+		if a.NetworkIsolation.Duration < 0 {
+			return errors.New("'network_isolation.duration' can't be negative")
+		}
+	}
+
+	if rl := a.GetRateLimit(); rl != nil {
+		if rl.Limit < 0 {
+			return errors.New("'rate_limit.limit' can't be negative")
+		}
+
+		if rl.Period < 0 {
+			return errors.New("'rate_limit.period' can't be negative")
+		}
 	}
 
 	return nil
 }
 
+func (a *ActionDefinition) sectionCount() int {
+	var count int
+	for _, set := range []bool{a.Set != nil, a.Kill != nil, a.Hash != nil, a.CoreDump != nil, a.NetworkIsolation != nil} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// GetRateLimit returns the rate limit of the action, if any
+func (a *ActionDefinition) GetRateLimit() *RateLimitDefinition {
+	switch {
+	case a.Hash != nil:
+		return a.Hash.RateLimit
+	case a.CoreDump != nil:
+		return a.CoreDump.RateLimit
+	case a.NetworkIsolation != nil:
+		return a.NetworkIsolation.RateLimit
+	}
+	return nil
+}
+
 // CompileFilter compiles the filter expression
 func (a *ActionDefinition) CompileFilter(parsingContext *ast.ParsingContext, model eval.Model, evalOpts *eval.Opts) error {
 	if a.Filter == nil || *a.Filter == "" {
@@ -120,3 +203,33 @@ type KillDefinition struct {
 	Signal string `yaml:"signal"`
 	Scope  string `yaml:"scope"`
 }
+
+// RateLimitDefinition describes the rate limit of an action, per rule
+type RateLimitDefinition struct {
+	Limit  int           `yaml:"limit"`
+	Period time.Duration `yaml:"period"`
+}
+
+// HashDefinition describes the 'hash' section of a rule action
+type HashDefinition struct {
+	// Field is the file path field to hash, the file of the event is used by default
+	Field       string               `yaml:"field"`
+	MaxFileSize int64                `yaml:"max_file_size"`
+	RateLimit   *RateLimitDefinition `yaml:"rate_limit"`
+}
+
+// CoreDumpDefinition describes the 'coredump' section of a rule action
+type CoreDumpDefinition struct {
+	Env        bool                 `yaml:"env"`
+	OpenFiles  bool                 `yaml:"open_files"`
+	MemoryMaps bool                 `yaml:"memory_maps"`
+	MaxEntries int                  `yaml:"max_entries"`
+	RateLimit  *RateLimitDefinition `yaml:"rate_limit"`
+}
+
+// NetworkIsolationDefinition describes the 'network_isolation' section of a rule action
+type NetworkIsolationDefinition struct {
+	// Duration is how long the container stays isolated, until the rules are reloaded by default
+	Duration  time.Duration        `yaml:"duration"`
+	RateLimit *RateLimitDefinition `yaml:"rate_limit"`
+}
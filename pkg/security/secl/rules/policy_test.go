@@ -15,6 +15,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -852,3 +853,130 @@ broken
 		})
 	}
 }
+
+func TestActionResponse(t *testing.T) {
+	testPolicy := &PolicyDef{
+		Rules: []*RuleDefinition{{
+			ID:         "test_rule",
+			Expression: `open.file.path == "/tmp/test"`,
+			Actions: []*ActionDefinition{{
+				Hash: &HashDefinition{
+					Field: "process.file.path",
+				},
+			}, {
+				CoreDump: &CoreDumpDefinition{
+					Env:       true,
+					OpenFiles: true,
+				},
+			}, {
+				NetworkIsolation: &NetworkIsolationDefinition{
+					Duration: 10 * time.Minute,
+					RateLimit: &RateLimitDefinition{
+						Limit:  1,
+						Period: time.Minute,
+					},
+				},
+			}},
+		}},
+	}
+
+	rs, err := loadPolicyIntoProbeEvaluationRuleSet(t, testPolicy, PolicyLoaderOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rule := rs.RuleSets[DefaultRuleSetTagValue].GetRules()["test_rule"]
+	if !assert.NotNil(t, rule) {
+		return
+	}
+
+	assert.Equal(t, 3, len(rule.Definition.Actions))
+	assert.Equal(t, DefaultCoreDumpMaxEntries, rule.Definition.Actions[1].CoreDump.MaxEntries)
+	assert.Equal(t, time.Minute, rule.Definition.Actions[2].GetRateLimit().Period)
+	assert.Equal(t, 10*time.Minute, rule.Definition.Actions[2].NetworkIsolation.Duration)
+}
+
+func TestActionResponseInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *ActionDefinition
+		opts   PolicyLoaderOpts
+	}{
+		{
+			name: "multiple-sections",
+			action: &ActionDefinition{
+				Hash:     &HashDefinition{},
+				CoreDump: &CoreDumpDefinition{},
+			},
+		},
+		{
+			name: "hash-not-a-path",
+			action: &ActionDefinition{
+				Hash: &HashDefinition{
+					Field: "process.is_root",
+				},
+			},
+		},
+		{
+			name: "hash-unknown-field",
+			action: &ActionDefinition{
+				Hash: &HashDefinition{
+					Field: "process.unknown.file.path",
+				},
+			},
+		},
+		{
+			name: "coredump-too-many-entries",
+			action: &ActionDefinition{
+				CoreDump: &CoreDumpDefinition{
+					MaxEntries: MaxCoreDumpMaxEntries + 1,
+				},
+			},
+		},
+		{
+			name: "negative-rate-limit",
+			action: &ActionDefinition{
+				CoreDump: &CoreDumpDefinition{
+					RateLimit: &RateLimitDefinition{
+						Limit: -1,
+					},
+				},
+			},
+		},
+		{
+			name: "network-isolation-enforcement-disabled",
+			action: &ActionDefinition{
+				NetworkIsolation: &NetworkIsolationDefinition{},
+			},
+			opts: PolicyLoaderOpts{
+				DisableEnforcement: true,
+			},
+		},
+		{
+			name: "network-isolation-negative-duration",
+			action: &ActionDefinition{
+				NetworkIsolation: &NetworkIsolationDefinition{
+					Duration: -time.Minute,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testPolicy := &PolicyDef{
+				Rules: []*RuleDefinition{{
+					ID:         "test_rule",
+					Expression: `open.file.path == "/tmp/test"`,
+					Actions:    []*ActionDefinition{test.action},
+				}},
+			}
+
+			if _, err := loadPolicyIntoProbeEvaluationRuleSet(t, testPolicy, test.opts); err == nil {
+				t.Error("expected policy to fail to load")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
@@ -489,6 +489,12 @@ func (rs *RuleSet) AddRule(parsingContext *ast.ParsingContext, ruleDef *RuleDefi
 				rs.fieldEvaluators[action.Set.Field] = evaluator
 			}
 		}
+
+		if action.Hash != nil && action.Hash.Field != "" {
+			if _, err := rs.model.GetEvaluator(action.Hash.Field, ""); err != nil {
+				return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+			}
+		}
 	}
 
 	for _, event := range rule.GetEvaluator().EventTypes {
@@ -637,7 +643,7 @@ func (rs *RuleSet) runRuleActions(_ eval.Event, ctx *eval.Context, rule *Rule) e
 		}
 
 		switch {
-		// action.Kill has to handled by a ruleset listener
+		// action.Kill, action.Hash, action.CoreDump and action.NetworkIsolation have to handled by a ruleset listener
 		case action.Set != nil:
 			name := string(action.Set.Scope)
 			if name != "" {
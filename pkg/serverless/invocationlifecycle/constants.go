@@ -47,4 +47,6 @@ const (
 	sns                     = "sns"
 	sqs                     = "sqs"
 	functionURL             = "lambda-function-url"
+	kafka                   = "kafka"
+	stepFunctions           = "step-functions"
 )
@@ -189,3 +189,25 @@ func (lp *LifecycleProcessor) initFromLambdaFunctionURLEvent(event events.Lambda
 	lp.addTag(tagFunctionTriggerEventSourceArn, fmt.Sprintf("arn:aws:lambda:%v:%v:url:%v", region, accountID, functionName))
 	lp.addTags(trigger.GetTagsFromLambdaFunctionURLRequest(event))
 }
+
+func (lp *LifecycleProcessor) initFromKafkaEvent(event events.KafkaEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithKafkaEvent(event)
+	}
+
+	lp.requestHandler.event = event
+	lp.addTag(tagFunctionTriggerEventSource, kafka)
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractKafkaEventARN(event))
+	lp.addTags(trigger.GetTagsFromKafkaEvent(event))
+}
+
+func (lp *LifecycleProcessor) initFromStepFunctionEvent(event events.StepFunctionEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithStepFunctionEvent(event)
+	}
+
+	lp.requestHandler.event = event
+	lp.addTag(tagFunctionTriggerEventSource, stepFunctions)
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractStepFunctionEventARN(event))
+	lp.addTags(trigger.GetTagsFromStepFunctionEvent(event))
+}
@@ -230,6 +230,23 @@ func (lp *LifecycleProcessor) OnInvokeStart(startDetails *InvocationStartDetails
 		}
 		ev = event
 		lp.initFromLambdaFunctionURLEvent(event, region, account, resource)
+	case trigger.KafkaEvent:
+		var event events.KafkaEvent
+		if err := json.Unmarshal(payloadBytes, &event); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", kafka, err)
+			break
+		}
+		ev = event
+		lp.initFromKafkaEvent(event)
+	case trigger.StepFunctionEvent:
+		var payload events.StepFunctionPayload
+		if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", stepFunctions, err)
+			break
+		}
+		event := payload.Context()
+		ev = event
+		lp.initFromStepFunctionEvent(event)
 	default:
 		log.Debug("Skipping adding trigger types and inferred spans as a non-supported payload was received.")
 	}
@@ -1160,6 +1160,119 @@ func TestTriggerTypesLifecycleEventForEventBridge(t *testing.T) {
 	}, testProcessor.GetTags())
 }
 
+func TestTriggerTypesLifecycleEventForKafka(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getEventFromFile("kafka.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                        "false",
+		"function_trigger.event_source_arn": "arn:aws:kafka:us-east-1:123456789012:cluster/demo-cluster/751d2973-a626-431c-9d4e-d7975eb44dd7-2",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "kafka",
+		"kafka.topic":                       "mytopic",
+		"kafka.partition":                   "0",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForSelfManagedKafka(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getEventFromFile("self-managed-kafka.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                    "false",
+		"request_id":                    "test-request-id",
+		"function_trigger.event_source": "kafka",
+		"kafka.topic":                   "mytopic",
+		"kafka.partition":               "0",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForStepFunction(t *testing.T) {
+	for _, filename := range []string{"step-function.json", "step-function-legacy.json"} {
+		t.Run(filename, func(t *testing.T) {
+			startDetails := &InvocationStartDetails{
+				InvokeEventRawPayload: getEventFromFile(filename),
+				InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+			}
+
+			testProcessor := &LifecycleProcessor{
+				DetectLambdaLibrary: func() bool { return false },
+				ProcessTrace:        func(*api.Payload) {},
+			}
+
+			testProcessor.OnInvokeStart(startDetails)
+			testProcessor.OnInvokeEnd(&InvocationEndDetails{
+				RequestID: "test-request-id",
+			})
+			assert.Equal(t, map[string]string{
+				"cold_start":                        "false",
+				"function_trigger.event_source_arn": "arn:aws:states:us-east-1:123456789012:stateMachine:MyStateMachine",
+				"request_id":                        "test-request-id",
+				"function_trigger.event_source":     "step-functions",
+				"step_function.state_machine_name":  "MyStateMachine",
+				"step_function.execution_arn":       "arn:aws:states:us-east-1:123456789012:execution:MyStateMachine:aa6c9316-713a-41d4-9c30-61131716744f",
+				"step_function.state_name":          "InvokeLambda",
+			}, testProcessor.GetTags())
+			assert.Equal(t, uint64(8744517334633232975), testProcessor.GetExecutionInfo().TraceID)
+			assert.Equal(t, uint64(2584187595560372216), testProcessor.GetExecutionInfo().parentID)
+			assert.Equal(t, sampler.PriorityAutoKeep, testProcessor.GetExecutionInfo().SamplingPriority)
+		})
+	}
+}
+
+func TestKafkaInferredSpanTraceContext(t *testing.T) {
+	var tracePayload *api.Payload
+	startInvocationTime := time.Now()
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getEventFromFile("kafka.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+		StartTime:             startInvocationTime,
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary:  func() bool { return false },
+		ProcessTrace:         func(payload *api.Payload) { tracePayload = payload },
+		InferredSpansEnabled: true,
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+		EndTime:   startInvocationTime.Add(time.Second),
+	})
+
+	spans := tracePayload.TracerPayload.Chunks[0].Spans
+	assert.Equal(t, 2, len(spans))
+	executionSpan, kafkaSpan := spans[0], spans[1]
+	assert.Equal(t, "aws.kafka", kafkaSpan.Name)
+	assert.Equal(t, uint64(1234567890), kafkaSpan.TraceID)
+	assert.Equal(t, uint64(9876543210), kafkaSpan.ParentID)
+	assert.Equal(t, uint64(1234567890), executionSpan.TraceID)
+	assert.Equal(t, kafkaSpan.SpanID, executionSpan.ParentID)
+}
+
 // Helper function for reading test file
 func getEventFromFile(filename string) []byte {
 	event, err := os.ReadFile("../trace/testdata/event_samples/" + filename)
@@ -11,6 +11,7 @@ const (
 	apiName          = "apiname"
 	bucketARN        = "bucket_arn"
 	bucketName       = "bucketname"
+	bootstrapServers = "bootstrap_servers"
 	connectionID     = "connection_id"
 	detailType       = "detail_type"
 	endpoint         = "endpoint"
@@ -19,6 +20,8 @@ const (
 	eventSourceArn   = "event_source_arn"
 	eventType        = "event_type"
 	eventVersion     = "event_version"
+	executionARN     = "execution_arn"
+	executionName    = "execution_name"
 	httpURL          = "http.url"
 	httpMethod       = "http.method"
 	httpProtocol     = "http.protocol"
@@ -30,7 +33,9 @@ const (
 	objectKey        = "object_key"
 	objectSize       = "object_size"
 	objectETag       = "object_etag"
+	offset           = "offset"
 	operationName    = "operation_name"
+	partition        = "partition"
 	partitionKey     = "partition_key"
 	queueName        = "queuename"
 	receiptHandle    = "receipt_handle"
@@ -41,10 +46,14 @@ const (
 	shardID          = "shardid"
 	sizeBytes        = "size_bytes"
 	stage            = "stage"
+	stateMachineARN  = "state_machine_arn"
+	stateMachineName = "state_machine_name"
+	stateName        = "state_name"
 	streamName       = "streamname"
 	streamViewType   = "stream_view_type"
 	subject          = "subject"
 	tableName        = "tablename"
+	topic            = "topic"
 	topicName        = "topicname"
 	topicARN         = "topic_arn"
 
@@ -359,6 +359,59 @@ func (inferredSpan *InferredSpan) EnrichInferredSpanWithDynamoDBEvent(eventPaylo
 	}
 }
 
+// EnrichInferredSpanWithKafkaEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from an Amazon MSK
+// or self-managed Kafka event.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithKafkaEvent(eventPayload events.KafkaEvent) {
+	eventRecord, ok := eventPayload.FirstRecord()
+	if !ok {
+		return
+	}
+	serviceName := DetermineServiceName(serviceMapping, eventRecord.Topic, "lambda_kafka", "kafka")
+	inferredSpan.IsAsync = true
+	inferredSpan.Span.Name = "aws.kafka"
+	inferredSpan.Span.Service = serviceName
+	inferredSpan.Span.Start = eventRecord.Timestamp.UnixNano()
+	inferredSpan.Span.Resource = eventRecord.Topic
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:    "aws.kafka",
+		resourceNames:    eventRecord.Topic,
+		topic:            eventRecord.Topic,
+		partition:        strconv.FormatInt(eventRecord.Partition, 10),
+		offset:           strconv.FormatInt(eventRecord.Offset, 10),
+		bootstrapServers: eventPayload.BootstrapServers,
+	}
+	if eventPayload.EventSourceArn != "" {
+		inferredSpan.Span.Meta[eventSourceArn] = eventPayload.EventSourceArn
+	}
+}
+
+// EnrichInferredSpanWithStepFunctionEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from a Step Functions
+// context object.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithStepFunctionEvent(eventPayload events.StepFunctionEvent) {
+	stateMachine := eventPayload.StateMachine.Name
+	serviceName := DetermineServiceName(serviceMapping, stateMachine, "lambda_stepfunctions", "stepfunctions")
+	inferredSpan.IsAsync = false
+	inferredSpan.Span.Name = "aws.stepfunctions"
+	inferredSpan.Span.Service = serviceName
+	inferredSpan.Span.Start = formatISOStartTime(eventPayload.State.EnteredTime)
+	inferredSpan.Span.Resource = stateMachine
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:    "aws.stepfunctions",
+		resourceNames:    stateMachine,
+		stateMachineName: stateMachine,
+		stateMachineARN:  eventPayload.StateMachine.ID,
+		executionARN:     eventPayload.Execution.ID,
+		executionName:    eventPayload.Execution.Name,
+		stateName:        eventPayload.State.Name,
+	}
+}
+
 // CalculateStartTime converts AWS event timeEpochs to nanoseconds
 func calculateStartTime(epoch int64) int64 {
 	return epoch * 1e6
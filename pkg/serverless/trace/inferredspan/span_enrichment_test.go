@@ -1011,6 +1011,86 @@ func TestRemapsSpecificInferredSpanServiceNamesFromDynamoDBEvent(t *testing.T) {
 	assert.Equal(t, "dynamodb", span2.Service)
 }
 
+func TestEnrichInferredSpanWithKafkaEvent(t *testing.T) {
+	var kafkaRequest events.KafkaEvent
+	_ = json.Unmarshal(getEventFromFile("kafka.json"), &kafkaRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, uint64(7353030974370088224), span.TraceID)
+	assert.Equal(t, uint64(8048964810003407541), span.SpanID)
+	assert.Equal(t, int64(1545084650987000000), span.Start)
+	assert.Equal(t, "kafka", span.Service)
+	assert.Equal(t, "aws.kafka", span.Name)
+	assert.Equal(t, "mytopic", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.kafka", span.Meta[operationName])
+	assert.Equal(t, "mytopic", span.Meta[resourceNames])
+	assert.Equal(t, "mytopic", span.Meta[topic])
+	assert.Equal(t, "0", span.Meta[partition])
+	assert.Equal(t, "15", span.Meta[offset])
+	assert.Equal(t, "b-1.demo-cluster.a1bcde.c1.kafka.us-east-1.amazonaws.com:9092", span.Meta[bootstrapServers])
+	assert.Equal(t, "arn:aws:kafka:us-east-1:123456789012:cluster/demo-cluster/751d2973-a626-431c-9d4e-d7975eb44dd7-2", span.Meta[eventSourceArn])
+	assert.True(t, inferredSpan.IsAsync)
+}
+
+func TestEnrichInferredSpanWithSelfManagedKafkaEvent(t *testing.T) {
+	var kafkaRequest events.KafkaEvent
+	_ = json.Unmarshal(getEventFromFile("self-managed-kafka.json"), &kafkaRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, "aws.kafka", span.Name)
+	assert.Equal(t, "mytopic", span.Resource)
+	assert.NotContains(t, span.Meta, eventSourceArn)
+	assert.True(t, inferredSpan.IsAsync)
+}
+
+func TestRemapsSpecificInferredSpanServiceNamesFromKafkaEvent(t *testing.T) {
+	origServiceMapping := GetServiceMapping()
+	defer func() {
+		SetServiceMapping(origServiceMapping)
+	}()
+	SetServiceMapping(map[string]string{
+		"mytopic":      "accepted-name",
+		"lambda_kafka": "generic-name",
+	})
+
+	var kafkaRequest events.KafkaEvent
+	_ = json.Unmarshal(getEventFromFile("kafka.json"), &kafkaRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+	assert.Equal(t, "accepted-name", inferredSpan.Span.Service)
+
+	kafkaRequest.Records["mytopic-0"][0].Topic = "othertopic"
+	inferredSpan2 := mockInferredSpan()
+	inferredSpan2.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+	assert.Equal(t, "generic-name", inferredSpan2.Span.Service)
+}
+
+func TestEnrichInferredSpanWithStepFunctionEvent(t *testing.T) {
+	var stepFunctionRequest events.StepFunctionEvent
+	_ = json.Unmarshal(getEventFromFile("step-function.json"), &stepFunctionRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithStepFunctionEvent(stepFunctionRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, uint64(7353030974370088224), span.TraceID)
+	assert.Equal(t, uint64(8048964810003407541), span.SpanID)
+	assert.Equal(t, int64(1722369353018000000), span.Start)
+	assert.Equal(t, "stepfunctions", span.Service)
+	assert.Equal(t, "aws.stepfunctions", span.Name)
+	assert.Equal(t, "MyStateMachine", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.stepfunctions", span.Meta[operationName])
+	assert.Equal(t, "MyStateMachine", span.Meta[resourceNames])
+	assert.Equal(t, "MyStateMachine", span.Meta[stateMachineName])
+	assert.Equal(t, "arn:aws:states:us-east-1:123456789012:stateMachine:MyStateMachine", span.Meta[stateMachineARN])
+	assert.Equal(t, "arn:aws:states:us-east-1:123456789012:execution:MyStateMachine:aa6c9316-713a-41d4-9c30-61131716744f", span.Meta[executionARN])
+	assert.Equal(t, "aa6c9316-713a-41d4-9c30-61131716744f", span.Meta[executionName])
+	assert.Equal(t, "InvokeLambda", span.Meta[stateName])
+	assert.False(t, inferredSpan.IsAsync)
+}
+
 func TestFormatISOStartTime(t *testing.T) {
 	isotime := "2022-01-31T14:13:41.637Z"
 	startTime := formatISOStartTime(isotime)
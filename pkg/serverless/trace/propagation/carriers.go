@@ -7,7 +7,9 @@
 package propagation
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"regexp"
@@ -46,6 +48,7 @@ var (
 	errorUnsupportedValueType   = errors.New("Unsupported value type in _datadog payload")
 	errorUnsupportedTypeValue   = errors.New("Unsupported Type in _datadog payload")
 	errorCouldNotUnmarshal      = errors.New("Could not unmarshal the invocation event payload")
+	errorNoStepFunctionContext  = errors.New("Step Functions context does not contain execution ARN, state name and entered time")
 )
 
 // extractTraceContextfromAWSTraceHeader extracts trace context from the
@@ -201,6 +204,47 @@ func snsEntityCarrier(event events.SNSEntity) (tracer.TextMapReader, error) {
 	return carrier, nil
 }
 
+// kafkaRecordCarrier returns the tracer.TextMapReader used to extract trace
+// context from the headers of an events.KafkaRecord type.
+func kafkaRecordCarrier(record events.KafkaRecord) (tracer.TextMapReader, error) {
+	carrier := make(tracer.TextMapCarrier)
+	for _, headers := range record.Headers {
+		for key, value := range headers {
+			carrier[key] = string(value)
+		}
+	}
+	return carrier, nil
+}
+
+// extractTraceContextFromStepFunctionContext derives the trace context from
+// the Step Functions context object. The trace ID is a hash of the execution
+// ARN and the parent ID a hash of the execution ARN, state name and entered
+// time, so that the spans generated for the state machine execution and for
+// the function share the same IDs. Like extractTraceContextfromAWSTraceHeader,
+// it should not be passed to the tracer.Propagator.
+func extractTraceContextFromStepFunctionContext(event events.StepFunctionEvent) (*TraceContext, error) {
+	execution, state := event.Execution, event.State
+	if execution.ID == "" || state.Name == "" || state.EnteredTime == "" {
+		return nil, errorNoStepFunctionContext
+	}
+	return &TraceContext{
+		TraceID:          stepFunctionHash(execution.ID, false),
+		ParentID:         stepFunctionHash(execution.ID+"#"+state.Name+"#"+state.EnteredTime, true),
+		SamplingPriority: sampler.PriorityAutoKeep,
+	}, nil
+}
+
+// stepFunctionHash returns the higher or lower 64 bits of the SHA-256 hash of
+// the given string, with the most significant bit cleared.
+func stepFunctionHash(s string, higher bool) uint64 {
+	hash := sha256.Sum256([]byte(s))
+	bits := hash[8:16]
+	if higher {
+		bits = hash[0:8]
+	}
+	return binary.BigEndian.Uint64(bits) & 0x7fffffffffffffff
+}
+
 type invocationPayload struct {
 	Headers tracer.TextMapCarrier `json:"headers"`
 }
@@ -7,6 +7,7 @@ package propagation
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -638,6 +639,72 @@ func TestSqsMessageCarrier(t *testing.T) {
 	}
 }
 
+func TestKafkaRecordCarrier(t *testing.T) {
+	testcases := []struct {
+		name   string
+		event  events.KafkaRecord
+		expMap map[string]string
+	}{
+		{
+			name:   "no-headers",
+			event:  events.KafkaRecord{},
+			expMap: headersMapEmpty,
+		},
+		{
+			name:   "headers-all",
+			event:  eventKafkaRecord(headersMapAll),
+			expMap: headersMapAll,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, err := kafkaRecordCarrier(tc.event)
+			t.Logf("kafkaRecordCarrier returned TextMapReader=%#v error=%#v", tm, err)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expMap, getMapFromCarrier(tm))
+		})
+	}
+}
+
+func TestKafkaHeaderValueUnmarshal(t *testing.T) {
+	var record events.KafkaRecord
+	err := json.Unmarshal([]byte(`{"headers":[{"key":[118,97,108,-1]}]}`), &record)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]events.KafkaHeaderValue{{"key": {'v', 'a', 'l', 0xff}}}, record.Headers)
+}
+
+func TestExtractTraceContextFromStepFunctionContext(t *testing.T) {
+	sfnContext := events.StepFunctionEvent{
+		Execution: events.StepFunctionExecution{
+			ID: "arn:aws:states:us-east-1:123456789012:execution:MyStateMachine:aa6c9316-713a-41d4-9c30-61131716744f",
+		},
+		State: events.StepFunctionState{
+			Name:        "InvokeLambda",
+			EnteredTime: "2024-07-30T19:55:53.018Z",
+		},
+	}
+
+	tc, err := extractTraceContextFromStepFunctionContext(sfnContext)
+	assert.NoError(t, err)
+	assert.Equal(t, &TraceContext{
+		TraceID:          8744517334633232975,
+		ParentID:         2584187595560372216,
+		SamplingPriority: sampler.PriorityAutoKeep,
+	}, tc)
+
+	// A retry of the state gets a new parent ID within the same trace
+	sfnContext.State.EnteredTime = "2024-07-30T19:55:55.018Z"
+	retry, err := extractTraceContextFromStepFunctionContext(sfnContext)
+	assert.NoError(t, err)
+	assert.Equal(t, tc.TraceID, retry.TraceID)
+	assert.NotEqual(t, tc.ParentID, retry.ParentID)
+
+	sfnContext.State = events.StepFunctionState{}
+	_, err = extractTraceContextFromStepFunctionContext(sfnContext)
+	assert.Error(t, err)
+}
+
 func TestRawPayloadCarrier(t *testing.T) {
 	testcases := []struct {
 		name   string
@@ -33,6 +33,7 @@ var (
 	errorNoContextFound            = errors.New("No trace context found")
 	errorNoSQSRecordFound          = errors.New("No sqs message records found for trace context extraction")
 	errorNoSNSRecordFound          = errors.New("No sns message records found for trace context extraction")
+	errorNoKafkaRecordFound        = errors.New("No kafka records found for trace context extraction")
 	errorNoTraceIDFound            = errors.New("No trace ID found")
 	errorNoParentIDFound           = errors.New("No parent ID found")
 )
@@ -100,6 +101,16 @@ func (e Extractor) extract(event interface{}) (*TraceContext, error) {
 		return nil, errorNoSNSRecordFound
 	case events.SNSEntity:
 		carrier, err = snsEntityCarrier(ev)
+	case events.KafkaEvent:
+		// look for context in just the first record
+		if record, ok := ev.FirstRecord(); ok {
+			return e.extract(record)
+		}
+		return nil, errorNoKafkaRecordFound
+	case events.KafkaRecord:
+		carrier, err = kafkaRecordCarrier(ev)
+	case events.StepFunctionEvent:
+		return extractTraceContextFromStepFunctionContext(ev)
 	case events.APIGatewayProxyRequest:
 		carrier, err = headersCarrier(ev.Headers)
 	case events.APIGatewayV2HTTPRequest:
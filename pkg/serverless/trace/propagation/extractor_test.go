@@ -152,6 +152,14 @@ var (
 		return e
 	}
 
+	eventKafkaRecord = func(hdrs map[string]string) events.KafkaRecord {
+		e := events.KafkaRecord{}
+		for key, value := range hdrs {
+			e.Headers = append(e.Headers, map[string]events.KafkaHeaderValue{key: events.KafkaHeaderValue(value)})
+		}
+		return e
+	}
+
 	eventSnsEntity = func(binHdrs, strHdrs string) events.SNSEntity {
 		e := events.SNSEntity{}
 		if len(binHdrs) > 0 && len(strHdrs) == 0 {
@@ -479,6 +487,39 @@ func TestExtractorExtract(t *testing.T) {
 			expNoErr: true,
 		},
 
+		// events.KafkaEvent
+		{
+			name: "kafka-event-no-records",
+			events: []interface{}{
+				events.KafkaEvent{},
+			},
+			expCtx:   nil,
+			expNoErr: false,
+		},
+		{
+			name: "kafka-event-uses-first-record",
+			events: []interface{}{
+				events.KafkaEvent{
+					Records: map[string][]events.KafkaRecord{
+						"topic-1": {eventKafkaRecord(headersMapW3C)},
+						"topic-0": {eventKafkaRecord(headersMapDD), eventKafkaRecord(headersMapW3C)},
+					},
+				},
+			},
+			expCtx:   ddTraceContext,
+			expNoErr: true,
+		},
+
+		// events.StepFunctionEvent
+		{
+			name: "step-function-event-no-context",
+			events: []interface{}{
+				events.StepFunctionEvent{},
+			},
+			expCtx:   nil,
+			expNoErr: false,
+		},
+
 		// multiple events
 		{
 			name: "multiple-events-1",
@@ -592,6 +633,42 @@ func TestExtractorExtractPayloadJson(t *testing.T) {
 				SamplingPriority: 1,
 			},
 		},
+		{
+			filename: "kafka.json",
+			eventTyp: "KafkaEvent",
+			expCtx: &TraceContext{
+				TraceID:          1234567890,
+				ParentID:         9876543210,
+				SamplingPriority: 1,
+			},
+		},
+		{
+			filename: "self-managed-kafka.json",
+			eventTyp: "KafkaEvent",
+			expCtx: &TraceContext{
+				TraceID:          1234567890,
+				ParentID:         9876543210,
+				SamplingPriority: 1,
+			},
+		},
+		{
+			filename: "step-function.json",
+			eventTyp: "StepFunctionEvent",
+			expCtx: &TraceContext{
+				TraceID:          8744517334633232975,
+				ParentID:         2584187595560372216,
+				SamplingPriority: 1,
+			},
+		},
+		{
+			filename: "step-function-legacy.json",
+			eventTyp: "StepFunctionEvent",
+			expCtx: &TraceContext{
+				TraceID:          8744517334633232975,
+				ParentID:         2584187595560372216,
+				SamplingPriority: 1,
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -616,6 +693,16 @@ func TestExtractorExtractPayloadJson(t *testing.T) {
 				err = json.Unmarshal(body, &event)
 				assert.NoError(t, err)
 				ev = event
+			case "KafkaEvent":
+				var event events.KafkaEvent
+				err = json.Unmarshal(body, &event)
+				assert.NoError(t, err)
+				ev = event
+			case "StepFunctionEvent":
+				var payload events.StepFunctionPayload
+				err = json.Unmarshal(body, &payload)
+				assert.NoError(t, err)
+				ev = payload.Context()
 			default:
 				t.Fatalf("bad type: %s", tc.eventTyp)
 			}
@@ -76,6 +76,12 @@ const (
 
 	// LambdaFunctionURLEvent describes an event from an HTTP lambda function URL invocation
 	LambdaFunctionURLEvent
+
+	// KafkaEvent describes an event from Amazon MSK or a self-managed Kafka cluster
+	KafkaEvent
+
+	// StepFunctionEvent describes an event from a Step Functions state machine
+	StepFunctionEvent
 )
 
 // eventParseFunc defines the signature of AWS event parsing functions
@@ -110,6 +116,8 @@ var (
 		{isAppSyncResolverEvent, AppSyncResolverEvent},
 		{isEventBridgeEvent, EventBridgeEvent},
 		{isLambdaFunctionURLEvent, LambdaFunctionURLEvent},
+		{isKafkaEvent, KafkaEvent},
+		{isStepFunctionEvent, StepFunctionEvent},
 		// Ultimately check this is a Kong API Gateway event as a last resort.
 		// This is because Kong API Gateway events are a subset of API Gateway events
 		// as of https://github.com/Kong/kong/blob/348c980/kong/plugins/aws-lambda/request-util.lua#L248-L260
@@ -270,6 +278,36 @@ func isLambdaFunctionURLEvent(event map[string]any) bool {
 	return strings.Contains(lambdaURL, "lambda-url")
 }
 
+func isKafkaEvent(event map[string]any) bool {
+	eventSource, ok := json.GetNestedValue(event, "eventsource").(string)
+	if !ok || (eventSource != "aws:kafka" && eventSource != "selfmanagedkafka") {
+		return false
+	}
+	_, ok = json.GetNestedValue(event, "records").(map[string]any)
+	return ok
+}
+
+// isStepFunctionEvent returns true when the payload is a Step Functions context
+// object, either passed as the payload itself or nested under the payload key
+// (legacy Lambda Invoke task) or the _datadog key.
+func isStepFunctionEvent(event map[string]any) bool {
+	if isStepFunctionContext(event) {
+		return true
+	}
+	for _, key := range []string{"_datadog", "payload"} {
+		if nested, ok := event[key].(map[string]any); ok && isStepFunctionContext(nested) {
+			return true
+		}
+	}
+	return false
+}
+
+func isStepFunctionContext(event map[string]any) bool {
+	return json.GetNestedValue(event, "execution", "id") != nil &&
+		json.GetNestedValue(event, "statemachine") != nil &&
+		json.GetNestedValue(event, "state", "name") != nil
+}
+
 func eventRecordsKeyExists(event map[string]any, key string) bool {
 	records, ok := json.GetNestedValue(event, "records").([]interface{})
 	if !ok {
@@ -336,6 +374,10 @@ func (et AWSEventType) String() string {
 		return "EventBridgeEvent"
 	case LambdaFunctionURLEvent:
 		return "LambdaFunctionURLEvent"
+	case KafkaEvent:
+		return "KafkaEvent"
+	case StepFunctionEvent:
+		return "StepFunctionEvent"
 	default:
 		return fmt.Sprintf("EventType(%d)", et)
 	}
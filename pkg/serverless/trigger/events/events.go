@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -307,6 +308,109 @@ type SQSMessageAttribute struct {
 	DataType    string
 }
 
+// KafkaEvent mirrors events.KafkaEvent type, removing unused fields. It is
+// used for both Amazon MSK and self-managed Kafka events.
+type KafkaEvent struct {
+	EventSource      string
+	EventSourceArn   string
+	BootstrapServers string
+	Records          map[string][]KafkaRecord
+}
+
+// FirstRecord returns the first record of the topic partition sorting first,
+// as the records are grouped by topic partition in a map.
+func (e KafkaEvent) FirstRecord() (KafkaRecord, bool) {
+	keys := make([]string, 0, len(e.Records))
+	for key, records := range e.Records {
+		if len(records) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return KafkaRecord{}, false
+	}
+	sort.Strings(keys)
+	return e.Records[keys[0]][0], true
+}
+
+// KafkaRecord mirrors events.KafkaRecord type, removing unused fields.
+type KafkaRecord struct {
+	Topic     string
+	Partition int64
+	Offset    int64
+	Timestamp events.MilliSecondsEpochTime
+	Headers   []map[string]KafkaHeaderValue
+}
+
+// KafkaHeaderValue is the value of a Kafka record header. Lambda sends header
+// values as arrays of (possibly signed) bytes rather than base64 strings, so
+// they can't be unmarshalled into a []byte directly.
+type KafkaHeaderValue []byte
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *KafkaHeaderValue) UnmarshalJSON(data []byte) error {
+	var values []int
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = make(KafkaHeaderValue, len(values))
+	for i, value := range values {
+		(*v)[i] = byte(value)
+	}
+	return nil
+}
+
+// StepFunctionEvent is used for unmarshalling the Step Functions context object
+// passed to a function invoked by a state machine. AWS Go libraries do not
+// provide this type of event for deserialization.
+type StepFunctionEvent struct {
+	Execution    StepFunctionExecution
+	StateMachine StepFunctionStateMachine
+	State        StepFunctionState
+}
+
+// StepFunctionExecution is the execution of a Step Functions context object.
+type StepFunctionExecution struct {
+	ID        string `json:"Id"`
+	Name      string
+	StartTime string
+}
+
+// StepFunctionStateMachine is the state machine of a Step Functions context
+// object.
+type StepFunctionStateMachine struct {
+	ID   string `json:"Id"`
+	Name string
+}
+
+// StepFunctionState is the current state of a Step Functions context object.
+type StepFunctionState struct {
+	Name        string
+	EnteredTime string
+	RetryCount  int
+}
+
+// StepFunctionPayload is used for unmarshalling the payload of a function
+// invoked by a state machine. The context object is either the payload itself,
+// or nested under the Payload key (legacy Lambda Invoke task) or the _datadog
+// key.
+type StepFunctionPayload struct {
+	StepFunctionEvent
+	Payload *StepFunctionEvent
+	Datadog *StepFunctionEvent `json:"_datadog"`
+}
+
+// Context returns the Step Functions context object found in the payload.
+func (p StepFunctionPayload) Context() StepFunctionEvent {
+	if p.Datadog != nil && p.Datadog.Execution.ID != "" {
+		return *p.Datadog
+	}
+	if p.Payload != nil && p.Payload.Execution.ID != "" {
+		return *p.Payload
+	}
+	return p.StepFunctionEvent
+}
+
 // LambdaFunctionURLRequest mirrors events.LambdaFunctionURLRequest type,
 // removing unused fields.
 type LambdaFunctionURLRequest struct {
@@ -37,6 +37,9 @@ func TestEventPayloadParsing(t *testing.T) {
 		"sns.json":                            isSNSEvent,
 		"sqs.json":                            isSQSEvent,
 		"lambdaurl.json":                      isLambdaFunctionURLEvent,
+		"kafka.json":                          isKafkaEvent,
+		"self-managed-kafka.json":             isKafkaEvent,
+		"step-function.json":                  isStepFunctionEvent,
 	}
 	for testFile, testFunc := range testCases {
 		file, err := os.Open(fmt.Sprintf("%v/%v", testDir, testFile))
@@ -71,6 +74,8 @@ func TestEventPayloadParsingWrong(t *testing.T) {
 		"sns.json":                            isSNSEvent,
 		"sqs.json":                            isSQSEvent,
 		"lambdaurl.json":                      isLambdaFunctionURLEvent,
+		"kafka.json":                          isKafkaEvent,
+		"step-function.json":                  isStepFunctionEvent,
 	}
 	for correctTestFile, testFunc := range testCases {
 		wrongTestFiles, err := os.ReadDir(testDir)
@@ -81,6 +86,10 @@ func TestEventPayloadParsingWrong(t *testing.T) {
 				// skip testing the correct case
 				continue
 			}
+			if correctTestFile == "kafka.json" && wrongTestFile.Name() == "self-managed-kafka.json" {
+				// both Amazon MSK and self-managed Kafka events are Kafka events
+				continue
+			}
 			file, err := os.Open(fmt.Sprintf("%v/%v", testDir, wrongTestFile.Name()))
 			assert.NoError(t, err)
 
@@ -115,6 +124,9 @@ func TestGetEventType(t *testing.T) {
 		"sns.json":                            SNSEvent,
 		"sqs.json":                            SQSEvent,
 		"lambdaurl.json":                      LambdaFunctionURLEvent,
+		"kafka.json":                          KafkaEvent,
+		"self-managed-kafka.json":             KafkaEvent,
+		"step-function.json":                  StepFunctionEvent,
 	}
 
 	for testFile, expectedEventType := range testCases {
@@ -101,6 +101,18 @@ func ExtractSQSEventARN(event events.SQSEvent) string {
 	return event.Records[0].EventSourceARN
 }
 
+// ExtractKafkaEventARN returns the ARN of the Amazon MSK cluster from a
+// KafkaEvent. It is empty for self-managed Kafka events.
+func ExtractKafkaEventARN(event events.KafkaEvent) string {
+	return event.EventSourceArn
+}
+
+// ExtractStepFunctionEventARN returns the ARN of the state machine from a
+// StepFunctionEvent
+func ExtractStepFunctionEventARN(event events.StepFunctionEvent) string {
+	return event.StateMachine.ID
+}
+
 // GetTagsFromAPIGatewayEvent returns a tagset containing http tags from an
 // APIGatewayProxyRequest
 func GetTagsFromAPIGatewayEvent(event events.APIGatewayProxyRequest) map[string]string {
@@ -219,6 +231,35 @@ func GetTagsFromLambdaFunctionURLRequest(event events.LambdaFunctionURLRequest)
 	return httpTags
 }
 
+// GetTagsFromKafkaEvent returns a tagset containing the topic and partition
+// of the first record of a KafkaEvent
+func GetTagsFromKafkaEvent(event events.KafkaEvent) map[string]string {
+	record, ok := event.FirstRecord()
+	if !ok {
+		return nil
+	}
+	return map[string]string{
+		"kafka.topic":     record.Topic,
+		"kafka.partition": strconv.FormatInt(record.Partition, 10),
+	}
+}
+
+// GetTagsFromStepFunctionEvent returns a tagset containing the state machine,
+// execution and state tags from a StepFunctionEvent
+func GetTagsFromStepFunctionEvent(event events.StepFunctionEvent) map[string]string {
+	tags := make(map[string]string, 3)
+	if event.StateMachine.Name != "" {
+		tags["step_function.state_machine_name"] = event.StateMachine.Name
+	}
+	if event.Execution.ID != "" {
+		tags["step_function.execution_arn"] = event.Execution.ID
+	}
+	if event.State.Name != "" {
+		tags["step_function.state_name"] = event.State.Name
+	}
+	return tags
+}
+
 // GetStatusCodeFromHTTPResponse parses a generic payload and returns
 // a status code, if it contains one. Returns an empty string if it does not,
 // or an error in case of json parsing error.
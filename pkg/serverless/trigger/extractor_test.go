@@ -253,6 +253,27 @@ func TestExtractFunctionURLEventARN(t *testing.T) {
 	}, httpTags)
 }
 
+func TestExtractKafkaEventARN(t *testing.T) {
+	event := events.KafkaEvent{
+		EventSource:    "aws:kafka",
+		EventSourceArn: "arn:aws:kafka:us-east-1:123456789012:cluster/demo-cluster/abc",
+	}
+
+	arn := ExtractKafkaEventARN(event)
+	assert.Equal(t, "arn:aws:kafka:us-east-1:123456789012:cluster/demo-cluster/abc", arn)
+}
+
+func TestExtractStepFunctionEventARN(t *testing.T) {
+	event := events.StepFunctionEvent{
+		StateMachine: events.StepFunctionStateMachine{
+			ID: "arn:aws:states:us-east-1:123456789012:stateMachine:MyStateMachine",
+		},
+	}
+
+	arn := ExtractStepFunctionEventARN(event)
+	assert.Equal(t, "arn:aws:states:us-east-1:123456789012:stateMachine:MyStateMachine", arn)
+}
+
 func TestGetTagsFromAPIGatewayEvent(t *testing.T) {
 	event := events.APIGatewayProxyRequest{
 		Headers: map[string]string{
@@ -378,6 +399,42 @@ func TestGetTagsFromFunctionURLRequest(t *testing.T) {
 	}, httpTags)
 }
 
+func TestGetTagsFromKafkaEvent(t *testing.T) {
+	event := events.KafkaEvent{
+		Records: map[string][]events.KafkaRecord{
+			"topic-b-0": {{Topic: "topic-b", Partition: 0}},
+			"topic-a-3": {{Topic: "topic-a", Partition: 3}},
+			"topic-a-1": {},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"kafka.topic":     "topic-a",
+		"kafka.partition": "3",
+	}, GetTagsFromKafkaEvent(event))
+	assert.Nil(t, GetTagsFromKafkaEvent(events.KafkaEvent{}))
+}
+
+func TestGetTagsFromStepFunctionEvent(t *testing.T) {
+	event := events.StepFunctionEvent{
+		Execution: events.StepFunctionExecution{
+			ID: "arn:aws:states:us-east-1:123456789012:execution:MyStateMachine:abc",
+		},
+		StateMachine: events.StepFunctionStateMachine{
+			Name: "MyStateMachine",
+		},
+		State: events.StepFunctionState{
+			Name: "InvokeLambda",
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"step_function.state_machine_name": "MyStateMachine",
+		"step_function.execution_arn":      "arn:aws:states:us-east-1:123456789012:execution:MyStateMachine:abc",
+		"step_function.state_name":         "InvokeLambda",
+	}, GetTagsFromStepFunctionEvent(event))
+}
+
 func TestExtractStatusCodeFromHTTPResponse(t *testing.T) {
 	noStatusCodePayload := []byte(`{}`)
 
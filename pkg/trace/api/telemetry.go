@@ -34,6 +34,8 @@ const (
 	awsLambda                     cloudResourceType = "AWSLambda"
 	awsFargate                    cloudResourceType = "AWSFargate"
 	cloudRun                      cloudResourceType = "GCPCloudRun"
+	cloudRunJobs                  cloudResourceType = "GCPCloudRunJobs"
+	cloudFunctions                cloudResourceType = "GCPCloudFunctions"
 	azureAppService               cloudResourceType = "AzureAppService"
 	azureContainerApp             cloudResourceType = "AzureContainerApp"
 	aws                           cloudProvider     = "AWS"
@@ -147,6 +149,18 @@ func (r *HTTPReceiver) telemetryProxyHandler() http.Handler {
 				if serviceName, found := r.conf.GlobalTags["service_name"]; found {
 					req.Header.Set(cloudResourceIdentifierHeader, serviceName)
 				}
+			case "cloudrunjobs":
+				req.Header.Set(cloudProviderHeader, string(gcp))
+				req.Header.Set(cloudResourceTypeHeader, string(cloudRunJobs))
+				if jobName, found := r.conf.GlobalTags["job_name"]; found {
+					req.Header.Set(cloudResourceIdentifierHeader, jobName)
+				}
+			case "cloudfunctions":
+				req.Header.Set(cloudProviderHeader, string(gcp))
+				req.Header.Set(cloudResourceTypeHeader, string(cloudFunctions))
+				if functionName, found := r.conf.GlobalTags["function_name"]; found {
+					req.Header.Set(cloudResourceIdentifierHeader, functionName)
+				}
 			case "appservice":
 				req.Header.Set(cloudProviderHeader, string(azure))
 				req.Header.Set(cloudResourceTypeHeader, string(azureAppService))
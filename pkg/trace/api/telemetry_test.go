@@ -119,6 +119,54 @@ func TestGoogleCloudRun(t *testing.T) {
 	assert.Equal(uint64(1), endpointCalled.Load())
 }
 
+func TestGoogleCloudRunJobs(t *testing.T) {
+	endpointCalled := atomic.NewUint64(0)
+	assert := assert.New(t)
+
+	srv := assertingServer(t, func(req *http.Request, body []byte) error {
+		assert.Equal("GCP", req.Header.Get("DD-Cloud-Provider"))
+		assert.Equal("GCPCloudRunJobs", req.Header.Get("DD-Cloud-Resource-Type"))
+		assert.Equal("test_job", req.Header.Get("DD-Cloud-Resource-Identifier"))
+
+		endpointCalled.Inc()
+		return nil
+	})
+
+	req, rec := newRequestRecorder(t)
+	cfg := getTestConfig(srv.URL)
+	cfg.GlobalTags["job_name"] = "test_job"
+	cfg.GlobalTags["origin"] = "cloudrunjobs"
+	recv := newTestReceiverFromConfig(cfg)
+	recv.buildMux().ServeHTTP(rec, req)
+
+	assert.Equal("OK", recordedResponse(t, rec))
+	assert.Equal(uint64(1), endpointCalled.Load())
+}
+
+func TestGoogleCloudFunctions(t *testing.T) {
+	endpointCalled := atomic.NewUint64(0)
+	assert := assert.New(t)
+
+	srv := assertingServer(t, func(req *http.Request, body []byte) error {
+		assert.Equal("GCP", req.Header.Get("DD-Cloud-Provider"))
+		assert.Equal("GCPCloudFunctions", req.Header.Get("DD-Cloud-Resource-Type"))
+		assert.Equal("test_function", req.Header.Get("DD-Cloud-Resource-Identifier"))
+
+		endpointCalled.Inc()
+		return nil
+	})
+
+	req, rec := newRequestRecorder(t)
+	cfg := getTestConfig(srv.URL)
+	cfg.GlobalTags["function_name"] = "test_function"
+	cfg.GlobalTags["origin"] = "cloudfunctions"
+	recv := newTestReceiverFromConfig(cfg)
+	recv.buildMux().ServeHTTP(rec, req)
+
+	assert.Equal("OK", recordedResponse(t, rec))
+	assert.Equal(uint64(1), endpointCalled.Load())
+}
+
 func TestAzureAppService(t *testing.T) {
 	endpointCalled := atomic.NewUint64(0)
 	assert := assert.New(t)
@@ -66,6 +66,7 @@ type DCAClientInterface interface {
 	GetKubernetesClusterID() (string, error)
 
 	PostLanguageMetadata(ctx context.Context, data *pbgo.ParentLanguageAnnotationRequest) error
+	PostLocalExternalMetrics(ctx context.Context, payload *apiv1.LocalExternalMetricsPayload) error
 }
 
 // DCAClient is required to query the API of Datadog cluster agent
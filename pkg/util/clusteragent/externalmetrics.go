@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package clusteragent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
+)
+
+const dcaLocalExternalMetricsPath = "api/v1/externalmetrics/local"
+
+// PostLocalExternalMetrics is called by the aggregator to forward the points
+// of the metrics used to compute external metrics in the cluster
+func (c *DCAClient) PostLocalExternalMetrics(ctx context.Context, payload *apiv1.LocalExternalMetricsPayload) error {
+	queryBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// query https://host:port/api/v1/externalmetrics/local without expecting a response
+	_, err = c.doQuery(ctx, dcaLocalExternalMetricsPath, "POST", bytes.NewBuffer(queryBody), false, false)
+	return err
+}
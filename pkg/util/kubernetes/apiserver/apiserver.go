@@ -122,6 +122,10 @@ type APIClient struct {
 	// initRetry used to setup the APIClient
 	initRetry retry.Retrier
 
+	// informersStopCh is the stop channel of the informers of the factories, it is
+	// never closed as the factories can't be stopped safely
+	informersStopCh chan struct{}
+
 	// Client and informer timeout
 	defaultClientTimeout        time.Duration
 	defaultInformerTimeout      time.Duration
@@ -133,6 +137,7 @@ func initAPIClient() {
 		defaultClientTimeout:        time.Duration(config.Datadog.GetInt64("kubernetes_apiserver_client_timeout")) * time.Second,
 		defaultInformerTimeout:      time.Duration(config.Datadog.GetInt64("kubernetes_apiserver_informer_client_timeout")) * time.Second,
 		defaultInformerResyncPeriod: time.Duration(config.Datadog.GetInt64("kubernetes_informers_resync_period")) * time.Second,
+		informersStopCh:             make(chan struct{}),
 	}
 	globalAPIClient.initRetry.SetupRetrier(&retry.Config{ //nolint:errcheck
 		Name:              "apiserver",
@@ -277,6 +282,12 @@ func (c *APIClient) GetInformerWithOptions(resyncPeriod *time.Duration, options
 	return informers.NewSharedInformerFactoryWithOptions(c.InformerCl, *resyncPeriod, options...)
 }
 
+// InformersStopCh returns the stop channel to start the informers of the factories of the
+// client with, it is never closed as the informers live as long as the agent
+func (c *APIClient) InformersStopCh() <-chan struct{} {
+	return c.informersStopCh
+}
+
 func (c *APIClient) connect() error {
 	var err error
 	// Clients
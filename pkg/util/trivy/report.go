@@ -12,9 +12,13 @@ package trivy
 import (
 	"context"
 
+	"github.com/DataDog/datadog-agent/pkg/version"
+
 	cyclonedxgo "github.com/CycloneDX/cyclonedx-go"
 	"github.com/aquasecurity/trivy/pkg/sbom/cyclonedx"
+	trivyspdx "github.com/aquasecurity/trivy/pkg/sbom/spdx"
 	"github.com/aquasecurity/trivy/pkg/types"
+	"github.com/spdx/tools-golang/spdx"
 )
 
 // Report describes a trivy report along with its marshaler
@@ -36,6 +40,11 @@ func (r *Report) ToCycloneDX() (*cyclonedxgo.BOM, error) {
 	return bom, nil
 }
 
+// ToSPDX returns the report as a SPDX document
+func (r *Report) ToSPDX() (*spdx.Document, error) {
+	return trivyspdx.NewMarshaler(version.AgentVersion).MarshalReport(context.TODO(), *r.Report)
+}
+
 // ID returns the report identifier
 func (r *Report) ID() string {
 	return r.id
@@ -33,6 +33,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	image2 "github.com/aquasecurity/trivy/pkg/fanal/artifact/image"
 	local2 "github.com/aquasecurity/trivy/pkg/fanal/artifact/local"
+	fanalimage "github.com/aquasecurity/trivy/pkg/fanal/image"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/sbom/cyclonedx"
 	"github.com/aquasecurity/trivy/pkg/scanner"
@@ -388,6 +389,17 @@ func (c *Collector) ScanFilesystem(ctx context.Context, fsys fs.FS, path string,
 	return c.scanFilesystem(ctx, fsys, path, nil, scanOptions)
 }
 
+// ScanImageArchive scans a container image saved as a tarball, as produced
+// by `docker save`
+func (c *Collector) ScanImageArchive(ctx context.Context, path string, scanOptions sbom.ScanOptions) (sbom.Report, error) {
+	fanalImage, err := fanalimage.NewArchiveImage(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open image archive %s, err: %w", path, err)
+	}
+
+	return c.scanImage(ctx, fanalImage, nil, scanOptions)
+}
+
 func (c *Collector) scan(ctx context.Context, artifact artifact.Artifact, applier applier.Applier, imgMeta *workloadmeta.ContainerImageMetadata, cache CacheWithCleaner) (*types.Report, error) {
 	if imgMeta != nil && cache != nil {
 		// The artifact reference is only needed to clean up the blobs after the scan.
@@ -413,9 +425,14 @@ func (c *Collector) scan(ctx context.Context, artifact artifact.Artifact, applie
 }
 
 func (c *Collector) scanImage(ctx context.Context, fanalImage ftypes.Image, imgMeta *workloadmeta.ContainerImageMetadata, scanOptions sbom.ScanOptions) (sbom.Report, error) {
-	cache, err := c.getCache()
-	if err != nil {
-		return nil, err
+	var cache CacheWithCleaner
+	if scanOptions.NoCache {
+		cache = newMemoryCache()
+	} else {
+		var err error
+		if cache, err = c.getCache(); err != nil {
+			return nil, err
+		}
 	}
 
 	imageArtifact, err := image2.NewArtifact(fanalImage, cache, getDefaultArtifactOption("", scanOptions))
@@ -423,7 +440,7 @@ func (c *Collector) scanImage(ctx context.Context, fanalImage ftypes.Image, imgM
 		return nil, fmt.Errorf("unable to create artifact from image, err: %w", err)
 	}
 
-	trivyReport, err := c.scan(ctx, imageArtifact, applier.NewApplier(cache), imgMeta, c.cache)
+	trivyReport, err := c.scan(ctx, imageArtifact, applier.NewApplier(cache), imgMeta, cache)
 	if err != nil {
 		return nil, fmt.Errorf("unable to marshal report to sbom format, err: %w", err)
 	}